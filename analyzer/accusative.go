@@ -0,0 +1,70 @@
+// accusative.go содержит эвристики разрешения омонимии винительного падежа.
+// У одушевленных существительных мужского рода (и у всех одушевленных во множественном числе)
+// винительный падеж совпадает с родительным, у неодушевленных - с именительным.
+// Словарь хранит оба варианта винительного падежа с пометой одушевленности,
+// поэтому для "стола" появляется ложный разбор "Винительный, Одушевленное".
+package analyzer
+
+const (
+	animate   = "Одушевленное"
+	inanimate = "Неодушевленное"
+)
+
+// ResolveAccusative убирает разборы существительных в винительном падеже, чья помета
+// одушевленности противоречит лексической одушевленности леммы.
+// Лексическая одушевленность определяется по остальным (не винительным) разборам той же леммы.
+// Если определить ее не удалось, разборы леммы остаются без изменений.
+// Порядок оставшихся разборов сохраняется. Граммемы разборов могут быть на любом языке (см. WithLang).
+func ResolveAccusative(parses []*Parsed) []*Parsed {
+	// Подсчитываем одушевленность каждой леммы по разборам в остальных падежах.
+	type votes struct{ animate, inanimate int }
+	lemmaAnimacy := make(map[string]*votes)
+	for _, p := range parses {
+		p := p.Details()
+		if !isNoun(p) || isAccusative(p) {
+			continue
+		}
+		v, ok := lemmaAnimacy[p.Lemma]
+		if !ok {
+			v = &votes{}
+			lemmaAnimacy[p.Lemma] = v
+		}
+		switch canonicalGrammeme(p.Animacy) {
+		case animate:
+			v.animate++
+		case inanimate:
+			v.inanimate++
+		}
+	}
+
+	resolved := make([]*Parsed, 0, len(parses))
+	for _, p := range parses {
+		if d := p.Details(); isNoun(d) && isAccusative(d) {
+			if v, ok := lemmaAnimacy[d.Lemma]; ok {
+				switch animacy := canonicalGrammeme(d.Animacy); {
+				case animacy == animate && v.inanimate > v.animate:
+					continue
+				case animacy == inanimate && v.animate > v.inanimate:
+					continue
+				}
+			}
+		}
+		resolved = append(resolved, p)
+	}
+
+	// Не возвращаем пустой результат: лучше неоднозначный разбор, чем никакого.
+	if len(resolved) == 0 {
+		return parses
+	}
+	return resolved
+}
+
+// isNoun сообщает, что разбор - существительное (граммемы могут быть на любом языке).
+func isNoun(p *Parsed) bool {
+	return canonicalGrammeme(p.PartOfSpeech) == "Существительное"
+}
+
+// isAccusative сообщает, что разбор в винительном падеже (граммемы могут быть на любом языке).
+func isAccusative(p *Parsed) bool {
+	return canonicalGrammeme(p.Case) == "Винительный"
+}
@@ -7,20 +7,18 @@ package analyzer
 
 import (
 	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/binary"
 	"encoding/gob"
-	"errors"
 	"fmt"
-	"io"
-	"io/fs"
+	"log/slog"
+	"maps"
 	"os"
-	"path/filepath"
-	"reflect"
-	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/edsrzf/mmap-go"
@@ -31,6 +29,9 @@ import (
 // EnvDictPath - имя переменной окружения для переопределения пути к словарю.
 const EnvDictPath = "STEOSMORPHY_DICT_PATH"
 
+// DictFileName - имя объединенного файла словаря, который ищет загрузчик.
+const DictFileName = "morph.dawg"
+
 // --- СТРУКТУРЫ ДАННЫХ ---
 
 // MorphInfo - Хранит индексы, указывающие на пулы строк и информацию о парадигме.
@@ -43,6 +44,7 @@ type MorphInfo struct {
 // PredictInfo - Хранит полезную информацию в узлах DAWG предсказателя.
 type PredictInfo struct {
 	Frequency  uint16 // Как часто это правило (суффикс + парадигма) встречалось в словаре.
+	_          uint16 // Явное дополнение до выравнивания ParadigmID (см. format.go).
 	ParadigmID uint32 // ID парадигмы, которую нужно использовать для склонения.
 	FormIdx    uint32 // Индекс слова-образца в его канонически отсортированной парадигме.
 	TagsID     uint32 // ID тегов для этой конкретной формы-образца.
@@ -59,9 +61,10 @@ type Node struct {
 // FlatNode - "Плоское" представление узла для сохранения на диск.
 // Вместо указателей используются индексы в глобальных массивах.
 type FlatNode struct {
-	PayloadIdx, EdgesIdx uint32 // Индексы начала срезов в массивах Payloads и Edges.
-	PayloadLen, EdgesLen uint16 // Длины этих срезов.
-	IsFinal              bool   // Является ли этот узел концом слова/правила.
+	PayloadIdx, EdgesIdx uint32  // Индексы начала срезов в массивах Payloads и Edges.
+	PayloadLen, EdgesLen uint16  // Длины этих срезов.
+	IsFinal              bool    // Является ли этот узел концом слова/правила.
+	_                    [3]byte // Явное дополнение до 16 байт (см. format.go).
 }
 
 // FlatEdge - "Плоское" представление ребра графа.
@@ -77,42 +80,113 @@ type ParadigmInfo struct {
 	NodeID uint32 // ID узла в "плоском" DAWG, где эта основа заканчивается.
 }
 
-// Header - Заголовок бинарного файла morph_3.dawg.
+// Версии формата словаря. Версии 7-9 записывали версию цифрой в сигнатуре ("DAW7")
+// и заголовок без метаданных (см. legacyHeader); начиная с версии 10 сигнатура
+// постоянна ("DAWG"), а версия и метаданные хранятся в Header.
+const (
+	// dictVersionGob - пулы строк и таблицы парадигм в блоке gob; они распаковываются в кучу.
+	dictVersionGob = 7
+	// dictVersionFlat - плоские пулы (см. PoolsHeader) и массивы в раскладке платформы сборки.
+	dictVersionFlat = 8
+	// dictVersionPortable - массивы в платформонезависимой раскладке little-endian (см. format.go).
+	dictVersionPortable = 9
+	// dictVersionMeta - заголовок с версией и метаданными (см. DictInfo).
+	dictVersionMeta = 10
+	// dictVersionChecksums - контрольные суммы секций (см. Checksums).
+	dictVersionChecksums = 11
+	// dictVersion - текущая версия, которую записывает DictBuilder.
+	// Прежние версии переводятся в нее функцией ConvertDict.
+	dictVersion = dictVersionChecksums
+
+	// minDictVersion - старейшая версия, которую читает загрузчик.
+	minDictVersion = dictVersionGob
+	// dictMagic - сигнатура словарей версии 10 и новее.
+	dictMagic = "DAWG"
+	// legacyMagicPrefix - начало сигнатуры словарей версий до 10, за ним следует цифра версии.
+	legacyMagicPrefix = "DAW"
+)
+
+// Header - Заголовок бинарного файла morph.dawg.
 // Это "карта" всего файла, которая позволяет анализатору загружать данные методом Zero-Copy.
+// За ним следуют PoolsHeader и, начиная с версии 11, Checksums.
 type Header struct {
-	Magic                 [4]byte // Сигнатура "DAW7" для проверки корректности файла.
-	ComplexDataOffset     int64   // Смещение до блока "сложных" данных (в байтах).
-	ComplexDataLength     int64   // Длина этого блока (в байтах).
-	NodesOffset           int64   // Смещение до массива узлов основного словаря.
-	NodesCount            int64   // Количество элементов в этом массиве.
-	EdgesOffset           int64   // Смещение до массива ребер основного словаря.
-	EdgesCount            int64   // Количество элементов.
-	PayloadsOffset        int64   // Смещение до массива payload-ов основного словаря.
-	PayloadsCount         int64   // Количество элементов.
-	PredictNodesOffset    int64   // Смещение до массива узлов предсказателя.
-	PredictNodesCount     int64   // Количество элементов.
-	PredictEdgesOffset    int64   // Смещение до массива ребер предсказателя.
-	PredictEdgesCount     int64   // Количество элементов.
-	PredictPayloadsOffset int64   // Смещение до массива payload-ов предсказателя.
-	PredictPayloadsCount  int64   // Количество элементов.
+	Magic                 [4]byte  // Сигнатура "DAWG" для проверки корректности файла.
+	Version               uint32   // Версия формата.
+	ComplexDataOffset     int64    // Смещение до блока "сложных" данных (в байтах).
+	ComplexDataLength     int64    // Длина этого блока (в байтах).
+	NodesOffset           int64    // Смещение до массива узлов основного словаря.
+	NodesCount            int64    // Количество элементов в этом массиве.
+	EdgesOffset           int64    // Смещение до массива ребер основного словаря.
+	EdgesCount            int64    // Количество элементов.
+	PayloadsOffset        int64    // Смещение до массива payload-ов основного словаря.
+	PayloadsCount         int64    // Количество элементов.
+	PredictNodesOffset    int64    // Смещение до массива узлов предсказателя.
+	PredictNodesCount     int64    // Количество элементов.
+	PredictEdgesOffset    int64    // Смещение до массива ребер предсказателя.
+	PredictEdgesCount     int64    // Количество элементов.
+	PredictPayloadsOffset int64    // Смещение до массива payload-ов предсказателя.
+	PredictPayloadsCount  int64    // Количество элементов.
+	BuildTime             int64    // Время сборки (Unix, секунды).
+	WordCount             int64    // Количество словоформ.
+	LemmaCount            int64    // Количество лемм.
+	SourceRevision        [64]byte // Ревизия исходного корпуса, дополненная нулями.
+}
+
+// legacyHeader - заголовок словарей версий 7-9: сигнатура "DAW7".."DAW9" и карта файла
+// без версии и метаданных. В версиях 8 и 9 за ним следует PoolsHeader.
+type legacyHeader struct {
+	Magic                 [4]byte
+	ComplexDataOffset     int64
+	ComplexDataLength     int64
+	NodesOffset           int64
+	NodesCount            int64
+	EdgesOffset           int64
+	EdgesCount            int64
+	PayloadsOffset        int64
+	PayloadsCount         int64
+	PredictNodesOffset    int64
+	PredictNodesCount     int64
+	PredictEdgesOffset    int64
+	PredictEdgesCount     int64
+	PredictPayloadsOffset int64
+	PredictPayloadsCount  int64
 }
 
 // ComplexData - Контейнер для всех данных, которые неэффективно хранить в "сыром" виде.
 // Эта часть файла сериализуется с помощью `gob` и полностью загружается в память.
+// В формате DAW8 пулы строк и таблицы парадигм хранятся плоско (см. PoolsHeader),
+// и блок содержит только необязательные данные.
 type ComplexData struct {
-	LemmaPool         []string                  // Пул всех лемм.
-	TagsPool          []string                  // Пул всех наборов тегов.
-	Paradigms         map[uint32][]ParadigmInfo // Информация о парадигмах.
-	ParadigmToLemmaID map[uint32]uint32         // Карта для быстрого поиска леммы по ID парадигмы.
+	LemmaPool         []string                  // Пул всех лемм (только DAW7).
+	TagsPool          []string                  // Пул всех наборов тегов (только DAW7).
+	Paradigms         map[uint32][]ParadigmInfo // Информация о парадигмах (только DAW7).
+	ParadigmToLemmaID map[uint32]uint32         // Карта для быстрого поиска леммы по ID парадигмы (только DAW7).
+	Valency           map[uint32][]ValencyFrame // Валентные рамки глаголов по ID леммы (необязательный блок).
+	License           *DictLicense              // Лицензия исходного лексикона (необязательный блок).
+	SelfTest          []SelfTestCase            // Контрольная выборка для SelfTest (необязательный блок).
+	TagIndex          map[uint32][]uint32       // ID набора тегов -> возрастающие ID парадигм с формой в нем (необязательный блок, см. FindByTags).
+	AspectPairs       map[uint32][]uint32       // ID леммы глагола -> ID лемм глаголов другого вида (необязательный блок, см. AspectPair).
+	Derivations       map[uint32][]uint32       // ID леммы -> ID производных лемм (необязательный блок, см. Derivations).
 }
 
 // MorphAnalyzer - основная структура, хранящая все данные и состояние анализатора.
 type MorphAnalyzer struct {
 	// Данные словаря.
-	LemmaPool         []string                  // Пул всех лемм.
-	tagsPool          []string                  // Пул всех наборов тегов.
-	paradigms         map[uint32][]ParadigmInfo // Информация о парадигмах.
-	paradigmToLemmaID map[uint32]uint32         // Карта для быстрого поиска леммы по ID парадигмы.
+	lemmas       stringPool                // Пул всех лемм.
+	tags         stringPool                // Пул всех наборов тегов.
+	paradigms    paradigmTable             // Основы и леммы парадигм.
+	valency      map[uint32][]ValencyFrame // Валентные рамки глаголов (nil, если словарь их не содержит).
+	license      *DictLicense              // Лицензия исходного лексикона (nil, если словарь ее не содержит).
+	selfTest     []SelfTestCase            // Контрольная выборка для SelfTest (nil, если словарь ее не содержит).
+	tagIndex     map[uint32][]uint32       // Обратный индекс наборов тегов (nil, если словарь его не содержит).
+	aspectPairs  map[uint32][]uint32       // Видовые пары глаголов (nil, если словарь их не содержит).
+	derivations  map[uint32][]uint32       // Производные леммы (nil, если словарь их не содержит).
+	grammemeBits map[string]int            // Граммема -> номер ее бита в GrammemeMask.
+	tagMasks     []GrammemeMask            // Маски граммем наборов тегов (по ID набора).
+	info         DictInfo                  // Сведения из заголовка словаря.
+	data         []byte                    // Содержимое файла словаря (mmap-область или встроенный срез).
+	wordsOnce    sync.Once                 // Подсчет словоформ для Info (в словарях версий до 10 его нет в заголовке).
+	words        int64
 
 	// "Сырые" данные, отображенные в память (mmap), но не скопированные в "кучу" Go.
 	// Это срезы, указывающие на область памяти, управляемую ОС.
@@ -127,6 +201,45 @@ type MorphAnalyzer struct {
 	// Ссылка на mmap-объект, чтобы он не был собран сборщиком мусора
 	// и память оставалась доступной.
 	mmapFile mmap.MMap
+	// Освобождает отображение частей словаря (см. LoadMorphAnalyzerFromParts).
+	unmapParts func() error
+
+	// Настройки, задаваемые опциями при загрузке.
+	verifyOnLoad      bool              // Сверять контрольные суммы словаря при загрузке (см. WithVerifyOnLoad).
+	autoMerge         bool              // Объединять найденные части словаря (см. WithAutoMerge).
+	heapLoad          bool              // Читать файл словаря в кучу вместо mmap (см. WithHeapLoad).
+	prefault          bool              // Прогревать отображение словаря при загрузке (см. WithPrefault).
+	mlock             bool              // Закреплять словарь в памяти (см. WithMlock).
+	locked            bool              // Словарь закреплен в памяти, Close должен снять закрепление.
+	resolveAccusative bool              // Отбрасывать винительный падеж, противоречащий одушевленности существительного.
+	repairMixedScript bool              // Исправлять слова со смешанной кириллицей и латиницей (см. WithMixedScriptRepair).
+	ocrTolerance      bool              // Исправлять типичные ошибки OCR в несловарных словах (см. WithOCRTolerance).
+	foldHomoglyphs    bool              // Заменять буквы-двойники до поиска (см. WithHomoglyphFolding).
+	fallbackStemmers  []scriptStemmer   // Стеммеры языков без словаря (см. WithFallbackStemmer).
+	dictionaryOnly    bool              // Не предсказывать несловарные слова (см. WithDictionaryOnly).
+	lazyDetails       bool              // Не раскладывать граммемы разборов по категориям (см. WithLazyDetails).
+	maxWordLen        int               // Максимальная длина слова в символах (0 - DefaultMaxWordLen, < 0 - без ограничения).
+	wordFrequencies   map[string]uint64 // Частоты словоформ для ранжирования (см. WithWordFrequencies).
+	frequencyOrder    bool              // Упорядочивать словоформы по частоте (см. WithFrequencyOrder).
+	tagModel          *TagModel         // Модель переходов для Disambiguate (nil - встроенная).
+	budget            *MemoryBudget     // Лимит памяти под порции InflectListFunc (nil - без ограничения).
+	cache             Cache             // Кэш результатов Parse, ParsePredicted и Inflect (nil - без кэша).
+	units             []AnalyzerUnit    // Цепочка звеньев разбора (nil - DefaultUnits).
+	lang              Lang              // Язык граммем в результатах (см. WithLang).
+	feedback          FeedbackSink      // Приемник отчетов ReportMisparse (nil - не задан).
+	stats             *statsCounters    // Счетчики Stats (nil - статистика не ведется, см. WithStats).
+	hooks             []AnalysisHook    // Хуки, вызываемые после каждого разбора (см. WithAnalysisHook).
+	batchHooks        []BatchHook       // Хуки пакетных операций (см. WithBatchHook).
+	logger            *slog.Logger      // Журнал анализатора (nil - журнал пакета, см. SetLogger).
+
+	parseInterceptors   []Interceptor // Перехватчики Parse в порядке добавления.
+	inflectInterceptors []Interceptor // Перехватчики Inflect в порядке добавления.
+	parseChain          ParseFunc     // Собранная цепочка перехватчиков Parse (nil, если их нет).
+	inflectChain        ParseFunc     // Собранная цепочка перехватчиков Inflect (nil, если их нет).
+
+	lookupStrategy   LookupStrategy  // Стратегия поиска переходов (после загрузки - не LookupAuto).
+	rootIndex        *firstCharIndex // Таблица переходов из корня основного DAWG (nil - бинарный поиск).
+	predictRootIndex *firstCharIndex // Таблица переходов из корня DAWG предсказателя.
 }
 
 // PredictionCandidate - временная структура для хранения кандидата на предсказание.
@@ -139,64 +252,131 @@ type PredictionCandidate struct {
 // --- ЛОГИКА АНАЛИЗАТОРА ---
 
 // LoadMorphAnalyzer - конструктор анализатора.
-func LoadMorphAnalyzer() (*MorphAnalyzer, error) {
-	dictPath := os.Getenv(EnvDictPath)
-	if dictPath != "" {
-		return loadInternal(dictPath)
+// Словарь ищется по порядку: путь из переменной окружения STEOSMORPHY_DICT_PATH,
+// источники, зарегистрированные через RegisterDictLocator (их регистрирует пакет
+// github.com/steosofficial/steosmorphy/dict), и файл в DefaultDictDir, куда его скачивает EnsureDict.
+// Части словаря, найденные источником, объединяются в DefaultDictDir только с опцией
+// WithAutoMerge; без нее используется объединенный ранее файл, а если его нет, словарь
+// загружается прямо из частей (см. LoadMorphAnalyzerFromParts). Если словарь не найден,
+// возвращается ErrDictNotFound с подсказкой, как его подключить.
+// Поведение анализатора можно настроить опциями (см. Option).
+func LoadMorphAnalyzer(opts ...Option) (*MorphAnalyzer, error) {
+	if dictPath := os.Getenv(EnvDictPath); dictPath != "" {
+		return loadWithOptions(dictPath, opts)
 	}
 
-	_, currentFilePath, _, ok := runtime.Caller(0)
-	if !ok {
-		return nil, errors.New("не удалось определить путь к пакету steosmorphy")
+	source, err := locateDict()
+	if err != nil {
+		return nil, err
 	}
+	if source.Data != nil {
+		started := time.Now()
+		analyzer, err := loadFromBytes(source.Data)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки встроенного словаря: %w", err)
+		}
+		analyzer = applyOptions(analyzer, opts)
+		if err := analyzer.prepare(); err != nil {
+			return nil, fmt.Errorf("ошибка загрузки встроенного словаря: %w", err)
+		}
+		analyzer.logLoaded("встроенный словарь", started)
+		return analyzer, nil
+	}
+	if source.Parts != "" {
+		merged, err := mergedParts(source.Parts, loadSettings(opts).autoMerge)
+		if err != nil {
+			return nil, err
+		}
+		if merged == "" {
+			return LoadMorphAnalyzerFromParts(source.Parts, opts...)
+		}
+		source.Path = merged
+	}
+	return loadWithOptions(source.Path, opts)
+}
 
-	packageDir := filepath.Dir(currentFilePath)
-	dictPath = filepath.Join(packageDir, "morph.dawg")
-
-	// Проверяем, существует ли объединенный файл.
-	// Если нет, ищем части и объединяем их.
-	if _, err := os.Stat(dictPath); os.IsNotExist(err) {
-		fmt.Printf("Объединенный файл словаря '%s' не найден. Ищем части для объединения.\n", dictPath)
+// LoadMorphAnalyzerFromFile загружает анализатор из файла словаря по явному пути,
+// без переменной окружения и опроса источников словаря (см. LoadMorphAnalyzer).
+func LoadMorphAnalyzerFromFile(dictPath string, opts ...Option) (*MorphAnalyzer, error) {
+	return loadWithOptions(dictPath, opts)
+}
 
-		// Получаем директорию, где должен находиться файл (или его части)
-		dirToSearchParts := filepath.Dir(dictPath)
-		if dirToSearchParts == "" { // Если dictPath - просто имя файла, то текущая директория
-			dirToSearchParts = "."
+// Close освобождает отображение файла словаря в память. После Close анализатором
+// пользоваться нельзя; уже полученные результаты (Parsed) остаются корректными.
+// Для анализатора со встроенным или прочитанным в кучу словарем Close только снимает
+// закрепление в памяти (см. WithMlock).
+func (a *MorphAnalyzer) Close() error {
+	if a.locked {
+		a.locked = false
+		if err := unlockMemory(a.data); err != nil {
+			return fmt.Errorf("ошибка munlock: %w", err)
 		}
-
-		// Вызываем функцию объединения.
-		// Имя префикса частей: "morph_"
-		// Имя целевого объединенного файла: "morph.dawg" (или что было в dictPath)
-		err = mergeFilesWithPrefix(dirToSearchParts, "morph_", dictPath)
+	}
+	if a.unmapParts != nil {
+		err := a.unmapParts()
+		a.unmapParts = nil
 		if err != nil {
-			// Если произошла ошибка при объединении, проверяем, была ли это ошибка "файлы не найдены".
-			// Если да, то, возможно, словарь просто отсутствует.
-			if strings.Contains(err.Error(), "не найдено файлов с префиксом") {
-				return nil, fmt.Errorf(
-					"словарь или его части не найдены по вычисленному пути '%s'. "+
-						"Убедитесь, что библиотека установлена корректно и файлы 'morph_aa', 'morph_ab', ... присутствуют. "+
-						"Либо установите переменную окружения %s",
-					dictPath, EnvDictPath,
-				)
-			}
-			return nil, fmt.Errorf("ошибка при объединении частей словаря: %w", err)
+			return fmt.Errorf("ошибка munmap: %w", err)
 		}
-		fmt.Printf("Части словаря успешно объединены в '%s'.\n", dictPath)
 	}
+	if a.mmapFile == nil {
+		return nil
+	}
+	err := a.mmapFile.Unmap()
+	a.mmapFile = nil
+	if err != nil {
+		return fmt.Errorf("ошибка mmap.Unmap: %w", err)
+	}
+	return nil
+}
 
-	// 4. После того как убедились, что объединенный файл существует (или был создан),
-	// передаем его путь в loadInternal.
+// loadWithOptions загружает словарь способом, заданным опциями (см. WithHeapLoad),
+// и применяет к анализатору переданные опции.
+func loadWithOptions(dictPath string, opts []Option) (*MorphAnalyzer, error) {
+	started := time.Now()
+	load := loadInternal
+	if loadSettings(opts).heapLoad {
+		load = loadHeap
+	}
+	analyzer, err := load(dictPath)
+	if err != nil {
+		return nil, err
+	}
+	return finishLoad(analyzer, dictPath, opts, started)
+}
+
+// finishLoad применяет опции к только что загруженному из source анализатору
+// и готовит его к работе; при ошибке анализатор закрывается.
+func finishLoad(analyzer *MorphAnalyzer, source string, opts []Option, started time.Time) (*MorphAnalyzer, error) {
+	analyzer = applyOptions(analyzer, opts)
+	if err := analyzer.prepare(); err != nil {
+		_ = analyzer.Close()
+		return nil, err
+	}
+	analyzer.logLoaded(source, started)
+	return analyzer, nil
+}
 
-	if _, err := os.Stat(dictPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf(
-			"словарь не найден по вычисленному пути '%s'. "+
-				"Убедитесь, что библиотека установлена корректно и файл 'morph.dawg' присутствует. "+
-				"Либо установите переменную окружения %s",
-			dictPath, EnvDictPath,
-		)
+// applyOptions применяет опции к только что загруженному анализатору.
+func applyOptions(analyzer *MorphAnalyzer, opts []Option) *MorphAnalyzer {
+	for _, opt := range opts {
+		opt(analyzer)
+	}
+	if analyzer.dictionaryOnly {
+		WithoutUnits(predictingUnits...)(analyzer)
 	}
+	analyzer.buildInterceptorChains()
+	analyzer.initLookup()
+	return analyzer
+}
 
-	return loadInternal(dictPath)
+// prepare выполняет после применения опций прогрев словаря и проверку контрольных сумм
+// (см. WithPrefault, WithMlock, WithVerifyOnLoad).
+func (a *MorphAnalyzer) prepare() error {
+	if err := a.warmUp(); err != nil {
+		return err
+	}
+	return a.verifyLoaded()
 }
 
 // loadInternal Загружает бинарный словарь, читает его заголовок, декодирует "сложную" часть
@@ -217,174 +397,324 @@ func loadInternal(filepath string) (*MorphAnalyzer, error) {
 		return nil, fmt.Errorf("ошибка mmap.Map: %w", err)
 	}
 
-	// 3. Читаем заголовок (карту файла) прямо из mmap-среза.
-	var header Header
-	headerSize := int(unsafe.Sizeof(header))
-	if len(mmapFile) < headerSize {
-		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("файл слишком мал для заголовка")
-	}
-	if err := binary.Read(bytes.NewReader(mmapFile[:headerSize]), binary.LittleEndian, &header); err != nil {
+	analyzer, err := loadFromBytes(mmapFile)
+	if err != nil {
 		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("ошибка чтения заголовка: %w", err)
+		return nil, err
 	}
-	if string(header.Magic[:]) != "DAW7" {
+	if analyzer.info.LoadMode == LoadModeZstd {
+		// Словарь распакован в кучу, отображение файла больше не нужно.
 		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("неверная сигнатура файла")
+		return analyzer, nil
 	}
+	analyzer.mmapFile = mmapFile
+	analyzer.setLoadMode(LoadModeMmap)
+	return analyzer, nil
+}
 
-	// 4. Декодируем "сложный" блок (строки, карты) с помощью gob.
-	complexStart := header.ComplexDataOffset
-	complexEnd := complexStart + header.ComplexDataLength
-	compressedBlock := mmapFile[complexStart:complexEnd]
+// loadFromBytes создает анализатор поверх содержимого файла словаря, уже находящегося в памяти
+// (в mmap-области или, например, во встроенном в бинарник срезе).
+// Срез data не копируется: анализатор ссылается на него до конца своей жизни.
+func loadFromBytes(data []byte) (*MorphAnalyzer, error) {
+	// Файл, целиком сжатый zstd, распаковывается в кучу.
+	if isZstd(data) {
+		return loadCompressed(data)
+	}
 
-	// 4.1. Распаковываем блок в памяти
-	gzipReader, err := gzip.NewReader(bytes.NewReader(compressedBlock))
+	// 3. Читаем заголовок (карту файла) прямо из среза.
+	header, headerSize, err := readHeader(data)
 	if err != nil {
-		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("ошибка создания gzip.Reader: %w", err)
+		return nil, err
 	}
 
-	decompressedBytes, err := io.ReadAll(gzipReader)
+	// 4. Декодируем "сложный" блок (строки, карты) с помощью gob.
+	compressedBlock, err := section(data, header.ComplexDataOffset, header.ComplexDataLength)
 	if err != nil {
-		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("ошибка распаковки данных: %w", err)
+		return nil, err
 	}
-	if err := gzipReader.Close(); err != nil {
-		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("ошибка закрытия gzip.Reader: %w", err)
+
+	// 4.1. Распаковываем блок в памяти (gzip или zstd, см. compress.go).
+	decompressedBytes, err := decompressBlock(compressedBlock)
+	if err != nil {
+		return nil, err
 	}
 
 	// 4.2 Декодируем РАСПАКОВАННЫЕ байты с помощью gob
 	var complexData ComplexData
 	if err := gob.NewDecoder(bytes.NewReader(decompressedBytes)).Decode(&complexData); err != nil {
-		_ = mmapFile.Unmap()
-		return nil, fmt.Errorf("ошибка gob-декодирования: %w", err)
+		return nil, fmt.Errorf("%w: ошибка gob-декодирования: %w", ErrDictCorrupted, err)
 	}
 
 	// 5. Создаем "виртуальные" срезы, используя `bytesToSlice`.
-	// Эти срезы не владеют данными, а лишь указывают на нужные участки mmap-файла.
-	nodes := bytesToSlice[FlatNode](mmapFile[header.NodesOffset : header.NodesOffset+header.NodesCount*int64(unsafe.Sizeof(FlatNode{}))])
-	edges := bytesToSlice[FlatEdge](mmapFile[header.EdgesOffset : header.EdgesOffset+header.EdgesCount*int64(unsafe.Sizeof(FlatEdge{}))])
-	payloads := bytesToSlice[MorphInfo](mmapFile[header.PayloadsOffset : header.PayloadsOffset+header.PayloadsCount*int64(unsafe.Sizeof(MorphInfo{}))])
-	predictNodes := bytesToSlice[FlatNode](mmapFile[header.PredictNodesOffset : header.PredictNodesOffset+header.PredictNodesCount*int64(unsafe.Sizeof(FlatNode{}))])
-	predictEdges := bytesToSlice[FlatEdge](mmapFile[header.PredictEdgesOffset : header.PredictEdgesOffset+header.PredictEdgesCount*int64(unsafe.Sizeof(FlatEdge{}))])
-	predictPayloads := bytesToSlice[PredictInfo](mmapFile[header.PredictPayloadsOffset : header.PredictPayloadsOffset+header.PredictPayloadsCount*int64(unsafe.Sizeof(PredictInfo{}))])
+	// Эти срезы не владеют данными, а лишь указывают на нужные участки исходного среза.
+	nodes, err := sectionSlice[FlatNode](data, header.NodesOffset, header.NodesCount)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := sectionSlice[FlatEdge](data, header.EdgesOffset, header.EdgesCount)
+	if err != nil {
+		return nil, err
+	}
+	payloads, err := sectionSlice[MorphInfo](data, header.PayloadsOffset, header.PayloadsCount)
+	if err != nil {
+		return nil, err
+	}
+	predictNodes, err := sectionSlice[FlatNode](data, header.PredictNodesOffset, header.PredictNodesCount)
+	if err != nil {
+		return nil, err
+	}
+	predictEdges, err := sectionSlice[FlatEdge](data, header.PredictEdgesOffset, header.PredictEdgesCount)
+	if err != nil {
+		return nil, err
+	}
+	predictPayloads, err := sectionSlice[PredictInfo](data, header.PredictPayloadsOffset, header.PredictPayloadsCount)
+	if err != nil {
+		return nil, err
+	}
 
 	// 6. Инициализируем и возвращаем готовый к работе анализатор.
 	analyzer := &MorphAnalyzer{
-		LemmaPool:         complexData.LemmaPool,
-		tagsPool:          complexData.TagsPool,
-		paradigms:         complexData.Paradigms,
-		paradigmToLemmaID: complexData.ParadigmToLemmaID,
-		nodes:             nodes,
-		edges:             edges,
-		payloads:          payloads,
-		predictNodes:      predictNodes,
-		predictEdges:      predictEdges,
-		predictPayloads:   predictPayloads,
-		mmapFile:          mmapFile,
+		valency:         complexData.Valency,
+		license:         complexData.License,
+		selfTest:        complexData.SelfTest,
+		tagIndex:        complexData.TagIndex,
+		aspectPairs:     complexData.AspectPairs,
+		derivations:     complexData.Derivations,
+		nodes:           nodes,
+		edges:           edges,
+		payloads:        payloads,
+		predictNodes:    predictNodes,
+		predictEdges:    predictEdges,
+		predictPayloads: predictPayloads,
 	}
 
+	// 7. Пулы строк и таблицы парадигм: начиная с версии 8 они читаются прямо из data,
+	// в версии 7 - собираются из декодированного блока gob.
+	analyzer.info = newDictInfo(&header)
+	analyzer.data = data
+	if header.Version != dictVersionGob {
+		var pools PoolsHeader
+		poolsBytes, err := section(data, headerSize, int64(binary.Size(pools)))
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Read(bytes.NewReader(poolsBytes), binary.LittleEndian, &pools); err != nil {
+			return nil, fmt.Errorf("%w: ошибка чтения заголовка пулов: %w", ErrDictCorrupted, err)
+		}
+		if err := analyzer.loadFlatPools(data, &pools); err != nil {
+			return nil, err
+		}
+		analyzer.info.Lemmas = int64(analyzer.lemmas.len())
+		return analyzer, nil
+	}
+	analyzer.lemmas = newStringPool(complexData.LemmaPool)
+	analyzer.tags = newStringPool(complexData.TagsPool)
+	analyzer.paradigms = newParadigmTable(complexData.Paradigms, complexData.ParadigmToLemmaID)
+	// Словари версии 7 не содержат масок граммем: они строятся по пулу тегов.
+	if err := analyzer.initGrammemeMasks(nil, nil); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDictCorrupted, err)
+	}
+	analyzer.info.Lemmas = int64(analyzer.lemmas.len())
 	return analyzer, nil
 }
 
-// mergeFilesWithPrefix объединяет файлы с заданным префиксом в один большой файл.
-// sourceDir - директория, где находятся части.
-// prefix - префикс имен файлов частей (например, "morph_").
-// outputPath - путь к файлу, куда будут записаны объединенные данные.
-func mergeFilesWithPrefix(sourceDir, prefix, outputPath string) error {
-	// 1. Найти все файлы, начинающиеся с префикса в указанной директории.
-	var partFiles []string
-	err := filepath.WalkDir(sourceDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+// readHeader читает заголовок словаря любой поддерживаемой версии и возвращает его
+// вместе со смещением PoolsHeader. Заголовок версий 7-9 переводится в Header
+// (метаданных в нем нет). Для слишком старой или слишком новой версии возвращает
+// ErrDictVersion, для неизвестной сигнатуры - ErrDictCorrupted.
+func readHeader(data []byte) (Header, int64, error) {
+	var header Header
+	if len(data) < len(header.Magic) {
+		return header, 0, fmt.Errorf("%w: файл слишком мал для заголовка", ErrDictCorrupted)
+	}
+	magic := string(data[:len(header.Magic)])
+	if magic == dictMagic {
+		size := binary.Size(header)
+		if len(data) < size {
+			return header, 0, fmt.Errorf("%w: файл слишком мал для заголовка", ErrDictCorrupted)
 		}
-		if !d.IsDir() && filepath.Base(path) != outputPath && filepath.HasPrefix(filepath.Base(path), prefix) {
-			partFiles = append(partFiles, path)
+		if err := binary.Read(bytes.NewReader(data[:size]), binary.LittleEndian, &header); err != nil {
+			return header, 0, fmt.Errorf("%w: ошибка чтения заголовка: %w", ErrDictCorrupted, err)
 		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("ошибка при поиске файлов: %w", err)
+		if header.Version > dictVersion {
+			return header, 0, fmt.Errorf("%w: версия %d новее поддерживаемой (%d), обновите steosmorphy",
+				ErrDictVersion, header.Version, dictVersion)
+		}
+		if header.Version < dictVersionMeta {
+			return header, 0, fmt.Errorf("%w: заголовок версии %d с сигнатурой %q", ErrDictCorrupted, header.Version, magic)
+		}
+		return header, int64(size), nil
 	}
 
-	if len(partFiles) == 0 {
-		return fmt.Errorf("не найдено файлов с префиксом '%s' в директории '%s'", prefix, sourceDir)
+	digit, ok := strings.CutPrefix(magic, legacyMagicPrefix)
+	if !ok || len(digit) != 1 || digit[0] < '0' || digit[0] > '9' {
+		return header, 0, fmt.Errorf("%w: неверная сигнатура файла", ErrDictCorrupted)
 	}
-
-	// 2. Сортировать файлы по имени, чтобы обеспечить правильный порядок.
-	// `split` по умолчанию создает файлы с суффиксами `aa`, `ab`, `ac` и т.д.,
-	// что обеспечивает правильный лексикографический порядок.
-	sort.Strings(partFiles)
-
-	fmt.Printf("Будут объединены следующие части (в порядке):\n")
-	for _, part := range partFiles {
-		fmt.Printf("- %s\n", filepath.Base(part))
+	version := uint32(digit[0] - '0')
+	if version < minDictVersion {
+		return header, 0, fmt.Errorf("%w: версия %d устарела (поддерживаются версии %d-%d), пересоберите словарь",
+			ErrDictVersion, version, minDictVersion, dictVersion)
 	}
-
-	// 3. Создать или перезаписать выходной файл.
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("ошибка создания выходного файла %s: %w", outputPath, err)
+	var legacy legacyHeader
+	size := binary.Size(legacy)
+	if len(data) < size {
+		return header, 0, fmt.Errorf("%w: файл слишком мал для заголовка", ErrDictCorrupted)
 	}
-	defer outFile.Close() // Закрыть выходной файл в конце
-
-	// 4. Скопировать содержимое каждой части в выходной файл.
-	for _, partPath := range partFiles {
-		inFile, err := os.Open(partPath)
-		if err != nil {
-			return fmt.Errorf("ошибка открытия части файла %s: %w", partPath, err)
-		}
+	if err := binary.Read(bytes.NewReader(data[:size]), binary.LittleEndian, &legacy); err != nil {
+		return header, 0, fmt.Errorf("%w: ошибка чтения заголовка: %w", ErrDictCorrupted, err)
+	}
+	header = Header{
+		Magic:                 legacy.Magic,
+		Version:               version,
+		ComplexDataOffset:     legacy.ComplexDataOffset,
+		ComplexDataLength:     legacy.ComplexDataLength,
+		NodesOffset:           legacy.NodesOffset,
+		NodesCount:            legacy.NodesCount,
+		EdgesOffset:           legacy.EdgesOffset,
+		EdgesCount:            legacy.EdgesCount,
+		PayloadsOffset:        legacy.PayloadsOffset,
+		PayloadsCount:         legacy.PayloadsCount,
+		PredictNodesOffset:    legacy.PredictNodesOffset,
+		PredictNodesCount:     legacy.PredictNodesCount,
+		PredictEdgesOffset:    legacy.PredictEdgesOffset,
+		PredictEdgesCount:     legacy.PredictEdgesCount,
+		PredictPayloadsOffset: legacy.PredictPayloadsOffset,
+		PredictPayloadsCount:  legacy.PredictPayloadsCount,
+	}
+	return header, int64(size), nil
+}
 
-		_, err = io.Copy(outFile, inFile)
-		inFile.Close() // Важно закрыть каждую часть после копирования
-		if err != nil {
-			return fmt.Errorf("ошибка копирования данных из %s в %s: %w", partPath, outputPath, err)
-		}
+// section возвращает участок data длиной length от смещения offset или ErrDictCorrupted,
+// если заголовок указывает за пределы файла.
+func section(data []byte, offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 0 || offset > int64(len(data)) || length > int64(len(data))-offset {
+		return nil, fmt.Errorf("%w: раздел [%d, %d) за пределами файла размером %d байт",
+			ErrDictCorrupted, offset, offset+length, len(data))
 	}
+	return data[offset : offset+length], nil
+}
 
-	fmt.Printf("Все части успешно объединены в файл: %s\n", outputPath)
-	return nil
+// sectionSlice возвращает массив из count записей типа T от смещения offset (см. decodeLE).
+// Прежние форматы записывали массивы в раскладке платформы сборки; на little-endian
+// платформах она совпадает с текущей.
+func sectionSlice[T any](data []byte, offset, count int64) ([]T, error) {
+	var t T
+	size := int64(binary.Size(t))
+	if count < 0 || count > int64(len(data))/size {
+		return nil, fmt.Errorf("%w: неверное число элементов массива: %d", ErrDictCorrupted, count)
+	}
+	b, err := section(data, offset, count*size)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLE[T](b), nil
 }
 
-// bytesToSlice - "небезопасная" функция, которая создает заголовок среза,
-// указывающий на область байт, без копирования самих данных.
+// bytesToSlice создает срез элементов T поверх области байт без копирования.
+// Длина b должна быть кратна размеру T. Если область не выровнена под T (например,
+// словарь встроен в исполняемый файл или передан срезом с произвольным началом),
+// данные копируются в выровненный срез: на строгих архитектурах невыровненное
+// чтение приводит к аварийному завершению.
 func bytesToSlice[T any](b []byte) []T {
-	if len(b) == 0 {
-		return nil
-	}
 	var t T
 	size := int(unsafe.Sizeof(t))
-	header := reflect.SliceHeader{Data: uintptr(unsafe.Pointer(&b[0])), Len: len(b) / size, Cap: len(b) / size}
-	return *(*[]T)(unsafe.Pointer(&header))
+	if len(b) == 0 || size == 0 {
+		return nil
+	}
+	n := len(b) / size
+	ptr := unsafe.Pointer(unsafe.SliceData(b))
+	if uintptr(ptr)%unsafe.Alignof(t) == 0 {
+		return unsafe.Slice((*T)(ptr), n)
+	}
+	aligned := make([]T, n)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(aligned))), n*size), b)
+	return aligned
 }
 
 // Analyze - главный публичный метод. Принимает слово и возвращает полный его разбор.
-// Работает для словарных и несловарных слов
+// Работает для словарных и несловарных слов: слово разбирает первое подходящее звено
+// цепочки (словарь, числа, латиница, дефис, приставки, предсказатель; см. WithUnits),
+// оно же генерирует словоформы. Если слово не разобрало ни одно звено, возвращает nil, nil.
+// С WithCache разборы и формы разделяются с другими вызовами и не должны изменяться.
 func (a *MorphAnalyzer) Analyze(word string) ([]*Parsed, []*Parsed) {
-	// Сначала пытаемся найти слово в словаре.
-	parses := a.Parse(word)
-	if len(parses) > 0 {
-		// Если нашли, то и все его формы тоже есть в словаре.
-		forms := a.Inflect(word)
-		return parses, forms
-	}
-	// Если слово не найдено, пытаемся его предсказать.
-	predictedParses := a.ParsePredicted(word)
-	if predictedParses == nil {
-		// Если и предсказать не удалось, возвращаем nil.
+	parses, forms := a.analyze(word)
+	return a.localize(parses), a.localize(forms)
+}
+
+// analyze - Analyze без перевода граммем (см. WithLang).
+func (a *MorphAnalyzer) analyze(word string) ([]*Parsed, []*Parsed) {
+	if !a.observed() {
+		return a.analyzeWord(word)
+	}
+	started := time.Now()
+	parses, forms := a.analyzeWord(word)
+	a.observe(OpAnalyze, word, parses, started)
+	return parses, forms
+}
+
+// analyzeWord - analyze без статистики и хуков: для звеньев, разбирающих части слова.
+func (a *MorphAnalyzer) analyzeWord(word string) ([]*Parsed, []*Parsed) {
+	word, ok := a.cleanWord(word)
+	if !ok {
+		return nil, nil
+	}
+	unit, parses := a.parseWithUnits(word)
+	if unit == nil {
 		return nil, nil
 	}
-	// Если предсказание удалось, генерируем для него все словоформы.
-	predictedForms := a.Predict(word, predictedParses[0].Lemma)
-	return predictedParses, predictedForms
+	return parses, unit.Inflect(a, word, parses)
+}
+
+// Lemmatize возвращает уникальные леммы слова в порядке разборов.
+// Для несловарных слов возвращается предсказанная лемма. Словоформы не генерируются,
+// поэтому метод значительно дешевле Analyze, когда нужны только леммы.
+func (a *MorphAnalyzer) Lemmatize(word string) []string {
+	var lemmas []string
+	seen := make(map[string]struct{})
+	for _, p := range a.parseOrPredict(word) {
+		if _, ok := seen[p.Lemma]; !ok {
+			seen[p.Lemma] = struct{}{}
+			lemmas = append(lemmas, p.Lemma)
+		}
+	}
+	return lemmas
+}
+
+// parseOrPredict возвращает разборы слова от первого разобравшего его звена цепочки
+// без генерации словоформ.
+func (a *MorphAnalyzer) parseOrPredict(word string) []*Parsed {
+	if !a.observed() {
+		_, parses := a.parseWithUnits(word)
+		return parses
+	}
+	started := time.Now()
+	_, parses := a.parseWithUnits(word)
+	a.observe(OpAnalyze, word, parses, started)
+	return parses
 }
 
 // Inflect генерирует все словоформы для словарного слова.
+// С WithCache формы общие для всех вызывающих: срез можно менять, сами формы - нет.
 func (a *MorphAnalyzer) Inflect(word string) []*Parsed {
+	return a.localize(a.inflectDict(word))
+}
+
+// inflectDict - Inflect без перевода граммем: через перехватчики и кэш.
+func (a *MorphAnalyzer) inflectDict(word string) []*Parsed {
+	word, ok := a.cleanWord(word)
+	if !ok {
+		return nil
+	}
+	if a.inflectChain != nil {
+		return a.inflectChain(word)
+	}
+	return a.cached(cacheOpInflect, word, a.inflect)
+}
+
+// inflect - Inflect без кэша.
+func (a *MorphAnalyzer) inflect(word string) []*Parsed {
 	// Находим все возможные разборы для введенного слова.
-	initialParses := a.Parse(word)
+	initialParses := a.parseDict(word)
 	if len(initialParses) == 0 {
 		return nil
 	}
@@ -394,10 +724,16 @@ func (a *MorphAnalyzer) Inflect(word string) []*Parsed {
 
 	// Для этого нам нужно снова найти payload'ы, чтобы связать разбор с ID парадигмы.
 	lowerWord := strings.ToLower(word)
+	if a.lookupPayloads(lowerWord) == nil {
+		// Parse разобрал исправленное слово: склоняем его же.
+		if corrected, ok := a.correctedWord(word); ok {
+			lowerWord = strings.ToLower(corrected)
+		}
+	}
 	currentNodeIndex := uint32(0)
 	pathFound := true
 	for _, char := range lowerWord {
-		childNodeIndex, found := a.findChildGeneral(currentNodeIndex, char, a.nodes, a.edges)
+		childNodeIndex, found := a.findChildGeneral(currentNodeIndex, char, a.nodes, a.edges, a.rootIndex)
 		if !found {
 			pathFound = false
 			break
@@ -409,29 +745,26 @@ func (a *MorphAnalyzer) Inflect(word string) []*Parsed {
 		node := a.nodes[currentNodeIndex]
 		payloadStart, payloadEnd := node.PayloadIdx, node.PayloadIdx+uint32(node.PayloadLen)
 		for _, info := range a.payloads[payloadStart:payloadEnd] {
-			paradigmsToProcess[info.ParadigmID] = a.LemmaPool[info.LemmaID]
+			paradigmsToProcess[info.ParadigmID] = a.lemmas.at(info.LemmaID)
 		}
 	}
 
 	// Генерируем все формы для каждой найденной уникальной парадигмы.
 	finalResults := make(map[string]*Parsed) // Используем карту для уникальности результатов.
 
-	for pID, lemma := range paradigmsToProcess {
-		// Получаем ВСЕ основы (stems) для данной парадигмы.
-		paradigmInfoSlice, ok := a.paradigms[pID]
-		if !ok {
-			continue
-		}
-
-		// Для КАЖДОЙ основы запускаем генерацию.
-		for _, pInfo := range paradigmInfoSlice {
+	// Парадигмы обходятся по возрастанию ID: форма, общая для нескольких лексем ("стали"),
+	// всегда достается одной и той же лексеме, и результат не зависит от порядка обхода карты.
+	for _, pID := range slices.Sorted(maps.Keys(paradigmsToProcess)) {
+		lemma := paradigmsToProcess[pID]
+		// Для КАЖДОЙ основы (stem) парадигмы запускаем генерацию.
+		for stem, nodeID := range a.paradigms.stemsOf(pID) {
 			generatedForms := make(map[string]uint32)
-			a.dfsGenerate(pInfo.NodeID, []rune(pInfo.Stem), pID, generatedForms)
+			a.dfsGenerate(nodeID, stem, pID, generatedForms)
 
 			for form, tagsID := range generatedForms {
 				// Добавляем в итоговую карту.
 				if _, exists := finalResults[form]; !exists {
-					finalResults[form] = newParsed(form, lemma, a.tagsPool[tagsID])
+					finalResults[form] = newParsed(form, lemma, a.tags.at(tagsID))
 				}
 			}
 		}
@@ -447,125 +780,300 @@ func (a *MorphAnalyzer) Inflect(word string) []*Parsed {
 		finalList = append(finalList, p)
 	}
 
-	sort.Slice(finalList, func(i, j int) bool {
-		return finalList[i].Word < finalList[j].Word
-	})
-
+	a.sortForms(finalList)
 	return finalList
 }
 
 // Parse ищет слово в основном словаре (DAWG).
+// С WithCache разборы общие для всех вызывающих и не должны изменяться (см. WithCache).
 func (a *MorphAnalyzer) Parse(word string) []*Parsed {
+	if !a.observed() {
+		return a.localize(a.parseDict(word))
+	}
+	started := time.Now()
+	parses := a.parseDict(word)
+	a.observe(OpParse, word, parses, started)
+	return a.localize(parses)
+}
+
+// ParseInto - вариант Parse для горячих путей: разборы словарного слова записываются
+// значениями в buf[:0] (при нехватке емкости буфер растет) и возвращаются.
+// Если емкости buf хватает, слово в нижнем регистре, а граммемы не переводятся (см. WithLang),
+// метод не выделяет память: строки ссылаются на пулы словаря, а карты OtherTags элементов
+// buf переиспользуются. Поэтому разборы действительны до следующего вызова с тем же буфером.
+// Перехватчики, кэш, исправление слов (WithMixedScriptRepair, WithOCRTolerance),
+// нормализация входа (NFC, WithMaxWordLen) и WithAccusativeResolution не применяются;
+// для несловарного слова возвращается buf[:0].
+func (a *MorphAnalyzer) ParseInto(word string, buf []Parsed) []Parsed {
+	buf = buf[:0]
+	for _, info := range a.lookupPayloads(strings.ToLower(word)) {
+		if len(buf) < cap(buf) {
+			buf = buf[:len(buf)+1]
+		} else {
+			buf = append(buf, Parsed{})
+		}
+		p := &buf[len(buf)-1]
+		p.fill(word, a.lemmas.at(info.LemmaID), a.tags.at(info.TagsID))
+		p.Method, p.Confidence = UnitDictionary, 1
+		p.mask, p.masked = a.tagMasks[info.TagsID], true
+		if a.lang != LangRussian {
+			*p = *p.Localize(a.lang)
+		}
+	}
+	return buf
+}
+
+// parseDict - Parse без перевода граммем: через перехватчики и кэш.
+func (a *MorphAnalyzer) parseDict(word string) []*Parsed {
+	word, ok := a.cleanWord(word)
+	if !ok {
+		return nil
+	}
+	if a.parseChain != nil {
+		return a.parseChain(word)
+	}
+	return a.cached(cacheOpParse, word, a.parse)
+}
+
+// parse - Parse без кэша.
+func (a *MorphAnalyzer) parse(word string) []*Parsed {
 	lowerWord := strings.ToLower(word)
 	currentNodeIndex := uint32(0)
 
 	// Идем по графу символ за символом.
 	for _, char := range lowerWord {
-		childNodeIndex, found := a.findChildGeneral(currentNodeIndex, char, a.nodes, a.edges)
+		childNodeIndex, found := a.findChildGeneral(currentNodeIndex, char, a.nodes, a.edges, a.rootIndex)
 		if !found {
-			return nil // Если пути нет, слова в словаре нет.
+			return a.parseNotFound(word) // Если пути нет, слова в словаре нет.
 		}
 		currentNodeIndex = childNodeIndex
 	}
 
 	node := a.nodes[currentNodeIndex]
 	if !node.IsFinal {
-		return nil // Дошли до конца слова, но узел не является финальным.
+		return a.parseNotFound(word) // Дошли до конца слова, но узел не является финальным.
 	}
 
 	// Если узел финальный, собираем все варианты разбора, используя его payload.
 	var results []*Parsed
 	payloadStart, payloadEnd := node.PayloadIdx, node.PayloadIdx+uint32(node.PayloadLen)
 	for _, info := range a.payloads[payloadStart:payloadEnd] {
-		results = append(results, newParsed(word, a.LemmaPool[info.LemmaID], a.tagsPool[info.TagsID]))
+		p := a.resultParsed(word, a.lemmas.at(info.LemmaID), info.TagsID)
+		p.Method, p.Confidence = UnitDictionary, 1
+		results = append(results, p)
+	}
+	if a.resolveAccusative {
+		results = ResolveAccusative(results)
+	}
+	return results
+}
+
+// resultParsed создает разбор словаря или предсказателя с набором тегов tagsID
+// и его маской граммем: полный или, с опцией WithLazyDetails, без раскладки граммем
+// по категориям.
+func (a *MorphAnalyzer) resultParsed(word, lemma string, tagsID uint32) *Parsed {
+	var p *Parsed
+	if a.lazyDetails {
+		p = newLazyParsed(word, lemma, a.tags.at(tagsID))
+	} else {
+		p = newParsed(word, lemma, a.tags.at(tagsID))
+	}
+	p.mask, p.masked = a.tagMasks[tagsID], true
+	return p
+}
+
+// parseNotFound вызывается, когда слова нет в словаре: разбирает исправленное слово
+// (см. correctedWord), сохраняя в разборах исходное написание, или возвращает nil.
+func (a *MorphAnalyzer) parseNotFound(word string) []*Parsed {
+	corrected, ok := a.correctedWord(word)
+	if !ok {
+		return nil
+	}
+	results := a.parse(corrected)
+	for _, p := range results {
+		p.Word = word
 	}
 	return results
 }
 
+// correctedWord исправляет несловарное слово способами, включенными опциями:
+// смешанный алфавит (WithMixedScriptRepair), затем ошибки OCR (WithOCRTolerance).
+// Возвращает словарное слово и true или word и false.
+func (a *MorphAnalyzer) correctedWord(word string) (string, bool) {
+	if a.repairMixedScript && IsMixedScript(word) {
+		if repaired, ok := a.RepairMixedScript(word); ok {
+			return repaired, true
+		}
+	}
+	if a.ocrTolerance {
+		if matches := a.ParseOCR(word); len(matches) > 0 {
+			return matches[0].Word, true
+		}
+	}
+	return word, false
+}
+
 // ParsePredicted пытается предсказать разбор для несловарного слова.
+// Разборы с WithCache закэшированы и не должны изменяться (см. WithCache).
 func (a *MorphAnalyzer) ParsePredicted(word string) []*Parsed {
-	lowerWord := strings.ToLower(word)
-	best := a.findBestPrediction(lowerWord)
-	if best == nil {
+	if !a.observed() {
+		return a.localize(a.parsePredictedCached(word))
+	}
+	started := time.Now()
+	parses := a.parsePredictedCached(word)
+	a.observe(OpPredict, word, parses, started)
+	return a.localize(parses)
+}
+
+// parsePredictedCached - ParsePredicted без перевода граммем.
+func (a *MorphAnalyzer) parsePredictedCached(word string) []*Parsed {
+	word, ok := a.cleanWord(word)
+	if !ok {
 		return nil
 	}
+	return a.cached(cacheOpPredict, word, a.parsePredicted)
+}
 
-	var predictedLemma string
+// parsePredicted - ParsePredicted без кэша.
+func (a *MorphAnalyzer) parsePredicted(word string) []*Parsed {
+	// Правило предсказания берется одно лучшее, а короткое слово получает все разборы
+	// по закрытому списку (см. parseShortWord).
+	n := 1
+	if isShortWord(strings.ToLower(word)) {
+		n = 0
+	}
+	var results []*Parsed
+	for _, p := range a.predictions(word, n) {
+		results = append(results, p.Parse)
+	}
+	return results
+}
 
-	// Получаем все формы и лемму для парадигмы-образца.
-	allFormsOfTemplate := a.getFormsByParadigmID(best.ParadigmID)
-	lemmaID, ok := a.paradigmToLemmaID[best.ParadigmID]
+// Prediction - вариант разбора несловарного слова с оценкой правдоподобия.
+type Prediction struct {
+	Parse *Parsed // Предсказанный разбор.
+	Score float64 // Оценка от 0 до 1; больше - правдоподобнее.
+}
 
-	// Проверяем, что все данные на месте.
-	if !ok || len(allFormsOfTemplate) == 0 || int(best.FormIdx) >= len(allFormsOfTemplate) {
-		// Fallback: если что-то пошло не так, лемма - само слово.
-		predictedLemma = lowerWord
-	} else {
-		// Логика "пропорциональной замены" для определения леммы.
-		wordOfTemplate := allFormsOfTemplate[int(best.FormIdx)]
-		lemmaOfTemplate := a.LemmaPool[lemmaID]
+// ParsePredictedN возвращает до n вариантов разбора несловарного слова по убыванию оценки
+// (n <= 0 - все варианты). Первый вариант совпадает с разбором ParsePredicted.
+// Оценка складывается из длины совпавшего суффикса правила (каждый символ - 0,2)
+// и доли частоты правила среди правил с тем же суффиксом, поэтому правило с более длинным
+// суффиксом всегда оценивается выше. Варианты с одинаковыми леммой и тегами не повторяются.
+// Слова из одной-двух букв разбираются по закрытому списку с оценкой 1.
+func (a *MorphAnalyzer) ParsePredictedN(word string, n int) []Prediction {
+	result := a.predictions(word, n)
+	for i := range result {
+		result[i].Parse = a.localizeOne(result[i].Parse)
+	}
+	return result
+}
 
-		if len([]rune(wordOfTemplate)) < best.SuffixLen {
-			// Fallback: слово-образец короче суффикса.
-			predictedLemma = lowerWord
-		} else {
-			commonSuffix := string([]rune(lowerWord)[len([]rune(lowerWord))-best.SuffixLen:])
-			if !strings.HasSuffix(wordOfTemplate, commonSuffix) {
-				// Fallback: аналогия неполная.
-				predictedLemma = lowerWord
-			} else {
-				// Все проверки пройдены, вычисления безопасны.
-				oovPrefix := strings.TrimSuffix(lowerWord, commonSuffix)
-				templateWordPrefix := strings.TrimSuffix(wordOfTemplate, commonSuffix)
-				if !strings.HasPrefix(lemmaOfTemplate, templateWordPrefix) {
-					// Сложный случай (супплетивизм).
-					predictedLemma = lowerWord
-				} else {
-					templateLemmaEnding := strings.TrimPrefix(lemmaOfTemplate, templateWordPrefix)
-					predictedLemma = oovPrefix + templateLemmaEnding
-				}
+// predictions - ParsePredictedN без перевода граммем. Оценка каждого варианта
+// записывается и в Confidence разбора.
+func (a *MorphAnalyzer) predictions(word string, n int) []Prediction {
+	word, ok := a.cleanWord(word)
+	if a.dictionaryOnly || !ok {
+		return nil
+	}
+	lowerWord := strings.ToLower(word)
+	var result []Prediction
+	if isShortWord(lowerWord) {
+		for _, p := range parseShortWord(word, lowerWord) {
+			result = append(result, Prediction{Parse: p, Score: 1})
+		}
+	} else {
+		candidates := a.findPredictions(lowerWord)
+		totals := make(map[int]float64)
+		for _, c := range candidates {
+			totals[c.SuffixLen] += float64(c.Frequency)
+		}
+		seen := make(map[string]struct{})
+		for i := range candidates {
+			if n > 0 && len(result) == n {
+				break
+			}
+			c := &candidates[i]
+			// Теги берем напрямую из правила предсказания.
+			p := a.resultParsed(word, a.predictedLemma(lowerWord, c), c.TagsID)
+			key := p.Lemma + "|" + p.Tags
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			share := 1.0
+			if totals[c.SuffixLen] > 0 {
+				share = float64(c.Frequency) / totals[c.SuffixLen]
 			}
+			result = append(result, Prediction{Parse: p, Score: (float64(c.SuffixLen-1) + share) / maxPredictSuffixLen})
 		}
 	}
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	for _, p := range result {
+		p.Parse.Method, p.Parse.Confidence = UnitPredictor, p.Score
+	}
+	return result
+}
+
+// predictedLemma вычисляет лемму несловарного слова по правилу предсказания
+// пропорциональной заменой: окончание формы-образца заменяется окончанием ее леммы.
+// Если аналогия неполная, лемма - само слово.
+func (a *MorphAnalyzer) predictedLemma(lowerWord string, c *PredictionCandidate) string {
+	// Получаем все формы и лемму для парадигмы-образца.
+	allFormsOfTemplate := a.getFormsByParadigmID(c.ParadigmID)
+	lemmaID, ok := a.paradigms.lemma(c.ParadigmID)
 
-	// Теги берем напрямую из найденного правила предсказания.
-	tags := a.tagsPool[best.TagsID]
-	return []*Parsed{newParsed(word, predictedLemma, tags)}
+	// Проверяем, что все данные на месте.
+	if !ok || len(allFormsOfTemplate) == 0 || int(c.FormIdx) >= len(allFormsOfTemplate) {
+		// Fallback: если что-то пошло не так, лемма - само слово.
+		return lowerWord
+	}
+	wordOfTemplate := allFormsOfTemplate[int(c.FormIdx)]
+	lemmaOfTemplate := a.lemmas.at(lemmaID)
+
+	if len([]rune(wordOfTemplate)) < c.SuffixLen {
+		// Fallback: слово-образец короче суффикса.
+		return lowerWord
+	}
+	commonSuffix := string([]rune(lowerWord)[len([]rune(lowerWord))-c.SuffixLen:])
+	if !strings.HasSuffix(wordOfTemplate, commonSuffix) {
+		// Fallback: аналогия неполная.
+		return lowerWord
+	}
+	// Все проверки пройдены, вычисления безопасны.
+	oovPrefix := strings.TrimSuffix(lowerWord, commonSuffix)
+	templateWordPrefix := strings.TrimSuffix(wordOfTemplate, commonSuffix)
+	if !strings.HasPrefix(lemmaOfTemplate, templateWordPrefix) {
+		// Сложный случай (супплетивизм).
+		return lowerWord
+	}
+	return oovPrefix + strings.TrimPrefix(lemmaOfTemplate, templateWordPrefix)
 }
 
 // Predict генерирует все словоформы для несловарного слова.
 func (a *MorphAnalyzer) Predict(word string, lemma string) []*Parsed {
+	return a.localize(a.predict(word, lemma))
+}
+
+// predict - Predict без перевода граммем.
+func (a *MorphAnalyzer) predict(word string, lemma string) []*Parsed {
 	lowerWord := strings.ToLower(word)
 	best := a.findBestPrediction(lowerWord)
 	if best == nil {
 		return nil
 	}
 
-	// Получаем слово-образец для вычисления префиксов.
-	allFormsInParadigm := a.getFormsByParadigmID(best.ParadigmID)
-	if len(allFormsInParadigm) == 0 || int(best.FormIdx) >= len(allFormsInParadigm) {
-		return nil
-	}
-	wordOfTemplate := allFormsInParadigm[int(best.FormIdx)]
-
-	// Проверяем корректность аналогии.
-	if len([]rune(wordOfTemplate)) < best.SuffixLen {
-		return nil
-	}
-	commonSuffix := string([]rune(lowerWord)[len([]rune(lowerWord))-best.SuffixLen:])
-	if !strings.HasSuffix(wordOfTemplate, commonSuffix) {
+	inputPrefix, dictPrefix, ok := a.predictionPrefixes(lowerWord, best)
+	if !ok {
 		return nil
 	}
 
-	// Вычисляем префиксы.
-	inputPrefix := strings.TrimSuffix(lowerWord, commonSuffix)
-	dictPrefix := strings.TrimSuffix(wordOfTemplate, commonSuffix)
-
 	// Получаем все формы и теги из парадигмы-образца.
 	formsAndTags := make(map[string]uint32)
-	paradigmInfoSlice, _ := a.paradigms[best.ParadigmID]
-	for _, pInfo := range paradigmInfoSlice {
-		a.dfsGenerate(pInfo.NodeID, []rune(pInfo.Stem), best.ParadigmID, formsAndTags)
+	for stem, nodeID := range a.paradigms.stemsOf(best.ParadigmID) {
+		a.dfsGenerate(nodeID, stem, best.ParadigmID, formsAndTags)
 	}
 
 	// Генерируем новые формы, заменяя префикс.
@@ -574,24 +1082,63 @@ func (a *MorphAnalyzer) Predict(word string, lemma string) []*Parsed {
 		if strings.HasPrefix(dictForm, dictPrefix) {
 			ending := strings.TrimPrefix(dictForm, dictPrefix)
 			newForm := inputPrefix + ending
-			results = append(results, newParsed(newForm, lemma, a.tagsPool[tagsID]))
+			results = append(results, newParsed(newForm, lemma, a.tags.at(tagsID)))
 		}
 	}
 
 	if len(results) == 0 {
 		return nil
 	}
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Word < results[j].Word
-	})
+	a.sortForms(results)
 	return results
 }
 
-// findBestPrediction ищет лучшее правило предсказания для слова.
-// Пробует суффиксы длиной от 5 до 1, ищет их в DAWG предсказателя.
-// Среди всех найденных правил выбирает то, у которого самый длинный суффикс,
-// а при равенстве длин - самая высокая частота.
+// predictionPrefixes вычисляет префиксы несловарного слова и слова-образца, отличающиеся
+// при общем суффиксе правила предсказания. Форма образца с префиксом dictPrefix
+// превращается в форму несловарного слова заменой префикса на inputPrefix.
+func (a *MorphAnalyzer) predictionPrefixes(lowerWord string, best *PredictionCandidate) (string, string, bool) {
+	// Получаем слово-образец для вычисления префиксов.
+	allFormsInParadigm := a.getFormsByParadigmID(best.ParadigmID)
+	if len(allFormsInParadigm) == 0 || int(best.FormIdx) >= len(allFormsInParadigm) {
+		return "", "", false
+	}
+	wordOfTemplate := allFormsInParadigm[int(best.FormIdx)]
+
+	// Проверяем корректность аналогии.
+	if len([]rune(wordOfTemplate)) < best.SuffixLen {
+		return "", "", false
+	}
+	commonSuffix := string([]rune(lowerWord)[len([]rune(lowerWord))-best.SuffixLen:])
+	if !strings.HasSuffix(wordOfTemplate, commonSuffix) {
+		return "", "", false
+	}
+
+	// Вычисляем префиксы.
+	inputPrefix := strings.TrimSuffix(lowerWord, commonSuffix)
+	dictPrefix := strings.TrimSuffix(wordOfTemplate, commonSuffix)
+	return inputPrefix, dictPrefix, true
+}
+
+// findBestPrediction ищет лучшее правило предсказания для слова (см. findPredictions).
 func (a *MorphAnalyzer) findBestPrediction(word string) *PredictionCandidate {
+	candidates := a.findPredictions(word)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return &candidates[0]
+}
+
+// findPredictions ищет правила предсказания для слова.
+// Пробует суффиксы длиной от 5 до 1, ищет их в DAWG предсказателя.
+// Правила отсортированы по длине суффикса, а при равенстве длин - по убыванию частоты.
+func (a *MorphAnalyzer) findPredictions(word string) []PredictionCandidate {
+	if a.dictionaryOnly {
+		return nil
+	}
+	// Слова из одной-двух букв не предсказываются: правила для них случайны (см. parseShortWord).
+	if isShortWord(word) {
+		return nil
+	}
 	runes := []rune(word)
 	var candidates []PredictionCandidate
 
@@ -609,7 +1156,7 @@ func (a *MorphAnalyzer) findBestPrediction(word string) *PredictionCandidate {
 
 		// Обходим DAWG предсказателя.
 		for _, char := range suffix {
-			childNodeIndex, ok := a.findChildGeneral(currentNodeIndex, char, a.predictNodes, a.predictEdges)
+			childNodeIndex, ok := a.findChildGeneral(currentNodeIndex, char, a.predictNodes, a.predictEdges, a.predictRootIndex)
 			if !ok {
 				foundSuffix = false
 				break
@@ -645,25 +1192,19 @@ func (a *MorphAnalyzer) findBestPrediction(word string) *PredictionCandidate {
 		return candidates[i].Frequency > candidates[j].Frequency
 	})
 
-	// Возвращаем самого лучшего кандидата, который оказался на первом месте после сортировки.
-	return &candidates[0]
+	return candidates
 }
 
 // getFormsByParadigmID возвращает канонически отсортированный срез всех словоформ для данной парадигмы.
 // Сортировка важна для того, чтобы FormIdx из предсказателя всегда указывал на одно и то же слово.
 func (a *MorphAnalyzer) getFormsByParadigmID(pID uint32) []string {
-	// Находим информацию о парадигме, включая все ее возможные основы (stems).
-	paradigmInfoSlice, ok := a.paradigms[pID]
-	if !ok {
-		return nil
-	}
-
-	// Используем `dfsGenerate` для сбора всех форм и их тегов в карту resultsMap.
+	// Используем `dfsGenerate` для сбора всех форм и их тегов в карту resultsMap
+	// по всем возможным основам (stems) парадигмы.
 	// resultsMap используется как set, чтобы автоматически избавиться от дубликатов,
 	// которые могли бы возникнуть из-за разных основ (stems).
 	resultsMap := make(map[string]uint32)
-	for _, pInfo := range paradigmInfoSlice {
-		a.dfsGenerate(pInfo.NodeID, []rune(pInfo.Stem), pID, resultsMap)
+	for stem, nodeID := range a.paradigms.stemsOf(pID) {
+		a.dfsGenerate(nodeID, stem, pID, resultsMap)
 	}
 
 	if len(resultsMap) == 0 {
@@ -681,10 +1222,46 @@ func (a *MorphAnalyzer) getFormsByParadigmID(pID uint32) []string {
 	return forms
 }
 
+// lexemeForm - словоформа лексемы вместе с ID ее тегов.
+type lexemeForm struct {
+	word   string
+	tagsID uint32
+}
+
+// lexemeForms возвращает все пары (словоформа, теги) парадигмы, отсортированные по слову.
+// В отличие от getFormsByParadigmID, омонимичные формы одной лексемы ("кота" - Р.п. и В.п.)
+// сохраняются со всеми наборами тегов.
+func (a *MorphAnalyzer) lexemeForms(pID uint32) []lexemeForm {
+	seen := make(map[lexemeForm]struct{})
+	var forms []lexemeForm
+	for stem, nodeID := range a.paradigms.stemsOf(pID) {
+		a.dfsVisit(nodeID, stem, pID, func(form string, tagsID uint32) {
+			f := lexemeForm{word: form, tagsID: tagsID}
+			if _, ok := seen[f]; !ok {
+				seen[f] = struct{}{}
+				forms = append(forms, f)
+			}
+		})
+	}
+
+	sort.Slice(forms, func(i, j int) bool {
+		if forms[i].word != forms[j].word {
+			return forms[i].word < forms[j].word
+		}
+		return forms[i].tagsID < forms[j].tagsID
+	})
+	return forms
+}
+
 // findChildGeneral - универсальная функция поиска дочернего узла по символу.
 // Работает с "плоскими" представлениями узлов и ребер.
 // Использует бинарный поиск, так как ребра для каждого узла отсортированы.
-func (a *MorphAnalyzer) findChildGeneral(nodeIndex uint32, char rune, nodes []FlatNode, edges []FlatEdge) (uint32, bool) {
+// Переходы из корня при наличии таблицы root (см. LookupFirstCharIndex) берутся из нее.
+func (a *MorphAnalyzer) findChildGeneral(nodeIndex uint32, char rune, nodes []FlatNode, edges []FlatEdge, root *firstCharIndex) (uint32, bool) {
+	if nodeIndex == 0 && root != nil {
+		return root.lookup(char)
+	}
+
 	// Получаем информацию о текущем узле из глобального массива узлов.
 	// Быстрая проверка: если у узла нет исходящих ребер, то и перехода быть не может.
 	// Это очень частый случай для листовых узлов, поэтому проверка важна для производительности.
@@ -713,168 +1290,338 @@ func (a *MorphAnalyzer) findChildGeneral(nodeIndex uint32, char rune, nodes []Fl
 	return 0, false
 }
 
-// dfsGenerate рекурсивно обходит DAWG, начиная с узла `nodeIndex`,
-// и собирает все возможные словоформы, добавляя к ним префикс.
-// Использует поиск в глубину (Depth-First Search).
-func (a *MorphAnalyzer) dfsGenerate(nodeIndex uint32, prefix []rune, targetID uint32, results map[string]uint32) {
-	// Создаем буфер для накапливания суффикса текущей формы.
-	suffixPart := make([]rune, 0)
+// dfsGenerate обходит DAWG, начиная с узла `nodeIndex`,
+// и собирает все возможные словоформы, добавляя к ним основу stem.
+// Если у формы несколько наборов тегов в парадигме, в карте остается последний.
+func (a *MorphAnalyzer) dfsGenerate(nodeIndex uint32, stem string, targetID uint32, results map[string]uint32) {
+	a.dfsVisit(nodeIndex, stem, targetID, func(form string, tagsID uint32) {
+		results[form] = tagsID
+	})
+}
 
-	// Объявляем рекурсивную функцию `findWord`, чтобы она могла ссылаться сама на себя.
-	var findWord func(uint32, []rune)
+// dfsFrame - узел на пути обхода dfsVisit: еще не пройденные ребра [next, end).
+type dfsFrame struct {
+	next, end uint32
+}
+
+// dfsVisit обходит DAWG в глубину (Depth-First Search), начиная с узла `nodeIndex`,
+// и вызывает visit для каждой пары (словоформа, ID тегов) целевой парадигмы в том же
+// порядке, что и рекурсивный обход. Обход итеративный: путь хранится в явном стеке,
+// а словоформа собирается в одном буфере, поэтому память выделяется только под строки
+// словоформ (и под буферы, если путь длиннее их начальной емкости).
+func (a *MorphAnalyzer) dfsVisit(nodeIndex uint32, stem string, targetID uint32, visit func(form string, tagsID uint32)) {
+	// Буфер словоформы: основа и символы ребер на пути от узла nodeIndex.
+	var wordBuf [64]rune
+	word := wordBuf[:0]
+	for _, r := range stem {
+		word = append(word, r)
+	}
+	// Стек обхода: по кадру на каждый узел пути, начиная с nodeIndex.
+	var stackBuf [32]dfsFrame
+	stack := stackBuf[:0]
 
-	findWord = func(currNodeIdx uint32, currentSuffix []rune) {
-		// Получаем текущий узел.
-		currNode := a.nodes[currNodeIdx]
-		//  Проверяем, является ли узел финальным для какого-либо слова.
+	for node := nodeIndex; ; {
+		// Входим в узел: если он финальный, отдаем словоформы целевой парадигмы.
+		currNode := a.nodes[node]
 		if currNode.IsFinal {
-			// Если да, то извлекаем его полезную информацию (payload).
 			payloadStart, payloadEnd := currNode.PayloadIdx, currNode.PayloadIdx+uint32(currNode.PayloadLen)
 			for _, info := range a.payloads[payloadStart:payloadEnd] {
-				// Проверяем, относится ли найденная информация к нашей целевой парадигме.
 				if info.ParadigmID == targetID {
-					// Если да, собираем полную форму (основа + найденный суффикс)
-					// и записываем ее в карту результатов вместе с ID ее тегов.
-					form := string(append(prefix, currentSuffix...))
-					results[form] = info.TagsID
+					visit(string(word), info.TagsID)
 				}
 			}
 		}
+		stack = append(stack, dfsFrame{next: currNode.EdgesIdx, end: currNode.EdgesIdx + uint32(currNode.EdgesLen)})
 
-		// Рекурсивно переходим ко всем дочерним узлам.
-		edgesStart, edgesEnd := currNode.EdgesIdx, currNode.EdgesIdx+uint32(currNode.EdgesLen)
-		for _, edge := range a.edges[edgesStart:edgesEnd] {
-			// При переходе к дочернему узлу мы добавляем символ с ребра к нашему суффиксу.
-			findWord(edge.NodeID, append(currentSuffix, edge.Char))
+		// Поднимаемся из узлов, все ребра которых пройдены, убирая их символы из словоформы.
+		for len(stack) > 0 && stack[len(stack)-1].next == stack[len(stack)-1].end {
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				word = word[:len(word)-1]
+			}
+		}
+		if len(stack) == 0 {
+			return
 		}
-	}
 
-	// Запускаем рекурсивный поиск с начального узла и пустым суффиксом.
-	findWord(nodeIndex, suffixPart)
+		// Спускаемся по следующему ребру верхнего узла.
+		top := &stack[len(stack)-1]
+		edge := a.edges[top.next]
+		top.next++
+		word = append(word, edge.Char)
+		node = edge.NodeID
+	}
 }
 
 // ParseList анализирует срез слов в конкурентном режиме, используя пул воркеров.
-func (a *MorphAnalyzer) ParseList(words []string) []*Parsed {
-	const chunkSize = 1000
-	numWorkers := runtime.NumCPU()
+// Результат отсортирован по слову; порядок, число воркеров и удаление повторов
+// настраиваются опциями (см. BatchOption).
+func (a *MorphAnalyzer) ParseList(words []string, opts ...BatchOption) []*Parsed {
+	result, _ := a.ParseListCtx(context.Background(), words, opts...)
+	return result
+}
 
-	// Канал для отправки "пакетов" (чанков) в воркеры.
-	chunksCh := make(chan []string, numWorkers)
-	// Канал для сбора результатов от воркеров.
-	resultCh := make(chan []*Parsed, numWorkers)
+// ParseListCtx - вариант ParseList с поддержкой отмены через контекст.
+// При отмене возвращает nil и ошибку контекста.
+func (a *MorphAnalyzer) ParseListCtx(ctx context.Context, words []string, opts ...BatchOption) ([]*Parsed, error) {
+	done := a.startBatch(ctx, OpParseList, len(words))
+	result, err := a.processList(ctx, words, func(word string) []*Parsed {
+		parses, _ := a.analyze(word)
+		return parses
+	}, sortByWord, opts)
+	done(len(result), err)
+	return a.localize(result), err
+}
 
-	var wg sync.WaitGroup
+// InflectList анализирует срез слов, возвращает срез всех словоформ.
+// Весь результат удерживается в памяти; для длинных списков используйте InflectListFunc.
+// Настраивается теми же опциями, что и ParseList.
+func (a *MorphAnalyzer) InflectList(words []string, opts ...BatchOption) []*Parsed {
+	result, _ := a.InflectListCtx(context.Background(), words, opts...)
+	return result
+}
+
+// InflectListCtx - вариант InflectList с поддержкой отмены через контекст.
+// При отмене возвращает nil и ошибку контекста.
+func (a *MorphAnalyzer) InflectListCtx(ctx context.Context, words []string, opts ...BatchOption) ([]*Parsed, error) {
+	done := a.startBatch(ctx, OpInflectList, len(words))
+	result, err := a.processList(ctx, words, func(word string) []*Parsed {
+		_, forms := a.analyze(word)
+		return forms
+	}, a.sortForms, opts)
+	done(len(result), err)
+	return a.localize(result), err
+}
+
+// InflectEach генерирует словоформы слов по одному и передает их в fn вместе с исходным
+// словом в порядке words (для неразобранного слова forms равен nil). Результат слова
+// не удерживается после возврата из fn, поэтому память не зависит от длины списка.
+// Слова обрабатываются последовательно в вызывающей горутине; для параллельной
+// генерации порциями используйте InflectListFunc. Ошибка fn прекращает обработку
+// и возвращается из метода.
+func (a *MorphAnalyzer) InflectEach(words []string, fn func(word string, forms []*Parsed) error) error {
+	for _, word := range words {
+		_, forms := a.analyze(word)
+		if err := fn(word, a.localize(forms)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processList обрабатывает срез слов пулом воркеров (см. BatchOption), применяя process
+// к каждому слову, и возвращает объединенный результат в порядке слов или, без PreserveOrder,
+// упорядоченный функцией sortResult. Отмена контекста проверяется перед каждым словом,
+// поэтому долгие пакеты прерываются быстро.
+func (a *MorphAnalyzer) processList(ctx context.Context, words []string, process func(word string) []*Parsed, sortResult func([]*Parsed), opts []BatchOption) ([]*Parsed, error) {
+	o := newBatchOptions(opts)
+
+	// Каждый воркер пишет результат пакета в свой элемент chunks, поэтому порядок
+	// пакетов восстанавливается без сортировки.
+	chunks := make([][]*Parsed, (len(words)+o.chunkSize-1)/o.chunkSize)
+	chunksCh := make(chan int, o.workers)
 
-	// Запускаем воркеры
-	wg.Add(numWorkers)
-	for i := 0; i < numWorkers; i++ {
+	var wg sync.WaitGroup
+	wg.Add(o.workers)
+	for i := 0; i < o.workers; i++ {
 		go func() {
 			defer wg.Done()
-			for chunk := range chunksCh {
+			for c := range chunksCh {
+				chunk := words[c*o.chunkSize : min((c+1)*o.chunkSize, len(words))]
 				parsedChunk := make([]*Parsed, 0, len(chunk))
 				for _, word := range chunk {
-					parses, _ := a.Analyze(word)
-					if parses != nil {
-						parsedChunk = append(parsedChunk, parses...)
+					if ctx.Err() != nil {
+						break
 					}
+					parsedChunk = append(parsedChunk, process(word)...)
 				}
-				resultCh <- parsedChunk
+				chunks[c] = parsedChunk
 			}
 		}()
 	}
 
-	// Запускаем диспетчера, который нарезает `words` на чанки и отправляет их в `chunksCh`.
-	go func() {
-		for i := 0; i < len(words); i += chunkSize {
-			end := i + chunkSize
-			if end > len(words) {
-				end = len(words)
-			}
-			chunksCh <- words[i:end]
+	// Раздаем номера пакетов, пока не отменен контекст.
+dispatch:
+	for c := range chunks {
+		select {
+		case chunksCh <- c:
+		case <-ctx.Done():
+			break dispatch
 		}
-		close(chunksCh) // Закрываем канал, чтобы воркеры завершили работу.
-	}()
-
-	// Запускаем "сборщика", который дождется всех воркеров и закроет канал результатов.
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+	}
+	close(chunksCh)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// Собираем все результаты в один большой срез.
-	// Предварительно выделяем память, чтобы избежать лишних аллокаций.
 	allParsed := make([]*Parsed, 0, len(words))
-	for result := range resultCh {
-		allParsed = append(allParsed, result...)
+	for _, chunk := range chunks {
+		allParsed = append(allParsed, chunk...)
 	}
+	if !o.preserveOrder {
+		// Финальная сортировка для консистентного результата.
+		sortResult(allParsed)
+	}
+	if o.dedupe {
+		allParsed = dedupeParsed(allParsed)
+	}
+	return allParsed, nil
+}
 
-	// Финальная сортировка для консистентного результата.
-	sort.Slice(allParsed, func(i, j int) bool {
-		return allParsed[i].Word < allParsed[j].Word
-	})
+// InflectListTo ставит все слова среза в одну и ту же грамматическую форму
+// (например, все в родительный падеж множественного числа: target = {"Родительный", "Множественное число"}).
+// Результат выровнен по индексам с words: для слова без подходящей формы элемент равен nil.
+// Слова обрабатываются параллельно, без генерации и сортировки полных лексем;
+// число воркеров и размер пакета задаются опциями BatchWorkers и BatchChunkSize.
+func (a *MorphAnalyzer) InflectListTo(words []string, target []string, opts ...BatchOption) []*Parsed {
+	// Граммемы target могут быть заданы на любом языке (см. WithLang), словарь хранит русские.
+	canonicalTarget := make([]string, len(target))
+	for i, g := range target {
+		canonicalTarget[i] = canonicalGrammeme(g)
+	}
 
-	return allParsed
+	done := a.startBatch(context.Background(), OpInflectListTo, len(words))
+	results := make([]*Parsed, len(words))
+	processIndexed(len(words), newBatchOptions(opts), func(j int) {
+		results[j] = a.localizeOne(a.inflectTo(words[j], canonicalTarget))
+	})
+	found := 0
+	for _, p := range results {
+		if p != nil {
+			found++
+		}
+	}
+	done(found, nil)
+	return results
 }
 
-// InflectList анализирует срез слов, возвращает срез всех словоформ.
-func (a *MorphAnalyzer) InflectList(words []string) []*Parsed {
-	const chunkSize = 1000 // Размер одного "пакета" для обработки воркером.
-	numWorkers := runtime.NumCPU()
+// ParseListGrouped разбирает срез слов параллельно, как ParseList, но не объединяет результаты:
+// элемент i содержит разборы words[i] (nil, если слово не разобрано), поэтому порядок
+// входных слов и принадлежность разборов сохраняются. Число воркеров и размер пакета
+// задаются опциями BatchWorkers и BatchChunkSize.
+func (a *MorphAnalyzer) ParseListGrouped(words []string, opts ...BatchOption) [][]*Parsed {
+	done := a.startBatch(context.Background(), OpParseListGrouped, len(words))
+	results := make([][]*Parsed, len(words))
+	processIndexed(len(words), newBatchOptions(opts), func(j int) {
+		parses, _ := a.analyze(words[j])
+		results[j] = a.localize(parses)
+	})
+	parsed := 0
+	for _, parses := range results {
+		if len(parses) > 0 {
+			parsed++
+		}
+	}
+	done(parsed, nil)
+	return results
+}
 
-	// Канал для отправки "пакетов" (чанков) в воркеры.
-	chunksCh := make(chan []string, numWorkers)
-	// Канал для сбора результатов от воркеров.
-	resultCh := make(chan []*Parsed, numWorkers)
+// processIndexed вызывает process для индексов от 0 до n пулом воркеров и ждет завершения.
+// Индексы раздаются пакетами, поэтому process может без блокировок писать в свой элемент результата.
+func processIndexed(n int, o batchOptions, process func(i int)) {
+	// Канал для отправки границ "пакетов" в воркеры.
+	chunksCh := make(chan [2]int, o.workers)
 
 	var wg sync.WaitGroup
-
-	// Запускаем воркеры
-	wg.Add(numWorkers)
-	for i := 0; i < numWorkers; i++ {
+	wg.Add(o.workers)
+	for i := 0; i < o.workers; i++ {
 		go func() {
 			defer wg.Done()
 			for chunk := range chunksCh {
-				parsedChunk := make([]*Parsed, 0, len(chunk))
-				for _, word := range chunk {
-					_, forms := a.Analyze(word)
-					if forms != nil {
-						parsedChunk = append(parsedChunk, forms...)
-					}
+				for j := chunk[0]; j < chunk[1]; j++ {
+					process(j)
 				}
-				resultCh <- parsedChunk
 			}
 		}()
 	}
 
-	// Запускаем диспетчера, который нарезает `words` на чанки и отправляет их в `chunksCh`.
-	go func() {
-		for i := 0; i < len(words); i += chunkSize {
-			end := i + chunkSize
-			if end > len(words) {
-				end = len(words)
+	for i := 0; i < n; i += o.chunkSize {
+		chunksCh <- [2]int{i, min(i+o.chunkSize, n)}
+	}
+	close(chunksCh)
+	wg.Wait()
+}
+
+// inflectTo находит форму слова, содержащую все граммемы target.
+// Лексемы перебираются в порядке разборов; нормативные формы предпочтительнее
+// помеченных как "Устаревший" или "Разговорный".
+func (a *MorphAnalyzer) inflectTo(word string, target []string) *Parsed {
+	lowerWord := strings.ToLower(word)
+
+	payloads := a.lookupPayloads(lowerWord)
+	if payloads == nil {
+		return a.inflectPredictedTo(word, target)
+	}
+
+	var fallback *Parsed
+	checked := make(map[uint32]struct{})
+	for _, info := range payloads {
+		if _, ok := checked[info.ParadigmID]; ok {
+			continue
+		}
+		checked[info.ParadigmID] = struct{}{}
+
+		for _, f := range a.lexemeForms(info.ParadigmID) {
+			tags := a.tags.at(f.tagsID)
+			if !hasAllGrammemes(tags, target) {
+				continue
+			}
+			if !isNonNormative(tags) {
+				return newParsed(f.word, a.lemmas.at(info.LemmaID), tags)
+			}
+			if fallback == nil {
+				fallback = newParsed(f.word, a.lemmas.at(info.LemmaID), tags)
 			}
-			chunksCh <- words[i:end]
 		}
-		close(chunksCh) // Закрываем канал, чтобы воркеры завершили работу.
-	}()
+	}
+	return fallback
+}
 
-	// Запускаем "сборщика", который дождется всех воркеров и закроет канал результатов.
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+// inflectPredictedTo - вариант inflectTo для несловарного слова.
+func (a *MorphAnalyzer) inflectPredictedTo(word string, target []string) *Parsed {
+	lemma, forms := a.predictedLexeme(word)
 
-	// Собираем все результаты в один большой срез.
-	// Предварительно выделяем память, чтобы избежать лишних аллокаций.
-	allParsed := make([]*Parsed, 0, len(words))
-	for result := range resultCh {
-		allParsed = append(allParsed, result...)
+	var fallback *Parsed
+	for _, f := range forms {
+		tags := a.tags.at(f.tagsID)
+		if !hasAllGrammemes(tags, target) {
+			continue
+		}
+		p := newParsed(f.word, lemma, tags)
+		if !isNonNormative(tags) {
+			return p
+		}
+		if fallback == nil {
+			fallback = p
+		}
 	}
+	return fallback
+}
 
-	// Финальная сортировка для консистентного результата.
-	sort.Slice(allParsed, func(i, j int) bool {
-		return allParsed[i].Word < allParsed[j].Word
-	})
+// predictedLexeme строит лексему несловарного слова по парадигме-образцу предсказателя.
+// В отличие от Predict сохраняет все наборы тегов омонимичных форм.
+// Возвращает предсказанную лемму и формы, отсортированные как в lexemeForms.
+func (a *MorphAnalyzer) predictedLexeme(word string) (string, []lexemeForm) {
+	lowerWord := strings.ToLower(word)
+	best := a.findBestPrediction(lowerWord)
+	if best == nil {
+		return "", nil
+	}
+	inputPrefix, dictPrefix, ok := a.predictionPrefixes(lowerWord, best)
+	if !ok {
+		return "", nil
+	}
+	predicted := a.parsePredictedCached(word)
+	if predicted == nil {
+		return "", nil
+	}
 
-	return allParsed
+	var forms []lexemeForm
+	for _, f := range a.lexemeForms(best.ParadigmID) {
+		if strings.HasPrefix(f.word, dictPrefix) {
+			forms = append(forms, lexemeForm{word: inputPrefix + strings.TrimPrefix(f.word, dictPrefix), tagsID: f.tagsID})
+		}
+	}
+	return predicted[0].Lemma, forms
 }
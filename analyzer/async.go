@@ -0,0 +1,90 @@
+// async.go содержит фоновую загрузку словаря: сервис может сразу начать слушать порт
+// и отвечать на проверки здоровья, пока словарь отображается, распаковывается
+// и проверяется, а запросы к анализатору обслуживать после готовности.
+package analyzer
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLoading возвращается AsyncAnalyzer, пока словарь загружается.
+var ErrLoading = errors.New("словарь загружается")
+
+// AsyncAnalyzer - анализатор, загружаемый в фоне (см. LoadMorphAnalyzerAsync).
+// Методы безопасны для параллельного вызова.
+type AsyncAnalyzer struct {
+	ready    chan struct{}
+	analyzer *MorphAnalyzer
+	err      error
+}
+
+// LoadMorphAnalyzerAsync начинает загрузку анализатора в фоне так же, как LoadMorphAnalyzer,
+// и сразу возвращает управление. Окончание загрузки сообщает канал Ready,
+// ее результат - Analyzer и Err.
+func LoadMorphAnalyzerAsync(opts ...Option) *AsyncAnalyzer {
+	return loadAsync(func() (*MorphAnalyzer, error) {
+		return LoadMorphAnalyzer(opts...)
+	})
+}
+
+// LoadMorphAnalyzerFromFileAsync - LoadMorphAnalyzerAsync для файла словаря по явному пути.
+func LoadMorphAnalyzerFromFileAsync(dictPath string, opts ...Option) *AsyncAnalyzer {
+	return loadAsync(func() (*MorphAnalyzer, error) {
+		return LoadMorphAnalyzerFromFile(dictPath, opts...)
+	})
+}
+
+// loadAsync запускает load в отдельной горутине.
+func loadAsync(load func() (*MorphAnalyzer, error)) *AsyncAnalyzer {
+	l := &AsyncAnalyzer{ready: make(chan struct{})}
+	go func() {
+		defer close(l.ready)
+		l.analyzer, l.err = load()
+	}()
+	return l
+}
+
+// Ready возвращает канал, который закрывается по окончании загрузки - успешной или нет.
+func (l *AsyncAnalyzer) Ready() <-chan struct{} {
+	return l.ready
+}
+
+// Err возвращает ErrLoading, пока словарь загружается, ошибку загрузки, если она
+// не удалась, и nil, если анализатор готов. Подходит для проверки здоровья сервиса.
+func (l *AsyncAnalyzer) Err() error {
+	select {
+	case <-l.ready:
+		return l.err
+	default:
+		return ErrLoading
+	}
+}
+
+// Analyzer возвращает анализатор, не дожидаясь окончания загрузки: пока она идет,
+// возвращается ErrLoading, а если не удалась - ее ошибка.
+func (l *AsyncAnalyzer) Analyzer() (*MorphAnalyzer, error) {
+	if err := l.Err(); err != nil {
+		return nil, err
+	}
+	return l.analyzer, nil
+}
+
+// Wait ждет окончания загрузки или отмены ctx и возвращает анализатор.
+func (l *AsyncAnalyzer) Wait(ctx context.Context) (*MorphAnalyzer, error) {
+	select {
+	case <-l.ready:
+		return l.analyzer, l.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close дожидается окончания загрузки и закрывает анализатор, если он загружен.
+func (l *AsyncAnalyzer) Close() error {
+	<-l.ready
+	if l.analyzer == nil {
+		return nil
+	}
+	return l.analyzer.Close()
+}
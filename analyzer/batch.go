@@ -0,0 +1,84 @@
+// batch.go содержит настройки пакетных методов (ParseList, InflectList и их вариантов):
+// число воркеров, размер пакета, порядок результата и удаление повторов. По умолчанию
+// слова обрабатываются runtime.NumCPU() воркерами пакетами по 1000 слов, а результат
+// сортируется; для задач, чувствительных к задержке или порядку, это можно изменить.
+package analyzer
+
+import (
+	"runtime"
+)
+
+// defaultBatchChunkSize - размер пакета слов, который воркер обрабатывает за раз.
+const defaultBatchChunkSize = 1000
+
+// batchOptions - настройки пакетной обработки.
+type batchOptions struct {
+	workers       int
+	chunkSize     int
+	preserveOrder bool
+	dedupe        bool
+}
+
+// BatchOption - функциональная опция для пакетных методов.
+type BatchOption func(*batchOptions)
+
+// BatchWorkers задает число воркеров (по умолчанию runtime.NumCPU()). Значение
+// меньше 1 оставляет число по умолчанию; 1 - последовательная обработка.
+func BatchWorkers(n int) BatchOption {
+	return func(o *batchOptions) {
+		if n > 0 {
+			o.workers = n
+		}
+	}
+}
+
+// BatchChunkSize задает число слов в пакете воркера (по умолчанию 1000). Маленькие
+// пакеты равномернее загружают воркеры на коротких списках, большие - дешевле на длинных.
+// Значение меньше 1 оставляет размер по умолчанию.
+func BatchChunkSize(n int) BatchOption {
+	return func(o *batchOptions) {
+		if n > 0 {
+			o.chunkSize = n
+		}
+	}
+}
+
+// PreserveOrder сохраняет порядок входных слов: результаты идут по словам в порядке
+// среза, а внутри слова - в порядке разборов (словоформ). Без опции результат сортируется.
+func PreserveOrder() BatchOption {
+	return func(o *batchOptions) {
+		o.preserveOrder = true
+	}
+}
+
+// Deduplicate удаляет повторы результатов с одинаковыми словом, леммой и тегами
+// (например, от повторяющихся входных слов), оставляя первый.
+func Deduplicate() BatchOption {
+	return func(o *batchOptions) {
+		o.dedupe = true
+	}
+}
+
+// newBatchOptions применяет опции к настройкам по умолчанию.
+func newBatchOptions(opts []BatchOption) batchOptions {
+	o := batchOptions{workers: runtime.NumCPU(), chunkSize: defaultBatchChunkSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// dedupeParsed удаляет повторы с одинаковыми словом, леммой и тегами, сохраняя порядок.
+func dedupeParsed(parses []*Parsed) []*Parsed {
+	seen := make(map[string]struct{}, len(parses))
+	result := parses[:0]
+	for _, p := range parses {
+		key := p.Word + "\x00" + p.Lemma + "\x00" + p.Tags
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, p)
+	}
+	return result
+}
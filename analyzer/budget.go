@@ -0,0 +1,170 @@
+// budget.go содержит ограничение памяти при массовой генерации словоформ.
+// InflectList собирает все формы всех слов в один срез, и на длинных списках глаголов
+// это миллионы объектов. InflectListFunc выдает формы порциями, а MemoryBudget
+// ограничивает суммарный объем порций, одновременно находящихся в памяти.
+package analyzer
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// MemoryBudget - потокобезопасный учет памяти, занятой сгенерированными словоформами.
+// Один бюджет можно разделить между несколькими анализаторами и параллельными вызовами
+// InflectListFunc: тогда лимит действует на все вызовы вместе.
+type MemoryBudget struct {
+	limit int64
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	used int64
+}
+
+// NewMemoryBudget создает бюджет с лимитом в байтах.
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	b := &MemoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Limit возвращает лимит бюджета в байтах.
+func (b *MemoryBudget) Limit() int64 {
+	return b.limit
+}
+
+// Used возвращает объем памяти, занятый порциями, которые еще не обработаны.
+func (b *MemoryBudget) Used() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// acquire резервирует n байт, ожидая освобождения памяти другими порциями.
+// Если бюджет пуст, резерв выдается даже сверх лимита, иначе порция
+// крупнее лимита никогда не была бы выдана.
+func (b *MemoryBudget) acquire(n int64) {
+	b.mu.Lock()
+	for b.used > 0 && b.used+n > b.limit {
+		b.cond.Wait()
+	}
+	b.used += n
+	b.mu.Unlock()
+}
+
+// release возвращает в бюджет n байт.
+func (b *MemoryBudget) release(n int64) {
+	b.mu.Lock()
+	b.used -= n
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// WithMemoryBudget ограничивает память, которую InflectListFunc удерживает под еще не выданные формы.
+func WithMemoryBudget(budget *MemoryBudget) Option {
+	return func(a *MorphAnalyzer) {
+		a.budget = budget
+	}
+}
+
+// parsedChunk - порция словоформ вместе с зарезервированным под нее объемом памяти.
+type parsedChunk struct {
+	forms []*Parsed
+	size  int64
+}
+
+// InflectListFunc генерирует словоформы для среза слов и передает их в emit порциями
+// (каждая порция отсортирована по слову), не накапливая весь результат в памяти.
+// Порции выдаются в порядке готовности, emit вызывается последовательно из вызывающей горутины.
+// Если анализатору задан MemoryBudget, воркеры приостанавливаются, пока emit не обработает
+// ранее выданные порции. Ошибка emit прекращает генерацию и возвращается из метода.
+func (a *MorphAnalyzer) InflectListFunc(words []string, emit func(forms []*Parsed) error) error {
+	done := a.startBatch(context.Background(), OpInflectList, len(words))
+	emitted := 0
+	err := a.inflectListFunc(words, func(forms []*Parsed) error {
+		emitted += len(forms)
+		return emit(a.localize(forms))
+	})
+	done(emitted, err)
+	return err
+}
+
+// inflectListFunc - InflectListFunc без перевода граммем (см. WithLang).
+func (a *MorphAnalyzer) inflectListFunc(words []string, emit func(forms []*Parsed) error) error {
+	const chunkSize = 1000
+	numWorkers := runtime.NumCPU()
+
+	chunksCh := make(chan []string, numWorkers)
+	resultCh := make(chan parsedChunk, numWorkers)
+	var stopped atomic.Bool
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for chunk := range chunksCh {
+				if stopped.Load() {
+					continue
+				}
+				var forms []*Parsed
+				for _, word := range chunk {
+					_, wordForms := a.analyze(word)
+					forms = append(forms, wordForms...)
+				}
+				if len(forms) == 0 {
+					continue
+				}
+				a.sortForms(forms)
+
+				size := parsedSize(forms)
+				if a.budget != nil {
+					a.budget.acquire(size)
+				}
+				resultCh <- parsedChunk{forms: forms, size: size}
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < len(words); i += chunkSize {
+			chunksCh <- words[i:min(i+chunkSize, len(words))]
+		}
+		close(chunksCh)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	// Канал вычитывается до конца даже после ошибки, чтобы воркеры не заблокировались
+	// и зарезервированная память вернулась в бюджет.
+	var emitErr error
+	for chunk := range resultCh {
+		if emitErr == nil {
+			if err := emit(chunk.forms); err != nil {
+				emitErr = err
+				stopped.Store(true)
+			}
+		}
+		if a.budget != nil {
+			a.budget.release(chunk.size)
+		}
+	}
+	return emitErr
+}
+
+// parsedSize приближенно оценивает объем памяти, занятый разборами.
+// Строка тегов не учитывается: она разделяется с пулом тегов словаря.
+func parsedSize(forms []*Parsed) int64 {
+	const grammemeSetEntrySize = 48 // Оценка места под один элемент карты OtherTags.
+	size := int64(len(forms)) * int64(unsafe.Sizeof(uintptr(0)))
+	for _, p := range forms {
+		size += int64(unsafe.Sizeof(*p)) + int64(len(p.Word)+len(p.Lemma)) +
+			int64(len(p.OtherTags))*grammemeSetEntrySize
+	}
+	return size
+}
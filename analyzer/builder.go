@@ -0,0 +1,520 @@
+// builder.go содержит компилятор словаря: по лексикону (набору словоформ с леммами и тегами)
+// он строит файл в формате morph.dawg, который читает loadInternal.
+// Компилятор строит минимизированный DAWG основного словаря, DAWG предсказателя
+// по суффиксам, плоские пулы строк и таблицу парадигм (см. format.go) и "сложный" блок
+// необязательных данных.
+package analyzer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// Параметры построения предсказателя.
+const (
+	maxPredictSuffixLen  = 5  // Максимальная длина суффикса (должна совпадать с findBestPrediction).
+	maxPredictRulesPerSx = 64 // Сколько самых частых правил хранится для одного суффикса.
+)
+
+// predictOpenClasses - части речи, по которым строятся правила предсказания.
+// Служебные и местоименные слова образуют закрытые классы и для предсказания бесполезны.
+var predictOpenClasses = GrammemeSet{
+	"Существительное": {},
+	"Прилагательное":  {},
+	"Глагол":          {},
+	"Причастие":       {},
+	"Деепричастие":    {},
+	"Наречие":         {},
+}
+
+// LexEntry - одна словоформа лексикона.
+type LexEntry struct {
+	Word  string // Словоформа.
+	Lemma string // Нормальная форма.
+	Tags  string // Теги через запятую; первой идет часть речи ("Существительное,Мужской,...").
+	// Lexeme - необязательный идентификатор лексемы. Если пуст, лексема определяется
+	// парой (лемма, часть речи). Нужен, чтобы различать омонимичные лексемы ("лук" - растение и оружие).
+	Lexeme string
+}
+
+// builderForm - словоформа лексемы внутри компилятора.
+type builderForm struct {
+	word   string
+	tagsID uint32
+}
+
+// builderLexeme - лексема (будущая парадигма) внутри компилятора.
+type builderLexeme struct {
+	lemma string
+	pos   string
+	forms []builderForm
+}
+
+// DictBuilder собирает словарь из лексикона. Не потокобезопасен.
+type DictBuilder struct {
+	lexemes     []*builderLexeme
+	lexemeByKey map[string]*builderLexeme
+	tagsPool    []string
+	tagsIDs     map[string]uint32
+	valency     map[string][]ValencyFrame
+	relations   []LexRelation
+	license     DictLicense
+	tagIndex    bool
+
+	sourceRevision string
+	buildTime      time.Time
+	compression    Compression
+}
+
+// NewDictBuilder создает пустой компилятор словаря.
+func NewDictBuilder() *DictBuilder {
+	return &DictBuilder{
+		lexemeByKey: make(map[string]*builderLexeme),
+		tagsIDs:     make(map[string]uint32),
+		valency:     make(map[string][]ValencyFrame),
+	}
+}
+
+// Add добавляет словоформу в лексикон. Слово и лемма приводятся к нижнему регистру,
+// варианты апострофа в них - к U+0027 (см. NormalizeApostrophes).
+func (b *DictBuilder) Add(e LexEntry) error {
+	word := NormalizeApostrophes(strings.ToLower(strings.TrimSpace(e.Word)))
+	lemma := NormalizeApostrophes(strings.ToLower(strings.TrimSpace(e.Lemma)))
+	if word == "" || lemma == "" {
+		return fmt.Errorf("пустое слово или лемма в записи %+v", e)
+	}
+	pos, _, _ := strings.Cut(e.Tags, ",")
+
+	key := e.Lexeme
+	if key == "" {
+		key = lemma + "\x00" + pos
+	}
+	lex, ok := b.lexemeByKey[key]
+	if !ok {
+		lex = &builderLexeme{lemma: lemma, pos: pos}
+		b.lexemeByKey[key] = lex
+		b.lexemes = append(b.lexemes, lex)
+	}
+
+	tagsID, ok := b.tagsIDs[e.Tags]
+	if !ok {
+		tagsID = uint32(len(b.tagsPool))
+		b.tagsIDs[e.Tags] = tagsID
+		b.tagsPool = append(b.tagsPool, e.Tags)
+	}
+	lex.forms = append(lex.forms, builderForm{word: word, tagsID: tagsID})
+	return nil
+}
+
+// AddValency добавляет валентные рамки для глагола с леммой lemma (см. Valency).
+func (b *DictBuilder) AddValency(lemma string, frames ...ValencyFrame) {
+	lemma = strings.ToLower(lemma)
+	b.valency[lemma] = append(b.valency[lemma], frames...)
+}
+
+// Build компилирует лексикон и записывает словарь в w.
+func (b *DictBuilder) Build(w io.Writer) error {
+	if len(b.lexemes) == 0 {
+		return errors.New("лексикон пуст")
+	}
+
+	// 1. Пулы лемм и таблица парадигма -> лемма. ID парадигмы - порядковый номер лексемы.
+	var complexData ComplexData
+	var lemmaPool []string
+	paradigmLemmas := make([]uint32, len(b.lexemes))
+	lemmaIDs := make(map[string]uint32)
+	for pID, lex := range b.lexemes {
+		lemmaID, ok := lemmaIDs[lex.lemma]
+		if !ok {
+			lemmaID = uint32(len(lemmaPool))
+			lemmaIDs[lex.lemma] = lemmaID
+			lemmaPool = append(lemmaPool, lex.lemma)
+		}
+		paradigmLemmas[pID] = lemmaID
+	}
+	for lemma, frames := range b.valency {
+		if lemmaID, ok := lemmaIDs[lemma]; ok {
+			if complexData.Valency == nil {
+				complexData.Valency = make(map[uint32][]ValencyFrame)
+			}
+			complexData.Valency[lemmaID] = frames
+		}
+	}
+
+	complexData.AspectPairs, complexData.Derivations = b.buildRelations(lemmaIDs)
+
+	if !b.license.IsZero() {
+		complexData.License = &b.license
+	}
+	grammemes, masks, err := buildGrammemeMasks(b.tagsPool)
+	if err != nil {
+		return err
+	}
+	complexData.SelfTest = b.selfTestSample()
+	if b.tagIndex {
+		complexData.TagIndex = b.buildTagIndex()
+	}
+
+	// 2. Основной DAWG: каждая словоформа с payload-ом (лемма, теги, парадигма).
+	root := &Node{Children: make(map[rune]*Node)}
+	for pID, lex := range b.lexemes {
+		lemmaID := paradigmLemmas[pID]
+		for _, f := range lex.forms {
+			insertPayload(root, f.word, MorphInfo{LemmaID: lemmaID, TagsID: f.tagsID, ParadigmID: uint32(pID)})
+		}
+	}
+	root = newDawgMinimizer().minimize(root)
+	nodes, edges, payloads, index := flattenDAWG[MorphInfo](root)
+
+	// 3. Основы парадигм: узлы, из которых dfsGenerate найдет все формы лексемы.
+	// Основы парадигмы p - элементы [paradigmStems[p], paradigmStems[p+1]) срезов stems и stemNodes.
+	var stems []string
+	var stemNodes []uint32
+	paradigmStems := make([]uint32, 0, len(b.lexemes)+1)
+	for _, lex := range b.lexemes {
+		paradigmStems = append(paradigmStems, uint32(len(stems)))
+		for _, stem := range lexemeStems(lex) {
+			node := root
+			for _, char := range stem {
+				node = node.Children[char]
+			}
+			stems = append(stems, stem)
+			stemNodes = append(stemNodes, index[node])
+		}
+	}
+	paradigmStems = append(paradigmStems, uint32(len(stems)))
+
+	// 4. DAWG предсказателя по суффиксам.
+	predictRoot := newDawgMinimizer().minimize(b.buildPredictTrie())
+	predictNodes, predictEdges, predictPayloads, _ := flattenDAWG[PredictInfo](predictRoot)
+
+	// 5. "Сложный" блок необязательных данных: gob + gzip или zstd.
+	complexBytes, err := encodeComplexData(&complexData, b.compression)
+	if err != nil {
+		return err
+	}
+
+	f := dictFile{
+		complexData:     complexBytes,
+		nodes:           nodes,
+		edges:           edges,
+		payloads:        payloads,
+		predictNodes:    predictNodes,
+		predictEdges:    predictEdges,
+		predictPayloads: predictPayloads,
+		lemmas:          lemmaPool,
+		tags:            b.tagsPool,
+		stems:           stems,
+		grammemes:       grammemes,
+		stemNodes:       stemNodes,
+		paradigmStems:   paradigmStems,
+		paradigmLemmas:  paradigmLemmas,
+		tagMasks:        masks,
+		sourceRevision:  b.sourceRevision,
+		buildTime:       b.buildTime,
+	}
+	if f.buildTime.IsZero() {
+		f.buildTime = time.Now()
+	}
+	return f.writeTo(w)
+}
+
+// predictRuleKey - правило предсказания: суффикс формы, класс словоизменения,
+// теги формы и ее окончание.
+type predictRuleKey struct {
+	suffix string
+	class  uint32
+	tagsID uint32
+	ending string
+}
+
+// predictRule - накопленная статистика правила и лексема-образец для него.
+type predictRule struct {
+	count      int
+	paradigmID uint32
+	formIdx    uint32
+}
+
+// buildPredictTrie строит trie суффиксов для предсказателя.
+// Лексемы группируются в классы словоизменения (одинаковый набор "окончание + теги"),
+// для каждого суффикса длиной до maxPredictSuffixLen подсчитывается, сколько форм
+// с этим суффиксом дает каждое правило. Образцом правила становится первая лексема,
+// форма которой имеет этот суффикс, - так предсказатель всегда найдет у образца общий суффикс.
+func (b *DictBuilder) buildPredictTrie() *Node {
+	classIDs := make(map[string]uint32)
+	rules := make(map[predictRuleKey]*predictRule)
+
+	for pID, lex := range b.lexemes {
+		if !inMap(lex.pos, predictOpenClasses) {
+			continue
+		}
+		stem := lexemeCommonPrefix(lex)
+
+		// Класс словоизменения - отсортированный набор (окончание, теги).
+		signature := make([]string, 0, len(lex.forms))
+		for _, f := range lex.forms {
+			signature = append(signature, fmt.Sprintf("%s\x00%d", strings.TrimPrefix(f.word, stem), f.tagsID))
+		}
+		sort.Strings(signature)
+		classKey := strings.Join(signature, "\x01")
+		class, ok := classIDs[classKey]
+		if !ok {
+			class = uint32(len(classIDs))
+			classIDs[classKey] = class
+		}
+
+		// Индекс формы в канонически отсортированном списке форм (см. getFormsByParadigmID).
+		formIdx := lexemeFormIndex(lex)
+		for _, f := range lex.forms {
+			runes := []rune(f.word)
+			ending := strings.TrimPrefix(f.word, stem)
+			for suffixLen := 1; suffixLen <= maxPredictSuffixLen && suffixLen <= len(runes); suffixLen++ {
+				key := predictRuleKey{suffix: string(runes[len(runes)-suffixLen:]), class: class, tagsID: f.tagsID, ending: ending}
+				rule, ok := rules[key]
+				if !ok {
+					rule = &predictRule{paradigmID: uint32(pID), formIdx: formIdx[f.word]}
+					rules[key] = rule
+				}
+				rule.count++
+			}
+		}
+	}
+
+	// Группируем правила по суффиксам и оставляем самые частые.
+	bySuffix := make(map[string][]*predictRule)
+	ruleTags := make(map[*predictRule]uint32)
+	for key, rule := range rules {
+		bySuffix[key.suffix] = append(bySuffix[key.suffix], rule)
+		ruleTags[rule] = key.tagsID
+	}
+
+	root := &Node{Children: make(map[rune]*Node)}
+	suffixes := make([]string, 0, len(bySuffix))
+	for suffix := range bySuffix {
+		suffixes = append(suffixes, suffix)
+	}
+	sort.Strings(suffixes)
+	for _, suffix := range suffixes {
+		candidates := bySuffix[suffix]
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].count != candidates[j].count {
+				return candidates[i].count > candidates[j].count
+			}
+			if candidates[i].paradigmID != candidates[j].paradigmID {
+				return candidates[i].paradigmID < candidates[j].paradigmID
+			}
+			return ruleTags[candidates[i]] < ruleTags[candidates[j]]
+		})
+		if len(candidates) > maxPredictRulesPerSx {
+			candidates = candidates[:maxPredictRulesPerSx]
+		}
+		for _, rule := range candidates {
+			frequency := rule.count
+			if frequency > 0xFFFF {
+				frequency = 0xFFFF
+			}
+			insertPayload(root, suffix, PredictInfo{
+				Frequency:  uint16(frequency),
+				ParadigmID: rule.paradigmID,
+				FormIdx:    rule.formIdx,
+				TagsID:     ruleTags[rule],
+			})
+		}
+	}
+	return root
+}
+
+// insertPayload добавляет в trie слово с полезной нагрузкой (повторы не дублируются).
+func insertPayload(root *Node, word string, payload any) {
+	node := root
+	for _, char := range word {
+		child, ok := node.Children[char]
+		if !ok {
+			child = &Node{Children: make(map[rune]*Node)}
+			node.Children[char] = child
+		}
+		node = child
+	}
+	node.IsFinal = true
+	for _, p := range node.Payload {
+		if p == payload {
+			return
+		}
+	}
+	node.Payload = append(node.Payload, payload)
+}
+
+// lexemeCommonPrefix возвращает наибольший общий префикс всех форм лексемы.
+func lexemeCommonPrefix(lex *builderLexeme) string {
+	prefix := []rune(lex.forms[0].word)
+	for _, f := range lex.forms[1:] {
+		runes := []rune(f.word)
+		n := 0
+		for n < len(prefix) && n < len(runes) && prefix[n] == runes[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// lexemeFormIndex возвращает позиции форм лексемы в отсортированном списке ее уникальных форм.
+func lexemeFormIndex(lex *builderLexeme) map[string]uint32 {
+	unique := make(map[string]struct{}, len(lex.forms))
+	for _, f := range lex.forms {
+		unique[f.word] = struct{}{}
+	}
+	words := make([]string, 0, len(unique))
+	for word := range unique {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	index := make(map[string]uint32, len(words))
+	for i, word := range words {
+		index[word] = uint32(i)
+	}
+	return index
+}
+
+// lexemeStems разбивает формы лексемы на группы с длинным общим префиксом и возвращает эти префиксы.
+// Одна короткая основа (или пустая, как у "идти/шёл") заставила бы dfsGenerate обходить
+// огромное поддерево, поэтому формы с разными корнями получают отдельные основы.
+func lexemeStems(lex *builderLexeme) []string {
+	const minStemLen = 3
+
+	words := make([]string, 0, len(lex.forms))
+	for _, f := range lex.forms {
+		words = append(words, f.word)
+	}
+	sort.Strings(words)
+
+	var stems []string
+	current := []rune(words[0])
+	for _, word := range words[1:] {
+		runes := []rune(word)
+		n := 0
+		for n < len(current) && n < len(runes) && current[n] == runes[n] {
+			n++
+		}
+		if n > 0 && n >= min(minStemLen, len(current)) {
+			current = current[:n]
+			continue
+		}
+		stems = append(stems, string(current))
+		current = runes
+	}
+	return append(stems, string(current))
+}
+
+// dawgMinimizer объединяет одинаковые поддеревья trie, превращая его в DAWG.
+// Узлы равны, если у них одинаковые финальность, payload-ы и (уже минимизированные) дети.
+type dawgMinimizer struct {
+	registry map[string]*Node
+	ids      map[*Node]uint32
+}
+
+func newDawgMinimizer() *dawgMinimizer {
+	return &dawgMinimizer{registry: make(map[string]*Node), ids: make(map[*Node]uint32)}
+}
+
+// minimize рекурсивно минимизирует поддерево и возвращает канонический узел.
+func (m *dawgMinimizer) minimize(node *Node) *Node {
+	for char, child := range node.Children {
+		node.Children[char] = m.minimize(child)
+	}
+
+	key := m.signature(node)
+	if canonical, ok := m.registry[key]; ok {
+		return canonical
+	}
+	m.registry[key] = node
+	m.ids[node] = uint32(len(m.ids))
+	return node
+}
+
+// signature кодирует содержимое узла в строку-ключ.
+func (m *dawgMinimizer) signature(node *Node) string {
+	var buf bytes.Buffer
+	if node.IsFinal {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	for _, payload := range node.Payload {
+		_ = binary.Write(&buf, binary.LittleEndian, payload)
+	}
+	buf.WriteByte(0xFF)
+	for _, char := range sortedChildren(node) {
+		_ = binary.Write(&buf, binary.LittleEndian, char)
+		_ = binary.Write(&buf, binary.LittleEndian, m.ids[node.Children[char]])
+	}
+	return buf.String()
+}
+
+// flattenDAWG превращает DAWG в "плоские" массивы узлов, ребер и payload-ов.
+// Корень всегда получает индекс 0, ребра каждого узла отсортированы по символу.
+func flattenDAWG[P any](root *Node) ([]FlatNode, []FlatEdge, []P, map[*Node]uint32) {
+	index := map[*Node]uint32{root: 0}
+	order := []*Node{root}
+	for i := 0; i < len(order); i++ {
+		for _, char := range sortedChildren(order[i]) {
+			child := order[i].Children[char]
+			if _, ok := index[child]; !ok {
+				index[child] = uint32(len(order))
+				order = append(order, child)
+			}
+		}
+	}
+
+	nodes := make([]FlatNode, len(order))
+	var edges []FlatEdge
+	var payloads []P
+	for i, node := range order {
+		nodes[i] = FlatNode{
+			PayloadIdx: uint32(len(payloads)),
+			PayloadLen: uint16(len(node.Payload)),
+			EdgesIdx:   uint32(len(edges)),
+			EdgesLen:   uint16(len(node.Children)),
+			IsFinal:    node.IsFinal,
+		}
+		for _, payload := range node.Payload {
+			payloads = append(payloads, payload.(P))
+		}
+		for _, char := range sortedChildren(node) {
+			edges = append(edges, FlatEdge{Char: char, NodeID: index[node.Children[char]]})
+		}
+	}
+	return nodes, edges, payloads, index
+}
+
+// sortedChildren возвращает символы дочерних ребер узла по возрастанию.
+func sortedChildren(node *Node) []rune {
+	chars := make([]rune, 0, len(node.Children))
+	for char := range node.Children {
+		chars = append(chars, char)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	return chars
+}
+
+// sliceToBytes - операция, обратная bytesToSlice: представляет срез структур
+// как срез байт в раскладке памяти текущей платформы, без копирования.
+func sliceToBytes[T any](s []T) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(s)*int(unsafe.Sizeof(s[0])))
+}
+
+// alignUp округляет offset вверх до кратного align.
+func alignUp(offset, align int64) int64 {
+	return (offset + align - 1) / align * align
+}
@@ -0,0 +1,110 @@
+// cache.go содержит подключаемый кэш результатов анализа и встроенную LRU-реализацию.
+package analyzer
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+)
+
+// Cache - кэш результатов анализа. Реализация должна быть безопасна для конкурентного
+// использования: методы анализатора вызываются из пула воркеров ParseList/InflectList.
+// Ключ имеет вид "<операция>:<слово>" ("parse:Кота", "predict:нейросеть", "inflect:кот").
+// Закэшированные срезы разделяются между вызовами и не должны изменяться.
+// Анализатор возвращает вызывающим копии срезов, но не самих разборов.
+type Cache interface {
+	Get(key string) ([]*Parsed, bool)
+	Put(key string, v []*Parsed)
+}
+
+// Операции, результаты которых кэшируются.
+const (
+	cacheOpParse   = "parse"
+	cacheOpPredict = "predict"
+	cacheOpInflect = "inflect"
+)
+
+// WithCache подключает кэш к Parse, ParsePredicted и Inflect (а через них - к Analyze
+// и пакетным методам). Подходит как NewLRUCache, так и обертка над ristretto/groupcache.
+//
+// Методы возвращают копию закэшированного среза: его можно сортировать и обрезать.
+// Сами разборы (*Parsed) общие для всех вызывающих, поэтому их поля изменять нельзя -
+// изменение увидят все горутины, получившие тот же результат; для правок скопируйте
+// разбор (p2 := *p).
+func WithCache(cache Cache) Option {
+	return func(a *MorphAnalyzer) {
+		a.cache = cache
+	}
+}
+
+// cached возвращает результат операции из кэша или вычисляет и кэширует его.
+// Вызывающий получает свою копию среза, чтобы перестановки не портили кэш.
+func (a *MorphAnalyzer) cached(op, word string, compute func(string) []*Parsed) []*Parsed {
+	if a.cache == nil {
+		return compute(word)
+	}
+	key := op + ":" + word
+	if v, ok := a.cache.Get(key); ok {
+		a.countCache(true)
+		return slices.Clone(v)
+	}
+	a.countCache(false)
+	v := compute(word)
+	a.cache.Put(key, v)
+	return slices.Clone(v)
+}
+
+// lruCache - потокобезопасный LRU-кэш фиксированной емкости.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List               // Элементы от самого свежего к самому старому.
+	items    map[string]*list.Element // Ключ -> элемент списка с lruEntry.
+}
+
+// lruEntry - запись LRU-кэша.
+type lruEntry struct {
+	key   string
+	value []*Parsed
+}
+
+// NewLRUCache создает встроенный LRU-кэш на capacity записей.
+func NewLRUCache(capacity int) Cache {
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element, capacity),
+	}
+}
+
+// Get возвращает значение и помечает запись как недавно использованную.
+func (c *lruCache) Get(key string) ([]*Parsed, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Put сохраняет значение, вытесняя самую давно использованную запись при переполнении.
+func (c *lruCache) Put(key string, v []*Parsed) {
+	if c.capacity <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry).value = v
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: v})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
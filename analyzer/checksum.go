@@ -0,0 +1,144 @@
+// checksum.go содержит контрольные суммы файла словаря: начиная с версии 11 сразу за
+// PoolsHeader записываются суммы CRC-32C заголовков и каждой секции. Неудачное объединение
+// частей, обрыв загрузки или копирования дают обрезанный или перепутанный файл, который
+// отображается в память без ошибок и выдает неверные разборы; VerifyDict находит такие файлы.
+package analyzer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+
+	"github.com/edsrzf/mmap-go"
+)
+
+// ErrNoChecksums возвращается VerifyDict для словаря без контрольных сумм (версий до 11).
+var ErrNoChecksums = errors.New("словарь не содержит контрольных сумм")
+
+// crcTable - таблица CRC-32C (Castagnoli): ее аппаратно ускоряют amd64 и arm64.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// dictSectionCount - число секций файла словаря (см. dictSections).
+const dictSectionCount = 19
+
+// Checksums - контрольные суммы CRC-32C файла словаря версии 11 и новее.
+type Checksums struct {
+	Headers  uint32                   // Header и PoolsHeader.
+	Sections [dictSectionCount]uint32 // Секции в порядке dictSections.
+}
+
+// dictSection - секция файла словаря.
+type dictSection struct {
+	name           string
+	offset, length int64
+}
+
+// arrayLength возвращает длину в байтах массива из count записей типа T.
+func arrayLength[T any](count int64) int64 {
+	var t T
+	return count * int64(binary.Size(t))
+}
+
+// dictSections возвращает секции файла в порядке их размещения (см. dictFile.writeTo).
+func dictSections(h *Header, p *PoolsHeader) [dictSectionCount]dictSection {
+	return [dictSectionCount]dictSection{
+		{"сложный блок", h.ComplexDataOffset, h.ComplexDataLength},
+		{"узлы DAWG", h.NodesOffset, arrayLength[FlatNode](h.NodesCount)},
+		{"ребра DAWG", h.EdgesOffset, arrayLength[FlatEdge](h.EdgesCount)},
+		{"разборы DAWG", h.PayloadsOffset, arrayLength[MorphInfo](h.PayloadsCount)},
+		{"узлы предсказателя", h.PredictNodesOffset, arrayLength[FlatNode](h.PredictNodesCount)},
+		{"ребра предсказателя", h.PredictEdgesOffset, arrayLength[FlatEdge](h.PredictEdgesCount)},
+		{"правила предсказателя", h.PredictPayloadsOffset, arrayLength[PredictInfo](h.PredictPayloadsCount)},
+		{"пул лемм", p.Lemmas.DataOffset, p.Lemmas.DataLength},
+		{"смещения пула лемм", p.Lemmas.IndexOffset, arrayLength[uint32](p.Lemmas.Count + 1)},
+		{"пул тегов", p.Tags.DataOffset, p.Tags.DataLength},
+		{"смещения пула тегов", p.Tags.IndexOffset, arrayLength[uint32](p.Tags.Count + 1)},
+		{"пул основ", p.Stems.DataOffset, p.Stems.DataLength},
+		{"смещения пула основ", p.Stems.IndexOffset, arrayLength[uint32](p.Stems.Count + 1)},
+		{"пул граммем", p.Grammemes.DataOffset, p.Grammemes.DataLength},
+		{"смещения пула граммем", p.Grammemes.IndexOffset, arrayLength[uint32](p.Grammemes.Count + 1)},
+		{"узлы основ", p.StemNodes.Offset, arrayLength[uint32](p.StemNodes.Count)},
+		{"основы парадигм", p.ParadigmStems.Offset, arrayLength[uint32](p.ParadigmStems.Count)},
+		{"леммы парадигм", p.ParadigmLemmas.Offset, arrayLength[uint32](p.ParadigmLemmas.Count)},
+		{"маски граммем", p.TagMasks.Offset, arrayLength[GrammemeMask](p.TagMasks.Count)},
+	}
+}
+
+// VerifyDict сверяет контрольные суммы заголовков и всех секций файла словаря.
+// Расхождение или обрезанный файл возвращаются ошибкой ErrDictCorrupted с названием
+// испорченной секции, словарь без контрольных сумм (версий до 11) - ErrNoChecksums.
+// Проверка читает весь файл, поэтому занимает заметное время (см. WithVerifyOnLoad).
+func VerifyDict(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+	data, err := mmap.Map(file, mmap.RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("ошибка mmap.Map: %w", err)
+	}
+	defer data.Unmap()
+	return verifyDict(data)
+}
+
+// verifyDict - VerifyDict для содержимого файла в памяти.
+func verifyDict(data []byte) error {
+	if isZstd(data) {
+		unpacked, err := decodeZstd(data)
+		if err != nil {
+			return err
+		}
+		data = unpacked
+	}
+	header, headerSize, err := readHeader(data)
+	if err != nil {
+		return err
+	}
+	if header.Version < dictVersionChecksums {
+		return fmt.Errorf("%w (версия %d)", ErrNoChecksums, header.Version)
+	}
+	var pools PoolsHeader
+	var sums Checksums
+	poolsSize, sumsSize := int64(binary.Size(pools)), int64(binary.Size(sums))
+	tail, err := section(data, headerSize, poolsSize+sumsSize)
+	if err != nil {
+		return err
+	}
+	if err := binary.Read(bytes.NewReader(tail), binary.LittleEndian, &pools); err != nil {
+		return fmt.Errorf("%w: ошибка чтения заголовка пулов: %w", ErrDictCorrupted, err)
+	}
+	if err := binary.Read(bytes.NewReader(tail[poolsSize:]), binary.LittleEndian, &sums); err != nil {
+		return fmt.Errorf("%w: ошибка чтения контрольных сумм: %w", ErrDictCorrupted, err)
+	}
+	if crc32.Checksum(data[:headerSize+poolsSize], crcTable) != sums.Headers {
+		return fmt.Errorf("%w: не совпадает контрольная сумма заголовка", ErrDictCorrupted)
+	}
+	for i, s := range dictSections(&header, &pools) {
+		b, err := section(data, s.offset, s.length)
+		if err != nil {
+			return fmt.Errorf("%w (секция %q)", err, s.name)
+		}
+		if crc32.Checksum(b, crcTable) != sums.Sections[i] {
+			return fmt.Errorf("%w: не совпадает контрольная сумма секции %q", ErrDictCorrupted, s.name)
+		}
+	}
+	return nil
+}
+
+// verifyLoaded сверяет контрольные суммы загруженного словаря, если это запрошено
+// опцией WithVerifyOnLoad.
+func (a *MorphAnalyzer) verifyLoaded() error {
+	if !a.verifyOnLoad {
+		return nil
+	}
+	err := verifyDict(a.data)
+	if errors.Is(err, ErrNoChecksums) {
+		a.log().Warn("словарь загружен без проверки контрольных сумм", "error", err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+// comparison.go содержит степени сравнения прилагательных: синтетические формы берутся
+// из парадигмы ("красивее", "красивейший"), аналитические строятся служебными словами
+// ("более красивый", "самый красивый", "наиболее красивый").
+package analyzer
+
+import (
+	"slices"
+	"strings"
+)
+
+// Comparative возвращает сравнительную степень прилагательного: синтетические формы
+// словаря ("красивее", "красивей", "краше"), затем формы с приставкой "по-" ("покрасивее")
+// и аналитическую форму с "более" от переданной словоформы ("более красивого").
+// Для формы сравнительной или превосходной степени строятся формы ее леммы.
+// Ненормативные варианты идут после нормативных. Для слова, которое не является
+// прилагательным, возвращает nil.
+func (a *MorphAnalyzer) Comparative(adjective string) []string {
+	word, _, lex := a.adjectiveLexeme(adjective)
+	if lex == nil {
+		return nil
+	}
+	return append(softComparativesLast(cellWords(lex.FormsWith("Сравнительная"))), "более "+word)
+}
+
+// Superlative возвращает превосходную степень прилагательного, согласованную по роду,
+// числу и падежу с переданной словоформой: синтетические формы словаря ("красивейшего"),
+// затем аналитические с "самый" и "наиболее" ("самого красивого", "наиболее красивого").
+// Для краткой формы строится только форма с "наиболее". Для слова, которое не является
+// прилагательным, возвращает nil.
+func (a *MorphAnalyzer) Superlative(adjective string) []string {
+	word, p, lex := a.adjectiveLexeme(adjective)
+	if lex == nil {
+		return nil
+	}
+	if p.hasCanonical("Краткая") {
+		return []string{"наиболее " + word}
+	}
+	agreement := []string{"Превосходная", "Полная"}
+	for _, g := range []string{p.Case, p.Number, p.Gender} {
+		if g != "" {
+			agreement = append(agreement, g)
+		}
+	}
+	if p.Case == "Винительный" && p.Animacy != "" {
+		agreement = append(agreement, p.Animacy)
+	}
+	result := cellWords(lex.FormsWith(agreement...))
+	if samyj := a.Synthesize("самый", agreement[2:]); len(samyj) > 0 {
+		result = append(result, samyj[0]+" "+word)
+	}
+	return append(result, "наиболее "+word)
+}
+
+// adjectiveLexeme возвращает словоформу в нижнем регистре, ее первый разбор
+// как прилагательного положительной степени и лексему этого прилагательного.
+// Для формы сравнительной или превосходной степени ("красивее") берется лемма.
+// Если разбора прилагательного нет, лексема равна nil.
+func (a *MorphAnalyzer) adjectiveLexeme(adjective string) (string, *Parsed, *Lexeme) {
+	word := strings.ToLower(adjective)
+	parses, _ := a.analyze(word)
+	for _, p := range parses {
+		if p.PartOfSpeech != "Прилагательное" || p.hasCanonical("Сравнительная") || p.hasCanonical("Превосходная") {
+			continue
+		}
+		for _, lex := range a.lexemes(word) {
+			if lex.Lemma == p.Lemma && lex.PartOfSpeech == p.PartOfSpeech {
+				return word, p.Details(), lex
+			}
+		}
+	}
+	for _, p := range parses {
+		if p.PartOfSpeech == "Прилагательное" && p.Lemma != word {
+			return a.adjectiveLexeme(p.Lemma)
+		}
+	}
+	return word, nil, nil
+}
+
+// softComparativesLast ставит формы с приставкой "по-" ("покрасивее") после основных,
+// если основная форма тоже есть.
+func softComparativesLast(words []string) []string {
+	result := make([]string, 0, len(words))
+	var soft []string
+	for _, w := range words {
+		if base, ok := strings.CutPrefix(w, "по"); ok && slices.Contains(words, base) {
+			soft = append(soft, w)
+		} else {
+			result = append(result, w)
+		}
+	}
+	return append(result, soft...)
+}
@@ -0,0 +1,139 @@
+// compress.go содержит сжатие словаря. "Сложный" блок сжимается gzip или zstd
+// (см. DictBuilder.SetCompression), а весь файл словаря можно сжать zstd для поставки
+// (zstd -19 morph.dawg). Алгоритм распознается по сигнатуре, поэтому загрузчику ничего
+// указывать не нужно. zstd распаковывается в несколько раз быстрее gzip и сжимает плотнее.
+package analyzer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression - алгоритм сжатия "сложного" блока словаря.
+type Compression string
+
+const (
+	// CompressionGzip - gzip, алгоритм по умолчанию: его читают все версии анализатора.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd - zstd: быстрее распаковывается; требует анализатор, знающий zstd.
+	CompressionZstd Compression = "zstd"
+)
+
+// LoadModeZstd - файл словаря целиком сжат zstd и распакован в кучу.
+const LoadModeZstd LoadMode = "zstd"
+
+// Сигнатуры сжатых данных.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// zstdDecoder - общий распаковщик zstd: DecodeAll безопасен для параллельных вызовов.
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil)
+})
+
+// SetCompression задает алгоритм сжатия "сложного" блока словаря (по умолчанию gzip).
+func (b *DictBuilder) SetCompression(c Compression) error {
+	switch c {
+	case CompressionGzip, CompressionZstd:
+		b.compression = c
+		return nil
+	}
+	return fmt.Errorf("неизвестный алгоритм сжатия %q (ожидали gzip или zstd)", c)
+}
+
+// isZstd сообщает, что data начинается с кадра zstd.
+func isZstd(data []byte) bool {
+	return bytes.HasPrefix(data, zstdMagic)
+}
+
+// compressBlock сжимает блок алгоритмом c.
+func compressBlock(data []byte, c Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch c {
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(&buf, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания zstd.Encoder: %w", err)
+		}
+		w = zw
+	default:
+		w = gzip.NewWriter(&buf)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("ошибка сжатия %s: %w", c, err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка сжатия %s: %w", c, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressBlock распаковывает блок, сжатый gzip или zstd, распознавая алгоритм по сигнатуре.
+func decompressBlock(block []byte) ([]byte, error) {
+	switch {
+	case isZstd(block):
+		return decodeZstd(block)
+	case bytes.HasPrefix(block, gzipMagic):
+		gzipReader, err := gzip.NewReader(bytes.NewReader(block))
+		if err != nil {
+			return nil, fmt.Errorf("%w: ошибка создания gzip.Reader: %w", ErrDictCorrupted, err)
+		}
+		data, err := io.ReadAll(gzipReader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ошибка распаковки данных: %w", ErrDictCorrupted, err)
+		}
+		if err := gzipReader.Close(); err != nil {
+			return nil, fmt.Errorf("ошибка закрытия gzip.Reader: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: неизвестный формат сжатия блока", ErrDictCorrupted)
+}
+
+// decodeZstd распаковывает данные, сжатые zstd.
+func decodeZstd(data []byte) ([]byte, error) {
+	decoder, err := zstdDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания zstd.Decoder: %w", err)
+	}
+	out, err := decoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ошибка распаковки zstd: %w", ErrDictCorrupted, err)
+	}
+	return out, nil
+}
+
+// loadCompressed распаковывает в кучу файл словаря, целиком сжатый zstd, и создает
+// анализатор поверх распакованных данных.
+func loadCompressed(data []byte) (*MorphAnalyzer, error) {
+	unpacked, err := decodeZstd(data)
+	if err != nil {
+		return nil, err
+	}
+	if isZstd(unpacked) {
+		return nil, fmt.Errorf("%w: словарь сжат zstd дважды", ErrDictCorrupted)
+	}
+	analyzer, err := loadFromBytes(unpacked)
+	if err != nil {
+		return nil, err
+	}
+	analyzer.info.LoadMode = LoadModeZstd
+	analyzer.info.FileSize = int64(len(data))
+	return analyzer, nil
+}
+
+// setLoadMode запоминает способ загрузки словаря. Сжатый словарь всегда распакован
+// в кучу, и его способ загрузки не меняется.
+func (a *MorphAnalyzer) setLoadMode(mode LoadMode) {
+	if a.info.LoadMode != LoadModeZstd {
+		a.info.LoadMode = mode
+	}
+}
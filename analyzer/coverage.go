@@ -0,0 +1,155 @@
+// coverage.go содержит отчет о покрытии корпуса словарем: доля словарных слов,
+// самые частые несловарные слова и кандидаты в новые суффиксные правила предсказателя.
+package analyzer
+
+import (
+	"iter"
+	"sort"
+	"strings"
+)
+
+const (
+	coverageTopOOV         = 50 // Количество самых частых несловарных слов в отчете.
+	coverageMinSuffixLen   = 2  // Минимальная длина суффикса-кандидата.
+	coverageTopSuffixes    = 20 // Количество кандидатов в суффиксные правила в отчете.
+	coverageSuffixExamples = 5  // Количество примеров слов на один суффикс.
+)
+
+// WordCount - слово и число его вхождений в корпус.
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// SuffixCandidate - суффикс, общий для нескольких несловарных слов корпуса.
+// Кандидат на новое правило предсказателя или на пополнение словаря группой слов.
+type SuffixCandidate struct {
+	Suffix        string   `json:"suffix"`
+	Count         int      `json:"count"`          // Вхождений несловарных слов с этим суффиксом
+	Words         int      `json:"words"`          // Различных несловарных слов с этим суффиксом
+	Examples      []string `json:"examples"`       // Самые частые слова с этим суффиксом
+	PredictedTags string   `json:"predicted_tags"` // Теги, которые предсказатель дает самому частому примеру
+}
+
+// Coverage - отчет о покрытии корпуса словарем.
+type Coverage struct {
+	TotalWords      int               `json:"total_words"`      // Всего слов в корпусе
+	DictionaryWords int               `json:"dictionary_words"` // Из них найдено в словаре
+	HitRate         float64           `json:"hit_rate"`         // Доля словарных слов (0..1)
+	TopOOV          []WordCount       `json:"top_oov"`          // Самые частые несловарные слова
+	SuffixRules     []SuffixCandidate `json:"suffix_rules"`     // Кандидаты в суффиксные правила
+}
+
+// CoverageReport подсчитывает покрытие корпуса словарем. Слова сравниваются без учета регистра,
+// пустые строки пропускаются. Помогает решить, какие слова стоит добавить в пользовательский словарь.
+func (a *MorphAnalyzer) CoverageReport(words iter.Seq[string]) *Coverage {
+	report, oov := a.coverage(words)
+	report.SuffixRules = a.suffixCandidates(oov)
+	report.TopOOV = oov[:min(len(oov), coverageTopOOV)]
+	return report
+}
+
+// coverage подсчитывает слова корпуса и возвращает отчет без списков
+// вместе со всеми несловарными словами, отсортированными по убыванию частоты.
+func (a *MorphAnalyzer) coverage(words iter.Seq[string]) (*Coverage, []WordCount) {
+	report := &Coverage{}
+	oovCounts := make(map[string]int)
+	known := make(map[string]bool)
+	for word := range words {
+		lowerWord := strings.ToLower(word)
+		if lowerWord == "" {
+			continue
+		}
+		report.TotalWords++
+
+		inDict, ok := known[lowerWord]
+		if !ok {
+			inDict = a.lookupPayloads(lowerWord) != nil
+			known[lowerWord] = inDict
+		}
+		if inDict {
+			report.DictionaryWords++
+		} else {
+			oovCounts[lowerWord]++
+		}
+	}
+	if report.TotalWords > 0 {
+		report.HitRate = float64(report.DictionaryWords) / float64(report.TotalWords)
+	}
+
+	oov := make([]WordCount, 0, len(oovCounts))
+	for word, count := range oovCounts {
+		oov = append(oov, WordCount{Word: word, Count: count})
+	}
+	sortWordCounts(oov)
+	return report, oov
+}
+
+// suffixGroup - суффикс-кандидат вместе с данными для отсева избыточных суффиксов.
+type suffixGroup struct {
+	SuffixCandidate
+	prev       rune // Символ перед суффиксом у всех слов группы, если он общий.
+	mixedPrev  bool // Символы перед суффиксом у слов группы различаются.
+	extendable bool // Все слова группы длиннее суффикса хотя бы на два символа.
+}
+
+// suffixCandidates группирует несловарные слова по суффиксам длиной от coverageMinSuffixLen
+// до максимальной длины правила предсказателя. Короткий суффикс не попадает в отчет,
+// если более длинный суффикс покрывает те же слова ("инг" при "динг").
+func (a *MorphAnalyzer) suffixCandidates(oov []WordCount) []SuffixCandidate {
+	groups := make(map[string]*suffixGroup)
+	for _, wc := range oov {
+		runes := []rune(wc.Word)
+		// Суффикс, равный всему слову, - не суффикс.
+		for suffixLen := coverageMinSuffixLen; suffixLen <= maxPredictSuffixLen && suffixLen < len(runes); suffixLen++ {
+			suffix := string(runes[len(runes)-suffixLen:])
+			prev := runes[len(runes)-suffixLen-1]
+			group, ok := groups[suffix]
+			if !ok {
+				group = &suffixGroup{SuffixCandidate: SuffixCandidate{Suffix: suffix}, prev: prev, extendable: true}
+				groups[suffix] = group
+			}
+			group.Count += wc.Count
+			group.Words++
+			group.mixedPrev = group.mixedPrev || prev != group.prev
+			group.extendable = group.extendable && suffixLen+1 < len(runes)
+			// oov отсортирован по частоте, поэтому первые примеры - самые частые.
+			if len(group.Examples) < coverageSuffixExamples {
+				group.Examples = append(group.Examples, wc.Word)
+			}
+		}
+	}
+
+	candidates := make([]SuffixCandidate, 0, len(groups))
+	for _, g := range groups {
+		// Суффикс одного слова - не правило, а кандидат в словарь (он уже есть в TopOOV).
+		if g.Words < 2 {
+			continue
+		}
+		// Все слова группы продолжаются одним и тем же символом: в отчет попадет более длинный суффикс.
+		if !g.mixedPrev && g.extendable && len([]rune(g.Suffix)) < maxPredictSuffixLen {
+			continue
+		}
+		if best := a.findBestPrediction(g.Examples[0]); best != nil {
+			g.PredictedTags = a.tags.at(best.TagsID)
+		}
+		candidates = append(candidates, g.SuffixCandidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Count != candidates[j].Count {
+			return candidates[i].Count > candidates[j].Count
+		}
+		return candidates[i].Suffix < candidates[j].Suffix
+	})
+	return candidates[:min(len(candidates), coverageTopSuffixes)]
+}
+
+// sortWordCounts сортирует слова по убыванию частоты, при равенстве - по алфавиту.
+func sortWordCounts(counts []WordCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Word < counts[j].Word
+	})
+}
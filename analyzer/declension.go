@@ -0,0 +1,257 @@
+// declension.go содержит таблицы словоизменения: падеж×число для существительных,
+// падеж×род/число для прилагательных и лицо×число по временам для глаголов.
+// Таблицы строятся по лексеме (см. Lexeme) и выводятся текстом, в HTML или JSON -
+// для словарей, приложений для изучения языка и отладки парадигм.
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table - таблица словоформ лексемы. Ячейка содержит все варианты формы
+// (нормативные первыми): "кошкой, кошкою"; пустая ячейка - формы нет.
+type Table struct {
+	Lemma        string     `json:"lemma"`
+	PartOfSpeech string     `json:"part_of_speech"`
+	Columns      []string   `json:"columns"` // Заголовки столбцов (число или род).
+	Rows         []TableRow `json:"rows"`
+}
+
+// TableRow - строка таблицы словоформ.
+type TableRow struct {
+	Label string     `json:"label"` // Заголовок строки: падеж или время и лицо.
+	Cells [][]string `json:"cells"` // Варианты форм по столбцам.
+}
+
+var (
+	// tableCases - падежи строк таблицы. Редкие падежи выводятся, только если у лексемы есть такие формы.
+	tableCases     = []string{"Именительный", "Родительный", "Дательный", "Винительный", "Творительный", "Предложный"}
+	tableRareCases = []string{"Партитивный", "Местный", "Звательный", "Счетный", "Ждательный"}
+	// tableNumbers - столбцы таблицы существительного.
+	tableNumbers = []string{"Единственное число", "Множественное число"}
+	// tableGenders - столбцы таблицы прилагательного: род в единственном числе и множественное число.
+	tableGenders = []string{"Мужской", "Средний", "Женский", "Множественное число"}
+	// tablePersons - строки времени в таблице глагола.
+	tablePersons = []string{"1-е лицо", "2-е лицо", "3-е лицо"}
+)
+
+// DeclensionTable строит таблицу словоизменения первой лексемы слова, для которой
+// она определена: падеж×число для существительных, падеж×род/число для полных форм
+// прилагательных (с отдельной строкой кратких форм) и лицо×число по временам для глаголов
+// (прошедшее время и повелительное наклонение - отдельными строками).
+// Для других частей речи и неразобранных слов возвращает nil.
+func (a *MorphAnalyzer) DeclensionTable(word string) *Table {
+	for _, lex := range a.lexemes(word) {
+		var t *Table
+		switch lex.PartOfSpeech {
+		case "Существительное":
+			t = nounTable(lex)
+		case "Прилагательное":
+			t = adjectiveTable(lex)
+		case "Глагол":
+			t = verbTable(lex)
+		}
+		if t != nil && len(t.Rows) > 0 {
+			return a.localizeTable(t)
+		}
+	}
+	return nil
+}
+
+// nounTable строит таблицу падеж×число.
+func nounTable(lex *Lexeme) *Table {
+	t := &Table{Lemma: lex.Lemma, PartOfSpeech: lex.PartOfSpeech, Columns: tableNumbers}
+	for _, c := range tableCases {
+		t.addRow(lex, []string{c}, [][]string{{c, tableNumbers[0]}, {c, tableNumbers[1]}})
+	}
+	for _, c := range tableRareCases {
+		if len(lex.FormsWith(c)) > 0 {
+			t.addRow(lex, []string{c}, [][]string{{c, tableNumbers[0]}, {c, tableNumbers[1]}})
+		}
+	}
+	return t
+}
+
+// adjectiveTable строит таблицу падеж×род/число по полным формам положительной степени.
+func adjectiveTable(lex *Lexeme) *Table {
+	full := lex.filtered(func(f *Parsed) bool {
+		return f.hasCanonical("Полная") && !f.hasCanonical("Превосходная") && !f.hasCanonical("Сравнительная")
+	})
+	t := &Table{Lemma: lex.Lemma, PartOfSpeech: lex.PartOfSpeech, Columns: tableGenders}
+	cells := func(extra ...string) [][]string {
+		queries := make([][]string, len(tableGenders))
+		for i, g := range tableGenders {
+			queries[i] = append([]string{g}, extra...)
+			if i < len(tableGenders)-1 {
+				queries[i] = append(queries[i], "Единственное число")
+			}
+		}
+		return queries
+	}
+	for _, c := range tableCases {
+		t.addRow(full, []string{c}, cells(c))
+	}
+	short := lex.filtered(func(f *Parsed) bool { return f.hasCanonical("Краткая") })
+	t.addRow(short, []string{"Краткая"}, cells())
+	return t
+}
+
+// verbTable строит таблицу лицо×число для настоящего и будущего времени
+// и строки прошедшего времени и повелительного наклонения.
+func verbTable(lex *Lexeme) *Table {
+	verb := lex.filtered(func(f *Parsed) bool { return f.PartOfSpeech == "Глагол" })
+	t := &Table{Lemma: lex.Lemma, PartOfSpeech: lex.PartOfSpeech, Columns: tableNumbers}
+	finite := verb.filtered(func(f *Parsed) bool { return !f.hasCanonical("Повелительное") })
+	for _, tense := range []string{"Настоящее", "Будущее"} {
+		for _, person := range tablePersons {
+			t.addRow(finite, []string{tense, person}, [][]string{{tense, person, tableNumbers[0]}, {tense, person, tableNumbers[1]}})
+		}
+	}
+	t.addRow(finite, []string{"Прошедшее"}, [][]string{{"Прошедшее", tableNumbers[0]}, {"Прошедшее", tableNumbers[1]}})
+	imperative := verb.filtered(func(f *Parsed) bool {
+		return f.hasCanonical("Повелительное") && !f.hasCanonical("1-е лицо")
+	})
+	t.addRow(imperative, []string{"Повелительное"}, [][]string{{tableNumbers[0]}, {tableNumbers[1]}})
+	return t
+}
+
+// addRow добавляет строку с формами, содержащими граммемы queries[i] для столбца i.
+// Строка без единой формы не добавляется.
+func (t *Table) addRow(lex *Lexeme, label []string, queries [][]string) {
+	row := TableRow{Label: strings.Join(label, ", "), Cells: make([][]string, len(queries))}
+	empty := true
+	for i, q := range queries {
+		row.Cells[i] = cellWords(lex.FormsWith(q...))
+		empty = empty && len(row.Cells[i]) == 0
+	}
+	if !empty {
+		t.Rows = append(t.Rows, row)
+	}
+}
+
+// cellWords возвращает различные словоформы ячейки: нормативные первыми.
+func cellWords(forms []*Parsed) []string {
+	normative, other := []string{}, []string(nil)
+	for _, f := range forms {
+		if slices.Contains(normative, f.Word) || slices.Contains(other, f.Word) {
+			continue
+		}
+		if isNonNormative(f.Tags) {
+			other = append(other, f.Word)
+		} else {
+			normative = append(normative, f.Word)
+		}
+	}
+	return append(normative, other...)
+}
+
+// filtered возвращает лексему из форм, удовлетворяющих keep.
+func (l *Lexeme) filtered(keep func(*Parsed) bool) *Lexeme {
+	result := &Lexeme{Lemma: l.Lemma, PartOfSpeech: l.PartOfSpeech, byGrammeme: make(map[string][]int)}
+	for _, f := range l.Forms {
+		if keep(f) {
+			result.add(f)
+		}
+	}
+	return result
+}
+
+// localizeTable переводит заголовки таблицы на язык анализатора (см. WithLang).
+func (a *MorphAnalyzer) localizeTable(t *Table) *Table {
+	if a.lang == LangRussian {
+		return t
+	}
+	t.PartOfSpeech = LocalizeGrammeme(t.PartOfSpeech, a.lang)
+	columns := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = LocalizeGrammeme(c, a.lang)
+	}
+	t.Columns = columns
+	for i := range t.Rows {
+		parts := strings.Split(t.Rows[i].Label, ", ")
+		for j, p := range parts {
+			parts[j] = LocalizeGrammeme(p, a.lang)
+		}
+		t.Rows[i].Label = strings.Join(parts, ", ")
+	}
+	return t
+}
+
+// WriteText выводит таблицу с выровненными столбцами; отсутствующая форма обозначается "—".
+func (t *Table) WriteText(w io.Writer) error {
+	header := append([]string{t.Lemma}, t.Columns...)
+	lines := [][]string{header}
+	for _, row := range t.Rows {
+		line := []string{row.Label}
+		for _, cell := range row.Cells {
+			line = append(line, cellText(cell))
+		}
+		lines = append(lines, line)
+	}
+	widths := make([]int, len(header))
+	for _, line := range lines {
+		for i, s := range line {
+			widths[i] = max(widths[i], utf8.RuneCountInString(s))
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		for i, s := range line {
+			if i < len(line)-1 {
+				s += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(s)+2)
+			}
+			bw.WriteString(s)
+		}
+		bw.WriteByte('\n')
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("ошибка вывода таблицы: %w", err)
+	}
+	return nil
+}
+
+// WriteHTML выводит таблицу элементом <table>; лемма выводится в <caption>.
+func (t *Table) WriteHTML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<table>\n<caption>" + html.EscapeString(t.Lemma) + "</caption>\n<thead><tr><th></th>")
+	for _, c := range t.Columns {
+		bw.WriteString("<th>" + html.EscapeString(c) + "</th>")
+	}
+	bw.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range t.Rows {
+		bw.WriteString("<tr><th>" + html.EscapeString(row.Label) + "</th>")
+		for _, cell := range row.Cells {
+			bw.WriteString("<td>" + html.EscapeString(cellText(cell)) + "</td>")
+		}
+		bw.WriteString("</tr>\n")
+	}
+	bw.WriteString("</tbody>\n</table>\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("ошибка вывода таблицы: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON выводит таблицу в JSON.
+func (t *Table) WriteJSON(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(t); err != nil {
+		return fmt.Errorf("ошибка вывода таблицы: %w", err)
+	}
+	return nil
+}
+
+// cellText возвращает текст ячейки для текстового и HTML-вывода.
+func cellText(cell []string) string {
+	if len(cell) == 0 {
+		return "—"
+	}
+	return strings.Join(cell, ", ")
+}
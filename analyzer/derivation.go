@@ -0,0 +1,161 @@
+// derivation.go содержит словообразовательные связи лемм: видовые пары глаголов
+// (делать <-> сделать) и производные слова (кот -> котик). Связи хранятся в необязательном
+// блоке словаря: если словарь собран без них, API возвращает пустой результат.
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// RelationKind - тип связи лемм.
+type RelationKind int
+
+const (
+	RelationAspect     RelationKind = iota // Видовая пара глаголов; связь симметрична.
+	RelationDerivation                     // From - производящее слово, To - производное.
+)
+
+// String возвращает название типа связи (так он записывается в TSV, см. ReadTSVRelations).
+func (k RelationKind) String() string {
+	switch k {
+	case RelationAspect:
+		return "aspect"
+	case RelationDerivation:
+		return "derivation"
+	default:
+		return "unknown"
+	}
+}
+
+// LexRelation - связь двух лемм лексикона.
+type LexRelation struct {
+	From string
+	To   string
+	Kind RelationKind
+}
+
+// AddRelation добавляет связь лемм. Связи с леммами, которых нет в лексиконе,
+// при сборке отбрасываются.
+func (b *DictBuilder) AddRelation(rel LexRelation) error {
+	from, to := strings.ToLower(strings.TrimSpace(rel.From)), strings.ToLower(strings.TrimSpace(rel.To))
+	if from == "" || to == "" || from == to {
+		return fmt.Errorf("неверная связь лемм %+v", rel)
+	}
+	if rel.Kind != RelationAspect && rel.Kind != RelationDerivation {
+		return fmt.Errorf("неизвестный тип связи %d", rel.Kind)
+	}
+	b.relations = append(b.relations, LexRelation{From: from, To: to, Kind: rel.Kind})
+	return nil
+}
+
+// buildRelations раскладывает связи по ID лемм: видовые пары - в обе стороны,
+// производные - от производящего слова. Повторы убираются.
+func (b *DictBuilder) buildRelations(lemmaIDs map[string]uint32) (aspectPairs, derivations map[uint32][]uint32) {
+	link := func(m map[uint32][]uint32, from, to uint32) map[uint32][]uint32 {
+		if m == nil {
+			m = make(map[uint32][]uint32)
+		}
+		if !slices.Contains(m[from], to) {
+			m[from] = append(m[from], to)
+		}
+		return m
+	}
+	for _, rel := range b.relations {
+		from, okFrom := lemmaIDs[rel.From]
+		to, okTo := lemmaIDs[rel.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		switch rel.Kind {
+		case RelationAspect:
+			aspectPairs = link(aspectPairs, from, to)
+			aspectPairs = link(aspectPairs, to, from)
+		case RelationDerivation:
+			derivations = link(derivations, from, to)
+		}
+	}
+	return aspectPairs, derivations
+}
+
+// ReadTSVRelations читает связи лемм в формате TSV и вызывает fn для каждой связи.
+// Формат строки: "лемма<TAB>лемма<TAB>тип", где тип - "aspect" или "derivation"
+// (для производных первой идет производящая лемма: "кот<TAB>котик<TAB>derivation").
+// Пустые строки и строки, начинающиеся с '#', пропускаются.
+func ReadTSVRelations(r io.Reader, fn func(LexRelation) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return fmt.Errorf("строка %d: ожидалось 3 поля, получено %d", lineNum, len(fields))
+		}
+		rel := LexRelation{From: fields[0], To: fields[1]}
+		switch fields[2] {
+		case RelationAspect.String():
+			rel.Kind = RelationAspect
+		case RelationDerivation.String():
+			rel.Kind = RelationDerivation
+		default:
+			return fmt.Errorf("строка %d: неизвестный тип связи %q", lineNum, fields[2])
+		}
+		if err := fn(rel); err != nil {
+			return fmt.Errorf("строка %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения TSV: %w", err)
+	}
+	return nil
+}
+
+// HasRelations сообщает, что словарь содержит словообразовательные связи (см. AspectPair).
+func (a *MorphAnalyzer) HasRelations() bool {
+	return a.aspectPairs != nil || a.derivations != nil
+}
+
+// AspectPair возвращает леммы глаголов другого вида для глагола: "делать" -> ["сделать"],
+// "сделать" -> ["делать"]. Принимает любую форму глагола, причастия или деепричастия.
+// Возвращает nil, если пары нет или словарь собран без связей.
+func (a *MorphAnalyzer) AspectPair(verb string) []string {
+	return a.relatedLemmas(verb, a.aspectPairs, verbLikeTags)
+}
+
+// Derivations возвращает производные леммы слова: "кот" -> ["котик", ...].
+// Принимает любую форму слова. Возвращает nil, если производных нет или словарь собран без связей.
+func (a *MorphAnalyzer) Derivations(word string) []string {
+	return a.relatedLemmas(word, a.derivations, nil)
+}
+
+// relatedLemmas возвращает связанные леммы всех лемм слова (с частью речи из pos,
+// если он задан) без повторов.
+func (a *MorphAnalyzer) relatedLemmas(word string, relations map[uint32][]uint32, pos GrammemeSet) []string {
+	if len(relations) == 0 {
+		return nil
+	}
+	var result []string
+	seen := make(map[uint32]struct{})
+	for _, info := range a.lookupPayloads(strings.ToLower(word)) {
+		if _, ok := seen[info.LemmaID]; ok {
+			continue
+		}
+		if p, _, _ := strings.Cut(a.tags.at(info.TagsID), ","); pos != nil && !inMap(p, pos) {
+			continue
+		}
+		seen[info.LemmaID] = struct{}{}
+		for _, id := range relations[info.LemmaID] {
+			if lemma := a.lemmas.at(id); !slices.Contains(result, lemma) {
+				result = append(result, lemma)
+			}
+		}
+	}
+	return result
+}
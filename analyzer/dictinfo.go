@@ -0,0 +1,118 @@
+// dictinfo.go содержит сведения о загруженном словаре: версию формата, дату сборки,
+// ревизию исходного корпуса, размеры и способ загрузки. По ним сервис может сообщить,
+// какой именно словарь загружен, а загрузчик - отличить несовместимый файл от поврежденного.
+package analyzer
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxSourceRevision - наибольшая длина ревизии исходного корпуса в байтах (см. Header).
+const maxSourceRevision = 64
+
+// LoadMode - способ загрузки словаря.
+type LoadMode string
+
+const (
+	// LoadModeMmap - файл отображен в память (mmap), страницы подгружает ОС.
+	LoadModeMmap LoadMode = "mmap"
+	// LoadModeMemory - словарь уже находился в памяти (например, встроен в исполняемый файл).
+	LoadModeMemory LoadMode = "memory"
+)
+
+// DictInfo - сведения о загруженном словаре.
+type DictInfo struct {
+	Version        int       `json:"version"`                   // Версия формата файла.
+	BuildTime      time.Time `json:"build_time,omitzero"`       // Время сборки (нулевое для словарей версий до 10).
+	SourceRevision string    `json:"source_revision,omitempty"` // Ревизия исходного корпуса, если ее задали при сборке.
+	Words          int64     `json:"words"`                     // Число словоформ.
+	Lemmas         int64     `json:"lemmas"`                    // Число лемм.
+	Paradigms      int       `json:"paradigms"`                 // Число парадигм (лексем).
+	TagSets        int       `json:"tag_sets"`                  // Число различных наборов тегов.
+	FileSize       int64     `json:"file_size"`                 // Размер файла словаря в байтах.
+	LoadMode       LoadMode  `json:"load_mode"`                 // Способ загрузки.
+}
+
+// Info возвращает сведения о загруженном словаре. Словари версий до 10 не хранят число
+// словоформ, и при первом вызове оно подсчитывается обходом DAWG.
+func (a *MorphAnalyzer) Info() DictInfo {
+	info := a.info
+	if info.Words == 0 {
+		a.wordsOnce.Do(func() { a.words = countWords(a.nodes, a.edges) })
+		info.Words = a.words
+	}
+	info.Paradigms = a.paradigms.len()
+	info.TagSets = a.tags.len()
+	if info.FileSize == 0 {
+		info.FileSize = int64(len(a.data))
+	}
+	return info
+}
+
+// SetSourceRevision задает ревизию исходного корпуса (например, ревизию дампа OpenCorpora),
+// которая будет записана в заголовок словаря. Длина ревизии - не больше 64 байт.
+func (b *DictBuilder) SetSourceRevision(revision string) error {
+	if len(revision) > maxSourceRevision {
+		return fmt.Errorf("ревизия корпуса длиннее %d байт: %q", maxSourceRevision, revision)
+	}
+	b.sourceRevision = revision
+	return nil
+}
+
+// SetBuildTime задает время сборки, записываемое в заголовок словаря, вместо текущего:
+// так повторная сборка из того же лексикона дает побайтно тот же заголовок.
+func (b *DictBuilder) SetBuildTime(t time.Time) {
+	b.buildTime = t
+}
+
+// newDictInfo собирает сведения о словаре из его заголовка.
+func newDictInfo(header *Header) DictInfo {
+	info := DictInfo{
+		Version:        int(header.Version),
+		SourceRevision: cString(header.SourceRevision[:]),
+		Words:          header.WordCount,
+		Lemmas:         header.LemmaCount,
+		LoadMode:       LoadModeMemory,
+	}
+	if header.BuildTime != 0 {
+		info.BuildTime = time.Unix(header.BuildTime, 0).UTC()
+	}
+	return info
+}
+
+// cString возвращает строку из буфера фиксированной длины, дополненного нулями.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+// countWords возвращает число слов DAWG: количество путей от корня до конечных узлов.
+func countWords(nodes []FlatNode, edges []FlatEdge) int64 {
+	if len(nodes) == 0 {
+		return 0
+	}
+	counts := make([]int64, len(nodes))
+	done := make([]bool, len(nodes))
+	var count func(id uint32) int64
+	count = func(id uint32) int64 {
+		if done[id] {
+			return counts[id]
+		}
+		node := nodes[id]
+		var n int64
+		if node.IsFinal {
+			n = 1
+		}
+		for _, edge := range edges[node.EdgesIdx : node.EdgesIdx+uint32(node.EdgesLen)] {
+			n += count(edge.NodeID)
+		}
+		counts[id], done[id] = n, true
+		return n
+	}
+	return count(0)
+}
@@ -0,0 +1,466 @@
+// disambiguate.go содержит снятие морфологической неоднозначности по контексту:
+// биграммную модель переходов между частями речи (HMM) и выбор разборов алгоритмом Витерби
+// ("мы стали" - глагол, "из стали" - существительное).
+package analyzer
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// sentenceStartTag - состояние модели в начале предложения.
+	sentenceStartTag = "^"
+	// punctuationTag - состояние модели для знаков препинания внутри предложения.
+	punctuationTag = "Пунктуация"
+	// wordPrefix отмечает в файле модели строки частот частей речи слова.
+	wordPrefix = "слово:"
+	// emissionSmoothing - прибавка к частотам частей речи слова. Она меньше единицы:
+	// даже одно употребление слова в корпусе должно перевешивать частые переходы.
+	emissionSmoothing = 0.1
+)
+
+// defaultTagModelData - модель переходов, поставляемая вместе со словарем.
+//
+//go:embed tagmodel.tsv
+var defaultTagModelData string
+
+// defaultTagModel разбирает встроенную модель при первом обращении.
+var defaultTagModel = sync.OnceValue(func() *TagModel {
+	m, err := ReadTagModel(strings.NewReader(defaultTagModelData))
+	if err != nil {
+		panic(fmt.Sprintf("steosmorphy: поврежденная встроенная модель переходов: %v", err))
+	}
+	return m
+})
+
+// prepositionCases - падежи, которыми управляют предлоги. После предлога из разборов
+// существительного (и согласованных с ним слов) выбирается разбор в одном из этих падежей.
+var prepositionCases = map[string][]string{
+	"без": {"Родительный", "Партитивный"}, "безо": {"Родительный"},
+	"в": {"Винительный", "Предложный", "Местный"}, "во": {"Винительный", "Предложный", "Местный"},
+	"для": {"Родительный"}, "до": {"Родительный"}, "за": {"Винительный", "Творительный"},
+	"из": {"Родительный", "Партитивный"}, "изо": {"Родительный"}, "из-за": {"Родительный"},
+	"из-под": {"Родительный"}, "к": {"Дательный"}, "ко": {"Дательный"},
+	"на": {"Винительный", "Предложный", "Местный"}, "над": {"Творительный"}, "надо": {"Творительный"},
+	"о": {"Винительный", "Предложный"}, "об": {"Винительный", "Предложный"}, "обо": {"Винительный", "Предложный"},
+	"от": {"Родительный"}, "ото": {"Родительный"}, "перед": {"Творительный"}, "передо": {"Творительный"},
+	"по": {"Дательный", "Винительный", "Предложный"}, "под": {"Винительный", "Творительный"},
+	"подо": {"Винительный", "Творительный"}, "при": {"Предложный"}, "про": {"Винительный"},
+	"ради": {"Родительный"}, "с": {"Родительный", "Винительный", "Творительный", "Партитивный"},
+	"со": {"Родительный", "Винительный", "Творительный"}, "у": {"Родительный"}, "через": {"Винительный"},
+	"после": {"Родительный"}, "около": {"Родительный"}, "среди": {"Родительный"}, "кроме": {"Родительный"},
+	"между": {"Творительный", "Родительный"}, "сквозь": {"Винительный"}, "благодаря": {"Дательный"},
+	"вопреки": {"Дательный"}, "согласно": {"Дательный"},
+}
+
+// governedTags - части речи, которые согласуются с существительным в падеже
+// и не прерывают управление предлога ("в [новом] доме").
+var governedTags = GrammemeSet{
+	"Прилагательное": {},
+	"Причастие":      {},
+	"Числительное":   {},
+	"Местоимение":    {},
+}
+
+// TagModel - биграммная модель переходов между частями речи для Disambiguate.
+// Вероятности переходов оцениваются по частотам со сглаживанием Лапласа,
+// поэтому неизвестные переходы маловероятны, но не запрещены. Модель, обученная
+// через AddTaggedSentence, помнит и части речи слов корпуса: для таких слов
+// вероятность выхода берется по этим частотам.
+type TagModel struct {
+	counts map[string]map[string]float64 // Частоты переходов: предыдущая часть речи -> следующая.
+	totals map[string]float64            // Суммарная частота переходов из каждой части речи.
+	tags   map[string]struct{}           // Все части речи, встретившиеся как следующие.
+	words  map[string]map[string]float64 // Частоты частей речи слов корпуса (в нижнем регистре).
+}
+
+// NewTagModel создает пустую модель для обучения через AddSentence или AddTaggedSentence.
+func NewTagModel() *TagModel {
+	return &TagModel{
+		counts: make(map[string]map[string]float64),
+		totals: make(map[string]float64),
+		tags:   make(map[string]struct{}),
+		words:  make(map[string]map[string]float64),
+	}
+}
+
+// ReadTagModel читает модель в формате "предыдущая<TAB>следующая<TAB>частота".
+// Начало предложения обозначается "^", знаки препинания - "Пунктуация".
+// Строки "слово:кот<TAB>часть речи<TAB>частота" задают частоты частей речи слова.
+// Пустые строки и строки, начинающиеся с '#', пропускаются.
+func ReadTagModel(r io.Reader) (*TagModel, error) {
+	m := NewTagModel()
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("строка %d: ожидалось 3 поля, получено %d", lineNum, len(fields))
+		}
+		count, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("строка %d: неверная частота %q", lineNum, fields[2])
+		}
+		if word, ok := strings.CutPrefix(fields[0], wordPrefix); ok {
+			m.addWord(word, fields[1], count)
+			continue
+		}
+		m.add(fields[0], fields[1], count)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения модели переходов: %w", err)
+	}
+	return m, nil
+}
+
+// AddSentence учитывает в модели одно предложение размеченного корпуса:
+// последовательность частей речи его слов (знаки препинания - "Пунктуация").
+func (m *TagModel) AddSentence(tags []string) {
+	prev := sentenceStartTag
+	for _, tag := range tags {
+		m.add(prev, tag, 1)
+		prev = tag
+	}
+}
+
+// AddTaggedSentence учитывает в модели предложение вместе с его словами: кроме
+// переходов (см. AddSentence) запоминаются части речи слов, которые Disambiguate
+// использует для тех же слов в новом тексте. words и tags выровнены по индексам.
+func (m *TagModel) AddTaggedSentence(words, tags []string) {
+	m.AddSentence(tags)
+	for i, tag := range tags {
+		if tag != punctuationTag {
+			m.addWord(words[i], tag, 1)
+		}
+	}
+}
+
+// ReadTaggedCorpus читает размеченный корпус со снятой неоднозначностью и вызывает fn
+// с токенами и частями речи каждого предложения (обычно fn - AddTaggedSentence модели).
+// Формат строки: "токен<TAB>часть речи", предложения разделяются пустой строкой;
+// знаки препинания размечаются "Пунктуация", числа - "Числительное". Как и в Disambiguate,
+// предложение заканчивается и на знаке конца предложения, который в модель не попадает.
+// Строки, начинающиеся с '#', пропускаются.
+func ReadTaggedCorpus(r io.Reader, fn func(words, tags []string)) error {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	var words, tags []string
+	flush := func() {
+		if len(tags) > 0 {
+			fn(words, tags)
+			words, tags = nil, nil
+		}
+	}
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "" {
+			flush()
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			return fmt.Errorf("строка %d: ожидалось 2 поля, получено %d", lineNum, len(fields))
+		}
+		tag := fields[1]
+		if tag != punctuationTag && !inMap(tag, posTags) {
+			return fmt.Errorf("строка %d: неизвестная часть речи %q", lineNum, tag)
+		}
+		if tag == punctuationTag && isSentenceEnd(fields[0]) {
+			flush()
+			continue
+		}
+		words = append(words, fields[0])
+		tags = append(tags, tag)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения корпуса: %w", err)
+	}
+	flush()
+	return nil
+}
+
+// WriteTo записывает модель в формате, который читает ReadTagModel.
+func (m *TagModel) WriteTo(w io.Writer) (int64, error) {
+	prevs := make([]string, 0, len(m.counts))
+	for prev := range m.counts {
+		prevs = append(prevs, prev)
+	}
+	sort.Strings(prevs)
+
+	words := make([]string, 0, len(m.words))
+	for word := range m.words {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	bw := bufio.NewWriter(w)
+	var written int64
+	write := func(first string, counts map[string]float64) error {
+		nexts := make([]string, 0, len(counts))
+		for next := range counts {
+			nexts = append(nexts, next)
+		}
+		sort.Strings(nexts)
+		for _, next := range nexts {
+			n, err := fmt.Fprintf(bw, "%s\t%s\t%s\n", first, next, strconv.FormatFloat(counts[next], 'f', -1, 64))
+			written += int64(n)
+			if err != nil {
+				return fmt.Errorf("ошибка записи модели переходов: %w", err)
+			}
+		}
+		return nil
+	}
+	for _, prev := range prevs {
+		if err := write(prev, m.counts[prev]); err != nil {
+			return written, err
+		}
+	}
+	for _, word := range words {
+		if err := write(wordPrefix+word, m.words[word]); err != nil {
+			return written, err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return written, fmt.Errorf("ошибка записи модели переходов: %w", err)
+	}
+	return written, nil
+}
+
+// add увеличивает частоту перехода prev -> next.
+func (m *TagModel) add(prev, next string, count float64) {
+	if m.counts[prev] == nil {
+		m.counts[prev] = make(map[string]float64)
+	}
+	m.counts[prev][next] += count
+	m.totals[prev] += count
+	m.tags[next] = struct{}{}
+}
+
+// addWord увеличивает частоту части речи tag у слова word.
+func (m *TagModel) addWord(word, tag string, count float64) {
+	word = strings.ToLower(word)
+	if m.words[word] == nil {
+		m.words[word] = make(map[string]float64)
+	}
+	m.words[word][tag] += count
+}
+
+// logEmission возвращает логарифм вероятности того, что слово word из токена
+// с возможными частями речи states имеет часть речи state. Частоты частей речи слова
+// сглаживаются прибавлением emissionSmoothing; для слов, которых не было в корпусе,
+// вероятность одинакова для всех states.
+func (m *TagModel) logEmission(word string, states []string, state string) float64 {
+	counts := m.words[strings.ToLower(word)]
+	var total float64
+	for _, s := range states {
+		total += counts[s]
+	}
+	return math.Log((counts[state] + emissionSmoothing) / (total + emissionSmoothing*float64(len(states))))
+}
+
+// logProb возвращает логарифм сглаженной вероятности перехода prev -> next.
+func (m *TagModel) logProb(prev, next string) float64 {
+	return math.Log((m.counts[prev][next] + 1) / (m.totals[prev] + float64(len(m.tags)) + 1))
+}
+
+// WithTagModel задает модель переходов для Disambiguate и AnalyzeText
+// вместо встроенной (например, обученную по своему корпусу через AddSentence).
+func WithTagModel(m *TagModel) Option {
+	return func(a *MorphAnalyzer) {
+		a.tagModel = m
+	}
+}
+
+// Disambiguate выбирает для каждого токена текста (см. LemmatizeText) один разбор
+// с учетом соседних слов: по модели переходов между частями речи находится наиболее
+// вероятная последовательность частей речи, а после предлога из разборов выбирается
+// управляемый им падеж. Результат выровнен по индексам с tokens: для чисел, знаков
+// препинания и слов без разборов элемент равен nil.
+// Токены должны идти подряд, как их возвращает LemmatizeText с KeepPunctuation.
+// Разборы токенов могут быть переведены на любой язык (см. WithLang): возвращаются
+// элементы tokens[i].Parses.
+func (a *MorphAnalyzer) Disambiguate(tokens []Token) []*Parsed {
+	if a.lang == LangRussian {
+		return a.disambiguate(tokens)
+	}
+
+	// Модель и управление предлогов описаны русскими граммемами: выбираем разбор
+	// среди переведенных на русский копий и возвращаем исходный по индексу.
+	canonical := make([]Token, len(tokens))
+	for i, tok := range tokens {
+		canonical[i] = tok
+		if len(tok.Parses) > 0 {
+			canonical[i].Parses = make([]*Parsed, len(tok.Parses))
+			for j, p := range tok.Parses {
+				canonical[i].Parses[j] = p.Localize(LangRussian)
+			}
+		}
+	}
+	result := a.disambiguate(canonical)
+	for i, p := range result {
+		if p != nil {
+			result[i] = tokens[i].Parses[slices.Index(canonical[i].Parses, p)]
+		}
+	}
+	return result
+}
+
+// disambiguate - Disambiguate для разборов с русскими граммемами.
+func (a *MorphAnalyzer) disambiguate(tokens []Token) []*Parsed {
+	model := a.tagModel
+	if model == nil {
+		model = defaultTagModel()
+	}
+
+	result := make([]*Parsed, len(tokens))
+	start := 0
+	for i, tok := range tokens {
+		if tok.Kind == TokenPunctuation && isSentenceEnd(tok.Text) {
+			a.disambiguateSentence(model, tokens[start:i], result[start:i])
+			start = i + 1
+		}
+	}
+	a.disambiguateSentence(model, tokens[start:], result[start:])
+	return result
+}
+
+// isSentenceEnd сообщает, что знак препинания завершает предложение.
+func isSentenceEnd(punct string) bool {
+	return strings.ContainsAny(punct, ".!?…")
+}
+
+// tokenStates возвращает возможные состояния модели для токена: различные части речи
+// его разборов в порядке первого появления.
+func tokenStates(tok Token) []string {
+	switch {
+	case tok.Kind == TokenPunctuation:
+		return []string{punctuationTag}
+	case tok.Kind == TokenNumber:
+		return []string{"Числительное"}
+	case len(tok.Parses) == 0:
+		// Неизвестное модели состояние: переходы из него и в него равновероятны.
+		return []string{""}
+	}
+	var states []string
+	for _, p := range tok.Parses {
+		for _, pos := range partsOfSpeech(p) {
+			if !slices.Contains(states, pos) {
+				states = append(states, pos)
+			}
+		}
+	}
+	return states
+}
+
+// partsOfSpeech возвращает все части речи разбора: у служебных слов словарь хранит
+// несколько частей речи в одном наборе тегов ("а" - "Частица,Союз,Междометие").
+func partsOfSpeech(p *Parsed) []string {
+	var result []string
+	for _, tag := range strings.Split(p.Tags, ",") {
+		if inMap(tag, posTags) {
+			result = append(result, tag)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, p.PartOfSpeech)
+	}
+	return result
+}
+
+// disambiguateSentence выбирает разборы токенов одного предложения алгоритмом Витерби.
+// Вероятность выхода берется по частотам частей речи слова в корпусе модели, а для
+// остальных слов считается равной для всех их частей речи: выбор определяется
+// переходами, а не числом разборов каждой части речи.
+func (a *MorphAnalyzer) disambiguateSentence(model *TagModel, tokens []Token, result []*Parsed) {
+	if len(tokens) == 0 {
+		return
+	}
+
+	states := make([][]string, len(tokens))
+	scores := make([][]float64, len(tokens))
+	back := make([][]int, len(tokens))
+	for i, tok := range tokens {
+		states[i] = tokenStates(tok)
+		scores[i] = make([]float64, len(states[i]))
+		back[i] = make([]int, len(states[i]))
+		for j, state := range states[i] {
+			emission := model.logEmission(tok.Text, states[i], state)
+			if i == 0 {
+				scores[i][j] = model.logProb(sentenceStartTag, state) + emission
+				continue
+			}
+			best := math.Inf(-1)
+			for k, prev := range states[i-1] {
+				if score := scores[i-1][k] + model.logProb(prev, state); score > best {
+					best, back[i][j] = score, k
+				}
+			}
+			scores[i][j] = best + emission
+		}
+	}
+
+	// Восстанавливаем лучшую последовательность с конца.
+	path := make([]int, len(tokens))
+	last := len(tokens) - 1
+	for j := range scores[last] {
+		if scores[last][j] > scores[last][path[last]] {
+			path[last] = j
+		}
+	}
+	for i := last; i > 0; i-- {
+		path[i-1] = back[i][path[i]]
+	}
+
+	// Выбираем разбор выбранной части речи, учитывая управление предлога.
+	var governed []string
+	for i, tok := range tokens {
+		if tok.Kind != TokenWord || len(tok.Parses) == 0 {
+			governed = nil
+			continue
+		}
+		pos := states[i][path[i]]
+		result[i] = pickParse(tok.Parses, pos, governed)
+		switch {
+		case pos == "Предлог":
+			governed = prepositionCases[strings.ToLower(tok.Text)]
+		case !inMap(pos, governedTags):
+			governed = nil
+		}
+	}
+}
+
+// pickParse возвращает первый разбор части речи pos, а если задан список падежей -
+// первый разбор этой части речи в одном из них.
+func pickParse(parses []*Parsed, pos string, cases []string) *Parsed {
+	var first *Parsed
+	for _, p := range parses {
+		if !slices.Contains(partsOfSpeech(p), pos) {
+			continue
+		}
+		if len(cases) == 0 || slices.Contains(cases, p.Details().Case) {
+			return p
+		}
+		if first == nil {
+			first = p
+		}
+	}
+	return first
+}
@@ -0,0 +1,206 @@
+// download.go содержит менеджер загрузки словаря из релизов проекта.
+// Вместо того чтобы поставлять многочастный словарь вместе с приложением,
+// его можно скачать при первом запуске: EnsureDict докачивает прерванные загрузки
+// и проверяет контрольную сумму SHA-256 перед тем, как положить файл на место.
+package analyzer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DictReleaseURL - базовый адрес релизов, из которых скачивается словарь.
+// Файлы ищутся по адресам "<DictReleaseURL>/<версия>/morph.dawg" и ".../morph.dawg.sha256".
+// Переменную можно переопределить, например, для внутреннего зеркала.
+var DictReleaseURL = "https://github.com/SteosOfficial/SteosMorphy/releases/download"
+
+// EnsureDict гарантирует, что в директории dir лежит словарь указанной версии, и возвращает путь к нему.
+// Если файл уже есть и его контрольная сумма совпадает с опубликованной, ничего не скачивается.
+// Прерванная загрузка продолжается с места остановки (файл "morph.dawg.part").
+// Если dir пуст, используется DefaultDictDir - там словарь найдет LoadMorphAnalyzer.
+//
+// Поддерживается только релиз с одним файлом словаря (morph.dawg и morph.dawg.sha256):
+// части "morph_aa", "morph_ab", ... не скачиваются. Такой релиз собирается из частей
+// функцией MergeDictParts.
+func EnsureDict(ctx context.Context, version, dir string) (string, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDictDir(); err != nil {
+			return "", err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("ошибка создания директории словаря: %w", err)
+	}
+
+	baseURL := strings.TrimSuffix(DictReleaseURL, "/") + "/" + url.PathEscape(version) + "/"
+	expectedSum, err := fetchChecksum(ctx, baseURL+DictFileName+".sha256")
+	if err != nil {
+		return "", err
+	}
+
+	// Словарь уже на месте и совпадает с релизом.
+	target := filepath.Join(dir, DictFileName)
+	if sum, err := fileSHA256(target); err == nil && sum == expectedSum {
+		pkgLogger().Debug("словарь уже скачан", "version", version, "path", target)
+		return target, nil
+	}
+
+	pkgLogger().Info("скачивание словаря", "version", version, "url", baseURL+DictFileName)
+
+	partPath := target + ".part"
+	if err := downloadResumable(ctx, baseURL+DictFileName, partPath); err != nil {
+		return "", err
+	}
+
+	sum, err := fileSHA256(partPath)
+	if err != nil {
+		return "", err
+	}
+	if sum != expectedSum {
+		// Поврежденную загрузку удаляем, чтобы следующая попытка начала с нуля.
+		_ = os.Remove(partPath)
+		return "", fmt.Errorf("контрольная сумма словаря не совпадает: ожидали %s, получили %s", expectedSum, sum)
+	}
+
+	if err := os.Rename(partPath, target); err != nil {
+		return "", fmt.Errorf("ошибка перемещения словаря в %s: %w", target, err)
+	}
+	pkgLogger().Info("словарь скачан", "version", version, "path", target)
+	return target, nil
+}
+
+// fetchChecksum скачивает файл с контрольной суммой в формате sha256sum ("<hex>  <имя файла>").
+func fetchChecksum(ctx context.Context, checksumURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ошибка загрузки контрольной суммы: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ошибка загрузки контрольной суммы %s: %s", checksumURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", fmt.Errorf("ошибка чтения контрольной суммы: %w", err)
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", errors.New("файл контрольной суммы пуст")
+	}
+	sum := strings.ToLower(fields[0])
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("некорректная контрольная сумма: %q", fields[0])
+	}
+	return sum, nil
+}
+
+// downloadResumable скачивает файл в partPath. Если partPath уже частично скачан,
+// запрашивает у сервера только недостающий диапазон байт (HTTP Range).
+func downloadResumable(ctx context.Context, fileURL, partPath string) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки словаря: %w", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, ok := contentRangeStart(resp.Header.Get("Content-Range"))
+		switch {
+		case ok && start == offset:
+			// Сервер поддерживает докачку: дописываем в конец.
+			flags |= os.O_APPEND
+		case ok && start == 0:
+			// Сервер отдал файл с начала: начинаем заново.
+			flags |= os.O_TRUNC
+		case offset > 0:
+			// Дописанный не с того места диапазон испортил бы файл: скачиваем его заново.
+			pkgLogger().Warn("сервер вернул не запрошенный диапазон, загрузка начнется заново",
+				"offset", offset, "content_range", resp.Header.Get("Content-Range"))
+			resp.Body.Close()
+			if err := os.Remove(partPath); err != nil {
+				return fmt.Errorf("ошибка удаления файла %s: %w", partPath, err)
+			}
+			return downloadResumable(ctx, fileURL, partPath)
+		default:
+			return fmt.Errorf("ошибка загрузки словаря %s: неожиданный диапазон %q", fileURL, resp.Header.Get("Content-Range"))
+		}
+	case http.StatusOK:
+		// Сервер отдал файл целиком: начинаем заново.
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Файл уже скачан полностью, дальше его проверит контрольная сумма.
+		return nil
+	default:
+		return fmt.Errorf("ошибка загрузки словаря %s: %s", fileURL, resp.Status)
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла %s: %w", partPath, err)
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return fmt.Errorf("загрузка словаря прервана: %w", err)
+	}
+	return out.Close()
+}
+
+// contentRangeStart возвращает начало диапазона из заголовка Content-Range ("bytes 100-199/200").
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil && n >= 0
+}
+
+// fileSHA256 считает контрольную сумму SHA-256 файла в шестнадцатеричном виде.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("ошибка чтения %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
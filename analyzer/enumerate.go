@@ -0,0 +1,59 @@
+// enumerate.go содержит перебор содержимого словаря: всех лемм и всех словоформ
+// с разборами. Нужен для аудита словаря, экспорта и построения производных ресурсов.
+package analyzer
+
+import (
+	"iter"
+)
+
+// WordEntry - словоформа словаря с одним из ее разборов.
+type WordEntry struct {
+	Word  string `json:"word"`
+	Lemma string `json:"lemma"`
+	Tags  string `json:"tags"` // Теги словаря (русские граммемы, независимо от WithLang).
+}
+
+// Lemmas возвращает все леммы словаря, каждую один раз, в порядке словаря.
+func (a *MorphAnalyzer) Lemmas() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for i := range uint32(a.lemmas.len()) {
+			if !yield(a.lemmas.at(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Words возвращает все словоформы словаря по алфавиту обходом DAWG; омонимичная
+// словоформа выдается отдельно для каждого разбора. В памяти хранится только путь
+// к текущему узлу, поэтому перебор не требует памяти на весь словарь и может быть
+// прерван в любой момент. Результат можно записать лексиконом TSV для компилятора.
+func (a *MorphAnalyzer) Words() iter.Seq[WordEntry] {
+	return func(yield func(WordEntry) bool) {
+		if len(a.nodes) == 0 {
+			return
+		}
+		path := make([]rune, 0, 32)
+		var walk func(nodeIndex uint32) bool
+		walk = func(nodeIndex uint32) bool {
+			node := a.nodes[nodeIndex]
+			if node.IsFinal {
+				word := string(path)
+				for _, info := range a.payloads[node.PayloadIdx : node.PayloadIdx+uint32(node.PayloadLen)] {
+					if !yield(WordEntry{Word: word, Lemma: a.lemmas.at(info.LemmaID), Tags: a.tags.at(info.TagsID)}) {
+						return false
+					}
+				}
+			}
+			for _, edge := range a.edges[node.EdgesIdx : node.EdgesIdx+uint32(node.EdgesLen)] {
+				path = append(path, edge.Char)
+				if !walk(edge.NodeID) {
+					return false
+				}
+				path = path[:len(path)-1]
+			}
+			return true
+		}
+		walk(0)
+	}
+}
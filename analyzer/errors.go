@@ -0,0 +1,106 @@
+// errors.go содержит ошибки-метки анализатора и варианты основных методов, возвращающие
+// ошибку: nil-результат Parse и Analyze не отличает несловарное слово от поврежденного
+// словаря, а сервисам нужно по-разному обрабатывать эти случаи.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrNotFound возвращается, если слово не удалось разобрать.
+	ErrNotFound = errors.New("слово не найдено")
+	// ErrDictCorrupted возвращается при загрузке и разборе, если файл словаря поврежден
+	// или собран несовместимой версией компилятора.
+	ErrDictCorrupted = errors.New("словарь поврежден")
+	// ErrDictVersion возвращается при загрузке словаря слишком старой или слишком новой
+	// для этой версии анализатора версии формата.
+	ErrDictVersion = errors.New("неподдерживаемая версия формата словаря")
+	// ErrDictNotFound возвращается LoadMorphAnalyzer, если словарь не найден ни одним
+	// источником: переменной окружения, RegisterDictLocator и DefaultDictDir.
+	ErrDictNotFound = errors.New("словарь не найден")
+)
+
+// ParseE - вариант Parse, возвращающий ErrNotFound для слова, которого нет в словаре,
+// и ErrDictCorrupted, если при обходе словаря встретились недопустимые данные.
+func (a *MorphAnalyzer) ParseE(word string) ([]*Parsed, error) {
+	var parses []*Parsed
+	err := guardDict(func() { parses = a.Parse(word) })
+	if err == nil && len(parses) == 0 {
+		err = fmt.Errorf("%w: %q", ErrNotFound, word)
+	}
+	return parses, err
+}
+
+// AnalyzeE - вариант Analyze, возвращающий ErrNotFound для слова, которое не разобрало
+// ни одно звено цепочки, и ErrDictCorrupted, если при обходе словаря встретились
+// недопустимые данные.
+func (a *MorphAnalyzer) AnalyzeE(word string) ([]*Parsed, []*Parsed, error) {
+	var parses, forms []*Parsed
+	err := guardDict(func() { parses, forms = a.Analyze(word) })
+	if err == nil && len(parses) == 0 {
+		err = fmt.Errorf("%w: %q", ErrNotFound, word)
+	}
+	return parses, forms, err
+}
+
+// InflectE - вариант Inflect, возвращающий ErrNotFound для слова, которого нет в словаре,
+// и ErrDictCorrupted, если при обходе словаря встретились недопустимые данные.
+func (a *MorphAnalyzer) InflectE(word string) ([]*Parsed, error) {
+	var forms []*Parsed
+	err := guardDict(func() { forms = a.Inflect(word) })
+	if err == nil && len(forms) == 0 {
+		err = fmt.Errorf("%w: %q", ErrNotFound, word)
+	}
+	return forms, err
+}
+
+// guardDict вызывает fn и превращает выход за границы массива в коде анализатора
+// (поврежденный словарь может содержать индексы за пределами массивов) в ошибку
+// ErrDictCorrupted. Остальные паники, в том числе из пользовательских кэшей,
+// перехватчиков и звеньев, не перехватываются.
+func guardDict(fn func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if !isDictAccessPanic(r) {
+			panic(r)
+		}
+		err = fmt.Errorf("%w: %v", ErrDictCorrupted, r)
+	}()
+	fn()
+	return nil
+}
+
+// analyzerFuncPrefix - префикс имен функций пакета в стеке вызовов.
+var analyzerFuncPrefix = reflect.TypeOf(MorphAnalyzer{}).PkgPath() + "."
+
+// isDictAccessPanic сообщает, что паника r - выход индекса или среза за границы,
+// возникший в функции этого пакета. Вызывается из отложенной функции guardDict:
+// первая функция стека не из пакета runtime - та, в которой возникла паника.
+func isDictAccessPanic(r any) bool {
+	re, ok := r.(runtime.Error)
+	if !ok {
+		return false
+	}
+	if msg := re.Error(); !strings.Contains(msg, "index out of range") && !strings.Contains(msg, "slice bounds out of range") {
+		return false
+	}
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			return strings.HasPrefix(frame.Function, analyzerFuncPrefix)
+		}
+		if !more {
+			return false
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// extsort.go содержит генерацию словоформ с внешней сортировкой: когда результат
+// InflectList не помещается в память, отсортированные порции сбрасываются во временные
+// файлы и затем сливаются в один упорядоченный поток.
+package analyzer
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// spillBufferSize - размер буфера чтения и записи одного временного файла.
+const spillBufferSize = 64 * 1024
+
+// InflectListSorted генерирует словоформы для среза слов и возвращает их итератором
+// в том же порядке, что и InflectList, не удерживая весь результат в памяти.
+// Как только сгенерированные формы занимают больше memLimit байт, они сортируются
+// и сбрасываются во временный файл в tempDir ("" - os.TempDir()); итератор сливает
+// файлы и остаток в памяти. Порядок детерминирован: формы с одинаковым словом
+// упорядочиваются по лемме и тегам.
+//
+// Генерация начинается при первом обходе итератора. Ошибка ввода-вывода выдается
+// вместе с nil и завершает обход; временные файлы удаляются по окончании обхода,
+// в том числе досрочном.
+func (a *MorphAnalyzer) InflectListSorted(words []string, memLimit int64, tempDir string) iter.Seq2[*Parsed, error] {
+	return func(yield func(*Parsed, error) bool) {
+		dir, err := os.MkdirTemp(tempDir, "steosmorphy-inflect-*")
+		if err != nil {
+			yield(nil, fmt.Errorf("ошибка создания временной директории: %w", err))
+			return
+		}
+		defer os.RemoveAll(dir)
+
+		var runs []string
+		var pending []*Parsed
+		var pendingSize int64
+		err = a.inflectListFunc(words, func(forms []*Parsed) error {
+			pending = append(pending, forms...)
+			pendingSize += parsedSize(forms)
+			if pendingSize < memLimit {
+				return nil
+			}
+			path := filepath.Join(dir, fmt.Sprintf("run-%06d", len(runs)))
+			if err := a.writeRun(path, pending); err != nil {
+				return err
+			}
+			runs = append(runs, path)
+			pending, pendingSize = nil, 0
+			return nil
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		slices.SortFunc(pending, a.compareForms)
+		if len(runs) == 0 {
+			for _, p := range pending {
+				if !yield(a.localizeOne(p), nil) {
+					return
+				}
+			}
+			return
+		}
+		a.mergeRuns(runs, pending, yield)
+	}
+}
+
+// writeRun сортирует формы и записывает их во временный файл.
+// Сохраняются только слово, лемма и теги: остальные поля восстанавливаются по тегам.
+func (a *MorphAnalyzer) writeRun(path string, forms []*Parsed) error {
+	slices.SortFunc(forms, a.compareForms)
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ошибка создания временного файла: %w", err)
+	}
+	w := bufio.NewWriterSize(f, spillBufferSize)
+	var buf []byte
+	for _, p := range forms {
+		buf = buf[:0]
+		for _, s := range [...]string{p.Word, p.Lemma, p.Tags} {
+			buf = binary.AppendUvarint(buf, uint64(len(s)))
+			buf = append(buf, s...)
+		}
+		if _, err := w.Write(buf); err != nil {
+			f.Close()
+			return fmt.Errorf("ошибка записи временного файла: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("ошибка записи временного файла: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("ошибка записи временного файла: %w", err)
+	}
+	return nil
+}
+
+// runReader последовательно читает формы из временного файла или из отсортированного среза.
+type runReader struct {
+	file    *os.File
+	r       *bufio.Reader
+	forms   []*Parsed // Остаток в памяти (если file == nil).
+	current *Parsed
+}
+
+// next читает следующую форму в current; по окончании данных current становится nil.
+func (rr *runReader) next() error {
+	if rr.file == nil {
+		rr.current = nil
+		if len(rr.forms) > 0 {
+			rr.current, rr.forms = rr.forms[0], rr.forms[1:]
+		}
+		return nil
+	}
+	var fields [3]string
+	for i := range fields {
+		n, err := binary.ReadUvarint(rr.r)
+		if i == 0 && errors.Is(err, io.EOF) {
+			rr.current = nil
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка чтения временного файла: %w", err)
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(rr.r, b); err != nil {
+			return fmt.Errorf("ошибка чтения временного файла: %w", err)
+		}
+		fields[i] = string(b)
+	}
+	rr.current = newParsed(fields[0], fields[1], fields[2])
+	return nil
+}
+
+// runHeap - куча источников слияния, упорядоченная по их текущим формам.
+type runHeap struct {
+	readers []*runReader
+	less    func(x, y *Parsed) int
+}
+
+func (h *runHeap) Len() int { return len(h.readers) }
+func (h *runHeap) Less(i, j int) bool {
+	return h.less(h.readers[i].current, h.readers[j].current) < 0
+}
+func (h *runHeap) Swap(i, j int) { h.readers[i], h.readers[j] = h.readers[j], h.readers[i] }
+func (h *runHeap) Push(x any)    { h.readers = append(h.readers, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	last := h.readers[len(h.readers)-1]
+	h.readers = h.readers[:len(h.readers)-1]
+	return last
+}
+
+// mergeRuns сливает временные файлы и остаток в памяти и передает формы в yield по порядку.
+func (a *MorphAnalyzer) mergeRuns(runs []string, pending []*Parsed, yield func(*Parsed, error) bool) {
+	sources := make([]*runReader, 0, len(runs)+1)
+	defer func() {
+		for _, rr := range sources {
+			if rr.file != nil {
+				rr.file.Close()
+			}
+		}
+	}()
+	for _, path := range runs {
+		f, err := os.Open(path)
+		if err != nil {
+			yield(nil, fmt.Errorf("ошибка открытия временного файла: %w", err))
+			return
+		}
+		sources = append(sources, &runReader{file: f, r: bufio.NewReaderSize(f, spillBufferSize)})
+	}
+	sources = append(sources, &runReader{forms: pending})
+
+	h := &runHeap{less: a.compareForms}
+	for _, rr := range sources {
+		if err := rr.next(); err != nil {
+			yield(nil, err)
+			return
+		}
+		if rr.current != nil {
+			h.readers = append(h.readers, rr)
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		rr := h.readers[0]
+		if !yield(a.localizeOne(rr.current), nil) {
+			return
+		}
+		if err := rr.next(); err != nil {
+			yield(nil, err)
+			return
+		}
+		if rr.current != nil {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+}
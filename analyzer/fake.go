@@ -0,0 +1,105 @@
+// fake.go содержит интерфейс Morph основных методов анализатора и его заглушку
+// FakeAnalyzer с заранее заданными разборами: код, которому нужен разбор слов,
+// может зависеть от Morph и подменять анализатор в тестах.
+package analyzer
+
+import "strings"
+
+// Morph - основные методы морфологического анализатора. Его реализует *MorphAnalyzer,
+// а для тестов - FakeAnalyzer.
+type Morph interface {
+	Parse(word string) []*Parsed
+	Inflect(word string) []*Parsed
+	Analyze(word string) ([]*Parsed, []*Parsed)
+	Lemmatize(word string) []string
+}
+
+var (
+	_ Morph = (*MorphAnalyzer)(nil)
+	_ Morph = (*FakeAnalyzer)(nil)
+)
+
+// FakeAnalyzer - заглушка анализатора для тестов: разбирает только слова лексикона Entries,
+// без словаря и предсказателя. Словоформы слова - все записи с той же леммой и частью речи
+// (или тем же Lexeme, если он задан). Слова сравниваются без учета регистра.
+// Нулевое значение - пустой анализатор, не разбирающий ни одного слова.
+// Безопасен для параллельного использования, пока Entries не изменяется.
+type FakeAnalyzer struct {
+	Entries []LexEntry
+}
+
+// NewFakeAnalyzer создает заглушку анализатора с лексиконом entries.
+func NewFakeAnalyzer(entries ...LexEntry) *FakeAnalyzer {
+	return &FakeAnalyzer{Entries: entries}
+}
+
+// Parse возвращает разборы слова по записям лексикона или nil, если слова в нем нет.
+func (f *FakeAnalyzer) Parse(word string) []*Parsed {
+	word = strings.ToLower(word)
+	var parses []*Parsed
+	for _, e := range f.Entries {
+		if strings.ToLower(e.Word) == word {
+			p := newParsed(word, strings.ToLower(e.Lemma), e.Tags)
+			p.Method, p.Confidence = UnitDictionary, 1
+			parses = append(parses, p)
+		}
+	}
+	return parses
+}
+
+// Inflect возвращает словоформы лексем, к которым относится слово, по одной на каждое
+// написание (с тегами первой записи), в алфавитном порядке, как MorphAnalyzer.Inflect.
+func (f *FakeAnalyzer) Inflect(word string) []*Parsed {
+	word = strings.ToLower(word)
+	lexemes := make(map[string]struct{})
+	for _, e := range f.Entries {
+		if strings.ToLower(e.Word) == word {
+			lexemes[fakeLexemeKey(e)] = struct{}{}
+		}
+	}
+	var forms []*Parsed
+	seen := make(map[string]struct{})
+	for _, e := range f.Entries {
+		form := strings.ToLower(e.Word)
+		if _, ok := lexemes[fakeLexemeKey(e)]; !ok {
+			continue
+		}
+		if _, ok := seen[form]; !ok {
+			seen[form] = struct{}{}
+			forms = append(forms, newParsed(form, strings.ToLower(e.Lemma), e.Tags))
+		}
+	}
+	sortByWord(forms)
+	return forms
+}
+
+// Analyze возвращает разборы и словоформы слова (см. Parse и Inflect).
+func (f *FakeAnalyzer) Analyze(word string) ([]*Parsed, []*Parsed) {
+	parses := f.Parse(word)
+	if parses == nil {
+		return nil, nil
+	}
+	return parses, f.Inflect(word)
+}
+
+// Lemmatize возвращает уникальные леммы слова в порядке записей лексикона.
+func (f *FakeAnalyzer) Lemmatize(word string) []string {
+	var lemmas []string
+	seen := make(map[string]struct{})
+	for _, p := range f.Parse(word) {
+		if _, ok := seen[p.Lemma]; !ok {
+			seen[p.Lemma] = struct{}{}
+			lemmas = append(lemmas, p.Lemma)
+		}
+	}
+	return lemmas
+}
+
+// fakeLexemeKey - ключ лексемы записи, как в DictBuilder.Add.
+func fakeLexemeKey(e LexEntry) string {
+	if e.Lexeme != "" {
+		return e.Lexeme
+	}
+	pos, _, _ := strings.Cut(e.Tags, ",")
+	return strings.ToLower(e.Lemma) + "\x00" + pos
+}
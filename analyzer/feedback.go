@@ -0,0 +1,196 @@
+// feedback.go содержит сбор отзывов о неверных разборах в работающих системах:
+// ReportMisparse записывает слово, ожидаемый и фактические разборы в приемник
+// (файл JSON Lines или HTTP), а MisparseFixes превращает накопленные отчеты
+// в записи пользовательского словаря и исправления лексикона для компилятора.
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReportedParse - разбор в отчете о неверном разборе.
+type ReportedParse struct {
+	Lemma string `json:"lemma"`
+	Tags  string `json:"tags"` // Теги словаря (русские граммемы).
+}
+
+// MisparseReport - отчет о неверном разборе слова.
+type MisparseReport struct {
+	Time         time.Time       `json:"time"`
+	Word         string          `json:"word"`             // Слово в нижнем регистре.
+	Expected     ReportedParse   `json:"expected"`         // Разбор, который ожидал пользователь.
+	Actual       []ReportedParse `json:"actual,omitempty"` // Разборы, которые вернул анализатор.
+	InDictionary bool            `json:"in_dictionary"`    // Слово найдено в словаре (иначе разобрано предсказателем).
+}
+
+// FeedbackSink - приемник отчетов о неверных разборах. Report вызывается
+// из разных горутин, поэтому реализации должны быть потокобезопасны.
+type FeedbackSink interface {
+	Report(r MisparseReport) error
+}
+
+// WithFeedbackSink задает приемник отчетов для ReportMisparse.
+func WithFeedbackSink(sink FeedbackSink) Option {
+	return func(a *MorphAnalyzer) {
+		a.feedback = sink
+	}
+}
+
+// ReportMisparse сообщает, что анализатор неверно разобрал слово: в приемник, заданный
+// WithFeedbackSink, записываются ожидаемый разбор и разборы, которые анализатор
+// возвращает сейчас. Граммемы expected могут быть на любом языке (см. WithLang),
+// в отчет они попадают в словарном виде.
+func (a *MorphAnalyzer) ReportMisparse(word string, expected *Parsed) error {
+	if a.feedback == nil {
+		return errors.New("не задан приемник отчетов (см. WithFeedbackSink)")
+	}
+	if expected == nil {
+		return errors.New("не задан ожидаемый разбор")
+	}
+	lowerWord := strings.ToLower(word)
+	report := MisparseReport{
+		Time: time.Now().UTC(),
+		Word: lowerWord,
+		Expected: ReportedParse{
+			Lemma: strings.ToLower(expected.Lemma),
+			Tags:  expected.Localize(LangRussian).Tags,
+		},
+		InDictionary: a.lookupPayloads(lowerWord) != nil,
+	}
+	for _, p := range a.parseOrPredict(lowerWord) {
+		report.Actual = append(report.Actual, ReportedParse{Lemma: p.Lemma, Tags: p.Tags})
+	}
+	if err := a.feedback.Report(report); err != nil {
+		return fmt.Errorf("ошибка записи отчета о неверном разборе: %w", err)
+	}
+	return nil
+}
+
+// FileFeedbackSink дописывает отчеты в файл в формате JSON Lines.
+type FileFeedbackSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileFeedbackSink открывает файл отчетов на дозапись, создавая его при необходимости.
+func NewFileFeedbackSink(path string) (*FileFeedbackSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла отчетов: %w", err)
+	}
+	return &FileFeedbackSink{file: f}, nil
+}
+
+// Report дописывает отчет одной строкой.
+func (s *FileFeedbackSink) Report(r MisparseReport) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close закрывает файл отчетов.
+func (s *FileFeedbackSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// HTTPFeedbackSink отправляет каждый отчет POST-запросом с JSON-телом.
+type HTTPFeedbackSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPFeedbackSink создает приемник, отправляющий отчеты на url.
+// Если client равен nil, используется http.DefaultClient.
+func NewHTTPFeedbackSink(url string, client *http.Client) *HTTPFeedbackSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPFeedbackSink{url: url, client: client}
+}
+
+// Report отправляет отчет; ответ с кодом вне диапазона 2xx считается ошибкой.
+func (s *HTTPFeedbackSink) Report(r MisparseReport) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("сервер отчетов вернул %s", resp.Status)
+	}
+	return nil
+}
+
+// ReadMisparseReports читает отчеты в формате JSON Lines (как их пишет FileFeedbackSink)
+// и вызывает fn для каждого. Пустые строки пропускаются.
+func ReadMisparseReports(r io.Reader, fn func(MisparseReport) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var report MisparseReport
+		if err := json.Unmarshal(line, &report); err != nil {
+			return fmt.Errorf("строка %d: %w", lineNum, err)
+		}
+		if err := fn(report); err != nil {
+			return fmt.Errorf("строка %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения отчетов: %w", err)
+	}
+	return nil
+}
+
+// MisparseFixes превращает отчеты в записи лексикона (см. WriteTSVLexicon):
+// для несловарных слов - записи пользовательского словаря, для словарных слов,
+// у которых нет ожидаемого разбора, - исправления исходного лексикона для компилятора.
+// Отчеты, в которых ожидаемый разбор уже есть среди фактических (ошибка выбора
+// разбора, а не словаря), пропускаются. Повторы отчетов объединяются, порядок сохраняется.
+func MisparseFixes(reports []MisparseReport) (userDict, lexiconFixes []LexEntry) {
+	seen := make(map[LexEntry]struct{})
+	for _, r := range reports {
+		if r.Word == "" || r.Expected.Lemma == "" || slices.Contains(r.Actual, r.Expected) {
+			continue
+		}
+		entry := LexEntry{Word: r.Word, Lemma: r.Expected.Lemma, Tags: r.Expected.Tags}
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		if r.InDictionary {
+			lexiconFixes = append(lexiconFixes, entry)
+		} else {
+			userDict = append(userDict, entry)
+		}
+	}
+	return userDict, lexiconFixes
+}
@@ -0,0 +1,280 @@
+// format.go содержит раскладку файла словаря на диске. Начиная с версии формата 9 все массивы
+// записываются в порядке байт little-endian, а записи (FlatNode, PredictInfo и др.) не
+// содержат неявного выравнивания: поля дополнения объявлены явно и всегда нулевые.
+// Поэтому словарь, собранный на одной платформе, читается на любой другой: на
+// little-endian платформах массивы отображаются в память без копирования,
+// на остальных декодируются при загрузке.
+package analyzer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+	"unsafe"
+)
+
+// nativeLittleEndian сообщает, что порядок байт платформы совпадает с порядком байт словаря.
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// zeroCopy сообщает, что запись T на диске совпадает с ее раскладкой в памяти
+// и массив таких записей можно читать из файла без декодирования.
+func zeroCopy[T any]() bool {
+	var t T
+	return nativeLittleEndian && binary.Size(t) == int(unsafe.Sizeof(t))
+}
+
+// encodeLE кодирует массив записей фиксированного размера в порядке байт словаря.
+func encodeLE[T any](s []T) []byte {
+	if zeroCopy[T]() {
+		return sliceToBytes(s)
+	}
+	b, err := binary.Append(nil, binary.LittleEndian, s)
+	if err != nil {
+		panic(fmt.Sprintf("запись %T не имеет фиксированного размера: %v", s, err))
+	}
+	return b
+}
+
+// decodeLE - операция, обратная encodeLE: без копирования, если это позволяет платформа
+// (см. bytesToSlice), иначе с декодированием в новый срез.
+func decodeLE[T any](b []byte) []T {
+	if zeroCopy[T]() {
+		return bytesToSlice[T](b)
+	}
+	var t T
+	out := make([]T, len(b)/binary.Size(t))
+	if _, err := binary.Decode(b, binary.LittleEndian, out); err != nil {
+		panic(fmt.Sprintf("запись %T не имеет фиксированного размера: %v", t, err))
+	}
+	return out
+}
+
+// encodeComplexData сериализует "сложный" блок: gob, сжатый алгоритмом c.
+func encodeComplexData(complexData *ComplexData, c Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(complexData); err != nil {
+		return nil, fmt.Errorf("ошибка gob-кодирования: %w", err)
+	}
+	block, err := compressBlock(buf.Bytes(), c)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сжатия сложного блока: %w", err)
+	}
+	return block, nil
+}
+
+// dictFile - содержимое файла словаря текущего формата.
+type dictFile struct {
+	complexData     []byte // Сжатый блок необязательных данных (см. encodeComplexData).
+	nodes           []FlatNode
+	edges           []FlatEdge
+	payloads        []MorphInfo
+	predictNodes    []FlatNode
+	predictEdges    []FlatEdge
+	predictPayloads []PredictInfo
+	lemmas          []string
+	tags            []string
+	stems           []string
+	grammemes       []string
+	stemNodes       []uint32
+	paradigmStems   []uint32
+	paradigmLemmas  []uint32
+	tagMasks        []GrammemeMask
+	buildTime       time.Time // Время сборки (нулевое, если неизвестно).
+	sourceRevision  string    // Ревизия исходного корпуса.
+}
+
+// writeTo записывает словарь: Header, PoolsHeader, Checksums и секции. Массивы выравниваются
+// по 8 байт, чтобы загрузчик мог отобразить их в память без копирования.
+func (f *dictFile) writeTo(w io.Writer) error {
+	lemmaData, lemmaIndex := flattenPool(f.lemmas)
+	tagsData, tagsIndex := flattenPool(f.tags)
+	stemData, stemIndex := flattenPool(f.stems)
+	grammemeData, grammemeIndex := flattenPool(f.grammemes)
+	sections := [][]byte{
+		f.complexData,
+		encodeLE(f.nodes),
+		encodeLE(f.edges),
+		encodeLE(f.payloads),
+		encodeLE(f.predictNodes),
+		encodeLE(f.predictEdges),
+		encodeLE(f.predictPayloads),
+		lemmaData, encodeLE(lemmaIndex),
+		tagsData, encodeLE(tagsIndex),
+		stemData, encodeLE(stemIndex),
+		grammemeData, encodeLE(grammemeIndex),
+		encodeLE(f.stemNodes),
+		encodeLE(f.paradigmStems),
+		encodeLE(f.paradigmLemmas),
+		encodeLE(f.tagMasks),
+	}
+	offsets := make([]int64, len(sections))
+	offset := int64(binary.Size(Header{}) + binary.Size(PoolsHeader{}) + binary.Size(Checksums{}))
+	for i, section := range sections {
+		if i > 0 {
+			offset = alignUp(offset, 8)
+		}
+		offsets[i] = offset
+		offset += int64(len(section))
+	}
+
+	header := Header{
+		ComplexDataOffset:     offsets[0],
+		ComplexDataLength:     int64(len(f.complexData)),
+		NodesOffset:           offsets[1],
+		NodesCount:            int64(len(f.nodes)),
+		EdgesOffset:           offsets[2],
+		EdgesCount:            int64(len(f.edges)),
+		PayloadsOffset:        offsets[3],
+		PayloadsCount:         int64(len(f.payloads)),
+		PredictNodesOffset:    offsets[4],
+		PredictNodesCount:     int64(len(f.predictNodes)),
+		PredictEdgesOffset:    offsets[5],
+		PredictEdgesCount:     int64(len(f.predictEdges)),
+		PredictPayloadsOffset: offsets[6],
+		PredictPayloadsCount:  int64(len(f.predictPayloads)),
+	}
+	copy(header.Magic[:], dictMagic)
+	header.Version = dictVersion
+	if !f.buildTime.IsZero() {
+		header.BuildTime = f.buildTime.Unix()
+	}
+	header.WordCount = countWords(f.nodes, f.edges)
+	header.LemmaCount = int64(len(f.lemmas))
+	copy(header.SourceRevision[:], f.sourceRevision)
+	pools := PoolsHeader{
+		Lemmas:         FlatPool{DataOffset: offsets[7], DataLength: int64(len(lemmaData)), IndexOffset: offsets[8], Count: int64(len(f.lemmas))},
+		Tags:           FlatPool{DataOffset: offsets[9], DataLength: int64(len(tagsData)), IndexOffset: offsets[10], Count: int64(len(f.tags))},
+		Stems:          FlatPool{DataOffset: offsets[11], DataLength: int64(len(stemData)), IndexOffset: offsets[12], Count: int64(len(f.stems))},
+		Grammemes:      FlatPool{DataOffset: offsets[13], DataLength: int64(len(grammemeData)), IndexOffset: offsets[14], Count: int64(len(f.grammemes))},
+		StemNodes:      FlatArray{Offset: offsets[15], Count: int64(len(f.stemNodes))},
+		ParadigmStems:  FlatArray{Offset: offsets[16], Count: int64(len(f.paradigmStems))},
+		ParadigmLemmas: FlatArray{Offset: offsets[17], Count: int64(len(f.paradigmLemmas))},
+		TagMasks:       FlatArray{Offset: offsets[18], Count: int64(len(f.tagMasks))},
+	}
+
+	headers, err := binary.Append(nil, binary.LittleEndian, &header)
+	if err != nil {
+		return fmt.Errorf("ошибка записи заголовка: %w", err)
+	}
+	if headers, err = binary.Append(headers, binary.LittleEndian, &pools); err != nil {
+		return fmt.Errorf("ошибка записи заголовка пулов: %w", err)
+	}
+	sums := Checksums{Headers: crc32.Checksum(headers, crcTable)}
+	for i, section := range sections {
+		sums.Sections[i] = crc32.Checksum(section, crcTable)
+	}
+	if headers, err = binary.Append(headers, binary.LittleEndian, &sums); err != nil {
+		return fmt.Errorf("ошибка записи контрольных сумм: %w", err)
+	}
+	if _, err := w.Write(headers); err != nil {
+		return fmt.Errorf("ошибка записи заголовка: %w", err)
+	}
+	written := int64(len(headers))
+	for i, section := range sections {
+		if padding := offsets[i] - written; padding > 0 {
+			if _, err := w.Write(make([]byte, padding)); err != nil {
+				return fmt.Errorf("ошибка записи словаря: %w", err)
+			}
+			written += padding
+		}
+		if _, err := w.Write(section); err != nil {
+			return fmt.Errorf("ошибка записи словаря: %w", err)
+		}
+		written += int64(len(section))
+	}
+	return nil
+}
+
+// ConvertDict переписывает словарь прежней версии формата из data в w в текущей версии.
+// Версии 7 и 8 хранили массивы в раскладке платформы сборки; все поставляемые словари
+// собраны на little-endian платформах, и ConvertDict читает их именно так.
+// Время сборки и ревизия корпуса хранятся начиная с версии 10, в более старых
+// словарях они остаются пустыми.
+// Словарь текущей версии записывается без изменений, а сжатый zstd целиком - распакованным.
+func ConvertDict(w io.Writer, data []byte) error {
+	a, err := loadFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if a.info.Version == dictVersion {
+		// Файл, сжатый zstd целиком, записывается распакованным.
+		if _, err := w.Write(a.data); err != nil {
+			return fmt.Errorf("ошибка записи словаря: %w", err)
+		}
+		return nil
+	}
+
+	f := dictFile{
+		nodes:          a.nodes,
+		edges:          a.edges,
+		payloads:       a.payloads,
+		predictNodes:   a.predictNodes,
+		predictEdges:   a.predictEdges,
+		lemmas:         a.lemmas.all(),
+		tags:           a.tags.all(),
+		stems:          a.paradigms.stems.all(),
+		grammemes:      a.Grammemes(),
+		stemNodes:      a.paradigms.nodes,
+		paradigmStems:  a.paradigms.index,
+		paradigmLemmas: a.paradigms.lemmas,
+		tagMasks:       a.tagMasks,
+		buildTime:      a.info.BuildTime,
+		sourceRevision: a.info.SourceRevision,
+	}
+	complexData := ComplexData{
+		Valency:     a.valency,
+		License:     a.license,
+		SelfTest:    a.selfTest,
+		TagIndex:    a.tagIndex,
+		AspectPairs: a.aspectPairs,
+		Derivations: a.derivations,
+	}
+
+	// В DAW7 ID парадигм идут не подряд: заменяем их номерами строк таблицы парадигм.
+	// Строки упорядочены по ID, поэтому списки парадигм в TagIndex остаются возрастающими.
+	f.predictPayloads = a.predictPayloads
+	if a.paradigms.sparse != nil {
+		row := func(pID uint32) (uint32, error) {
+			r, ok := a.paradigms.row(pID)
+			if !ok {
+				return 0, fmt.Errorf("%w: ссылка на несуществующую парадигму %d", ErrDictCorrupted, pID)
+			}
+			return uint32(r), nil
+		}
+		f.payloads = make([]MorphInfo, len(a.payloads))
+		for i, p := range a.payloads {
+			if p.ParadigmID, err = row(p.ParadigmID); err != nil {
+				return err
+			}
+			f.payloads[i] = p
+		}
+		f.predictPayloads = make([]PredictInfo, len(a.predictPayloads))
+		for i, p := range a.predictPayloads {
+			if p.ParadigmID, err = row(p.ParadigmID); err != nil {
+				return err
+			}
+			f.predictPayloads[i] = p
+		}
+		if a.tagIndex != nil {
+			complexData.TagIndex = make(map[uint32][]uint32, len(a.tagIndex))
+			for tagsID, pIDs := range a.tagIndex {
+				rows := make([]uint32, len(pIDs))
+				for i, pID := range pIDs {
+					if rows[i], err = row(pID); err != nil {
+						return err
+					}
+				}
+				complexData.TagIndex[tagsID] = rows
+			}
+		}
+	}
+
+	if f.complexData, err = encodeComplexData(&complexData, CompressionGzip); err != nil {
+		return err
+	}
+	return f.writeTo(w)
+}
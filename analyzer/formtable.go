@@ -0,0 +1,89 @@
+// formtable.go содержит компактное представление лексемы: словоформы и ID их тегов
+// хранятся параллельными срезами, а объекты Parsed создаются только по запросу.
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// FormTable - таблица словоформ, возвращаемая InflectCompact.
+// Words[i] - словоформа, TagsIDs[i] - ID ее набора тегов в пуле словаря.
+// Строки тегов и лемм не копируются, а разделяются с пулами словаря.
+type FormTable struct {
+	Words   []string
+	TagsIDs []uint32
+
+	lemmaIDs []uint32
+	tags     *stringPool
+	lemmas   *stringPool
+}
+
+// Len возвращает количество словоформ в таблице.
+func (t *FormTable) Len() int {
+	return len(t.Words)
+}
+
+// Tags возвращает строку тегов i-й словоформы.
+func (t *FormTable) Tags(i int) string {
+	return t.tags.at(t.TagsIDs[i])
+}
+
+// Lemma возвращает лемму i-й словоформы.
+func (t *FormTable) Lemma(i int) string {
+	return t.lemmas.at(t.lemmaIDs[i])
+}
+
+// Parsed строит полный разбор i-й словоформы.
+func (t *FormTable) Parsed(i int) *Parsed {
+	return newParsed(t.Words[i], t.Lemma(i), t.Tags(i))
+}
+
+// InflectCompact генерирует все словоформы словарного слова, как Inflect, но без создания
+// объекта Parsed на каждую форму. В отличие от Inflect омонимичные формы одной лексемы
+// ("кота" - Р.п. и В.п.) сохраняются отдельными строками со своими тегами.
+// Строки отсортированы по словоформе, затем по ID тегов. Для несловарного слова таблица пуста.
+func (a *MorphAnalyzer) InflectCompact(word string) FormTable {
+	table := FormTable{tags: &a.tags, lemmas: &a.lemmas}
+
+	type row struct {
+		form    lexemeForm
+		lemmaID uint32
+	}
+	var rows []row
+	seen := make(map[lexemeForm]struct{})
+	checked := make(map[uint32]struct{})
+	for _, info := range a.lookupPayloads(strings.ToLower(word)) {
+		if _, ok := checked[info.ParadigmID]; ok {
+			continue
+		}
+		checked[info.ParadigmID] = struct{}{}
+		for _, f := range a.lexemeForms(info.ParadigmID) {
+			if _, ok := seen[f]; ok {
+				continue
+			}
+			seen[f] = struct{}{}
+			rows = append(rows, row{form: f, lemmaID: info.LemmaID})
+		}
+	}
+	if len(rows) == 0 {
+		return table
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].form.word != rows[j].form.word {
+			return rows[i].form.word < rows[j].form.word
+		}
+		return rows[i].form.tagsID < rows[j].form.tagsID
+	})
+
+	table.Words = make([]string, len(rows))
+	table.TagsIDs = make([]uint32, len(rows))
+	table.lemmaIDs = make([]uint32, len(rows))
+	for i, r := range rows {
+		table.Words[i] = r.form.word
+		table.TagsIDs[i] = r.form.tagsID
+		table.lemmaIDs[i] = r.lemmaID
+	}
+	return table
+}
@@ -0,0 +1,90 @@
+// frequency.go содержит частоты словоформ, которые словарь не хранит, а пользователь
+// может подключить по своему корпусу: для ранжирования подсказок и порядка словоформ.
+package analyzer
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithWordFrequencies задает частоты словоформ (например, по корпусу), по которым
+// ранжируются подсказки TypeAhead и упорядочиваются словоформы с WithFrequencyOrder.
+// Ключи - словоформы в нижнем регистре.
+func WithWordFrequencies(freq map[string]uint64) Option {
+	return func(a *MorphAnalyzer) {
+		a.wordFrequencies = freq
+	}
+}
+
+// WithFrequencyOrder упорядочивает результаты Inflect, InflectList, InflectListFunc
+// и Predict по убыванию частоты словоформ из WithWordFrequencies, а словоформы
+// с равной частотой - по алфавиту. Так первой идет самая употребительная форма.
+// Без частот порядок остается алфавитным.
+func WithFrequencyOrder() Option {
+	return func(a *MorphAnalyzer) {
+		a.frequencyOrder = true
+	}
+}
+
+// ReadWordFrequencies читает частоты словоформ в формате "словоформа<TAB>частота".
+// Словоформы приводятся к нижнему регистру, частоты повторяющихся словоформ складываются.
+// Пустые строки и строки, начинающиеся с '#', пропускаются.
+func ReadWordFrequencies(r io.Reader) (map[string]uint64, error) {
+	freq := make(map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, count, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("строка %d: ожидалось 2 поля, разделенных табуляцией", lineNum)
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(count), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: неверная частота: %w", lineNum, err)
+		}
+		freq[strings.ToLower(word)] += n
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения частот: %w", err)
+	}
+	return freq, nil
+}
+
+// sortForms упорядочивает словоформы по алфавиту или, с WithFrequencyOrder, по частоте.
+func (a *MorphAnalyzer) sortForms(forms []*Parsed) {
+	slices.SortFunc(forms, a.compareForms)
+}
+
+// compareForms задает порядок словоформ для sortForms: по убыванию частоты (с WithFrequencyOrder),
+// затем по слову. Формы с одинаковым словом упорядочиваются по лемме и тегам, чтобы порядок
+// не зависел от алгоритма сортировки (см. InflectListSorted).
+func (a *MorphAnalyzer) compareForms(x, y *Parsed) int {
+	if a.frequencyOrder && len(a.wordFrequencies) > 0 {
+		if c := cmp.Compare(a.wordFrequencies[y.Word], a.wordFrequencies[x.Word]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Or(
+		strings.Compare(x.Word, y.Word),
+		strings.Compare(x.Lemma, y.Lemma),
+		strings.Compare(x.Tags, y.Tags),
+	)
+}
+
+// sortByWord упорядочивает разборы по алфавиту словоформ.
+func sortByWord(parses []*Parsed) {
+	sort.Slice(parses, func(i, j int) bool {
+		return parses[i].Word < parses[j].Word
+	})
+}
@@ -0,0 +1,149 @@
+// grammemes.go содержит битовое представление наборов граммем: словарь хранит перечень
+// своих граммем и для каждого набора тегов - битовую маску, поэтому фильтрация большого
+// числа разборов сводится к побитовым операциям без сравнения строк.
+package analyzer
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// maxGrammemes - наибольшее число граммем словаря (размер GrammemeMask в битах).
+const maxGrammemes = 256
+
+// GrammemeMask - множество граммем словаря фиксированного размера: бит i соответствует
+// i-й граммеме словаря. Маски разных словарей несовместимы; маски для запросов
+// строит MaskOf.
+type GrammemeMask [maxGrammemes / 64]uint64
+
+// set добавляет в маску граммему с номером bit.
+func (m *GrammemeMask) set(bit int) {
+	m[bit/64] |= 1 << (bit % 64)
+}
+
+// Contains сообщает, что маска содержит все граммемы маски q.
+func (m GrammemeMask) Contains(q GrammemeMask) bool {
+	for i := range m {
+		if m[i]&q[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects сообщает, что у масок есть общая граммема.
+func (m GrammemeMask) Intersects(q GrammemeMask) bool {
+	for i := range m {
+		if m[i]&q[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Union возвращает объединение масок.
+func (m GrammemeMask) Union(q GrammemeMask) GrammemeMask {
+	for i := range m {
+		m[i] |= q[i]
+	}
+	return m
+}
+
+// Count возвращает число граммем в маске.
+func (m GrammemeMask) Count() int {
+	n := 0
+	for _, w := range m {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// IsZero сообщает, что маска пуста.
+func (m GrammemeMask) IsZero() bool {
+	return m == GrammemeMask{}
+}
+
+// buildGrammemeMasks нумерует граммемы наборов тегов в порядке первого появления
+// и строит маску каждого набора. Возвращает ошибку, если граммем больше maxGrammemes.
+func buildGrammemeMasks(tagsPool []string) ([]string, []GrammemeMask, error) {
+	var grammemes []string
+	index := make(map[string]int)
+	masks := make([]GrammemeMask, len(tagsPool))
+	for i, tags := range tagsPool {
+		for rest, more := tags, tags != ""; more; {
+			var g string
+			g, rest, more = strings.Cut(rest, ",")
+			bit, ok := index[g]
+			if !ok {
+				if len(grammemes) == maxGrammemes {
+					return nil, nil, fmt.Errorf("в словаре больше %d граммем", maxGrammemes)
+				}
+				bit = len(grammemes)
+				index[g] = bit
+				grammemes = append(grammemes, g)
+			}
+			masks[i].set(bit)
+		}
+	}
+	return grammemes, masks, nil
+}
+
+// initGrammemeMasks заполняет перечень граммем и маски наборов тегов анализатора:
+// из словаря или, если словарь собран без них (DAW7), по пулу тегов.
+func (a *MorphAnalyzer) initGrammemeMasks(grammemes []string, masks []GrammemeMask) error {
+	if len(grammemes) > maxGrammemes || len(masks) != a.tags.len() {
+		var err error
+		if grammemes, masks, err = buildGrammemeMasks(a.tags.all()); err != nil {
+			return err
+		}
+	}
+	a.grammemeBits = make(map[string]int, len(grammemes))
+	for i, g := range grammemes {
+		a.grammemeBits[g] = i
+	}
+	a.tagMasks = masks
+	return nil
+}
+
+// MaskOf возвращает маску граммем для запроса (на любом языке, см. Lang):
+// MaskOf("Существительное", "Родительный"). Возвращает ошибку для граммемы,
+// которой нет в словаре.
+func (a *MorphAnalyzer) MaskOf(grammemes ...string) (GrammemeMask, error) {
+	var m GrammemeMask
+	for _, g := range grammemes {
+		bit, ok := a.grammemeBits[canonicalGrammeme(strings.TrimSpace(g))]
+		if !ok {
+			return GrammemeMask{}, fmt.Errorf("граммемы %q нет в словаре", g)
+		}
+		m.set(bit)
+	}
+	return m, nil
+}
+
+// Mask возвращает маску граммем разбора. У разборов словаря и предсказателя маска берется
+// из словаря; для остальных разборов (звенья цепочки, словоформы, переведенные разборы)
+// она строится по строке Tags, а граммемы, которых нет в словаре, пропускаются.
+func (a *MorphAnalyzer) Mask(p *Parsed) GrammemeMask {
+	if p.masked {
+		return p.mask
+	}
+	var m GrammemeMask
+	for rest, more := p.Tags, p.Tags != ""; more; {
+		var g string
+		g, rest, more = strings.Cut(rest, ",")
+		if bit, ok := a.grammemeBits[canonicalGrammeme(g)]; ok {
+			m.set(bit)
+		}
+	}
+	return m
+}
+
+// Grammemes возвращает граммемы словаря в порядке их битов в GrammemeMask.
+func (a *MorphAnalyzer) Grammemes() []string {
+	grammemes := make([]string, len(a.grammemeBits))
+	for g, bit := range a.grammemeBits {
+		grammemes[bit] = g
+	}
+	return grammemes
+}
@@ -0,0 +1,93 @@
+// highlight.go содержит поиск словоформ запроса в тексте для подсветки сниппетов
+// в результатах поиска без отдельного поискового движка.
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span - фрагмент текста, совпавший с одной из лемм запроса.
+// Start и End - байтовые смещения в исходной строке (text[Start:End]).
+type Span struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Lemma string `json:"lemma"` // Лемма запроса, с которой совпало слово
+}
+
+// HighlightMatches находит в тексте все словоформы лемм запроса ("кот" -> "кота", "котами")
+// и возвращает их смещения в порядке следования в тексте.
+// Леммы запроса сравниваются без учета регистра. Несловарные слова текста
+// сопоставляются по предсказанной лемме.
+func (a *MorphAnalyzer) HighlightMatches(text string, queryLemmas []string) []Span {
+	if len(queryLemmas) == 0 {
+		return nil
+	}
+	query := make(map[string]string, len(queryLemmas))
+	for _, lemma := range queryLemmas {
+		query[strings.ToLower(lemma)] = lemma
+	}
+
+	var spans []Span
+	// Кэш совпадений: в тексте одни и те же слова встречаются многократно.
+	matched := make(map[string]string)
+	forEachWord(text, func(start, end int) {
+		word := strings.ToLower(text[start:end])
+		lemma, ok := matched[word]
+		if !ok {
+			lemma = a.matchQueryLemma(word, query)
+			matched[word] = lemma
+		}
+		if lemma != "" {
+			spans = append(spans, Span{Start: start, End: end, Lemma: lemma})
+		}
+	})
+	return spans
+}
+
+// matchQueryLemma возвращает лемму запроса, с которой совпадает слово, или пустую строку.
+func (a *MorphAnalyzer) matchQueryLemma(lowerWord string, query map[string]string) string {
+	if lemma, ok := query[lowerWord]; ok {
+		return lemma
+	}
+	for _, p := range a.parseOrPredict(lowerWord) {
+		if lemma, ok := query[p.Lemma]; ok {
+			return lemma
+		}
+	}
+	return ""
+}
+
+// wordJoiners - символы, которые не разрывают слово, если с обеих сторон от них буквы:
+// дефис ("кто-нибудь") и апостроф во всех вариантах ("м'ясо", "п’ять", "пʼять").
+const wordJoiners = "-'" + apostrophes
+
+// forEachWord вызывает fn для каждого слова текста с его байтовыми границами.
+// Слово - последовательность букв, допускающая внутренние дефисы и апострофы (см. wordJoiners).
+func forEachWord(text string, fn func(start, end int)) {
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		// Дефис или апостроф внутри слова не разрывает его, если за ним снова идет буква.
+		if strings.ContainsRune(wordJoiners, r) {
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if unicode.IsLetter(next) {
+				continue
+			}
+		}
+		fn(start, i)
+		start = -1
+	}
+	if start >= 0 {
+		fn(start, len(text))
+	}
+}
@@ -0,0 +1,161 @@
+// hunspell.go содержит экспорт словаря в формат Hunspell (файлы .affix и .dict), который
+// понимает шаблон ispell полнотекстового поиска PostgreSQL: база приводит слова к тем же
+// леммам, что и анализатор. Каждая лемма записывается в .dict с флагом своей парадигмы,
+// а парадигма - правилами SFX, превращающими лемму в словоформы.
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHunspellFlags - наибольший числовой флаг Hunspell (FLAG num).
+const maxHunspellFlags = 65000
+
+// HunspellStats - итоги экспорта словаря в формат Hunspell (см. WriteHunspell).
+type HunspellStats struct {
+	Lemmas  int // Лемм в .dict.
+	Flags   int // Парадигм (флагов SFX) в .affix.
+	Rules   int // Правил SFX.
+	Skipped int // Словоформ без общего начала с леммой ("шёл" - "идти"), не попавших в экспорт.
+}
+
+// hunspellRule - правило SFX: отрезать от леммы strip и добавить add.
+type hunspellRule struct {
+	strip, add string
+}
+
+// WriteHunspell записывает словарь в формате Hunspell: правила словоизменения в aff,
+// леммы с флагами в dic. Словоформа описывается правилом относительно леммы: общее
+// начало остается, окончание леммы заменяется окончанием формы. Формы, не имеющие
+// с леммой общего начала (супплетивы, формы с приставкой "наи-"), пропускаются
+// и учитываются в HunspellStats.Skipped. Омонимичные леммы разных частей речи
+// объединяются: для поиска важна только лемма.
+//
+// В PostgreSQL файлы кладутся в $SHAREDIR/tsearch_data под именами <name>.affix и <name>.dict:
+//
+//	CREATE TEXT SEARCH DICTIONARY steosmorphy (TEMPLATE = ispell, DictFile = <name>, AffFile = <name>);
+func (a *MorphAnalyzer) WriteHunspell(aff, dic io.Writer) (HunspellStats, error) {
+	var stats HunspellStats
+	ruleIDs := make(map[hunspellRule]int)
+	var rules []hunspellRule
+	paradigms := make(map[string][]int) // Лемма -> номера правил.
+	for e := range a.Words() {
+		if !validHunspellWord(e.Lemma) || !validHunspellWord(e.Word) {
+			stats.Skipped++
+			continue
+		}
+		ids, ok := paradigms[e.Lemma]
+		if !ok {
+			ids = []int{}
+		}
+		if e.Word != e.Lemma {
+			prefix := commonPrefixLen(e.Lemma, e.Word)
+			if prefix == 0 {
+				stats.Skipped++
+				paradigms[e.Lemma] = ids
+				continue
+			}
+			rule := hunspellRule{strip: e.Lemma[prefix:], add: e.Word[prefix:]}
+			id, ok := ruleIDs[rule]
+			if !ok {
+				id = len(rules)
+				ruleIDs[rule] = id
+				rules = append(rules, rule)
+			}
+			ids = append(ids, id)
+		}
+		paradigms[e.Lemma] = ids
+	}
+
+	lemmas := make([]string, 0, len(paradigms))
+	for lemma := range paradigms {
+		lemmas = append(lemmas, lemma)
+	}
+	slices.Sort(lemmas)
+
+	// Флаги нумеруются по первой лемме парадигмы, поэтому вывод воспроизводим.
+	flags := make(map[string]int)
+	var flagRules [][]int
+	lemmaFlags := make([]int, len(lemmas)) // 0 - у леммы нет других форм.
+	for i, lemma := range lemmas {
+		ids := paradigms[lemma]
+		if len(ids) == 0 {
+			continue
+		}
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+		key := fmt.Sprint(ids)
+		flag, ok := flags[key]
+		if !ok {
+			flag = len(flagRules) + 1
+			if flag > maxHunspellFlags {
+				return stats, fmt.Errorf("ошибка экспорта в Hunspell: больше %d парадигм", maxHunspellFlags)
+			}
+			flags[key] = flag
+			flagRules = append(flagRules, ids)
+		}
+		lemmaFlags[i] = flag
+	}
+
+	affOut := bufio.NewWriter(aff)
+	affOut.WriteString("SET UTF-8\nFLAG num\n")
+	for i, ids := range flagRules {
+		fmt.Fprintf(affOut, "\nSFX %d Y %d\n", i+1, len(ids))
+		for _, id := range ids {
+			rule := rules[id]
+			strip, add, condition := rule.strip, rule.add, rule.strip
+			if strip == "" {
+				strip, condition = "0", "."
+			}
+			if add == "" {
+				add = "0"
+			}
+			fmt.Fprintf(affOut, "SFX %d %s %s %s\n", i+1, strip, add, condition)
+		}
+		stats.Rules += len(ids)
+	}
+	if err := affOut.Flush(); err != nil {
+		return stats, fmt.Errorf("ошибка записи правил Hunspell: %w", err)
+	}
+
+	dicOut := bufio.NewWriter(dic)
+	dicOut.WriteString(strconv.Itoa(len(lemmas)) + "\n")
+	for i, lemma := range lemmas {
+		dicOut.WriteString(lemma)
+		if lemmaFlags[i] != 0 {
+			dicOut.WriteString("/" + strconv.Itoa(lemmaFlags[i]))
+		}
+		dicOut.WriteByte('\n')
+	}
+	if err := dicOut.Flush(); err != nil {
+		return stats, fmt.Errorf("ошибка записи словаря Hunspell: %w", err)
+	}
+	stats.Lemmas, stats.Flags = len(lemmas), len(flagRules)
+	return stats, nil
+}
+
+// validHunspellWord сообщает, что слово можно записать в файлы Hunspell: в нем нет
+// пробелов, "/" (разделитель флагов) и символов шаблона условия SFX.
+func validHunspellWord(word string) bool {
+	return word != "" && !strings.ContainsAny(word, " \t/.[]^")
+}
+
+// commonPrefixLen возвращает длину общего начала строк в байтах по границам символов.
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) {
+		ra, size := utf8.DecodeRuneInString(a[n:])
+		rb, _ := utf8.DecodeRuneInString(b[n:])
+		if ra != rb {
+			break
+		}
+		n += size
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+// indeclinable.go содержит определение несклоняемых слов ("кофе", "метро", "Сочи").
+package analyzer
+
+import (
+	"strings"
+)
+
+// declinableTags - части речи, которые в общем случае изменяются по падежам.
+var declinableTags = GrammemeSet{
+	"Существительное": {},
+	"Прилагательное":  {},
+	"Причастие":       {},
+	"Местоимение":     {},
+	"Числительное":    {},
+}
+
+// IsIndeclinable сообщает, что слово не склоняется: все его разборы склоняемых частей речи
+// либо помечены как несклоняемые, либо их лексема состоит из одной формы.
+// Позволяет шаблонизаторам не пытаться склонять "кофе", "метро" и иностранные имена.
+// Для слов без разборов склоняемых частей речи ("быстро") возвращает false.
+// Несловарные слова оцениваются по парадигме-образцу предсказателя.
+func (a *MorphAnalyzer) IsIndeclinable(word string) bool {
+	lowerWord := strings.ToLower(word)
+
+	payloads := a.lookupPayloads(lowerWord)
+	if payloads == nil {
+		best := a.findBestPrediction(lowerWord)
+		if best == nil {
+			return false
+		}
+		tags := a.tags.at(best.TagsID)
+		pos, _, _ := strings.Cut(tags, ",")
+		return inMap(pos, declinableTags) && a.isIndeclinableLexeme(best.ParadigmID, tags)
+	}
+
+	found := false
+	checked := make(map[uint32]struct{})
+	for _, info := range payloads {
+		tags := a.tags.at(info.TagsID)
+		pos, _, _ := strings.Cut(tags, ",")
+		if !inMap(pos, declinableTags) {
+			continue
+		}
+		found = true
+		if _, ok := checked[info.ParadigmID]; ok {
+			continue
+		}
+		checked[info.ParadigmID] = struct{}{}
+		if !a.isIndeclinableLexeme(info.ParadigmID, tags) {
+			return false
+		}
+	}
+	return found
+}
+
+// isIndeclinableLexeme проверяет одну лексему: по помете несклоняемости в тегах формы
+// или по количеству различных нормативных словоформ в парадигме.
+func (a *MorphAnalyzer) isIndeclinableLexeme(pID uint32, tags string) bool {
+	if hasGrammeme(tags, "несклоняемые") || hasGrammeme(tags, "Несклоняемый") {
+		return true
+	}
+
+	// Разговорные и устаревшие формы ("в Сочах") не делают слово склоняемым.
+	distinct := ""
+	for _, f := range a.lexemeForms(pID) {
+		if isNonNormative(a.tags.at(f.tagsID)) {
+			continue
+		}
+		if distinct != "" && f.word != distinct {
+			return false
+		}
+		distinct = f.word
+	}
+	return true
+}
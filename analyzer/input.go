@@ -0,0 +1,82 @@
+// input.go содержит проверку и нормализацию входного слова перед разбором. Анализатору
+// может прийти что угодно: байты в чужой кодировке, слово в разложенной форме Unicode
+// ("и" + U+0306 вместо "й") или мегабайтная "строка без пробелов" от злоумышленника.
+// Недопустимые последовательности UTF-8 заменяются на U+FFFD, слово приводится к NFC,
+// варианты апострофа ("п’ять", "пʼять") заменяются на U+0027, а слово длиннее лимита
+// не разбирается вовсе, чтобы звенья разбора (предсказатель, приставки, дефисы,
+// исправление OCR) не тратили на него процессор.
+package analyzer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// apostrophes - варианты апострофа, которые NormalizeApostrophes заменяет на U+0027:
+// правая одинарная кавычка (U+2019, так апостроф ставят текстовые редакторы) и буква-апостроф
+// (U+02BC, рекомендована украинским правописанием).
+const apostrophes = "\u2019\u02BC"
+
+// NormalizeApostrophes заменяет в слове варианты апострофа на U+0027. Словарь хранит
+// слова с апострофом ("м'ясо", "п'ять" в украинском словаре) в этом написании, а в текстах
+// встречаются все три. Слово без апострофа возвращается без выделения памяти.
+func NormalizeApostrophes(word string) string {
+	if !strings.ContainsAny(word, apostrophes) {
+		return word
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(apostrophes, r) {
+			return '\''
+		}
+		return r
+	}, word)
+}
+
+// DefaultMaxWordLen - максимальная длина разбираемого слова в символах по умолчанию.
+// Самые длинные словоформы словаря - около 45 символов; запас оставлен для сложных слов через дефис.
+const DefaultMaxWordLen = 100
+
+// WithMaxWordLen задает максимальную длину слова в символах: Parse, Analyze, Inflect,
+// Lemmatize и другие методы возвращают для более длинного слова nil, не разбирая его.
+// n <= 0 снимает ограничение. По умолчанию - DefaultMaxWordLen.
+func WithMaxWordLen(n int) Option {
+	return func(a *MorphAnalyzer) {
+		if n <= 0 {
+			n = -1
+		}
+		a.maxWordLen = n
+	}
+}
+
+// cleanWord готовит слово к разбору: заменяет недопустимые последовательности UTF-8
+// на U+FFFD, приводит слово к NFC, заменяет варианты апострофа (см. NormalizeApostrophes)
+// и, с опцией WithHomoglyphFolding, заменяет буквы-двойники (см. FoldHomoglyphs).
+// Возвращает false для пустого слова и слова длиннее лимита (см. WithMaxWordLen).
+// Для корректного слова в NFC память не выделяется.
+func (a *MorphAnalyzer) cleanWord(word string) (string, bool) {
+	if word == "" {
+		return "", false
+	}
+	limit := a.maxWordLen
+	if limit == 0 {
+		limit = DefaultMaxWordLen
+	}
+	// Длина в байтах не меньше длины в символах, поэтому короткие слова не пересчитываются,
+	// а слова длиннее limit*UTFMax байт отбрасываются без подсчета.
+	if limit > 0 && len(word) > limit && (len(word) > limit*utf8.UTFMax || utf8.RuneCountInString(word) > limit) {
+		return "", false
+	}
+	if !utf8.ValidString(word) {
+		word = strings.ToValidUTF8(word, string(utf8.RuneError))
+	}
+	if norm.NFC.QuickSpanString(word) < len(word) {
+		word = norm.NFC.String(word)
+	}
+	word = NormalizeApostrophes(word)
+	if a.foldHomoglyphs {
+		word = FoldHomoglyphs(word)
+	}
+	return word, true
+}
@@ -0,0 +1,50 @@
+// interceptor.go содержит перехватчики (middleware) вокруг Parse и Inflect:
+// логирование, метрики, переписывание входа и постобработка результатов
+// без ручной обертки каждого метода анализатора.
+package analyzer
+
+// ParseFunc - операция анализа слова (Parse или Inflect).
+type ParseFunc func(word string) []*Parsed
+
+// Interceptor оборачивает операцию: вызывает next (или не вызывает, возвращая свой результат)
+// и может изменить как входное слово, так и результат.
+type Interceptor func(next ParseFunc) ParseFunc
+
+// WithInterceptor добавляет перехватчик вокруг Parse (а значит, и Analyze, ParseList).
+// Перехватчики вызываются в порядке добавления: первый добавленный - внешний.
+// Кэш (WithCache) находится внутри цепочки, поэтому перехватчики видят каждый вызов.
+func WithInterceptor(interceptor Interceptor) Option {
+	return func(a *MorphAnalyzer) {
+		a.parseInterceptors = append(a.parseInterceptors, interceptor)
+	}
+}
+
+// WithInflectInterceptor добавляет перехватчик вокруг Inflect (а значит, и Analyze, InflectList).
+func WithInflectInterceptor(interceptor Interceptor) Option {
+	return func(a *MorphAnalyzer) {
+		a.inflectInterceptors = append(a.inflectInterceptors, interceptor)
+	}
+}
+
+// buildInterceptorChains собирает цепочки перехватчиков после применения всех опций.
+func (a *MorphAnalyzer) buildInterceptorChains() {
+	a.parseChain = chainInterceptors(a.parseInterceptors, func(word string) []*Parsed {
+		return a.cached(cacheOpParse, word, a.parse)
+	})
+	a.inflectChain = chainInterceptors(a.inflectInterceptors, func(word string) []*Parsed {
+		return a.cached(cacheOpInflect, word, a.inflect)
+	})
+}
+
+// chainInterceptors оборачивает base перехватчиками. Без перехватчиков возвращает nil,
+// чтобы методы анализатора шли по короткому пути без лишнего косвенного вызова.
+func chainInterceptors(interceptors []Interceptor, base ParseFunc) ParseFunc {
+	if len(interceptors) == 0 {
+		return nil
+	}
+	fn := base
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		fn = interceptors[i](fn)
+	}
+	return fn
+}
@@ -0,0 +1,191 @@
+// jsonformat.go содержит настраиваемую JSON-сериализацию разборов: стиль имен полей,
+// пропуск пустых граммем и представление OtherTags. Позволяет подогнать вывод под схему
+// потребителя, не меняя JSON-теги структуры Parsed (стандартный вывод остается прежним).
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// JSONNaming - стиль имен полей в JSON.
+type JSONNaming int
+
+const (
+	SnakeCase JSONNaming = iota // part_of_speech (как в JSON-тегах Parsed)
+	CamelCase                   // partOfSpeech
+)
+
+// OtherTagsMode - представление OtherTags в JSON.
+type OtherTagsMode int
+
+const (
+	OtherTagsObject OtherTagsMode = iota // {"other_tags": {"Тег": {}}} (как у Parsed)
+	OtherTagsArray                       // {"other_tags": ["Тег"]}, теги отсортированы
+	OtherTagsInline                      // {"Тег": true} на верхнем уровне объекта
+)
+
+// JSONOptions - настройки сериализации Parsed. Нулевое значение дает вывод,
+// совпадающий с json.Marshal(Parsed).
+type JSONOptions struct {
+	Naming    JSONNaming
+	OmitEmpty bool // Пропускать пустые граммемы и пустой OtherTags
+	OtherTags OtherTagsMode
+}
+
+// Marshal сериализует разбор в JSON согласно настройкам.
+func (o JSONOptions) Marshal(p *Parsed) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := o.encode(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalList сериализует срез разборов в JSON-массив согласно настройкам.
+func (o JSONOptions) MarshalList(parses []*Parsed) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, p := range parses {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := o.encode(&buf, p); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// encode записывает один разбор в buf.
+func (o JSONOptions) encode(buf *bytes.Buffer, p *Parsed) error {
+	if p == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	p = p.Details()
+
+	first := true
+	writeKey := func(key string) error {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		return writeJSONValue(buf, key)
+	}
+
+	buf.WriteByte('{')
+	fields := []struct{ name, value string }{
+		{"word", p.Word},
+		{"lemma", p.Lemma},
+		{"tags", p.Tags},
+		{"part_of_speech", p.PartOfSpeech},
+		{"animacy", p.Animacy},
+		{"aspect", p.Aspect},
+		{"case", p.Case},
+		{"gender", p.Gender},
+		{"mood", p.Mood},
+		{"number", p.Number},
+		{"person", p.Person},
+		{"tense", p.Tense},
+		{"transitivity", p.Transitivity},
+		{"voice", p.Voice},
+	}
+	for _, f := range fields {
+		if o.OmitEmpty && f.value == "" {
+			continue
+		}
+		if err := writeKey(o.fieldName(f.name)); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := writeJSONValue(buf, f.value); err != nil {
+			return err
+		}
+	}
+
+	if !(o.OmitEmpty && len(p.OtherTags) == 0) {
+		switch o.OtherTags {
+		case OtherTagsObject:
+			if err := writeKey(o.fieldName("other_tags")); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSONValue(buf, p.OtherTags); err != nil {
+				return err
+			}
+		case OtherTagsArray:
+			if err := writeKey(o.fieldName("other_tags")); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSONValue(buf, sortedTags(p.OtherTags)); err != nil {
+				return err
+			}
+		case OtherTagsInline:
+			for _, tag := range sortedTags(p.OtherTags) {
+				if err := writeKey(tag); err != nil {
+					return err
+				}
+				buf.WriteString(":true")
+			}
+		}
+	}
+
+	// Источник разбора пропускается, если он не заполнен (как и в JSON-тегах Parsed).
+	if p.Method != "" {
+		if err := writeKey(o.fieldName("method")); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := writeJSONValue(buf, p.Method); err != nil {
+			return err
+		}
+	}
+	if p.Confidence != 0 {
+		if err := writeKey(o.fieldName("confidence")); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := writeJSONValue(buf, p.Confidence); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// fieldName возвращает имя поля в выбранном стиле.
+func (o JSONOptions) fieldName(snake string) string {
+	if o.Naming != CamelCase {
+		return snake
+	}
+	parts := strings.Split(snake, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// writeJSONValue записывает значение стандартным кодировщиком (с экранированием строк).
+func writeJSONValue(buf *bytes.Buffer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// sortedTags возвращает теги множества в алфавитном порядке.
+func sortedTags(set GrammemeSet) []string {
+	tags := make([]string, 0, len(set))
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
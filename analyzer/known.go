@@ -0,0 +1,46 @@
+// known.go содержит проверку наличия слова в словаре без построения разборов:
+// для проверки орфографии и валидации данных, где нужен только ответ "есть ли слово".
+package analyzer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// IsKnown сообщает, что словоформа есть в словаре. В отличие от Parse не создает
+// разборы, не обращается к кэшу, перехватчикам и звеньям разбора, поэтому
+// несловарные слова, которые разобрал бы предсказатель, считаются неизвестными.
+// Регистр букв не учитывается.
+func (a *MorphAnalyzer) IsKnown(word string) bool {
+	if word == "" {
+		return false
+	}
+	nodeIndex := uint32(0)
+	for _, char := range word {
+		next, ok := a.findChildGeneral(nodeIndex, unicode.ToLower(char), a.nodes, a.edges, a.rootIndex)
+		if !ok {
+			return false
+		}
+		nodeIndex = next
+	}
+	return a.nodes[nodeIndex].IsFinal
+}
+
+// KnownPrefix возвращает самое длинное начало слова, которое само является словоформой
+// из словаря ("интернетмагазин" -> "интернет"), в исходном написании. Если ни одно начало слова
+// (включая все слово) не найдено в словаре, возвращает "" и false.
+// Помогает найти место опечатки или разделить слитно написанные слова.
+func (a *MorphAnalyzer) KnownPrefix(word string) (prefix string, ok bool) {
+	nodeIndex := uint32(0)
+	for i, char := range word {
+		next, found := a.findChildGeneral(nodeIndex, unicode.ToLower(char), a.nodes, a.edges, a.rootIndex)
+		if !found {
+			break
+		}
+		nodeIndex = next
+		if a.nodes[nodeIndex].IsFinal {
+			prefix, ok = word[:i+utf8.RuneLen(char)], true
+		}
+	}
+	return prefix, ok
+}
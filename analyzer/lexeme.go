@@ -0,0 +1,213 @@
+// lexeme.go содержит структурированное представление лексемы: словоформы, которые
+// Inflect возвращает плоским списком, группируются по лексемам и индексируются
+// по граммемам, чтобы форму можно было найти запросом вроде
+// lex.Form("Дательный", "Множественное число").
+package analyzer
+
+import (
+	"slices"
+	"strings"
+)
+
+// Lexeme - словоформы одной лексемы с поиском по граммемам.
+type Lexeme struct {
+	Lemma        string    `json:"lemma"`          // Нормальная форма.
+	PartOfSpeech string    `json:"part_of_speech"` // Часть речи лексемы (для причастий и деепричастий - глагол).
+	Forms        []*Parsed `json:"forms"`          // Словоформы по алфавиту; омонимичные формы - отдельными элементами.
+
+	byGrammeme map[string][]int // Граммема в словарном виде -> индексы форм в Forms.
+}
+
+// Lexeme возвращает лексему первого разбора слова (см. Lexemes) или nil,
+// если слово не удалось разобрать.
+func (a *MorphAnalyzer) Lexeme(word string) *Lexeme {
+	lexemes := a.Lexemes(word)
+	if len(lexemes) == 0 {
+		return nil
+	}
+	return lexemes[0]
+}
+
+// Lexemes возвращает все лексемы слова в порядке его разборов ("стали" - "сталь" и "стать").
+// У словарного слова лексемы строятся по парадигмам словаря, и омонимичные формы
+// ("кота" - Р.п. и В.п.) сохраняются отдельными элементами Forms со своими тегами;
+// винительный падеж существительных согласуется с одушевленностью (см. ResolveAccusative).
+// Несловарные слова получают лексему из словоформ Analyze.
+func (a *MorphAnalyzer) Lexemes(word string) []*Lexeme {
+	lexemes := a.lexemes(word)
+	for _, lex := range lexemes {
+		lex.PartOfSpeech = LocalizeGrammeme(lex.PartOfSpeech, a.lang)
+		lex.Forms = a.localize(lex.Forms)
+	}
+	return lexemes
+}
+
+// Synthesize возвращает словоформы лексемы с нормальной формой lemma, содержащие все
+// граммемы tags (на любом языке, см. Lang), без исходной словоформы:
+// Synthesize("кот", []string{"Дательный", "Множественное число"}) -> ["котам"].
+// Несколько вариантов формы ("кошкой", "кошкою") возвращаются нормативными первыми.
+// Если lemma - нормальная форма нескольких лексем ("лук"), учитываются все.
+// Несловарная лемма изменяется по парадигме-образцу суффиксного предсказателя.
+// Результат - в нижнем регистре; nil, если такой леммы или формы нет.
+func (a *MorphAnalyzer) Synthesize(lemma string, tags []string) []string {
+	lowerLemma := strings.ToLower(lemma)
+	var lexemes []*Lexeme
+	if a.lookupPayloads(lowerLemma) != nil {
+		lexemes = a.lexemes(lowerLemma)
+	} else if predictedLemma, predicted := a.predictedLexeme(lowerLemma); predictedLemma == lowerLemma {
+		// Словоформы Analyze хранят по одному набору тегов на слово, поэтому
+		// несловарную лексему строим по всем формам парадигмы-образца.
+		var groups lexemeGroups
+		forms := make([]*Parsed, len(predicted))
+		for i, f := range predicted {
+			forms[i] = newParsed(f.word, lowerLemma, a.tags.at(f.tagsID))
+		}
+		for _, f := range ResolveAccusative(forms) {
+			groups.get(f.Lemma, f.PartOfSpeech).add(f)
+		}
+		lexemes = groups.list
+	}
+
+	var forms []*Parsed
+	for _, lex := range lexemes {
+		if lex.Lemma == lowerLemma {
+			forms = append(forms, lex.FormsWith(tags...)...)
+		}
+	}
+	words := cellWords(forms)
+	if len(words) == 0 {
+		return nil
+	}
+	return words
+}
+
+// lexemes - Lexemes без перевода граммем (см. WithLang).
+func (a *MorphAnalyzer) lexemes(word string) []*Lexeme {
+	var lexemes lexemeGroups
+	if payloads := a.lookupPayloads(strings.ToLower(word)); payloads != nil {
+		checked := make(map[uint32]struct{})
+		for _, info := range payloads {
+			if _, ok := checked[info.ParadigmID]; ok {
+				continue
+			}
+			checked[info.ParadigmID] = struct{}{}
+			lemma := a.lemmas.at(info.LemmaID)
+			pos, _, _ := strings.Cut(a.tags.at(info.TagsID), ",")
+			var forms []*Parsed
+			for _, f := range a.lexemeForms(info.ParadigmID) {
+				forms = append(forms, newParsed(f.word, lemma, a.tags.at(f.tagsID)))
+			}
+			// Словарь хранит для существительных оба варианта винительного падежа.
+			lex := lexemes.get(lemma, pos)
+			for _, f := range ResolveAccusative(forms) {
+				lex.add(f)
+			}
+		}
+	} else {
+		parses, forms := a.analyze(word)
+		for _, p := range parses {
+			lexemes.get(p.Lemma, p.PartOfSpeech)
+		}
+		for _, f := range forms {
+			lexemes.get(f.Lemma, f.PartOfSpeech).add(f)
+		}
+	}
+	return lexemes.list
+}
+
+// lexemeGroups собирает лексемы по паре (лемма, часть речи лексемы) в порядке появления.
+type lexemeGroups struct {
+	list  []*Lexeme
+	byKey map[[2]string]*Lexeme
+}
+
+// get возвращает лексему для леммы и части речи формы, создавая ее при необходимости.
+func (g *lexemeGroups) get(lemma, pos string) *Lexeme {
+	key := [2]string{lemma, lexemePartOfSpeech(pos)}
+	if lex, ok := g.byKey[key]; ok {
+		return lex
+	}
+	if g.byKey == nil {
+		g.byKey = make(map[[2]string]*Lexeme)
+	}
+	lex := &Lexeme{Lemma: key[0], PartOfSpeech: key[1], byGrammeme: make(map[string][]int)}
+	g.byKey[key] = lex
+	g.list = append(g.list, lex)
+	return lex
+}
+
+// add добавляет форму в лексему и в индекс граммем.
+func (l *Lexeme) add(f *Parsed) {
+	for tags := f.Tags; tags != ""; {
+		var g string
+		g, tags, _ = strings.Cut(tags, ",")
+		l.byGrammeme[g] = append(l.byGrammeme[g], len(l.Forms))
+	}
+	l.Forms = append(l.Forms, f)
+}
+
+// lexemePartOfSpeech возвращает часть речи лексемы по части речи формы:
+// причастия и деепричастия входят в лексему глагола.
+func lexemePartOfSpeech(pos string) string {
+	if inMap(pos, verbLikeTags) {
+		return "Глагол"
+	}
+	return pos
+}
+
+// Form возвращает форму, содержащую все граммемы (на любом языке, см. Lang),
+// или nil, если такой формы нет. Нормативные формы предпочтительнее помеченных
+// как "Устаревший" или "Разговорный". Без граммем возвращает первую форму.
+func (l *Lexeme) Form(grammemes ...string) *Parsed {
+	var fallback *Parsed
+	for _, f := range l.FormsWith(grammemes...) {
+		if !f.hasCanonical("Устаревший") && !f.hasCanonical("Разговорный") {
+			return f
+		}
+		if fallback == nil {
+			fallback = f
+		}
+	}
+	return fallback
+}
+
+// FormsWith возвращает все формы, содержащие все граммемы, в порядке Forms.
+func (l *Lexeme) FormsWith(grammemes ...string) []*Parsed {
+	if len(grammemes) == 0 {
+		return l.Forms
+	}
+	canonical := make([]string, len(grammemes))
+	for i, g := range grammemes {
+		canonical[i] = canonicalGrammeme(g)
+	}
+	// Перебираем формы самой редкой граммемы и проверяем по индексу остальные.
+	rarest := l.byGrammeme[canonical[0]]
+	for _, g := range canonical[1:] {
+		if indices := l.byGrammeme[g]; len(indices) < len(rarest) {
+			rarest = indices
+		}
+	}
+	var result []*Parsed
+	for _, i := range rarest {
+		if l.hasAll(i, canonical) {
+			result = append(result, l.Forms[i])
+		}
+	}
+	return result
+}
+
+// hasAll сообщает, что форма с индексом i содержит все граммемы.
+func (l *Lexeme) hasAll(i int, grammemes []string) bool {
+	for _, g := range grammemes {
+		if !containsIndex(l.byGrammeme[g], i) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsIndex ищет индекс в возрастающем срезе.
+func containsIndex(indices []int, i int) bool {
+	_, found := slices.BinarySearch(indices, i)
+	return found
+}
@@ -0,0 +1,50 @@
+// license.go содержит сведения о лицензии исходного лексикона, встроенные в файл словаря.
+// Словарь - производное от лексикона произведение, поэтому при его распространении
+// (например, внутри коммерческого продукта) действуют условия лицензии лексикона.
+package analyzer
+
+// DictLicense - лицензия и требования к указанию авторства исходного лексикона словаря.
+type DictLicense struct {
+	Source      string `json:"source,omitempty"`      // Исходный лексикон ("OpenCorpora").
+	Name        string `json:"name"`                  // Название лицензии ("CC BY-SA 3.0").
+	URL         string `json:"url,omitempty"`         // Ссылка на текст лицензии.
+	Attribution string `json:"attribution,omitempty"` // Текст, который нужно приводить при распространении.
+	Text        string `json:"text,omitempty"`        // Полный текст лицензии, если он встроен в словарь.
+}
+
+// OpenCorporaLicense - лицензия словаря OpenCorpora. Ее встраивает steosmorphy-build
+// при сборке словаря из дампа OpenCorpora.
+var OpenCorporaLicense = DictLicense{
+	Source:      "OpenCorpora",
+	Name:        "CC BY-SA 3.0",
+	URL:         "https://creativecommons.org/licenses/by-sa/3.0/",
+	Attribution: "Словарь основан на данных проекта OpenCorpora (http://opencorpora.org), распространяемых по лицензии CC BY-SA 3.0.",
+}
+
+// VESUMLicense - лицензия украинского словаря ВЕСУМ (проект dict_uk). Ее встраивает
+// steosmorphy-build при сборке словаря из ВЕСУМ. Лицензия запрещает коммерческое использование.
+var VESUMLicense = DictLicense{
+	Source:      "VESUM",
+	Name:        "CC BY-NC-SA 4.0",
+	URL:         "https://creativecommons.org/licenses/by-nc-sa/4.0/",
+	Attribution: "Словарь основан на данных ВЕСУМ (https://github.com/brown-uk/dict_uk), распространяемых по лицензии CC BY-NC-SA 4.0.",
+}
+
+// IsZero сообщает, что сведения о лицензии отсутствуют.
+func (l DictLicense) IsZero() bool {
+	return l == DictLicense{}
+}
+
+// SetLicense задает лицензию исходного лексикона, которая будет встроена в словарь.
+func (b *DictBuilder) SetLicense(l DictLicense) {
+	b.license = l
+}
+
+// License возвращает лицензию исходного лексикона, встроенную в словарь.
+// Для словарей, собранных без сведений о лицензии, возвращает нулевое значение (см. IsZero).
+func (a *MorphAnalyzer) License() DictLicense {
+	if a.license == nil {
+		return DictLicense{}
+	}
+	return *a.license
+}
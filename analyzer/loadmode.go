@@ -0,0 +1,103 @@
+// loadmode.go содержит способы загрузки словаря: отображение в память (по умолчанию),
+// чтение в кучу и прогрев отображения. При mmap страницы словаря подгружаются с диска
+// по первому обращению, и первые запросы после старта (или после вытеснения страниц
+// под нагрузкой на память) ждут диска. Прогрев переносит это ожидание на загрузку,
+// а mlock не дает ОС вытеснить страницы.
+package analyzer
+
+import (
+	"fmt"
+	"os"
+)
+
+// LoadModeHeap - файл прочитан в кучу Go (см. WithHeapLoad).
+const LoadModeHeap LoadMode = "heap"
+
+// WithHeapLoad читает файл словаря целиком в кучу вместо отображения в память:
+// для окружений, где mmap запрещен или ограничен, и для сервисов, которым задержки
+// на подгрузку страниц недопустимы. Загрузка дольше, а словарь занимает память
+// процесса, которую нельзя разделить с другими процессами.
+func WithHeapLoad() Option {
+	return func(a *MorphAnalyzer) {
+		a.heapLoad = true
+	}
+}
+
+// WithPrefault прогревает отображение словаря при загрузке: просит ОС заранее прочитать
+// файл (MADV_WILLNEED) и обращается к каждой его странице, так что первые запросы
+// не ждут диска. Загрузка занимает столько, сколько чтение всего файла.
+func WithPrefault() Option {
+	return func(a *MorphAnalyzer) {
+		a.prefault = true
+	}
+}
+
+// WithMlock закрепляет словарь в оперативной памяти (mlock), чтобы ОС не вытесняла его
+// страницы. Если закрепить не удалось (например, не хватает лимита RLIMIT_MEMLOCK
+// или платформа не поддерживает mlock), загрузка завершается ошибкой.
+func WithMlock() Option {
+	return func(a *MorphAnalyzer) {
+		a.mlock = true
+	}
+}
+
+// loadSettings применяет опции к пустому анализатору, чтобы узнать способ загрузки
+// до того, как словарь прочитан. Опции только запоминают значения, поэтому их можно
+// применить повторно к загруженному анализатору.
+func loadSettings(opts []Option) *MorphAnalyzer {
+	var settings MorphAnalyzer
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return &settings
+}
+
+// loadHeap читает файл словаря в кучу и создает анализатор поверх прочитанных данных.
+func loadHeap(path string) (*MorphAnalyzer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	analyzer, err := loadFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	analyzer.setLoadMode(LoadModeHeap)
+	return analyzer, nil
+}
+
+// warmUp прогревает и закрепляет в памяти данные словаря, если это запрошено опциями
+// WithPrefault и WithMlock.
+func (a *MorphAnalyzer) warmUp() error {
+	if a.prefault && a.info.LoadMode != LoadModeHeap && a.info.LoadMode != LoadModeZstd {
+		// Подсказка ОС необязательна: встроенный словарь может быть не выровнен по странице.
+		if err := adviseWillNeed(a.data); err != nil {
+			a.log().Debug("madvise(MADV_WILLNEED) не выполнен", "error", err)
+		}
+		a.log().Debug("словарь прогрет", "pages", touchPages(a.data))
+	}
+	if a.mlock {
+		if err := lockMemory(a.data); err != nil {
+			return fmt.Errorf("ошибка mlock: %w", err)
+		}
+		a.locked = true
+	}
+	return nil
+}
+
+// touchPages читает по байту с каждой страницы data, чтобы ОС загрузила их в память,
+// и возвращает число страниц.
+func touchPages(data []byte) int {
+	pageSize := os.Getpagesize()
+	var sum byte
+	pages := 0
+	for i := 0; i < len(data); i += pageSize {
+		sum += data[i]
+		pages++
+	}
+	// Сумма используется, чтобы компилятор не выбросил чтения.
+	if sum == 0 && pages == 0 {
+		return 0
+	}
+	return pages
+}
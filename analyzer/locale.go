@@ -0,0 +1,334 @@
+// locale.go содержит перевод названий граммем: словарь хранит теги по-русски
+// ("Существительное", "Родительный"), а вывод можно получить по-английски ("noun", "genitive")
+// или в виде кратких машинных кодов в стиле OpenCorpora ("NOUN", "gent").
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Lang - язык названий граммем в полях Parsed.
+type Lang int
+
+const (
+	LangRussian Lang = iota // Русские названия, как в словаре ("Существительное").
+	LangEnglish             // Английские названия ("noun").
+	LangCodes               // Машинные коды в стиле OpenCorpora ("NOUN").
+)
+
+// String возвращает краткое имя языка, которое принимает ParseLang.
+func (l Lang) String() string {
+	switch l {
+	case LangRussian:
+		return "ru"
+	case LangEnglish:
+		return "en"
+	case LangCodes:
+		return "codes"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLang разбирает имя языка: "ru", "en" или "codes".
+func ParseLang(s string) (Lang, error) {
+	for _, l := range []Lang{LangRussian, LangEnglish, LangCodes} {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return LangRussian, fmt.Errorf("неизвестный язык граммем %q (ожидалось ru, en или codes)", s)
+}
+
+// grammemeName - названия одной граммемы на всех языках.
+type grammemeName struct {
+	ru, en, code string
+}
+
+// grammemeNames - переводы граммем словаря. Порядок важен: при обратном переводе
+// английского названия, общего для нескольких граммем, выбирается первая из них.
+// Коды уникальны, поэтому перевод через коды обратим без потерь.
+var grammemeNames = []grammemeName{
+	// Части речи.
+	{"Существительное", "noun", "NOUN"},
+	{"Прилагательное", "adjective", "ADJ"},
+	{"Глагол", "verb", "VERB"},
+	{"Наречие", "adverb", "ADVB"},
+	{"Причастие", "participle", "PRT"},
+	{"Деепричастие", "gerund", "GRND"},
+	{"Местоимение", "pronoun", "NPRO"},
+	{"Числительное", "numeral", "NUMR"},
+	{"Предлог", "preposition", "PREP"},
+	{"Частица", "particle", "PRCL"},
+	{"Союз", "conjunction", "CONJ"},
+	{"Междометие", "interjection", "INTJ"},
+	{"Вводное слово", "parenthetical", "PRNT"},
+
+	// Одушевленность.
+	{"Одушевленное", "animate", "anim"},
+	{"Неодушевленное", "inanimate", "inan"},
+	{"одушевленное и неодушевленное", "animate-inanimate", "Inmx"},
+
+	// Вид.
+	{"Совершенный", "perfective", "perf"},
+	{"Несовершенный", "imperfective", "impf"},
+	{"Двувидовой", "biaspectual", "biasp"},
+
+	// Падеж.
+	{"Именительный", "nominative", "nomn"},
+	{"Родительный", "genitive", "gent"},
+	{"Дательный", "dative", "datv"},
+	{"Винительный", "accusative", "accs"},
+	{"Творительный", "instrumental", "ablt"},
+	{"Предложный", "prepositional", "loct"},
+	{"Звательный", "vocative", "voct"},
+	{"Местный", "locative", "loc2"},
+	{"Счетный", "count", "cnt"},
+	{"Партитивный", "partitive", "gen2"},
+	{"Ждательный", "expectative", "gen3"},
+
+	// Род.
+	{"Мужской", "masculine", "masc"},
+	{"Женский", "feminine", "femn"},
+	{"Средний", "neuter", "neut"},
+	{"Общий", "common", "ms-f"},
+	{"Парный", "paired", "pair"},
+
+	// Наклонение, число, лицо.
+	{"Повелительное", "imperative", "impr"},
+	{"Единственное число", "singular", "sing"},
+	{"Множественное число", "plural", "plur"},
+	{"1-е лицо", "first-person", "1per"},
+	{"2-е лицо", "second-person", "2per"},
+	{"3-е лицо", "third-person", "3per"},
+	{"нет лица", "impersonal", "Impe"},
+
+	// Время, переходность, залог.
+	{"Прошедшее", "past", "past"},
+	{"Настоящее", "present", "pres"},
+	{"Будущее", "future", "futr"},
+	{"Будущее аналитическое", "analytic-future", "afut"},
+	{"Переходный", "transitive", "tran"},
+	{"Непереходный", "intransitive", "intr"},
+	{"Лабильный", "labile", "labl"},
+	{"Переходный глагол", "transitive-verb", "tranv"},
+	{"Непереходный глагол", "intransitive-verb", "intrv"},
+	{"Переходный и непереходный глагол", "labile-verb", "lablv"},
+	{"Действительный", "active", "actv"},
+	{"Страдательный", "passive", "pssv"},
+
+	// Глагольные формы.
+	{"Инфинитив", "infinitive", "INFN"},
+	{"Не инфинитив", "finite", "fint"},
+	{"Возвратный", "reflexive", "refl"},
+	{"Невозвратный", "non-reflexive", "nrfl"},
+	{"1-е спряжение", "first-conjugation", "conj1"},
+	{"2-е спряжение", "second-conjugation", "conj2"},
+
+	// Прилагательные и наречия.
+	{"Полная", "full", "Full"},
+	{"Краткая", "short", "Shrt"},
+	{"Нормальная", "normal", "Norm"},
+	{"Положительная", "positive", "Pos"},
+	{"Сравнительная", "comparative", "Cmp"},
+	{"Превосходная", "superlative", "Supr"},
+	{"положительная", "positive", "posa"},
+	{"сравнительная", "comparative", "cmpa"},
+	{"превосходная", "superlative", "supa"},
+	{"Качественное", "qualitative", "Qual"},
+	{"качественное", "qualitative", "qual"},
+	{"Относительное", "relative", "Rltv"},
+	{"относительное", "relative", "rltv"},
+	{"Притяжательное", "possessive", "Poss"},
+	{"Порядковое", "ordinal", "Anum"},
+	{"Местоименное", "pronominal", "Apro"},
+	{"Вопросительное", "interrogative", "Ques"},
+	{"Определительное", "qualifying", "Qlfy"},
+	{"Обстоятельственное", "circumstantial", "Circ"},
+	{"Адъективное", "adjectival", "Adjv"},
+	{"Нулевое", "zero", "Zero"},
+	{"наречие образа действия", "manner", "Mann"},
+	{"образа действия", "manner", "mann"},
+	{"наречие частоты", "frequency", "Freq"},
+	{"меры и степени", "measure", "Meas"},
+	{"времени", "time", "Time"},
+	{"отрицательное", "negative", "Negt"},
+
+	// Существительные.
+	{"Собственное", "proper", "Name"},
+	{"Нарицательное", "common-noun", "Comn"},
+	{"Абстрактное", "abstract", "Abst"},
+	{"Конкретное", "concrete", "Conc"},
+	{"вещественное", "material", "Matr"},
+	{"собирательное", "collective", "Coll"},
+	{"единичное", "singulative", "Sgltv"},
+	{"Исчисляемое", "countable", "Cntb"},
+	{"Неисчисляемое", "uncountable", "Uncn"},
+	{"Исчисляемое и неисчисляемое", "countable-uncountable", "CntU"},
+	{"1-е склонение", "first-declension", "decl1"},
+	{"2-е склонение", "second-declension", "decl2"},
+	{"3-е склонение", "third-declension", "decl3"},
+	{"адъективное склонение", "adjectival-declension", "declA"},
+	{"смешанное склонение", "mixed-declension", "declM"},
+	{"разносклоняемые", "heteroclitic", "declH"},
+	{"несклоняемые", "indeclinable", "Fixd"},
+	{"Форма количественно-отделительного падежа", "partitive-form", "gen2f"},
+	{"Форма местного падежа", "locative-form", "loc2f"},
+	{"Форма счетного падежа", "count-form", "cntf"},
+	{"не имеет дополнительного признака", "no-extra-feature", "NoExt"},
+
+	// Числительные.
+	{"Простое", "simple", "Smpl"},
+	{"Сложное", "compound", "Cmpd"},
+	{"Количественное целое", "cardinal", "Card"},
+	{"Количественное дробное", "fractional", "Frac"},
+	{"Количественное собирательное", "collective-numeral", "Colnum"},
+
+	// Местоимения.
+	{"личное местоимение", "personal", "Pers"},
+	{"возвратное местоимение", "reflexive-pronoun", "Rflp"},
+	{"возвратные местоимения", "reflexive-pronoun", "Rflps"},
+	{"притяжательное местоимение", "possessive-pronoun", "Posp"},
+	{"притяжательные местоимения", "possessive-pronoun", "Posps"},
+	{"неопределённые местоимения", "indefinite-pronoun", "Indf"},
+	{"определительные местоимения", "attributive-pronoun", "Attr"},
+	{"относительные местоимения", "relative-pronoun", "Relp"},
+	{"отрицательные местоимения", "negative-pronoun", "Negp"},
+	{"указательные местоимения", "demonstrative-pronoun", "Dmns"},
+
+	// Стилистические пометы.
+	{"Обычный", "standard", "Std"},
+	{"Разговорный", "colloquial", "Infr"},
+	{"Сленг", "slang", "Slng"},
+	{"Устаревший", "archaic", "Arch"},
+	{"Литературный", "literary", "Litr"},
+
+	// Пометы звеньев разбора несловарных токенов (см. units.go).
+	{"Цифры", "digits", "NUMB"},
+	{"Латиница", "latin", "LATN"},
+	{"Электронная почта", "email", "EMAIL"},
+	{"Ссылка", "url", "URL"},
+	{"Неизвестное", "unknown", "UNKN"},
+	{"Основа", "stem", "STEM"},
+}
+
+// grammemeByRussian, grammemeByEnglish и grammemeByCode находят перевод граммемы
+// по ее названию на соответствующем языке.
+var grammemeByRussian, grammemeByEnglish, grammemeByCode = func() (map[string]*grammemeName, map[string]*grammemeName, map[string]*grammemeName) {
+	ru := make(map[string]*grammemeName, len(grammemeNames))
+	en := make(map[string]*grammemeName, len(grammemeNames))
+	codes := make(map[string]*grammemeName, len(grammemeNames))
+	for i := range grammemeNames {
+		name := &grammemeNames[i]
+		ru[name.ru] = name
+		if _, ok := en[name.en]; !ok {
+			en[name.en] = name
+		}
+		codes[name.code] = name
+	}
+	return ru, en, codes
+}()
+
+// LocalizeGrammeme переводит граммему на язык lang. Граммема может быть записана
+// на любом из языков; неизвестная граммема возвращается без изменений.
+func LocalizeGrammeme(g string, lang Lang) string {
+	name := findGrammeme(g)
+	if name == nil {
+		return g
+	}
+	switch lang {
+	case LangEnglish:
+		return name.en
+	case LangCodes:
+		return name.code
+	default:
+		return name.ru
+	}
+}
+
+// canonicalGrammeme возвращает русское (словарное) название граммемы.
+func canonicalGrammeme(g string) string {
+	return LocalizeGrammeme(g, LangRussian)
+}
+
+// findGrammeme ищет перевод граммемы, записанной на любом из языков.
+func findGrammeme(g string) *grammemeName {
+	if name, ok := grammemeByRussian[g]; ok {
+		return name
+	}
+	if name, ok := grammemeByCode[g]; ok {
+		return name
+	}
+	return grammemeByEnglish[g]
+}
+
+// Localize возвращает копию разбора, в которой теги и граммемы переведены на язык lang.
+// Слово и лемма не меняются. Разбор может быть уже переведен на любой язык,
+// поэтому Localize(LangRussian) возвращает словарное представление.
+func (p *Parsed) Localize(lang Lang) *Parsed {
+	grammemes := strings.Split(p.Tags, ",")
+	for i, g := range grammemes {
+		grammemes[i] = canonicalGrammeme(g)
+	}
+	localized := newParsed(p.Word, p.Lemma, strings.Join(grammemes, ","))
+	localized.Method, localized.Confidence = p.Method, p.Confidence
+	localized.mask, localized.masked = p.mask, p.masked
+	if lang == LangRussian {
+		return localized
+	}
+
+	for i, g := range grammemes {
+		grammemes[i] = LocalizeGrammeme(g, lang)
+	}
+	localized.Tags = strings.Join(grammemes, ",")
+	for _, field := range []*string{
+		&localized.PartOfSpeech, &localized.Animacy, &localized.Aspect, &localized.Case,
+		&localized.Gender, &localized.Mood, &localized.Number, &localized.Person,
+		&localized.Tense, &localized.Transitivity, &localized.Voice,
+	} {
+		*field = LocalizeGrammeme(*field, lang)
+	}
+	otherTags := make(GrammemeSet, len(localized.OtherTags))
+	for g := range maps.Keys(localized.OtherTags) {
+		otherTags[LocalizeGrammeme(g, lang)] = struct{}{}
+	}
+	localized.OtherTags = otherTags
+	return localized
+}
+
+// WithLang задает язык граммем в результатах анализатора (по умолчанию - русский, как в словаре).
+// Переводятся разборы, которые возвращают Parse, ParsePredicted, Analyze, Inflect, Predict, ParseOCR,
+// списочные, потоковые и текстовые методы. Граммемы, которые анализатор принимает
+// (target в InflectListTo), и разборы в токенах для Disambiguate можно передавать на любом языке.
+func WithLang(lang Lang) Option {
+	return func(a *MorphAnalyzer) {
+		a.lang = lang
+	}
+}
+
+// Lang возвращает язык граммем в результатах анализатора (см. WithLang).
+func (a *MorphAnalyzer) Lang() Lang {
+	return a.lang
+}
+
+// localize переводит разборы на язык анализатора. Для русского языка срез возвращается как есть.
+func (a *MorphAnalyzer) localize(parses []*Parsed) []*Parsed {
+	if a.lang == LangRussian || parses == nil {
+		return parses
+	}
+	localized := make([]*Parsed, len(parses))
+	for i, p := range parses {
+		localized[i] = p.Localize(a.lang)
+	}
+	return localized
+}
+
+// localizeOne переводит один разбор на язык анализатора; nil остается nil.
+func (a *MorphAnalyzer) localizeOne(p *Parsed) *Parsed {
+	if a.lang == LangRussian || p == nil {
+		return p
+	}
+	return p.Localize(a.lang)
+}
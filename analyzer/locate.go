@@ -0,0 +1,251 @@
+// locate.go содержит поиск словаря для LoadMorphAnalyzer. Сам словарь поставляется
+// отдельным модулем (github.com/steosofficial/steosmorphy/dict), чтобы импорт анализатора
+// не тянул за собой сотни мегабайт данных: модуль словаря регистрирует себя через
+// RegisterDictLocator, а без него словарь берется из переменной окружения или из
+// DefaultDictDir, куда его скачивает EnsureDict. Части словаря, поставляемые модулем,
+// объединяются в файл только явно: функцией MergeDictParts или при загрузке с опцией
+// WithAutoMerge; без этого словарь загружается прямо из частей.
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// dictPartsPrefix - префикс имен частей словаря ("morph_aa", "morph_ab", ...).
+const dictPartsPrefix = "morph_"
+
+// DictSource - найденный словарь: путь к файлу, содержимое, уже находящееся в памяти
+// (например, встроенное в исполняемый файл), или директория с необъединенными частями.
+// Если задано Data, Path и Parts не используются; если задано Parts, не используется Path.
+type DictSource struct {
+	Path  string
+	Data  []byte
+	Parts string // Директория частей "morph_aa", "morph_ab", ... (см. LoadMorphAnalyzerFromParts).
+}
+
+// WithAutoMerge разрешает LoadMorphAnalyzer объединить части словаря, найденные источником
+// (например, пакетом dict), в DefaultDictDir (см. MergeDictPartsToCache): последующие
+// запуски отображают в память готовый файл. Без опции библиотека не пишет файлы,
+// а части, которые еще не объединены, загружаются напрямую (см. LoadMorphAnalyzerFromParts).
+func WithAutoMerge() Option {
+	return func(a *MorphAnalyzer) {
+		a.autoMerge = true
+	}
+}
+
+// DictLocator находит словарь. Ошибка означает, что источник недоступен,
+// и LoadMorphAnalyzer переходит к следующему.
+type DictLocator func() (DictSource, error)
+
+var (
+	locatorsMu sync.Mutex
+	locators   []DictLocator
+)
+
+// RegisterDictLocator добавляет источник словаря для LoadMorphAnalyzer.
+// Источники опрашиваются в порядке регистрации; обычно регистрация выполняется
+// в init пакета, поставляющего словарь (см. пакет dict).
+func RegisterDictLocator(l DictLocator) {
+	locatorsMu.Lock()
+	defer locatorsMu.Unlock()
+	locators = append(locators, l)
+}
+
+// DefaultDictDir возвращает директорию словаря по умолчанию в пользовательском кэше
+// (например, ~/.cache/steosmorphy). Туда скачивает словарь EnsureDict и туда же
+// объединяются части словаря, если их директория доступна только для чтения.
+func DefaultDictDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("ошибка определения директории кэша: %w", err)
+	}
+	return filepath.Join(cacheDir, "steosmorphy"), nil
+}
+
+// locateDict опрашивает зарегистрированные источники и директорию по умолчанию.
+func locateDict() (DictSource, error) {
+	locatorsMu.Lock()
+	registered := append([]DictLocator(nil), locators...)
+	locatorsMu.Unlock()
+
+	var errs []error
+	for _, locate := range registered {
+		source, err := locate()
+		if err == nil {
+			return source, nil
+		}
+		pkgLogger().Debug("источник словаря недоступен", "error", err)
+		errs = append(errs, err)
+	}
+
+	path := DictFileName
+	if dir, err := DefaultDictDir(); err == nil {
+		path = filepath.Join(dir, DictFileName)
+		if _, err := os.Stat(path); err == nil {
+			return DictSource{Path: path}, nil
+		}
+	}
+
+	// Словарь больше не лежит рядом с пакетом анализатора: без модуля dict
+	// его нужно подключить или скачать явно.
+	hint := fmt.Sprintf("импортируйте пакет github.com/steosofficial/steosmorphy/dict "+
+		"(import _ \"github.com/steosofficial/steosmorphy/dict\"), скачайте словарь в %s функцией EnsureDict "+
+		"или укажите путь к нему в переменной окружения %s", path, EnvDictPath)
+	if len(errs) == 0 {
+		return DictSource{}, fmt.Errorf("%w: источники словаря не зарегистрированы; %s", ErrDictNotFound, hint)
+	}
+	return DictSource{}, fmt.Errorf("%w: %s: %w", ErrDictNotFound, hint, errors.Join(errs...))
+}
+
+// MergeDictParts объединяет части словаря ("morph_aa", "morph_ab", ...) из директории dir
+// в файл out, при необходимости создавая его директорию. Объединенный файл сверяется
+// по контрольным суммам и атомарно заменяет out, поэтому параллельные загрузки
+// никогда не увидят недописанный или неверно собранный словарь.
+func MergeDictParts(dir, out string) error {
+	parts, err := findParts(dir, dictPartsPrefix)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return fmt.Errorf("ошибка создания директории словаря: %w", err)
+	}
+	pkgLogger().Info("объединение частей словаря", "parts", len(parts), "target", out)
+	return mergeFiles(parts, out)
+}
+
+// MergeDictPartsToCache объединяет части словаря из директории dir в DefaultDictDir
+// и возвращает путь к объединенному файлу; если эти части уже объединены, они
+// не перечитываются. Директория частей не изменяется, поэтому функция подходит
+// и для модуля, установленного только для чтения (как кэш модулей Go).
+func MergeDictPartsToCache(dir string) (string, error) {
+	target, err := partsCachePath(dir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+	if err := MergeDictParts(dir, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// partsCachePath возвращает путь к словарю, объединенному из частей в dir, в DefaultDictDir.
+// Имя поддиректории зависит от набора частей, чтобы обновленный словарь не подменялся устаревшим.
+func partsCachePath(dir string) (string, error) {
+	parts, err := findParts(dir, dictPartsPrefix)
+	if err != nil {
+		return "", err
+	}
+	cacheDir, err := DefaultDictDir()
+	if err != nil {
+		return "", err
+	}
+	key, err := partsKey(parts)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "parts-"+key, DictFileName), nil
+}
+
+// mergedParts возвращает путь к словарю, объединенному из частей в dir: объединенному
+// ранее или, если autoMerge, объединяемому сейчас (см. WithAutoMerge). Пустой путь
+// означает, что объединенного словаря нет и части нужно загрузить без объединения.
+func mergedParts(dir string, autoMerge bool) (string, error) {
+	if autoMerge {
+		return MergeDictPartsToCache(dir)
+	}
+	target, err := partsCachePath(dir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(target); err != nil {
+		return "", nil
+	}
+	return target, nil
+}
+
+// findParts возвращает пути частей словаря в порядке объединения.
+// split по умолчанию создает части с суффиксами aa, ab, ac, ..., поэтому
+// лексикографический порядок имен совпадает с порядком частей.
+func findParts(dir, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске частей словаря: %w", err)
+	}
+	var parts []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			parts = append(parts, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("не найдено файлов с префиксом '%s' в директории '%s'", prefix, dir)
+	}
+	sort.Strings(parts)
+	return parts, nil
+}
+
+// partsKey вычисляет короткий ключ набора частей по их именам и размерам.
+func partsKey(parts []string) (string, error) {
+	h := sha256.New()
+	for _, part := range parts {
+		info, err := os.Stat(part)
+		if err != nil {
+			return "", fmt.Errorf("ошибка чтения части словаря: %w", err)
+		}
+		fmt.Fprintf(h, "%s:%d\n", filepath.Base(part), info.Size())
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// mergeFiles записывает части подряд во временный файл, сверяет его контрольные суммы
+// и переименовывает его в target.
+func mergeFiles(parts []string, target string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(target), DictFileName+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла словаря: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	for _, part := range parts {
+		if err := appendFile(tmp, part); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи файла словаря: %w", err)
+	}
+	// Пропавшая или лишняя часть дает файл, который загрузится, но будет выдавать мусор.
+	if err := VerifyDict(tmp.Name()); err != nil && !errors.Is(err, ErrNoChecksums) {
+		return fmt.Errorf("объединенный из %d частей словарь поврежден: %w", len(parts), err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("ошибка перемещения словаря в %s: %w", target, err)
+	}
+	return nil
+}
+
+// appendFile дописывает содержимое файла path в w.
+func appendFile(w io.Writer, path string) error {
+	part, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия части словаря %s: %w", path, err)
+	}
+	defer part.Close()
+	if _, err := io.Copy(w, part); err != nil {
+		return fmt.Errorf("ошибка копирования части словаря %s: %w", path, err)
+	}
+	return nil
+}
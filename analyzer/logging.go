@@ -0,0 +1,66 @@
+// logging.go содержит журналирование через log/slog. Библиотека не пишет в stdout и stderr:
+// по умолчанию журнал отбрасывается, а сервис подключает свой *slog.Logger - для функций
+// пакета через SetLogger, для отдельного анализатора опцией WithLogger.
+package analyzer
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// discardLogger - журнал по умолчанию: все записи отбрасываются.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// packageLogger - журнал, заданный SetLogger (nil - не задан).
+var packageLogger atomic.Pointer[slog.Logger]
+
+// SetLogger задает журнал для функций пакета, которые работают без анализатора
+// (поиск словаря, объединение его частей, EnsureDict), и журнал по умолчанию
+// для анализаторов без WithLogger. nil возвращает отбрасывание записей.
+// Безопасен для параллельного вызова.
+func SetLogger(l *slog.Logger) {
+	packageLogger.Store(l)
+}
+
+// WithLogger задает журнал анализатора: диагностику загрузки словаря (уровень Debug)
+// и перезагрузки словаря DictWatcher (Info, при ошибке - Warn).
+func WithLogger(l *slog.Logger) Option {
+	return func(a *MorphAnalyzer) {
+		a.logger = l
+	}
+}
+
+// pkgLogger возвращает журнал функций пакета.
+func pkgLogger() *slog.Logger {
+	if l := packageLogger.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+// log возвращает журнал анализатора.
+func (a *MorphAnalyzer) log() *slog.Logger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return pkgLogger()
+}
+
+// logLoaded записывает в журнал диагностику только что загруженного словаря.
+func (a *MorphAnalyzer) logLoaded(source string, started time.Time) {
+	a.log().Debug("словарь загружен",
+		"source", source,
+		"duration", time.Since(started),
+		"version", a.info.Version,
+		"load_mode", a.info.LoadMode,
+		"lemmas", a.lemmas.len(),
+		"tag_sets", a.tags.len(),
+		"paradigms", a.paradigms.len(),
+		"nodes", len(a.nodes),
+		"predict_nodes", len(a.predictNodes),
+		"self_test", len(a.selfTest) > 0,
+		"tag_index", a.tagIndex != nil,
+		"relations", a.HasRelations(),
+	)
+}
@@ -0,0 +1,142 @@
+// lookup.go содержит стратегии поиска перехода по символу в DAWG и выбор стратегии
+// при загрузке словаря по замеру на текущем оборудовании.
+package analyzer
+
+import (
+	"strings"
+	"time"
+)
+
+// LookupStrategy - способ поиска перехода по символу в DAWG.
+type LookupStrategy int
+
+const (
+	// LookupAuto выбирает стратегию при загрузке по короткому замеру на словах словаря.
+	LookupAuto LookupStrategy = iota
+	// LookupBinarySearch - бинарный поиск по отсортированным ребрам каждого узла.
+	LookupBinarySearch
+	// LookupFirstCharIndex - прямая таблица переходов из корня по первому символу слова
+	// (у корня самое большое число ребер) и бинарный поиск в остальных узлах.
+	LookupFirstCharIndex
+)
+
+// String возвращает имя стратегии, как его печатает "steosmorphy bench".
+func (s LookupStrategy) String() string {
+	switch s {
+	case LookupBinarySearch:
+		return "binary-search"
+	case LookupFirstCharIndex:
+		return "first-char-index"
+	default:
+		return "auto"
+	}
+}
+
+// maxFirstCharIndexSpan ограничивает размер таблицы переходов из корня: если символы
+// корневых ребер разбросаны по Unicode слишком широко, таблица не строится.
+const maxFirstCharIndexSpan = 1 << 16
+
+// calibrationDuration - время замера каждой стратегии при LookupAuto.
+const calibrationDuration = 2 * time.Millisecond
+
+// WithLookupStrategy задает стратегию поиска переходов вместо выбора по замеру при загрузке.
+func WithLookupStrategy(s LookupStrategy) Option {
+	return func(a *MorphAnalyzer) {
+		a.lookupStrategy = s
+	}
+}
+
+// LookupStrategy возвращает стратегию поиска переходов, выбранную при загрузке.
+func (a *MorphAnalyzer) LookupStrategy() LookupStrategy {
+	return a.lookupStrategy
+}
+
+// firstCharIndex - таблица переходов из корня DAWG, индексированная символом.
+type firstCharIndex struct {
+	base     rune     // Наименьший символ корневых ребер.
+	children []uint32 // ID дочернего узла + 1 для символа base+i; 0 - перехода нет.
+}
+
+// newFirstCharIndex строит таблицу переходов из корня. Возвращает nil для пустого графа
+// и для слишком разреженного набора символов.
+func newFirstCharIndex(nodes []FlatNode, edges []FlatEdge) *firstCharIndex {
+	if len(nodes) == 0 || nodes[0].EdgesLen == 0 {
+		return nil
+	}
+	root := nodes[0]
+	rootEdges := edges[root.EdgesIdx : root.EdgesIdx+uint32(root.EdgesLen)]
+	// Ребра отсортированы по символу: первое и последнее задают диапазон таблицы.
+	base, last := rootEdges[0].Char, rootEdges[len(rootEdges)-1].Char
+	if last-base >= maxFirstCharIndexSpan {
+		return nil
+	}
+	index := &firstCharIndex{base: base, children: make([]uint32, last-base+1)}
+	for _, e := range rootEdges {
+		index.children[e.Char-base] = e.NodeID + 1
+	}
+	return index
+}
+
+// lookup возвращает дочерний узел корня по символу.
+func (ix *firstCharIndex) lookup(char rune) (uint32, bool) {
+	i := char - ix.base
+	if i < 0 || int(i) >= len(ix.children) || ix.children[i] == 0 {
+		return 0, false
+	}
+	return ix.children[i] - 1, true
+}
+
+// initLookup применяет стратегию поиска: при LookupAuto сначала выбирает ее по замеру.
+func (a *MorphAnalyzer) initLookup() {
+	if a.lookupStrategy == LookupAuto {
+		a.lookupStrategy = a.calibrateLookup()
+	}
+	a.rootIndex, a.predictRootIndex = nil, nil
+	if a.lookupStrategy == LookupFirstCharIndex {
+		a.rootIndex = newFirstCharIndex(a.nodes, a.edges)
+		a.predictRootIndex = newFirstCharIndex(a.predictNodes, a.predictEdges)
+	}
+}
+
+// calibrateLookup замеряет обе стратегии на словах словаря и возвращает более быструю.
+func (a *MorphAnalyzer) calibrateLookup() LookupStrategy {
+	index := newFirstCharIndex(a.nodes, a.edges)
+	words := a.sampleWords(512)
+	if index == nil || len(words) == 0 {
+		return LookupBinarySearch
+	}
+	binary := measure("", calibrationDuration, func() { a.walkAll(words, nil) })
+	indexed := measure("", calibrationDuration, func() { a.walkAll(words, index) })
+	if indexed.NsPerOp < binary.NsPerOp {
+		return LookupFirstCharIndex
+	}
+	return LookupBinarySearch
+}
+
+// sampleWords возвращает до n лемм, равномерно выбранных из пула, в нижнем регистре.
+func (a *MorphAnalyzer) sampleWords(n int) []string {
+	step := max(a.lemmas.len()/n, 1)
+	words := make([]string, 0, n)
+	for i := 0; i < a.lemmas.len() && len(words) < n; i += step {
+		words = append(words, strings.ToLower(a.lemmas.raw(uint32(i))))
+	}
+	return words
+}
+
+// walkAll проходит по основному DAWG путь каждого слова с заданной таблицей корня
+// (nil - только бинарный поиск) и возвращает число найденных путей.
+func (a *MorphAnalyzer) walkAll(words []string, root *firstCharIndex) int {
+	found := 0
+	for _, word := range words {
+		currentNodeIndex, ok := uint32(0), true
+		for _, char := range word {
+			if currentNodeIndex, ok = a.findChildGeneral(currentNodeIndex, char, a.nodes, a.edges, root); !ok {
+				break
+			}
+		}
+		if ok {
+			found++
+		}
+	}
+	return found
+}
@@ -0,0 +1,8 @@
+package analyzer
+
+import "syscall"
+
+// adviseWillNeed просит ОС заранее прочитать страницы b.
+func adviseWillNeed(b []byte) error {
+	return syscall.Madvise(b, syscall.MADV_WILLNEED)
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package analyzer
+
+// adviseWillNeed ничего не делает: на этой платформе страницы прогреваются только
+// обращением к ним (см. touchPages).
+func adviseWillNeed([]byte) error {
+	return nil
+}
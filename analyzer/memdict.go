@@ -0,0 +1,37 @@
+// memdict.go содержит сборку анализатора из лексикона прямо в памяти, без файла словаря:
+// для модульных тестов кода, зависящего от анализатора, которым не нужен полный словарь
+// (готовый мини-словарь поставляет пакет github.com/steosofficial/steosmorphy/testdict).
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// BuildFromLexicon компилирует лексикон entries в память и загружает из него анализатор
+// (Info().LoadMode равен heap). Опции применяются так же, как в LoadMorphAnalyzer.
+func BuildFromLexicon(entries []LexEntry, opts ...Option) (*MorphAnalyzer, error) {
+	builder := NewDictBuilder()
+	for _, e := range entries {
+		if err := builder.Add(e); err != nil {
+			return nil, err
+		}
+	}
+	return builder.BuildAnalyzer(opts...)
+}
+
+// BuildAnalyzer компилирует лексикон в память и загружает из него анализатор, не записывая файл.
+func (b *DictBuilder) BuildAnalyzer(opts ...Option) (*MorphAnalyzer, error) {
+	started := time.Now()
+	var buf bytes.Buffer
+	if err := b.Build(&buf); err != nil {
+		return nil, fmt.Errorf("ошибка сборки словаря: %w", err)
+	}
+	analyzer, err := loadFromBytes(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	analyzer.setLoadMode(LoadModeHeap)
+	return finishLoad(analyzer, "лексикон в памяти", opts, started)
+}
@@ -0,0 +1,19 @@
+//go:build !(linux || darwin)
+
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// lockMemory возвращает ошибку: платформа не поддерживает mlock.
+func lockMemory([]byte) error {
+	return fmt.Errorf("%w на %s", errors.ErrUnsupported, runtime.GOOS)
+}
+
+// unlockMemory ничего не делает: lockMemory на этой платформе всегда завершается ошибкой.
+func unlockMemory([]byte) error {
+	return nil
+}
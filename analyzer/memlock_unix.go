@@ -0,0 +1,15 @@
+//go:build linux || darwin
+
+package analyzer
+
+import "syscall"
+
+// lockMemory закрепляет страницы b в оперативной памяти.
+func lockMemory(b []byte) error {
+	return syscall.Mlock(b)
+}
+
+// unlockMemory снимает закрепление, сделанное lockMemory.
+func unlockMemory(b []byte) error {
+	return syscall.Munlock(b)
+}
@@ -0,0 +1,84 @@
+// microbench.go содержит набор микробенчмарков горячего пути анализатора, который можно
+// запустить на оборудовании пользователя ("steosmorphy bench") без инструментов go test.
+package analyzer
+
+import (
+	"slices"
+	"time"
+)
+
+// BenchResult - результат одного микробенчмарка.
+type BenchResult struct {
+	Name       string  // Имя бенчмарка, например "dawg/binary-search".
+	Iterations int     // Количество выполненных операций.
+	NsPerOp    float64 // Среднее время одной операции в наносекундах.
+}
+
+// MicroBenchmarks замеряет операции горячего пути, выполняя каждую не меньше duration:
+//   - dawg/binary-search, dawg/first-char-index - проход слова по DAWG каждой стратегией;
+//   - tags/parse - разбор строки тегов в Parsed;
+//   - forms/generate - генерация всех словоформ лексемы.
+//
+// Операция - обработка одного слова, строки тегов или лексемы. Результаты dawg/*
+// показывают, какую стратегию выбрать через WithLookupStrategy.
+func (a *MorphAnalyzer) MicroBenchmarks(duration time.Duration) []BenchResult {
+	words := a.sampleWords(512)
+	index := newFirstCharIndex(a.nodes, a.edges)
+
+	results := []BenchResult{
+		perItem(measure("dawg/binary-search", duration, func() { a.walkAll(words, nil) }), len(words)),
+	}
+	if index != nil {
+		results = append(results, perItem(measure("dawg/first-char-index", duration, func() { a.walkAll(words, index) }), len(words)))
+	}
+
+	tags := a.tags.all()[:min(a.tags.len(), 256)]
+	results = append(results, perItem(measure("tags/parse", duration, func() {
+		for _, t := range tags {
+			newParsed("", "", t)
+		}
+	}), len(tags)))
+
+	paradigms := a.sampleParadigms(64)
+	results = append(results, perItem(measure("forms/generate", duration, func() {
+		for _, pID := range paradigms {
+			a.lexemeForms(pID)
+		}
+	}), len(paradigms)))
+	return results
+}
+
+// sampleParadigms возвращает до n ID парадигм, равномерно выбранных по возрастанию ID.
+func (a *MorphAnalyzer) sampleParadigms(n int) []uint32 {
+	ids := slices.Collect(a.paradigms.ids())
+	step := max(len(ids)/n, 1)
+	sample := make([]uint32, 0, n)
+	for i := 0; i < len(ids) && len(sample) < n; i += step {
+		sample = append(sample, ids[i])
+	}
+	return sample
+}
+
+// measure выполняет op, пока не истечет duration (но хотя бы один раз).
+func measure(name string, duration time.Duration, op func()) BenchResult {
+	iterations := 0
+	start := time.Now()
+	for {
+		op()
+		iterations++
+		if time.Since(start) >= duration {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+	return BenchResult{Name: name, Iterations: iterations, NsPerOp: float64(elapsed.Nanoseconds()) / float64(iterations)}
+}
+
+// perItem пересчитывает результат замера пакета из n элементов на один элемент.
+func perItem(r BenchResult, n int) BenchResult {
+	if n > 0 {
+		r.Iterations *= n
+		r.NsPerOp /= float64(n)
+	}
+	return r
+}
@@ -0,0 +1,181 @@
+// mixedscript.go содержит исправление слов, в которых кириллица смешана с латиницей
+// ("Моskва", "пpивет" с латинской p): частая проблема OCR и спама, из-за которой
+// слово не находится в словаре.
+package analyzer
+
+import (
+	"unicode"
+)
+
+// Стоимость замены латинской буквы кириллической: похожие по начертанию буквы
+// (p -> р) заменяются охотнее, чем похожие по звучанию (s -> с).
+const (
+	visualLookalikeCost   = 1
+	phoneticLookalikeCost = 2
+)
+
+// visualLookalikes - кириллические буквы, похожие на латинские по начертанию
+// (включая рукописные и курсивные формы, которые путает OCR).
+var visualLookalikes = map[rune][]rune{
+	'a': {'а'}, 'b': {'ь'}, 'c': {'с'}, 'e': {'е'}, 'k': {'к'}, 'm': {'т'}, 'n': {'п'},
+	'o': {'о'}, 'p': {'р'}, 'r': {'г'}, 'u': {'и'}, 'x': {'х'}, 'y': {'у'},
+	'A': {'а'}, 'B': {'в'}, 'C': {'с'}, 'E': {'е'}, 'H': {'н'}, 'K': {'к'}, 'M': {'м'},
+	'O': {'о'}, 'P': {'р'}, 'T': {'т'}, 'X': {'х'}, 'Y': {'у'},
+}
+
+// phoneticLookalikes - кириллические буквы, соответствующие латинским по звучанию.
+var phoneticLookalikes = map[rune][]rune{
+	'a': {'а'}, 'b': {'б'}, 'c': {'ц', 'к'}, 'd': {'д'}, 'e': {'е', 'э'}, 'f': {'ф'},
+	'g': {'г'}, 'h': {'х'}, 'i': {'и'}, 'j': {'й'}, 'k': {'к'}, 'l': {'л'}, 'm': {'м'},
+	'n': {'н'}, 'o': {'о'}, 'p': {'п'}, 'q': {'к'}, 'r': {'р'}, 's': {'с'}, 't': {'т'},
+	'u': {'у'}, 'v': {'в'}, 'w': {'в'}, 'y': {'ы', 'й'}, 'z': {'з'},
+}
+
+// homoglyphs - латинские буквы и неотличимые от них по начертанию кириллические
+// (в отличие от visualLookalikes - только точные двойники, без курсивных и рукописных форм).
+var homoglyphs = map[rune]rune{
+	'a': 'а', 'c': 'с', 'e': 'е', 'o': 'о', 'p': 'р', 'x': 'х', 'y': 'у',
+	'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'K': 'К', 'M': 'М',
+	'O': 'О', 'P': 'Р', 'T': 'Т', 'X': 'Х', 'Y': 'У',
+}
+
+// latinHomoglyphs - обратная к homoglyphs таблица: кириллическая буква -> латинский двойник.
+var latinHomoglyphs = func() map[rune]rune {
+	m := make(map[rune]rune, len(homoglyphs))
+	for latin, cyrillic := range homoglyphs {
+		m[cyrillic] = latin
+	}
+	return m
+}()
+
+// WithHomoglyphFolding включает замену букв-двойников до поиска в словаре (см. FoldHomoglyphs):
+// "пpивет" с латинской p разбирается как "привет" всеми звеньями цепочки, а не уходит
+// в предсказатель, и "pаypal" с кириллической а - как латинское слово. В отличие
+// от WithMixedScriptRepair замена не ищет вариантов по словарю, а поле Word разбора
+// содержит уже исправленное слово.
+func WithHomoglyphFolding() Option {
+	return func(a *MorphAnalyzer) {
+		a.foldHomoglyphs = true
+	}
+}
+
+// FoldHomoglyphs заменяет в слове со смешанными кириллицей и латиницей буквы-двойники
+// (a и а, o и о, P и Р, ...) буквами основного алфавита слова. Основной алфавит - тот,
+// у которого в слове есть буквы без двойника ("пpивет" - кириллица, "pаypal" - латиница);
+// если таких букв нет ни у одного ("сoр"), слово считается кириллическим. Слова с одним
+// алфавитом и слова, где буквы без двойника есть у обоих ("Моskва": такие исправляет
+// RepairMixedScript), возвращаются без изменений.
+func FoldHomoglyphs(word string) string {
+	cyrillicOnly, latinOnly := 0, 0
+	hasCyrillic, hasLatin := false, false
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			hasCyrillic = true
+			if _, ok := latinHomoglyphs[r]; !ok {
+				cyrillicOnly++
+			}
+		case unicode.Is(unicode.Latin, r):
+			hasLatin = true
+			if _, ok := homoglyphs[r]; !ok {
+				latinOnly++
+			}
+		}
+	}
+	if !hasCyrillic || !hasLatin || cyrillicOnly > 0 && latinOnly > 0 {
+		return word
+	}
+	table := homoglyphs
+	if latinOnly > 0 {
+		table = latinHomoglyphs
+	}
+	runes := []rune(word)
+	for i, r := range runes {
+		if folded, ok := table[r]; ok {
+			runes[i] = folded
+		}
+	}
+	return string(runes)
+}
+
+// WithMixedScriptRepair включает исправление слов со смешанной кириллицей и латиницей:
+// если такого слова нет в словаре, Parse (а через него Analyze и Inflect) разбирает
+// исправленное слово (см. RepairMixedScript). Поле Word разбора сохраняет исходное написание.
+func WithMixedScriptRepair() Option {
+	return func(a *MorphAnalyzer) {
+		a.repairMixedScript = true
+	}
+}
+
+// IsMixedScript сообщает, что в слове есть и кириллические, и латинские буквы.
+func IsMixedScript(word string) bool {
+	hasCyrillic, hasLatin := false, false
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			hasCyrillic = true
+		case unicode.Is(unicode.Latin, r):
+			hasLatin = true
+		}
+	}
+	return hasCyrillic && hasLatin
+}
+
+// RepairMixedScript заменяет латинские буквы слова со смешанным алфавитом кириллическими
+// так, чтобы получилось словарное слово ("Моskва" -> "Москва"). Среди вариантов выбирается
+// тот, где меньше замен по звучанию; регистр букв сохраняется.
+// Если слово не смешанное или ни один вариант не найден в словаре, возвращает word и false.
+func (a *MorphAnalyzer) RepairMixedScript(word string) (string, bool) {
+	if !IsMixedScript(word) {
+		return word, false
+	}
+	runes := []rune(word)
+	current := make([]rune, len(runes))
+	var best []rune
+	bestCost := -1
+
+	// Перебор в глубину по DAWG: ветвление только на латинских буквах, а пути,
+	// которых нет в словаре, отсекаются сразу.
+	var walk func(pos int, nodeIndex uint32, cost int)
+	walk = func(pos int, nodeIndex uint32, cost int) {
+		if bestCost >= 0 && cost >= bestCost {
+			return
+		}
+		if pos == len(runes) {
+			if a.nodes[nodeIndex].IsFinal {
+				best, bestCost = append(best[:0], current...), cost
+			}
+			return
+		}
+
+		original := runes[pos]
+		try := func(candidate rune, extra int) {
+			next, ok := a.findChildGeneral(nodeIndex, candidate, a.nodes, a.edges, a.rootIndex)
+			if !ok {
+				return
+			}
+			current[pos] = candidate
+			if unicode.IsUpper(original) {
+				current[pos] = unicode.ToUpper(candidate)
+			}
+			walk(pos+1, next, cost+extra)
+		}
+
+		if !unicode.Is(unicode.Latin, original) {
+			try(unicode.ToLower(original), 0)
+			return
+		}
+		for _, candidate := range visualLookalikes[original] {
+			try(candidate, visualLookalikeCost)
+		}
+		for _, candidate := range phoneticLookalikes[unicode.ToLower(original)] {
+			try(candidate, phoneticLookalikeCost)
+		}
+	}
+	walk(0, 0, 0)
+
+	if best == nil {
+		return word, false
+	}
+	return string(best), true
+}
@@ -0,0 +1,144 @@
+// number.go содержит логику, связанную с грамматическим числом существительных:
+// определение слов, употребляемых только во множественном ("ножницы") или только
+// в единственном ("доброта") числе, и согласование существительного с числительным.
+package analyzer
+
+import (
+	"strings"
+)
+
+// NumberTantum - ограничение существительного по числу.
+type NumberTantum int
+
+const (
+	NoTantum         NumberTantum = iota // Есть формы обоих чисел.
+	PluraliaTantum                       // Только множественное число ("ножницы", "сани").
+	SingulariaTantum                     // Только единственное число ("доброта", "смелость").
+)
+
+const (
+	singular = "Единственное число"
+	plural   = "Множественное число"
+	genitive = "Родительный"
+)
+
+// NumberTantum определяет по парадигме словарного существительного, употребляется ли оно
+// только в одном числе. Для несуществительных и несловарных слов возвращает NoTantum.
+func (a *MorphAnalyzer) NumberTantum(word string) NumberTantum {
+	for _, info := range a.lookupPayloads(strings.ToLower(word)) {
+		if !hasGrammeme(a.tags.at(info.TagsID), "Существительное") {
+			continue
+		}
+		return a.lexemeTantum(a.lexemeForms(info.ParadigmID))
+	}
+	return NoTantum
+}
+
+// lexemeTantum вычисляет ограничение по числу для набора форм одной лексемы.
+func (a *MorphAnalyzer) lexemeTantum(forms []lexemeForm) NumberTantum {
+	hasSingular, hasPlural := false, false
+	cases := make(map[string]struct{})
+	for _, f := range forms {
+		p := newParsed(f.word, "", a.tags.at(f.tagsID))
+		switch p.Number {
+		case singular:
+			hasSingular = true
+		case plural:
+			hasPlural = true
+		}
+		if p.Case != "" {
+			cases[p.Case] = struct{}{}
+		}
+	}
+
+	switch {
+	case hasPlural && !hasSingular:
+		return PluraliaTantum
+	// Несклоняемые слова ("кофе") словарь хранит одной формой единственного числа,
+	// поэтому singularia tantum считаем только полноценно склоняемые существительные.
+	case hasSingular && !hasPlural && len(cases) > 1:
+		return SingulariaTantum
+	}
+	return NoTantum
+}
+
+// MakeAgreeWithNumber ставит существительное в форму, согласованную с числом n
+// ("кот": 1 кот, 2 кота, 5 котов), сохраняя падеж исходной формы для косвенных падежей.
+// Учитывает ограничения по числу: для "ножницы" несуществующее единственное число не строится
+// (1 ножницы, 2 ножниц). Возвращает пустую строку, если подходящей формы нет.
+func (a *MorphAnalyzer) MakeAgreeWithNumber(word string, n int) string {
+	lowerWord := strings.ToLower(word)
+	for _, info := range a.lookupPayloads(lowerWord) {
+		p := newParsed(lowerWord, "", a.tags.at(info.TagsID))
+		if p.PartOfSpeech != "Существительное" {
+			continue
+		}
+		if p.Case == "" {
+			// Неизменяемое слово согласовывать не во что.
+			return lowerWord
+		}
+
+		forms := a.lexemeForms(info.ParadigmID)
+		targetCase, targetNumber := agreementTarget(p.Case, n, a.lexemeTantum(forms))
+		if form, ok := a.pickForm(forms, targetCase, targetNumber); ok {
+			return form
+		}
+		if len(forms) == 1 {
+			// Лексема из одной формы ("кофе") не изменяется по числам и падежам.
+			return forms[0].word
+		}
+	}
+	return ""
+}
+
+// agreementTarget вычисляет падеж и число существительного при числительном n.
+func agreementTarget(wordCase string, n int, tantum NumberTantum) (string, string) {
+	if n < 0 {
+		n = -n
+	}
+	last, lastTwo := n%10, n%100
+	isOne := last == 1 && lastTwo != 11
+	isFew := last >= 2 && last <= 4 && (lastTwo < 12 || lastTwo > 14)
+
+	number := plural
+	switch {
+	case tantum == PluraliaTantum:
+		number = plural
+	case tantum == SingulariaTantum, isOne:
+		number = singular
+	}
+
+	// В косвенных падежах числительное согласуется с существительным: "двум котам".
+	if wordCase != "Именительный" && wordCase != "Винительный" {
+		return wordCase, number
+	}
+	// В именительном и винительном падежах существительное управляется числительным:
+	// 1 кот, 2 кота, 5 котов.
+	switch {
+	case isOne:
+		return wordCase, number
+	case isFew && tantum == NoTantum:
+		return genitive, singular
+	}
+	return genitive, number
+}
+
+// pickForm выбирает из лексемы форму с заданными падежом и числом.
+// Формы с пометами "Устаревший" и "Разговорный" используются, только если других нет.
+func (a *MorphAnalyzer) pickForm(forms []lexemeForm, targetCase, targetNumber string) (string, bool) {
+	fallback := ""
+	for _, f := range forms {
+		tags := a.tags.at(f.tagsID)
+		if !hasGrammeme(tags, targetCase) || !hasGrammeme(tags, targetNumber) {
+			continue
+		}
+		if isNonNormative(tags) {
+			if fallback == "" {
+				fallback = f.word
+			}
+			continue
+		}
+		return f.word, true
+	}
+	return fallback, fallback != ""
+}
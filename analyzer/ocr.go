@@ -0,0 +1,117 @@
+// ocr.go содержит разбор слов с типичными ошибками распознавания текста (OCR):
+// поиск по DAWG словарных слов, отличающихся от исходного одной заменой символа
+// из набора путаемых пар (и/н, п/л, ш/щ, 0/о, ...).
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// ocrConfusions - символы, которые OCR путает с данным, и уверенность в такой замене.
+// Пары с цифрами исправляют слова вида "к0т" и "3има".
+var ocrConfusions = map[rune][]ocrConfusion{
+	'и': {{'н', 0.8}, {'й', 0.9}, {'п', 0.6}},
+	'н': {{'и', 0.8}, {'п', 0.7}},
+	'п': {{'л', 0.8}, {'н', 0.7}, {'и', 0.6}},
+	'л': {{'п', 0.8}, {'д', 0.6}},
+	'д': {{'л', 0.6}},
+	'й': {{'и', 0.9}},
+	'ш': {{'щ', 0.9}},
+	'щ': {{'ш', 0.9}, {'ц', 0.6}},
+	'ц': {{'щ', 0.6}},
+	'е': {{'ё', 0.9}, {'с', 0.6}},
+	'ё': {{'е', 0.9}},
+	'с': {{'е', 0.6}, {'о', 0.6}},
+	'о': {{'с', 0.6}},
+	'ь': {{'ъ', 0.8}, {'б', 0.6}},
+	'ъ': {{'ь', 0.8}},
+	'б': {{'ь', 0.6}},
+	'т': {{'г', 0.7}},
+	'г': {{'т', 0.7}},
+	'з': {{'э', 0.6}},
+	'э': {{'з', 0.6}},
+	'0': {{'о', 0.8}},
+	'3': {{'з', 0.8}},
+	'6': {{'б', 0.8}},
+	'4': {{'ч', 0.7}},
+}
+
+// ocrConfusion - замена, возможная при ошибке OCR.
+type ocrConfusion struct {
+	char       rune
+	confidence float64
+}
+
+// FuzzyMatch - словарное слово, найденное по слову с ошибкой OCR.
+type FuzzyMatch struct {
+	Word       string    // Исправленное словарное слово (в нижнем регистре).
+	Confidence float64   // Уверенность в исправлении от 0 до 1 (точное совпадение - 1).
+	Parses     []*Parsed // Разборы исправленного слова; поле Word содержит исходное написание.
+}
+
+// WithOCRTolerance включает исправление ошибок OCR: если слова нет в словаре,
+// Parse (а через него Analyze и Inflect) разбирает самое вероятное исправление (см. ParseOCR).
+// Такие разборы менее надежны; узнать исправление и его уверенность можно через ParseOCR.
+func WithOCRTolerance() Option {
+	return func(a *MorphAnalyzer) {
+		a.ocrTolerance = true
+	}
+}
+
+// ParseOCR ищет словарные слова, отличающиеся от word не более чем одной заменой символа
+// из набора путаемых OCR пар, и возвращает их разборы по убыванию уверенности.
+// Для словарного слова возвращает одно совпадение с уверенностью 1.
+func (a *MorphAnalyzer) ParseOCR(word string) []FuzzyMatch {
+	lowerWord := strings.ToLower(word)
+	if a.lookupPayloads(lowerWord) != nil {
+		return []FuzzyMatch{{Word: lowerWord, Confidence: 1, Parses: a.localize(a.parse(word))}}
+	}
+
+	runes := []rune(lowerWord)
+	current := make([]rune, len(runes))
+	confidence := make(map[string]float64)
+
+	// Обход в глубину по DAWG: точный переход по символу и, пока замена не потрачена,
+	// переходы по путаемым символам.
+	var walk func(pos int, nodeIndex uint32, substituted float64)
+	walk = func(pos int, nodeIndex uint32, substituted float64) {
+		if pos == len(runes) {
+			if substituted > 0 && a.nodes[nodeIndex].IsFinal {
+				w := string(current)
+				confidence[w] = max(confidence[w], substituted)
+			}
+			return
+		}
+		if next, ok := a.findChildGeneral(nodeIndex, runes[pos], a.nodes, a.edges, a.rootIndex); ok {
+			current[pos] = runes[pos]
+			walk(pos+1, next, substituted)
+		}
+		if substituted > 0 {
+			return
+		}
+		for _, c := range ocrConfusions[runes[pos]] {
+			if next, ok := a.findChildGeneral(nodeIndex, c.char, a.nodes, a.edges, a.rootIndex); ok {
+				current[pos] = c.char
+				walk(pos+1, next, c.confidence)
+			}
+		}
+	}
+	walk(0, 0, 0)
+
+	matches := make([]FuzzyMatch, 0, len(confidence))
+	for w, c := range confidence {
+		parses := a.parse(w)
+		for _, p := range parses {
+			p.Word = word
+		}
+		matches = append(matches, FuzzyMatch{Word: w, Confidence: c, Parses: a.localize(parses)})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].Word < matches[j].Word
+	})
+	return matches
+}
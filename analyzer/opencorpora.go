@@ -0,0 +1,413 @@
+// opencorpora.go содержит чтение исходных лексиконов для компилятора словаря:
+// XML-дампа OpenCorpora (dict.opcorpora.xml) и простого TSV-формата.
+// Граммемы OpenCorpora (латинские коды NOUN, nomn, ...) переводятся в теги словаря.
+package analyzer
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// openCorporaGrammemes сопоставляет граммемам OpenCorpora теги словаря.
+// Часть речи OpenCorpora иногда соответствует паре тегов: ADJS - это краткое прилагательное.
+// Граммемы, которых нет в таблице, при компиляции отбрасываются.
+var openCorporaGrammemes = map[string][]string{
+	// Части речи.
+	"NOUN": {"Существительное"},
+	"ADJF": {"Прилагательное", "Полная"},
+	"ADJS": {"Прилагательное", "Краткая"},
+	"COMP": {"Прилагательное", "Сравнительная"},
+	"VERB": {"Глагол", "Не инфинитив"},
+	"INFN": {"Глагол", "Инфинитив"},
+	"PRTF": {"Причастие", "Полная"},
+	"PRTS": {"Причастие", "Краткая"},
+	"GRND": {"Деепричастие"},
+	"NUMR": {"Числительное"},
+	"ADVB": {"Наречие"},
+	"NPRO": {"Местоимение"},
+	"PRED": {"Наречие"},
+	"PREP": {"Предлог"},
+	"CONJ": {"Союз"},
+	"PRCL": {"Частица"},
+	"INTJ": {"Междометие"},
+
+	// Одушевленность.
+	"anim": {"Одушевленное"},
+	"inan": {"Неодушевленное"},
+	"Inmx": {"одушевленное и неодушевленное"},
+
+	// Род.
+	"masc": {"Мужской"},
+	"femn": {"Женский"},
+	"neut": {"Средний"},
+	"ms-f": {"Общий"},
+
+	// Число.
+	"sing": {"Единственное число"},
+	"plur": {"Множественное число"},
+
+	// Падеж.
+	"nomn": {"Именительный"},
+	"gent": {"Родительный"},
+	"gen1": {"Родительный"},
+	"gen2": {"Партитивный"},
+	"datv": {"Дательный"},
+	"accs": {"Винительный"},
+	"acc2": {"Винительный"},
+	"ablt": {"Творительный"},
+	"loct": {"Предложный"},
+	"loc1": {"Предложный"},
+	"loc2": {"Местный"},
+	"voct": {"Звательный"},
+	"Fixd": {"несклоняемые"},
+
+	// Глагольные категории.
+	"perf": {"Совершенный"},
+	"impf": {"Несовершенный"},
+	"tran": {"Переходный"},
+	"intr": {"Непереходный"},
+	"pres": {"Настоящее"},
+	"past": {"Прошедшее"},
+	"futr": {"Будущее"},
+	"1per": {"1-е лицо"},
+	"2per": {"2-е лицо"},
+	"3per": {"3-е лицо"},
+	"impr": {"Повелительное"},
+	"actv": {"Действительный"},
+	"pssv": {"Страдательный"},
+
+	// Прочие пометы.
+	"Supr": {"Превосходная"},
+	"Qual": {"Качественное"},
+	"Poss": {"Притяжательное"},
+	"Anum": {"Порядковое"},
+	"Apro": {"Местоименное"},
+	"Ques": {"Вопросительное"},
+	"Name": {"Собственное"},
+	"Surn": {"Собственное"},
+	"Patr": {"Собственное"},
+	"Geox": {"Собственное"},
+	"Orgn": {"Собственное"},
+	"Trad": {"Собственное"},
+	"Infr": {"Разговорный"},
+	"Slng": {"Сленг"},
+	"Arch": {"Устаревший"},
+	"Litr": {"Литературный"},
+}
+
+// openCorporaLexemeLinks - типы связей OpenCorpora, объединяющие леммы в одну лексему.
+// В OpenCorpora инфинитив, личные формы, причастия и деепричастия - разные леммы,
+// а в словаре SteosMorphy это одна лексема с леммой-инфинитивом (как "сделавший" -> "сделать").
+var openCorporaLexemeLinks = map[string]struct{}{
+	"ADJF-ADJS":       {},
+	"ADJF-COMP":       {},
+	"ADJF-SUPR_ejsh":  {},
+	"ADJF-SUPR_ajsh":  {},
+	"ADJF-SUPR_suppl": {},
+	"ADJF-SUPR_nai":   {},
+	"ADJF-SUPR_slng":  {},
+	"INFN-VERB":       {},
+	"INFN-PRTF":       {},
+	"INFN-GRND":       {},
+	"PRTF-PRTS":       {},
+}
+
+// openCorporaTags переводит набор граммем OpenCorpora в строку тегов словаря.
+// Часть речи ставится первой, повторы убираются.
+func openCorporaTags(grammemes []string) string {
+	var pos, rest []string
+	seen := make(map[string]struct{})
+	for _, g := range grammemes {
+		for i, tag := range openCorporaGrammemes[g] {
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+			seen[tag] = struct{}{}
+			if i == 0 && inMap(tag, posTags) {
+				pos = append(pos, tag)
+			} else {
+				rest = append(rest, tag)
+			}
+		}
+	}
+	return strings.Join(append(pos, rest...), ",")
+}
+
+// openCorporaPreferred - граммемы OpenCorpora для тегов словаря, которым соответствует
+// несколько граммем (gent и gen1, Name и Surn, ...): обратный перевод выбирает основную.
+var openCorporaPreferred = map[string]string{
+	"Родительный": "gent",
+	"Винительный": "accs",
+	"Предложный":  "loct",
+	"Наречие":     "ADVB",
+	"Собственное": "Name",
+}
+
+// openCorporaByTag - обратная таблица openCorporaGrammemes для однозначных тегов.
+var openCorporaByTag = func() map[string]string {
+	byTag := make(map[string]string, len(openCorporaGrammemes))
+	for g, tags := range openCorporaGrammemes {
+		if len(tags) == 1 {
+			byTag[tags[0]] = g
+		}
+	}
+	maps.Copy(byTag, openCorporaPreferred)
+	return byTag
+}()
+
+// OpenCorporaGrammemes переводит строку тегов (на любом языке, см. Lang) в граммемы
+// OpenCorpora, как их записывает pymorphy2: часть речи с формой ("Прилагательное,Краткая" -> ADJS,
+// "Глагол,Инфинитив" -> INFN) первой, затем остальные граммемы в порядке тегов. Теги,
+// которых нет в OpenCorpora, переводятся в машинные коды (см. LangCodes).
+func OpenCorporaGrammemes(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	parts := strings.Split(tags, ",")
+	for i, tag := range parts {
+		parts[i] = canonicalGrammeme(tag)
+	}
+	has := func(tag string) bool { return slices.Contains(parts, tag) }
+	// Часть речи с формой переводится одной граммемой, а ее теги пропускаются.
+	var pos string
+	consumed := make(map[string]bool)
+	switch parts[0] {
+	case "Прилагательное":
+		switch {
+		case has("Сравнительная"):
+			pos, consumed["Сравнительная"] = "COMP", true
+		case has("Краткая"):
+			pos = "ADJS"
+		default:
+			pos = "ADJF"
+		}
+		consumed[parts[0]], consumed["Полная"], consumed["Краткая"] = true, true, true
+	case "Причастие":
+		pos = "PRTF"
+		if has("Краткая") {
+			pos = "PRTS"
+		}
+		consumed[parts[0]], consumed["Полная"], consumed["Краткая"] = true, true, true
+	case "Глагол":
+		pos = "VERB"
+		if has("Инфинитив") {
+			pos = "INFN"
+		}
+		consumed[parts[0]], consumed["Инфинитив"], consumed["Не инфинитив"] = true, true, true
+	}
+
+	var grammemes []string
+	if pos != "" {
+		grammemes = append(grammemes, pos)
+	}
+	for _, tag := range parts {
+		if consumed[tag] {
+			continue
+		}
+		g, ok := openCorporaByTag[tag]
+		if !ok {
+			g = LocalizeGrammeme(tag, LangCodes)
+		}
+		if !slices.Contains(grammemes, g) {
+			grammemes = append(grammemes, g)
+		}
+	}
+	return grammemes
+}
+
+// XML-структуры дампа OpenCorpora.
+type (
+	ocGrammeme struct {
+		V string `xml:"v,attr"`
+	}
+	ocForm struct {
+		T string       `xml:"t,attr"`
+		G []ocGrammeme `xml:"g"`
+	}
+	ocLemma struct {
+		ID    string   `xml:"id,attr"`
+		Lemma ocForm   `xml:"l"`
+		Forms []ocForm `xml:"f"`
+	}
+	ocLinkType struct {
+		ID   string `xml:"id,attr"`
+		Name string `xml:",chardata"`
+	}
+	ocLink struct {
+		From string `xml:"from,attr"`
+		To   string `xml:"to,attr"`
+		Type string `xml:"type,attr"`
+	}
+)
+
+// openCorporaAspectLink - тип связи OpenCorpora между глаголами совершенного и несовершенного вида.
+const openCorporaAspectLink = "PERF-IMPF"
+
+// ReadOpenCorporaXML читает XML-дамп словаря OpenCorpora и вызывает fn для каждой словоформы.
+// Связанные леммы (инфинитив и личные формы, полное и краткое прилагательное, ...)
+// объединяются в одну лексему, поэтому дамп читается целиком до вызова fn.
+func ReadOpenCorporaXML(r io.Reader, fn func(LexEntry) error) error {
+	return ReadOpenCorporaXMLWithRelations(r, fn, nil)
+}
+
+// ReadOpenCorporaXMLWithRelations работает как ReadOpenCorporaXML и дополнительно вызывает
+// relFn для видовых пар глаголов из связей дампа (после всех словоформ). relFn может быть nil.
+func ReadOpenCorporaXMLWithRelations(r io.Reader, fn func(LexEntry) error, relFn func(LexRelation) error) error {
+	var lemmas []ocLemma
+	var aspectLinks []ocLink
+	linkTypes := make(map[string]string)
+	parent := make(map[string]string)
+
+	var find func(id string) string
+	find = func(id string) string {
+		p, ok := parent[id]
+		if !ok || p == id {
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка чтения XML OpenCorpora: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "lemma":
+			var lemma ocLemma
+			if err := decoder.DecodeElement(&lemma, &start); err != nil {
+				return fmt.Errorf("ошибка разбора леммы: %w", err)
+			}
+			lemmas = append(lemmas, lemma)
+		case "type":
+			var linkType ocLinkType
+			if err := decoder.DecodeElement(&linkType, &start); err != nil {
+				return fmt.Errorf("ошибка разбора типа связи: %w", err)
+			}
+			linkTypes[linkType.ID] = strings.TrimSpace(linkType.Name)
+		case "link":
+			var link ocLink
+			if err := decoder.DecodeElement(&link, &start); err != nil {
+				return fmt.Errorf("ошибка разбора связи: %w", err)
+			}
+			if linkTypes[link.Type] == openCorporaAspectLink {
+				aspectLinks = append(aspectLinks, link)
+			}
+			if _, ok := openCorporaLexemeLinks[linkTypes[link.Type]]; ok {
+				// Корнем лексемы остается лемма "from" (инфинитив, полное прилагательное).
+				fromRoot, toRoot := find(link.From), find(link.To)
+				if fromRoot != toRoot {
+					parent[toRoot] = fromRoot
+				}
+			}
+		}
+	}
+
+	// Нормальная форма лексемы - нормальная форма корневой леммы.
+	normalForms := make(map[string]string, len(lemmas))
+	for _, lemma := range lemmas {
+		normalForms[lemma.ID] = lemma.Lemma.T
+	}
+
+	for _, lemma := range lemmas {
+		root := find(lemma.ID)
+		lexemeGrammemes := make([]string, 0, len(lemma.Lemma.G))
+		for _, g := range lemma.Lemma.G {
+			lexemeGrammemes = append(lexemeGrammemes, g.V)
+		}
+		for _, form := range lemma.Forms {
+			grammemes := append([]string(nil), lexemeGrammemes...)
+			for _, g := range form.G {
+				grammemes = append(grammemes, g.V)
+			}
+			entry := LexEntry{
+				Word:   form.T,
+				Lemma:  normalForms[root],
+				Tags:   openCorporaTags(grammemes),
+				Lexeme: "oc:" + root,
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	if relFn == nil {
+		return nil
+	}
+	for _, link := range aspectLinks {
+		rel := LexRelation{From: normalForms[find(link.From)], To: normalForms[find(link.To)], Kind: RelationAspect}
+		if rel.From == "" || rel.To == "" || rel.From == rel.To {
+			continue
+		}
+		if err := relFn(rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadTSVLexicon читает лексикон в формате TSV и вызывает fn для каждой словоформы.
+// Формат строки: "словоформа<TAB>лемма<TAB>теги[<TAB>id лексемы]".
+// Пустые строки и строки, начинающиеся с '#', пропускаются.
+func ReadTSVLexicon(r io.Reader, fn func(LexEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			return fmt.Errorf("строка %d: ожидалось минимум 3 поля, получено %d", lineNum, len(fields))
+		}
+		entry := LexEntry{Word: fields[0], Lemma: fields[1], Tags: fields[2]}
+		if len(fields) > 3 {
+			entry.Lexeme = fields[3]
+		}
+		if err := fn(entry); err != nil {
+			return fmt.Errorf("строка %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения TSV: %w", err)
+	}
+	return nil
+}
+
+// WriteTSVLexicon записывает словоформы в формате, который читает ReadTSVLexicon.
+func WriteTSVLexicon(w io.Writer, entries []LexEntry) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		line := e.Word + "\t" + e.Lemma + "\t" + e.Tags
+		if e.Lexeme != "" {
+			line += "\t" + e.Lexeme
+		}
+		if _, err := bw.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("ошибка записи TSV: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("ошибка записи TSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// options.go содержит функциональные опции, которыми настраивается анализатор
+// при загрузке через LoadMorphAnalyzer.
+package analyzer
+
+// Option - функциональная опция для настройки анализатора.
+type Option func(*MorphAnalyzer)
+
+// WithAccusativeResolution включает автоматическое разрешение омонимии винительного падежа
+// (см. ResolveAccusative) для всех результатов Parse и Analyze.
+func WithAccusativeResolution() Option {
+	return func(a *MorphAnalyzer) {
+		a.resolveAccusative = true
+	}
+}
+
+// WithVerifyOnLoad сверяет при загрузке контрольные суммы всего файла словаря (см. VerifyDict):
+// поврежденный словарь не загружается, а возвращает ErrDictCorrupted. Проверка читает
+// весь файл и замедляет загрузку; словари без контрольных сумм загружаются без проверки
+// с предупреждением в журнале.
+func WithVerifyOnLoad() Option {
+	return func(a *MorphAnalyzer) {
+		a.verifyOnLoad = true
+	}
+}
+
+// WithLazyDetails отключает раскладку граммем по категориям в разборах словаря
+// и предсказателя: у результатов Parse, ParsePredicted, Analyze, ParseList и т.д. заполнены
+// только Word, Lemma, Tags, PartOfSpeech, Method и Confidence, а полный разбор возвращает
+// Parsed.Details. Опция сокращает стоимость разбора для лемматизации и поиска по тегам
+// (Has, Match); при переводе граммем (WithLang) разборы заполняются полностью.
+func WithLazyDetails() Option {
+	return func(a *MorphAnalyzer) {
+		a.lazyDetails = true
+	}
+}
+
+// WithDictionaryOnly отключает предсказание несловарных слов для точных конвейеров
+// (юридические, медицинские тексты), где недопустимы выдуманные леммы. Из цепочки разбора
+// исключаются предсказывающие звенья (UnitHyphen, UnitPrefix, UnitUnknownPrefix,
+// UnitPredictor), а словарь, числа, латиница и слова других алфавитов, у которых лемма -
+// само слово (или основа, если задан WithFallbackStemmer), остаются. Звенья исключаются
+// после применения всех опций, поэтому порядок относительно WithUnits и WithoutUnits
+// не важен, а свои звенья цепочки сохраняются. Analyze и Lemmatize возвращают nil
+// для несловарного слова, а ParsePredicted, ParsePredictedN, Predict и Synthesize
+// не строят формы по образцу предсказателя.
+func WithDictionaryOnly() Option {
+	return func(a *MorphAnalyzer) {
+		a.dictionaryOnly = true
+	}
+}
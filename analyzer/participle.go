@@ -0,0 +1,133 @@
+// participle.go содержит выборку причастий и деепричастий глагола, сгруппированных
+// по залогу и времени, чтобы не искать их в общем списке словоформ Inflect.
+package analyzer
+
+import (
+	"slices"
+)
+
+// ParticipleGroup - причастия (или деепричастия) глагола одного залога и времени.
+type ParticipleGroup struct {
+	PartOfSpeech string    `json:"part_of_speech"`  // Причастие или Деепричастие.
+	Voice        string    `json:"voice,omitempty"` // Залог причастия; у деепричастий пуст.
+	Tense        string    `json:"tense"`           // Время.
+	Forms        []*Parsed `json:"forms"`           // Начальные формы первыми, затем остальные формы по алфавиту.
+}
+
+// participleOptions - настройки Participles.
+type participleOptions struct {
+	initialOnly    bool
+	skipGerunds    bool
+	skipParticiple bool
+}
+
+// ParticipleOption - функциональная опция для Participles.
+type ParticipleOption func(*participleOptions)
+
+// InitialFormsOnly оставляет только начальные формы причастий (именительный падеж
+// мужского рода единственного числа) без склонения и кратких форм.
+func InitialFormsOnly() ParticipleOption {
+	return func(o *participleOptions) {
+		o.initialOnly = true
+	}
+}
+
+// ParticiplesOnly отбрасывает деепричастия.
+func ParticiplesOnly() ParticipleOption {
+	return func(o *participleOptions) {
+		o.skipGerunds = true
+	}
+}
+
+// GerundsOnly отбрасывает причастия.
+func GerundsOnly() ParticipleOption {
+	return func(o *participleOptions) {
+		o.skipParticiple = true
+	}
+}
+
+// participleGroupOrder - порядок групп в результате Participles.
+var participleGroupOrder = []ParticipleGroup{
+	{PartOfSpeech: "Причастие", Voice: "Действительный", Tense: "Настоящее"},
+	{PartOfSpeech: "Причастие", Voice: "Действительный", Tense: "Прошедшее"},
+	{PartOfSpeech: "Причастие", Voice: "Страдательный", Tense: "Настоящее"},
+	{PartOfSpeech: "Причастие", Voice: "Страдательный", Tense: "Прошедшее"},
+	{PartOfSpeech: "Деепричастие", Tense: "Настоящее"},
+	{PartOfSpeech: "Деепричастие", Tense: "Прошедшее"},
+}
+
+// Participles возвращает причастия и деепричастия глагола (в любой форме), сгруппированные
+// по залогу и времени: действительные настоящего и прошедшего времени, затем страдательные,
+// затем деепричастия. Причастия возвращаются со всеми падежными и краткими формами,
+// если не задана опция InitialFormsOnly. Пустые группы не включаются; для слова,
+// которое не является глаголом, возвращает nil.
+func (a *MorphAnalyzer) Participles(verb string, opts ...ParticipleOption) []ParticipleGroup {
+	var o participleOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var verbLexeme *Lexeme
+	for _, lex := range a.lexemes(verb) {
+		if lex.PartOfSpeech == "Глагол" && len(lex.FormsWith("Инфинитив")) > 0 {
+			verbLexeme = lex
+			break
+		}
+	}
+	if verbLexeme == nil {
+		return nil
+	}
+
+	var groups []ParticipleGroup
+	for _, g := range participleGroupOrder {
+		if (g.PartOfSpeech == "Деепричастие" && o.skipGerunds) || (g.PartOfSpeech == "Причастие" && o.skipParticiple) {
+			continue
+		}
+		query := []string{g.PartOfSpeech, g.Tense}
+		if g.Voice != "" {
+			query = append(query, g.Voice)
+		}
+		initial := verbLexeme.FormsWith(query...)
+		if len(initial) == 0 {
+			continue
+		}
+		forms := slices.Clone(initial)
+		if g.PartOfSpeech == "Причастие" && !o.initialOnly {
+			forms = append(forms, a.participleForms(initial, query)...)
+		}
+		g.Forms = forms
+		groups = append(groups, g)
+	}
+	return a.localizeParticiples(groups)
+}
+
+// participleForms возвращает остальные формы причастий: в словаре склонение причастия
+// может храниться отдельной лексемой с леммой-причастием.
+func (a *MorphAnalyzer) participleForms(initial []*Parsed, query []string) []*Parsed {
+	var forms []*Parsed
+	for _, p := range initial {
+		for _, lex := range a.lexemes(p.Word) {
+			for _, f := range lex.FormsWith(query...) {
+				if !slices.ContainsFunc(initial, func(q *Parsed) bool { return q.Word == f.Word && q.Tags == f.Tags }) &&
+					!slices.ContainsFunc(forms, func(q *Parsed) bool { return q.Word == f.Word && q.Tags == f.Tags }) {
+					forms = append(forms, f)
+				}
+			}
+		}
+	}
+	return forms
+}
+
+// localizeParticiples переводит группы на язык анализатора (см. WithLang).
+func (a *MorphAnalyzer) localizeParticiples(groups []ParticipleGroup) []ParticipleGroup {
+	for i := range groups {
+		g := &groups[i]
+		g.PartOfSpeech = LocalizeGrammeme(g.PartOfSpeech, a.lang)
+		if g.Voice != "" {
+			g.Voice = LocalizeGrammeme(g.Voice, a.lang)
+		}
+		g.Tense = LocalizeGrammeme(g.Tense, a.lang)
+		g.Forms = a.localize(g.Forms)
+	}
+	return groups
+}
@@ -0,0 +1,147 @@
+// parts.go содержит загрузку словаря прямо из частей ("morph_aa", "morph_ab", ...)
+// без записи объединенного файла - для файловых систем только для чтения, контейнеров
+// и установок, где писать рядом с модулем нельзя. Если размеры всех частей, кроме
+// последней, кратны размеру страницы, части отображаются в память подряд, в одну
+// непрерывную область (см. mapParts), и словарь читается без копирования, как из
+// объединенного файла. Иначе части читаются в кучу через partsReader.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// LoadModeParts - части словаря отображены в память подряд (см. LoadMorphAnalyzerFromParts).
+const LoadModeParts LoadMode = "parts"
+
+// LoadMorphAnalyzerFromParts загружает анализатор из частей словаря в директории dir,
+// не записывая объединенный файл. Части, размеры которых (кроме последней) кратны размеру
+// страницы (например, нарезанные split -b 40M), отображаются в память без копирования;
+// остальные, как и с опцией WithHeapLoad, читаются в кучу.
+func LoadMorphAnalyzerFromParts(dir string, opts ...Option) (*MorphAnalyzer, error) {
+	started := time.Now()
+	parts, err := findParts(dir, dictPartsPrefix)
+	if err != nil {
+		return nil, err
+	}
+	r, err := openParts(parts)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var analyzer *MorphAnalyzer
+	if !loadSettings(opts).heapLoad {
+		analyzer, err = loadMappedParts(r)
+		if errors.Is(err, errPartsUnaligned) || errors.Is(err, errors.ErrUnsupported) {
+			pkgLogger().Debug("части словаря не отображаются подряд, чтение в память", "dir", dir, "error", err)
+			analyzer = nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if analyzer == nil {
+		data := make([]byte, r.Size())
+		if _, err := r.ReadAt(data, 0); err != nil {
+			return nil, err
+		}
+		if analyzer, err = loadFromBytes(data); err != nil {
+			return nil, err
+		}
+		analyzer.setLoadMode(LoadModeHeap)
+	}
+	return finishLoad(analyzer, dir, opts, started)
+}
+
+// errPartsUnaligned возвращается mapParts, если части нельзя отобразить подряд.
+var errPartsUnaligned = errors.New("размер части словаря не кратен размеру страницы")
+
+// loadMappedParts отображает части в память подряд и создает анализатор поверх них.
+func loadMappedParts(r *partsReader) (*MorphAnalyzer, error) {
+	pageSize := int64(os.Getpagesize())
+	for i, f := range r.files[:len(r.files)-1] {
+		if (r.offsets[i+1]-r.offsets[i])%pageSize != 0 {
+			return nil, fmt.Errorf("%w: %s", errPartsUnaligned, f.Name())
+		}
+	}
+	data, unmap, err := mapParts(r.files, r.offsets)
+	if err != nil {
+		return nil, err
+	}
+	analyzer, err := loadFromBytes(data)
+	if err != nil {
+		_ = unmap()
+		return nil, err
+	}
+	if analyzer.info.LoadMode == LoadModeZstd {
+		_ = unmap()
+		return analyzer, nil
+	}
+	analyzer.unmapParts = unmap
+	analyzer.setLoadMode(LoadModeParts)
+	return analyzer, nil
+}
+
+// partsReader читает части словаря подряд, как один файл.
+type partsReader struct {
+	files   []*os.File
+	offsets []int64 // Начало каждой части в объединенном файле и, последним, его размер.
+}
+
+// openParts открывает части словаря в порядке объединения.
+func openParts(paths []string) (*partsReader, error) {
+	r := &partsReader{offsets: []int64{0}}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("ошибка открытия части словаря %s: %w", path, err)
+		}
+		r.files = append(r.files, f)
+		info, err := f.Stat()
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("ошибка чтения части словаря %s: %w", path, err)
+		}
+		r.offsets = append(r.offsets, r.Size()+info.Size())
+	}
+	return r, nil
+}
+
+// Size возвращает размер объединенного файла.
+func (r *partsReader) Size() int64 {
+	return r.offsets[len(r.offsets)-1]
+}
+
+// ReadAt читает len(p) байт объединенного файла со смещения off (см. io.ReaderAt).
+func (r *partsReader) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if off >= r.Size() {
+			return n, io.EOF
+		}
+		// Часть, в которой лежит off: последняя, начинающаяся не позже off.
+		i := sort.Search(len(r.files), func(i int) bool { return r.offsets[i+1] > off })
+		chunk := p[:min(int64(len(p)), r.offsets[i+1]-off)]
+		read, err := r.files[i].ReadAt(chunk, off-r.offsets[i])
+		n += read
+		if err != nil {
+			return n, fmt.Errorf("ошибка чтения части словаря %s: %w", r.files[i].Name(), err)
+		}
+		p, off = p[read:], off+int64(read)
+	}
+	return n, nil
+}
+
+// Close закрывает файлы частей.
+func (r *partsReader) Close() error {
+	var errs []error
+	for _, f := range r.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin)
+
+package analyzer
+
+import (
+	"errors"
+	"os"
+)
+
+// mapParts не поддерживается на этой платформе: части словаря читаются в кучу.
+func mapParts([]*os.File, []int64) ([]byte, func() error, error) {
+	return nil, nil, errors.ErrUnsupported
+}
@@ -0,0 +1,41 @@
+//go:build linux || darwin
+
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// mapParts отображает файлы частей в одну непрерывную область памяти: резервирует
+// адресное пространство под весь словарь и отображает каждую часть поверх резерва
+// по ее смещению (MAP_FIXED). Размеры частей, кроме последней, должны быть кратны
+// размеру страницы. Возвращает область и функцию, освобождающую ее.
+func mapParts(files []*os.File, offsets []int64) ([]byte, func() error, error) {
+	size := offsets[len(offsets)-1]
+	if size == 0 {
+		return nil, nil, fmt.Errorf("%w: части словаря пусты", ErrDictCorrupted)
+	}
+	base, err := unix.MmapPtr(-1, 0, nil, uintptr(size), unix.PROT_NONE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка резервирования памяти под словарь: %w", err)
+	}
+	unmap := func() error {
+		return unix.MunmapPtr(base, uintptr(size))
+	}
+	for i, f := range files {
+		length := offsets[i+1] - offsets[i]
+		if length == 0 {
+			continue
+		}
+		addr := unsafe.Add(base, offsets[i])
+		if _, err := unix.MmapPtr(int(f.Fd()), 0, addr, uintptr(length), unix.PROT_READ, unix.MAP_SHARED|unix.MAP_FIXED); err != nil {
+			_ = unmap()
+			return nil, nil, fmt.Errorf("ошибка отображения части словаря %s: %w", f.Name(), err)
+		}
+	}
+	return unsafe.Slice((*byte)(base), size), unmap, nil
+}
@@ -0,0 +1,262 @@
+// phonetics.go содержит преобразование слова в фонемы МФА (IPA) для синтеза речи:
+// твердость и мягкость согласных, йотированные гласные, редукцию безударных гласных,
+// оглушение и озвончение согласных и чтения, зависящие от морфологии ("-ого" -> [əvə]).
+// Словарь не хранит ударений, поэтому ударение берется из знака ударения в слове,
+// из буквы "ё" (в том числе восстановленной по словарю) или из единственной гласной.
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// stressMark - знак ударения (U+0301), который ставится после ударной гласной: "молоко́".
+const stressMark = '́'
+
+// Буквы по роли в чтении.
+const (
+	phoneticVowels    = "аеёиоуыэюя"
+	iotatedVowels     = "еёюя"   // Гласные, дающие [j] в начале слова и после гласных, "ъ" и "ь".
+	softeningLetters  = "еёиюяь" // Буквы, смягчающие предшествующий согласный.
+	alwaysHardLetters = "жшц"
+	alwaysSoftLetters = "чщ"
+)
+
+// consonantPhonemes - фонемы согласных букв (для мягких согласных добавляется "ʲ").
+var consonantPhonemes = map[rune]string{
+	'б': "b", 'в': "v", 'г': "ɡ", 'д': "d", 'ж': "ʐ", 'з': "z", 'й': "j", 'к': "k",
+	'л': "ɫ", 'м': "m", 'н': "n", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ф': "f",
+	'х': "x", 'ц': "t͡s", 'ч': "t͡ɕ", 'ш': "ʂ", 'щ': "ɕː",
+}
+
+// vowelPhonemes - фонемы гласных букв под ударением (без йотации).
+var vowelPhonemes = map[rune]string{
+	'а': "a", 'е': "e", 'ё': "o", 'и': "i", 'о': "o", 'у': "u", 'ы': "ɨ", 'э': "e", 'ю': "u", 'я': "a",
+}
+
+// voicingPairs - пары звонких и глухих согласных для оглушения и озвончения.
+var voicingPairs = map[string]string{
+	"b": "p", "v": "f", "ɡ": "k", "d": "t", "z": "s", "ʐ": "ʂ", "ɣ": "x", "d͡z": "t͡s", "d͡ʑ": "t͡ɕ",
+}
+
+// genitiveVTags - части речи, у которых "г" в окончаниях "-ого", "-его" читается как [v].
+var genitiveVTags = GrammemeSet{"Прилагательное": {}, "Местоимение": {}, "Причастие": {}, "Числительное": {}}
+
+// phone - звук слова при транскрибировании.
+type phone struct {
+	symbol   string
+	soft     bool
+	vowel    bool
+	stressed bool
+}
+
+// Phonemes возвращает транскрипцию слова фонемами МФА: Phonemes("молоко́") -> [m ə ɫ ɐ k ˈo]
+// (ударная гласная помечена "ˈ", мягкость согласного - "ʲ"). Ударение задается знаком U+0301
+// после ударной гласной; без него ударной считается "ё" (пропущенная "ё" восстанавливается
+// по словарю: "елка" -> "ёлка") или единственная гласная слова. Если ударение неизвестно,
+// безударные гласные не редуцируются. По разбору слова "г" в окончаниях "-ого", "-его"
+// прилагательных и местоимений читается как [v], а "-тся", "-ться" глаголов - как [t͡sə].
+// Для слова без кириллических букв возвращает nil.
+func (a *MorphAnalyzer) Phonemes(word string) []string {
+	letters, stress := splitStress(strings.ToLower(word))
+	if !slices.ContainsFunc(letters, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) {
+		return nil
+	}
+	if stress < 0 {
+		letters = a.restoreYo(letters)
+		stress = findStress(letters)
+	}
+
+	phones := make([]phone, 0, len(letters)+2)
+	for i, r := range letters {
+		switch {
+		case strings.ContainsRune(phoneticVowels, r):
+			afterConsonant := i > 0 && consonantPhonemes[letters[i-1]] != "" && letters[i-1] != 'й'
+			if strings.ContainsRune(iotatedVowels, r) && !afterConsonant {
+				phones = append(phones, phone{symbol: "j"})
+			}
+			symbol := vowelPhonemes[r]
+			if r == 'и' && i > 0 && strings.ContainsRune(alwaysHardLetters, letters[i-1]) {
+				symbol = "ɨ"
+			}
+			phones = append(phones, phone{symbol: symbol, vowel: true, stressed: i == stress})
+		case r == 'й':
+			phones = append(phones, phone{symbol: "j"})
+		case consonantPhonemes[r] != "":
+			soft := strings.ContainsRune(alwaysSoftLetters, r) ||
+				(!strings.ContainsRune(alwaysHardLetters, r) && i+1 < len(letters) && strings.ContainsRune(softeningLetters, letters[i+1]))
+			phones = append(phones, phone{symbol: consonantPhonemes[r], soft: soft})
+		}
+	}
+
+	phones = a.applyMorphReadings(string(letters), phones)
+	reduceVowels(phones)
+	assimilateVoicing(phones)
+
+	result := make([]string, len(phones))
+	for i, p := range phones {
+		s := p.symbol
+		switch {
+		case p.soft && s == "ɫ":
+			s = "lʲ"
+		case p.soft && s != "t͡ɕ" && s != "d͡ʑ" && s != "ɕː":
+			s += "ʲ"
+		}
+		if p.stressed {
+			s = "ˈ" + s
+		}
+		result[i] = s
+	}
+	return result
+}
+
+// splitStress удаляет из слова знаки ударения и возвращает буквы и индекс ударной гласной
+// (-1, если знака нет).
+func splitStress(word string) ([]rune, int) {
+	letters := make([]rune, 0, len(word))
+	stress := -1
+	for _, r := range word {
+		if r == stressMark {
+			if len(letters) > 0 && strings.ContainsRune(phoneticVowels, letters[len(letters)-1]) {
+				stress = len(letters) - 1
+			}
+			continue
+		}
+		letters = append(letters, r)
+	}
+	return letters, stress
+}
+
+// findStress возвращает индекс "ё" или единственной гласной слова, иначе -1.
+func findStress(letters []rune) int {
+	vowel, count := -1, 0
+	for i, r := range letters {
+		if r == 'ё' {
+			return i
+		}
+		if strings.ContainsRune(phoneticVowels, r) {
+			vowel = i
+			count++
+		}
+	}
+	if count == 1 {
+		return vowel
+	}
+	return -1
+}
+
+// restoreYo заменяет "е" на "ё", если слова с "е" нет в словаре, а слово с "ё" есть.
+func (a *MorphAnalyzer) restoreYo(letters []rune) []rune {
+	if a.IsKnown(string(letters)) {
+		return letters
+	}
+	for i, r := range letters {
+		if r != 'е' {
+			continue
+		}
+		candidate := slices.Clone(letters)
+		candidate[i] = 'ё'
+		if a.IsKnown(string(candidate)) {
+			return candidate
+		}
+	}
+	return letters
+}
+
+// applyMorphReadings применяет чтения, которые зависят от разборов слова:
+// "г" в окончании "-ого", "-его" читается как [v], "-тся", "-ться" глаголов - как [t͡sə].
+func (a *MorphAnalyzer) applyMorphReadings(word string, phones []phone) []phone {
+	parses, _ := a.analyze(word)
+	for _, ending := range []string{"ого", "его"} {
+		// У "много" и "строго" это не окончание: лемма оканчивается так же.
+		if strings.HasSuffix(word, ending) && slices.ContainsFunc(parses, func(p *Parsed) bool {
+			return !strings.HasSuffix(p.Lemma, ending) && inMap(p.PartOfSpeech, genitiveVTags) &&
+				(hasGrammeme(p.Tags, "Родительный") || hasGrammeme(p.Tags, "Винительный"))
+		}) {
+			// Звуки окончания: [o], [ɡ], [o].
+			phones[len(phones)-2].symbol = "v"
+		}
+	}
+	if (strings.HasSuffix(word, "тся") || strings.HasSuffix(word, "ться")) && slices.ContainsFunc(parses, func(p *Parsed) bool {
+		return lexemePartOfSpeech(p.PartOfSpeech) == "Глагол"
+	}) {
+		// Звуки [t] или [tʲ], [sʲ], [a] сливаются в [t͡s] и [a].
+		n := len(phones)
+		phones[n-3] = phone{symbol: "t͡s"}
+		phones[n-2] = phones[n-1]
+		phones = phones[:n-1]
+	}
+	return phones
+}
+
+// reduceVowels заменяет безударные гласные редуцированными: [ɐ] в первом предударном
+// слоге и в начале слова после твердых согласных, [ə] в остальных безударных слогах,
+// [ɪ] после мягких согласных и [j], [ʊ] вместо [u]. Без ударения гласные не меняются.
+func reduceVowels(phones []phone) {
+	stressed := slices.IndexFunc(phones, func(p phone) bool { return p.stressed })
+	if stressed < 0 {
+		return
+	}
+	for i := range phones {
+		p := &phones[i]
+		if !p.vowel || p.stressed {
+			continue
+		}
+		var prev phone
+		if i > 0 {
+			prev = phones[i-1]
+		}
+		afterSoft := prev.soft || prev.symbol == "j"
+		afterHardSibilant := prev.symbol == "ʐ" || prev.symbol == "ʂ" || prev.symbol == "t͡s"
+		pretonic := i < stressed && !slices.ContainsFunc(phones[i+1:stressed], func(q phone) bool { return q.vowel })
+		switch p.symbol {
+		case "a", "o", "e":
+			switch {
+			case afterSoft && p.symbol == "a" && i == len(phones)-1:
+				p.symbol = "ə"
+			case afterSoft:
+				p.symbol = "ɪ"
+			case afterHardSibilant && p.symbol == "e":
+				p.symbol = "ɨ"
+			case pretonic || i == 0:
+				p.symbol = "ɐ"
+			default:
+				p.symbol = "ə"
+			}
+		case "u":
+			p.symbol = "ʊ"
+		case "i":
+			p.symbol = "ɪ"
+		}
+	}
+}
+
+// assimilateVoicing оглушает звонкие согласные на конце слова и перед глухими
+// и озвончает глухие перед звонкими ("сделать" -> [zd], "ложка" -> [ʂk]).
+// Сонорные и [v] предшествующий согласный не озвончают.
+func assimilateVoicing(phones []phone) {
+	voiceless := make(map[string]string, len(voicingPairs))
+	for voiced, unvoiced := range voicingPairs {
+		voiceless[unvoiced] = voiced
+	}
+	next := -1 // Звонкость следующего звука: 1 - звонкий шумный, -1 - глухой (и конец слова), 0 - прочие.
+	for i := len(phones) - 1; i >= 0; i-- {
+		p := &phones[i]
+		if unvoiced, ok := voicingPairs[p.symbol]; ok && next < 0 {
+			p.symbol = unvoiced
+		} else if voiced, ok := voiceless[p.symbol]; ok && next > 0 {
+			p.symbol = voiced
+		}
+		switch _, voiced := voicingPairs[p.symbol]; {
+		case p.symbol == "v":
+			next = 0
+		case voiced:
+			next = 1
+		case !p.vowel && (voiceless[p.symbol] != "" || p.symbol == "ɕː"):
+			next = -1
+		default:
+			next = 0
+		}
+	}
+}
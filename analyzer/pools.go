@@ -0,0 +1,298 @@
+// pools.go содержит плоские пулы строк и таблицы парадигм словаря формата DAW8.
+// Они хранятся в файле массивами со смещениями и читаются прямо из отображенной памяти:
+// при загрузке ничего не декодируется в кучу, а строки копируются в нее лениво,
+// при первом обращении (см. stringPool).
+package analyzer
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// FlatPool - расположение пула строк в файле: байты всех строк подряд и массив из Count+1
+// смещений uint32 (строка i занимает байты [offsets[i], offsets[i+1]) ).
+type FlatPool struct {
+	DataOffset  int64 // Смещение до байтов строк.
+	DataLength  int64 // Длина байтов строк.
+	IndexOffset int64 // Смещение до массива смещений.
+	Count       int64 // Количество строк.
+}
+
+// FlatArray - расположение массива элементов фиксированного размера в файле.
+type FlatArray struct {
+	Offset int64 // Смещение до массива.
+	Count  int64 // Количество элементов.
+}
+
+// PoolsHeader - продолжение заголовка в словарях DAW8, записывается сразу после Header.
+type PoolsHeader struct {
+	Lemmas         FlatPool  // Пул лемм.
+	Tags           FlatPool  // Пул наборов тегов.
+	Stems          FlatPool  // Основы всех парадигм подряд.
+	Grammemes      FlatPool  // Граммемы словаря в порядке битов GrammemeMask.
+	StemNodes      FlatArray // uint32: ID узла DAWG, в котором заканчивается основа.
+	ParadigmStems  FlatArray // uint32, Count+1 элементов: основы парадигмы p - [ParadigmStems[p], ParadigmStems[p+1]).
+	ParadigmLemmas FlatArray // uint32: ID леммы парадигмы (noLemma, если ее нет).
+	TagMasks       FlatArray // GrammemeMask: маска граммем набора тегов.
+}
+
+// noLemma - ID леммы парадигмы, у которой леммы нет.
+const noLemma = ^uint32(0)
+
+// stringPool - пул строк поверх одного блока байтов. Если блок лежит в отображенном файле,
+// строки при первом обращении копируются в кучу и запоминаются: так результаты
+// анализатора остаются корректными и после Close.
+type stringPool struct {
+	data    string                   // Байты всех строк подряд.
+	offsets []uint32                 // Count+1 смещений строк в data.
+	heap    []atomic.Pointer[string] // Копии строк в куче (nil, если data уже в куче).
+}
+
+// newStringPool собирает пул из строк в куче (словари DAW7 и DictBuilder).
+func newStringPool(strs []string) stringPool {
+	data, offsets := flattenPool(strs)
+	return stringPool{data: string(data), offsets: offsets}
+}
+
+// flattenPool раскладывает строки в блок байтов и массив смещений.
+func flattenPool(strs []string) ([]byte, []uint32) {
+	offsets := make([]uint32, 0, len(strs)+1)
+	var data []byte
+	for _, s := range strs {
+		offsets = append(offsets, uint32(len(data)))
+		data = append(data, s...)
+	}
+	return data, append(offsets, uint32(len(data)))
+}
+
+// loadStringPool открывает пул строк в data без копирования и проверяет смещения.
+func loadStringPool(data []byte, pool FlatPool) (stringPool, error) {
+	blob, err := section(data, pool.DataOffset, pool.DataLength)
+	if err != nil {
+		return stringPool{}, err
+	}
+	offsets, err := sectionSlice[uint32](data, pool.IndexOffset, pool.Count+1)
+	if err != nil {
+		return stringPool{}, err
+	}
+	if err := checkOffsets(offsets, uint32(len(blob))); err != nil {
+		return stringPool{}, err
+	}
+	p := stringPool{offsets: offsets, heap: make([]atomic.Pointer[string], pool.Count)}
+	if len(blob) > 0 {
+		p.data = unsafe.String(unsafe.SliceData(blob), len(blob))
+	}
+	return p, nil
+}
+
+// checkOffsets проверяет, что смещения начинаются с нуля, не убывают и заканчиваются на end.
+func checkOffsets(offsets []uint32, end uint32) error {
+	if len(offsets) == 0 || offsets[0] != 0 || offsets[len(offsets)-1] != end {
+		return fmt.Errorf("%w: неверные границы пула", ErrDictCorrupted)
+	}
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] < offsets[i-1] {
+			return fmt.Errorf("%w: смещения пула убывают", ErrDictCorrupted)
+		}
+	}
+	return nil
+}
+
+// len возвращает количество строк пула.
+func (p *stringPool) len() int {
+	return max(len(p.offsets)-1, 0)
+}
+
+// raw возвращает строку i без копирования. Результат может указывать в отображенный
+// файл, поэтому его нельзя сохранять за пределами вызова анализатора.
+func (p *stringPool) raw(i uint32) string {
+	return p.data[p.offsets[i]:p.offsets[i+1]]
+}
+
+// at возвращает строку i, которую можно хранить сколько угодно.
+func (p *stringPool) at(i uint32) string {
+	if p.heap == nil {
+		return p.raw(i)
+	}
+	if s := p.heap[i].Load(); s != nil {
+		return *s
+	}
+	// Гонка безвредна: конкурентные вызовы сохранят одинаковые копии.
+	s := strings.Clone(p.raw(i))
+	p.heap[i].Store(&s)
+	return s
+}
+
+// all возвращает все строки пула в куче.
+func (p *stringPool) all() []string {
+	strs := make([]string, p.len())
+	for i := range strs {
+		strs[i] = p.at(uint32(i))
+	}
+	return strs
+}
+
+// loadFlatPools открывает пулы строк, таблицу парадигм и маски граммем словаря DAW8
+// в data без копирования.
+func (a *MorphAnalyzer) loadFlatPools(data []byte, h *PoolsHeader) error {
+	var err error
+	if a.lemmas, err = loadStringPool(data, h.Lemmas); err != nil {
+		return err
+	}
+	if a.tags, err = loadStringPool(data, h.Tags); err != nil {
+		return err
+	}
+	if a.paradigms, err = loadParadigmTable(data, h, a.lemmas.len(), len(a.nodes)); err != nil {
+		return err
+	}
+	grammemes, err := loadStringPool(data, h.Grammemes)
+	if err != nil {
+		return err
+	}
+	if h.TagMasks.Count != h.Tags.Count {
+		return fmt.Errorf("%w: число масок граммем не совпадает с числом наборов тегов", ErrDictCorrupted)
+	}
+	masks, err := sectionSlice[GrammemeMask](data, h.TagMasks.Offset, h.TagMasks.Count)
+	if err != nil {
+		return err
+	}
+	if err := a.initGrammemeMasks(grammemes.all(), masks); err != nil {
+		return fmt.Errorf("%w: %w", ErrDictCorrupted, err)
+	}
+	return nil
+}
+
+// paradigmTable - основы парадигм: основы строки r - элементы [index[r], index[r+1])
+// пула stems и массива nodes. В словарях DAW8 строка таблицы совпадает с ID парадигмы.
+type paradigmTable struct {
+	index  []uint32   // Смещения основ по строкам таблицы, на одно больше числа строк.
+	stems  stringPool // Основы.
+	nodes  []uint32   // ID узла DAWG, в котором заканчивается основа.
+	lemmas []uint32   // ID леммы парадигмы (noLemma, если ее нет).
+	sparse []uint32   // Отсортированные ID парадигм по строкам (только DAW7, где ID идут не подряд).
+}
+
+// newParadigmTable собирает таблицу из карт словаря DAW7.
+func newParadigmTable(paradigms map[uint32][]ParadigmInfo, paradigmLemmas map[uint32]uint32) paradigmTable {
+	ids := slices.Collect(maps.Keys(paradigms))
+	for pID := range paradigmLemmas {
+		if _, ok := paradigms[pID]; !ok {
+			ids = append(ids, pID)
+		}
+	}
+	slices.Sort(ids)
+	t := paradigmTable{index: make([]uint32, 0, len(ids)+1), lemmas: make([]uint32, len(ids)), sparse: ids}
+	var stems []string
+	for row, pID := range ids {
+		t.index = append(t.index, uint32(len(stems)))
+		for _, info := range paradigms[pID] {
+			stems = append(stems, info.Stem)
+			t.nodes = append(t.nodes, info.NodeID)
+		}
+		t.lemmas[row] = noLemma
+		if lemmaID, ok := paradigmLemmas[pID]; ok {
+			t.lemmas[row] = lemmaID
+		}
+	}
+	t.index = append(t.index, uint32(len(stems)))
+	t.stems = newStringPool(stems)
+	return t
+}
+
+// loadParadigmTable открывает таблицу парадигм в data без копирования и проверяет ее.
+func loadParadigmTable(data []byte, h *PoolsHeader, lemmaCount, nodeCount int) (paradigmTable, error) {
+	var t paradigmTable
+	var err error
+	if t.stems, err = loadStringPool(data, h.Stems); err != nil {
+		return t, err
+	}
+	// Основы используются только при обходе DAWG и в результаты не попадают.
+	t.stems.heap = nil
+	if h.StemNodes.Count != h.Stems.Count || h.ParadigmLemmas.Count+1 != h.ParadigmStems.Count {
+		return t, fmt.Errorf("%w: размеры таблицы парадигм не совпадают", ErrDictCorrupted)
+	}
+	if t.nodes, err = sectionSlice[uint32](data, h.StemNodes.Offset, h.StemNodes.Count); err != nil {
+		return t, err
+	}
+	if t.index, err = sectionSlice[uint32](data, h.ParadigmStems.Offset, h.ParadigmStems.Count); err != nil {
+		return t, err
+	}
+	if t.lemmas, err = sectionSlice[uint32](data, h.ParadigmLemmas.Offset, h.ParadigmLemmas.Count); err != nil {
+		return t, err
+	}
+	if err := checkOffsets(t.index, uint32(len(t.nodes))); err != nil {
+		return t, err
+	}
+	for _, node := range t.nodes {
+		if int(node) >= nodeCount {
+			return t, fmt.Errorf("%w: основа указывает на несуществующий узел %d", ErrDictCorrupted, node)
+		}
+	}
+	for _, lemmaID := range t.lemmas {
+		if lemmaID != noLemma && int(lemmaID) >= lemmaCount {
+			return t, fmt.Errorf("%w: парадигма указывает на несуществующую лемму %d", ErrDictCorrupted, lemmaID)
+		}
+	}
+	return t, nil
+}
+
+// len возвращает количество парадигм.
+func (t *paradigmTable) len() int {
+	return len(t.lemmas)
+}
+
+// row возвращает строку таблицы парадигмы pID.
+func (t *paradigmTable) row(pID uint32) (int, bool) {
+	if t.sparse != nil {
+		return slices.BinarySearch(t.sparse, pID)
+	}
+	return int(pID), int(pID) < len(t.lemmas)
+}
+
+// lemma возвращает ID леммы парадигмы pID.
+func (t *paradigmTable) lemma(pID uint32) (uint32, bool) {
+	row, ok := t.row(pID)
+	if !ok || t.lemmas[row] == noLemma {
+		return 0, false
+	}
+	return t.lemmas[row], true
+}
+
+// ids перебирает по возрастанию ID парадигм, у которых есть основы.
+func (t *paradigmTable) ids() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for row := range len(t.lemmas) {
+			if t.index[row] == t.index[row+1] {
+				continue
+			}
+			pID := uint32(row)
+			if t.sparse != nil {
+				pID = t.sparse[row]
+			}
+			if !yield(pID) {
+				return
+			}
+		}
+	}
+}
+
+// stemsOf перебирает основы парадигмы pID и ID узлов DAWG, в которых они заканчиваются.
+// Строки основ не копируются (см. stringPool.raw).
+func (t *paradigmTable) stemsOf(pID uint32) iter.Seq2[string, uint32] {
+	return func(yield func(string, uint32) bool) {
+		row, ok := t.row(pID)
+		if !ok {
+			return
+		}
+		for i := t.index[row]; i < t.index[row+1]; i++ {
+			if !yield(t.stems.raw(i), t.nodes[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// pymorphy.go содержит чтение словаря pymorphy2 (и совместимого pymorphy3): каталога
+// с words.dawg, paradigms.array, suffixes.json, gramtab-opencorpora-int.json и meta.json,
+// который публикуется пакетом pymorphy2-dicts-ru. Из него собирается словарь steosmorphy
+// с тем же лексиконом и набором граммем, что проверен пользователем в pymorphy2.
+package analyzer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Pymorphy2Meta - сведения о словаре pymorphy2 из meta.json.
+type Pymorphy2Meta struct {
+	FormatVersion  string // Версия формата словаря ("2.4").
+	Language       string // Код языка ("ru").
+	SourceRevision string // Ревизия исходного корпуса OpenCorpora.
+}
+
+// pymorphy2PayloadSeparator отделяет в ключах words.dawg словоформу от записи
+// (как в BytesDAWG библиотеки DAWG).
+const pymorphy2PayloadSeparator = 0x01
+
+// ReadPymorphy2Dict читает словарь pymorphy2 из fsys (например, os.DirFS(".../pymorphy2_dicts_ru/data"))
+// и вызывает fn для каждой словоформы в алфавитном порядке. Леммой становится нормальная
+// форма pymorphy2 (первая форма парадигмы), граммемы OpenCorpora переводятся в теги
+// словаря, как в ReadOpenCorporaXML, а лексемы различаются по парадигме и лемме.
+func ReadPymorphy2Dict(fsys fs.FS, fn func(LexEntry) error) (Pymorphy2Meta, error) {
+	var meta Pymorphy2Meta
+	rawMeta, err := readPymorphy2Meta(fsys)
+	if err != nil {
+		return meta, err
+	}
+	meta.FormatVersion, _ = rawMeta["format_version"].(string)
+	meta.Language, _ = rawMeta["language_code"].(string)
+	if revision := rawMeta["source_revision"]; revision != nil {
+		meta.SourceRevision = fmt.Sprint(revision)
+	}
+	if !strings.HasPrefix(meta.FormatVersion, "2.") {
+		return meta, fmt.Errorf("неподдерживаемая версия формата словаря pymorphy2 %q", meta.FormatVersion)
+	}
+	var prefixes []string
+	if options, ok := rawMeta["compile_options"].(map[string]any); ok {
+		for _, p := range asList(options["paradigm_prefixes"]) {
+			s, _ := p.(string)
+			prefixes = append(prefixes, s)
+		}
+	}
+	if len(prefixes) == 0 {
+		prefixes = []string{"", "по", "наи"} // Значение по умолчанию pymorphy2.
+	}
+
+	var suffixes, gramtab []string
+	if err := readPymorphy2JSON(fsys, "suffixes.json", &suffixes); err != nil {
+		return meta, err
+	}
+	if err := readPymorphy2JSON(fsys, "gramtab-opencorpora-int.json", &gramtab); err != nil {
+		return meta, err
+	}
+	tags := make([]string, len(gramtab))
+	for i, tag := range gramtab {
+		tags[i] = openCorporaTags(strings.FieldsFunc(tag, func(r rune) bool { return r == ',' || r == ' ' }))
+	}
+	paradigms, err := readPymorphy2Paradigms(fsys)
+	if err != nil {
+		return meta, err
+	}
+	data, err := fs.ReadFile(fsys, "words.dawg")
+	if err != nil {
+		return meta, fmt.Errorf("ошибка чтения words.dawg: %w", err)
+	}
+	words, err := parseDAWGDic(data)
+	if err != nil {
+		return meta, fmt.Errorf("ошибка чтения words.dawg: %w", err)
+	}
+
+	// form возвращает приставку и окончание формы idx парадигмы.
+	form := func(paradigm []uint16, idx int) (prefix, suffix string, ok bool) {
+		n := len(paradigm) / 3
+		if idx >= n || int(paradigm[idx]) >= len(suffixes) || int(paradigm[2*n+idx]) >= len(prefixes) {
+			return "", "", false
+		}
+		return prefixes[paradigm[2*n+idx]], suffixes[paradigm[idx]], true
+	}
+	var record [4]byte
+	err = words.keys(func(key []byte) error {
+		sep := bytes.IndexByte(key, pymorphy2PayloadSeparator)
+		if sep < 0 {
+			return fmt.Errorf("ключ words.dawg без записи: %q", key)
+		}
+		if n, err := base64.StdEncoding.Decode(record[:], key[sep+1:]); err != nil || n != len(record) {
+			return fmt.Errorf("неверная запись words.dawg для %q", key[:sep])
+		}
+		word := string(key[:sep])
+		paradigmID, idx := binary.BigEndian.Uint16(record[:2]), int(binary.BigEndian.Uint16(record[2:]))
+		if int(paradigmID) >= len(paradigms) {
+			return fmt.Errorf("словоформа %q ссылается на несуществующую парадигму %d", word, paradigmID)
+		}
+		paradigm := paradigms[paradigmID]
+		prefix, suffix, ok := form(paradigm, idx)
+		normalPrefix, normalSuffix, _ := form(paradigm, 0)
+		if !ok || !strings.HasPrefix(word, prefix) || !strings.HasSuffix(word[len(prefix):], suffix) {
+			return fmt.Errorf("словоформа %q не соответствует форме %d парадигмы %d", word, idx, paradigmID)
+		}
+		stem := word[len(prefix) : len(word)-len(suffix)]
+		lemma := normalPrefix + stem + normalSuffix
+		tagID := int(paradigm[len(paradigm)/3+idx])
+		if tagID >= len(tags) {
+			return fmt.Errorf("словоформа %q ссылается на несуществующий набор граммем %d", word, tagID)
+		}
+		return fn(LexEntry{Word: word, Lemma: lemma, Tags: tags[tagID], Lexeme: "pymorphy2:" + strconv.Itoa(int(paradigmID)) + ":" + lemma})
+	})
+	return meta, err
+}
+
+// readPymorphy2Meta читает meta.json: объект или, в старых словарях, список пар [ключ, значение].
+func readPymorphy2Meta(fsys fs.FS) (map[string]any, error) {
+	var raw any
+	if err := readPymorphy2JSON(fsys, "meta.json", &raw); err != nil {
+		return nil, err
+	}
+	if m, ok := raw.(map[string]any); ok {
+		return m, nil
+	}
+	m := make(map[string]any)
+	for _, item := range asList(raw) {
+		if pair := asList(item); len(pair) == 2 {
+			if key, ok := pair[0].(string); ok {
+				m[key] = pair[1]
+			}
+		}
+	}
+	return m, nil
+}
+
+// asList возвращает значение JSON как список (nil, если это не список).
+func asList(v any) []any {
+	list, _ := v.([]any)
+	return list
+}
+
+// readPymorphy2JSON читает JSON-файл словаря pymorphy2.
+func readPymorphy2JSON(fsys fs.FS, name string, v any) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("ошибка разбора %s: %w", name, err)
+	}
+	return nil
+}
+
+// readPymorphy2Paradigms читает paradigms.array: число парадигм, затем для каждой длина
+// и массив uint16 - номера окончаний, наборов граммем и приставок форм (по трети массива).
+func readPymorphy2Paradigms(fsys fs.FS) ([][]uint16, error) {
+	data, err := fs.ReadFile(fsys, "paradigms.array")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения paradigms.array: %w", err)
+	}
+	next := func() (uint16, bool) {
+		if len(data) < 2 {
+			return 0, false
+		}
+		v := binary.LittleEndian.Uint16(data)
+		data = data[2:]
+		return v, true
+	}
+	count, ok := next()
+	if !ok {
+		return nil, fmt.Errorf("ошибка чтения paradigms.array: файл пуст")
+	}
+	paradigms := make([][]uint16, count)
+	for i := range paradigms {
+		n, ok := next()
+		if !ok || n%3 != 0 || len(data) < 2*int(n) {
+			return nil, fmt.Errorf("ошибка чтения paradigms.array: парадигма %d повреждена", i)
+		}
+		paradigm := make([]uint16, n)
+		for j := range paradigm {
+			paradigm[j], _ = next()
+		}
+		paradigms[i] = paradigm
+	}
+	return paradigms, nil
+}
+
+// Разряды элемента массива dawgdic (библиотеки DAWG, на которой построен pymorphy2).
+const (
+	dawgHasLeafBit   = 1 << 8
+	dawgExtensionBit = 1 << 9
+	dawgIsLeafBit    = 1 << 31
+)
+
+// dawgDic - словарь dawgdic с путеводителем (CompletionDAWG): массив элементов
+// с переходами по байтам и для каждого элемента метки первого потомка и следующего
+// брата, по которым перебираются ключи.
+type dawgDic struct {
+	units []uint32
+	guide []byte
+}
+
+// parseDAWGDic разбирает файл CompletionDAWG: число элементов и элементы (uint32),
+// затем число элементов путеводителя и пары байт (потомок, брат).
+func parseDAWGDic(data []byte) (*dawgDic, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("файл слишком короткий")
+	}
+	size := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if len(data) < 4*size+4 {
+		return nil, fmt.Errorf("файл обрезан")
+	}
+	d := &dawgDic{units: make([]uint32, size)}
+	for i := range d.units {
+		d.units[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	data = data[4*size:]
+	guideSize := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if guideSize != size || len(data) < 2*guideSize {
+		return nil, fmt.Errorf("путеводитель не соответствует словарю")
+	}
+	d.guide = data[:2*guideSize]
+	return d, nil
+}
+
+// follow возвращает элемент, в который ведет переход по метке label из элемента index.
+func (d *dawgDic) follow(index uint32, label byte) (uint32, bool) {
+	base := d.units[index]
+	offset := (base >> 10) << ((base & dawgExtensionBit) >> 6)
+	next := index ^ offset ^ uint32(label)
+	if int(next) >= len(d.units) || d.units[next]&(dawgIsLeafBit|0xFF) != uint32(label) {
+		return 0, false
+	}
+	return next, true
+}
+
+// keys перебирает ключи словаря в порядке возрастания байтов.
+func (d *dawgDic) keys(fn func(key []byte) error) error {
+	if len(d.units) == 0 {
+		return nil
+	}
+	key := make([]byte, 0, 64)
+	var walk func(index uint32) error
+	walk = func(index uint32) error {
+		if d.units[index]&dawgHasLeafBit != 0 {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+		for label := d.guide[2*index]; label != 0; {
+			child, ok := d.follow(index, label)
+			if !ok {
+				return fmt.Errorf("неверный переход по путеводителю")
+			}
+			key = append(key, label)
+			if err := walk(child); err != nil {
+				return err
+			}
+			key = key[:len(key)-1]
+			label = d.guide[2*child+1]
+		}
+		return nil
+	}
+	return walk(0)
+}
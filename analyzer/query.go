@@ -0,0 +1,175 @@
+// query.go содержит проверку граммем разбора без ручного разбора строки Tags:
+// Parsed.Has для одной граммемы и небольшой язык запросов для Parsed.Match
+// ("Существительное & Родительный & !Множественное число").
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryOperators - символы операторов языка запросов; граммемы их не содержат.
+const queryOperators = "&|!()"
+
+// Has сообщает, что разбор содержит граммему tag. Граммему и разбор можно
+// записывать на любом языке (см. Lang): "Родительный", "genitive" и "gent" равнозначны.
+func (p *Parsed) Has(tag string) bool {
+	return p.hasCanonical(canonicalGrammeme(strings.TrimSpace(tag)))
+}
+
+// hasCanonical - Has для граммемы в словарном (русском) виде.
+func (p *Parsed) hasCanonical(g string) bool {
+	if hasGrammeme(p.Tags, g) {
+		return true
+	}
+	// Разбор мог быть переведен на другой язык (см. Localize).
+	for tags := p.Tags; tags != ""; {
+		var tag string
+		tag, tags, _ = strings.Cut(tags, ",")
+		if canonicalGrammeme(tag) == g {
+			return true
+		}
+	}
+	return false
+}
+
+// Match сообщает, что разбор удовлетворяет запросу по граммемам (см. ParseTagQuery).
+// Для запроса с синтаксической ошибкой возвращает false; чтобы получить ошибку
+// или проверять много разборов одним запросом, используйте ParseTagQuery.
+func (p *Parsed) Match(query string) bool {
+	q, err := ParseTagQuery(query)
+	return err == nil && q.Match(p)
+}
+
+// TagQuery - разобранный запрос по граммемам.
+type TagQuery struct {
+	root *queryNode
+}
+
+// queryNode - узел дерева запроса: граммема или оператор над поддеревьями.
+type queryNode struct {
+	op          byte   // 0 - граммема, '&', '|' или '!'.
+	grammeme    string // Граммема в словарном виде (для op == 0).
+	left, right *queryNode
+}
+
+// ParseTagQuery разбирает запрос по граммемам. Граммемы записываются как в тегах
+// (на любом языке, с пробелами внутри: "Множественное число") и объединяются
+// операторами "&" (и), "|" (или) и "!" (не); порядок задается скобками.
+// "!" связывает сильнее "&", а "&" - сильнее "|":
+//
+//	Существительное & (Родительный | Партитивный) & !Множественное число
+func ParseTagQuery(query string) (*TagQuery, error) {
+	parser := queryParser{query: query}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.skipSpaces(); parser.pos < len(query) {
+		return nil, parser.errorf("лишний символ %q", query[parser.pos])
+	}
+	return &TagQuery{root: root}, nil
+}
+
+// Match сообщает, что разбор удовлетворяет запросу.
+func (q *TagQuery) Match(p *Parsed) bool {
+	return q.root.match(p)
+}
+
+// match вычисляет узел запроса для разбора.
+func (n *queryNode) match(p *Parsed) bool {
+	switch n.op {
+	case '&':
+		return n.left.match(p) && n.right.match(p)
+	case '|':
+		return n.left.match(p) || n.right.match(p)
+	case '!':
+		return !n.left.match(p)
+	default:
+		return p.hasCanonical(n.grammeme)
+	}
+}
+
+// queryParser - рекурсивный спуск по тексту запроса.
+type queryParser struct {
+	query string
+	pos   int
+}
+
+// parseOr разбирает "a | b | ...".
+func (qp *queryParser) parseOr() (*queryNode, error) {
+	return qp.parseBinary('|', qp.parseAnd)
+}
+
+// parseAnd разбирает "a & b & ...".
+func (qp *queryParser) parseAnd() (*queryNode, error) {
+	return qp.parseBinary('&', qp.parseNot)
+}
+
+// parseBinary разбирает цепочку операндов, разделенных оператором op.
+func (qp *queryParser) parseBinary(op byte, operand func() (*queryNode, error)) (*queryNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for qp.skipSpaces(); qp.pos < len(qp.query) && qp.query[qp.pos] == op; qp.skipSpaces() {
+		qp.pos++
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseNot разбирает "!a", "(...)" или граммему.
+func (qp *queryParser) parseNot() (*queryNode, error) {
+	qp.skipSpaces()
+	if qp.pos == len(qp.query) {
+		return nil, qp.errorf("ожидалась граммема")
+	}
+	switch qp.query[qp.pos] {
+	case '!':
+		qp.pos++
+		operand, err := qp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNode{op: '!', left: operand}, nil
+	case '(':
+		qp.pos++
+		inner, err := qp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if qp.skipSpaces(); qp.pos == len(qp.query) || qp.query[qp.pos] != ')' {
+			return nil, qp.errorf("ожидалась закрывающая скобка")
+		}
+		qp.pos++
+		return inner, nil
+	}
+
+	end := qp.pos
+	for end < len(qp.query) && !strings.ContainsRune(queryOperators, rune(qp.query[end])) {
+		end++
+	}
+	grammeme := strings.TrimSpace(qp.query[qp.pos:end])
+	if grammeme == "" {
+		return nil, qp.errorf("ожидалась граммема")
+	}
+	qp.pos = end
+	return &queryNode{grammeme: canonicalGrammeme(grammeme)}, nil
+}
+
+// skipSpaces пропускает пробелы перед очередной лексемой.
+func (qp *queryParser) skipSpaces() {
+	for qp.pos < len(qp.query) && (qp.query[qp.pos] == ' ' || qp.query[qp.pos] == '\t') {
+		qp.pos++
+	}
+}
+
+// errorf возвращает ошибку запроса с позицией (в байтах).
+func (qp *queryParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("ошибка в запросе %q, позиция %d: %s", qp.query, qp.pos, fmt.Sprintf(format, args...))
+}
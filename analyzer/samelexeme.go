@@ -0,0 +1,59 @@
+// samelexeme.go содержит морфологическое сравнение словоформ для дедупликации
+// и связывания записей ("Иванову" и "Ивановым" - одна фамилия).
+package analyzer
+
+import (
+	"strings"
+)
+
+// SameLexeme сообщает, что две словоформы могут принадлежать одной лексеме:
+// у них есть общая лемма хотя бы в одном разборе, либо одна из форм входит
+// в парадигму другой. Второе условие важно для несловарных слов (фамилий, названий),
+// где предсказатель может выбрать для разных форм разные леммы ("Иванову" -> "иванов",
+// "Ивановым" -> "ивановый"), но одна форма порождается из другой.
+// Регистр не учитывается.
+func (a *MorphAnalyzer) SameLexeme(first, second string) bool {
+	first, second = strings.ToLower(first), strings.ToLower(second)
+	if first == second {
+		return true
+	}
+
+	firstLemmas := a.Lemmatize(first)
+	for _, lemma := range a.Lemmatize(second) {
+		for _, l := range firstLemmas {
+			if l == lemma {
+				return true
+			}
+		}
+	}
+	return a.inParadigm(first, second) || a.inParadigm(second, first)
+}
+
+// inParadigm проверяет, есть ли форма form среди словоформ лексем слова word
+// (словарных или, для несловарного слова, предсказанных).
+func (a *MorphAnalyzer) inParadigm(word, form string) bool {
+	payloads := a.lookupPayloads(word)
+	if payloads == nil {
+		_, forms := a.predictedLexeme(word)
+		for _, f := range forms {
+			if f.word == form {
+				return true
+			}
+		}
+		return false
+	}
+
+	checked := make(map[uint32]struct{})
+	for _, info := range payloads {
+		if _, ok := checked[info.ParadigmID]; ok {
+			continue
+		}
+		checked[info.ParadigmID] = struct{}{}
+		for _, f := range a.lexemeForms(info.ParadigmID) {
+			if f.word == form {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// selftest.go содержит самопроверку загруженного словаря: компилятор встраивает в словарь
+// контрольную выборку словоформ с ожидаемыми разборами, а SelfTest сверяет с ней
+// результаты поиска. Так поврежденный или собранный несовместимой версией словарь
+// обнаруживается при загрузке, а не по неверным разборам в работе.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// selfTestLexemes - число лексем, формы которых попадают в контрольную выборку.
+	selfTestLexemes = 128
+	// selfTestMaxErrors - сколько расхождений перечисляется в ошибке SelfTest.
+	selfTestMaxErrors = 3
+)
+
+// ErrNoSelfTestSample возвращается SelfTest для словаря без контрольной выборки
+// (собранного предыдущими версиями компилятора).
+var ErrNoSelfTestSample = errors.New("словарь не содержит контрольной выборки для самопроверки")
+
+// SelfTestCase - контрольная словоформа и ее ожидаемый разбор.
+type SelfTestCase struct {
+	Word  string // Словоформа в нижнем регистре.
+	Lemma string // Ожидаемая лемма.
+	Tags  string // Ожидаемая строка тегов.
+}
+
+// SelfTest сверяет разборы контрольной выборки, встроенной в словарь компилятором,
+// с результатами поиска по загруженному словарю: каждая словоформа должна находиться
+// в DAWG с ожидаемыми леммой и тегами и порождаться своей парадигмой.
+// Проверка не зависит от опций анализатора (перехватчиков, кэша, языка граммем).
+// Расхождения возвращаются ошибкой ErrDictCorrupted, а для словаря без выборки - ErrNoSelfTestSample.
+func (a *MorphAnalyzer) SelfTest() (err error) {
+	if len(a.selfTest) == 0 {
+		return ErrNoSelfTestSample
+	}
+	// Поврежденные данные могут содержать индексы за пределами массивов.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: словарь не прошел самопроверку: %v", ErrDictCorrupted, r)
+		}
+	}()
+
+	var failures []string
+	failed := 0
+	for _, tc := range a.selfTest {
+		if problem := a.checkSelfTestCase(tc); problem != "" {
+			failed++
+			if len(failures) < selfTestMaxErrors {
+				failures = append(failures, fmt.Sprintf("%q: %s", tc.Word, problem))
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: словарь не прошел самопроверку: %d из %d словоформ разобраны неверно (%s)",
+			ErrDictCorrupted, failed, len(a.selfTest), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// checkSelfTestCase проверяет одну контрольную словоформу и возвращает описание
+// расхождения или пустую строку.
+func (a *MorphAnalyzer) checkSelfTestCase(tc SelfTestCase) string {
+	payloads := a.lookupPayloads(tc.Word)
+	if payloads == nil {
+		return "словоформа не найдена"
+	}
+	for _, info := range payloads {
+		if int(info.LemmaID) >= a.lemmas.len() || int(info.TagsID) >= a.tags.len() {
+			return "ссылка на несуществующую лемму или набор тегов"
+		}
+		if a.lemmas.at(info.LemmaID) != tc.Lemma || a.tags.at(info.TagsID) != tc.Tags {
+			continue
+		}
+		if lemmaID, ok := a.paradigms.lemma(info.ParadigmID); !ok || lemmaID != info.LemmaID {
+			return fmt.Sprintf("парадигма %d не связана с леммой %q", info.ParadigmID, tc.Lemma)
+		}
+		if !slices.Contains(a.lexemeForms(info.ParadigmID), lexemeForm{word: tc.Word, tagsID: info.TagsID}) {
+			return fmt.Sprintf("парадигма %d не порождает словоформу", info.ParadigmID)
+		}
+		return ""
+	}
+	return fmt.Sprintf("нет разбора с леммой %q и тегами %q", tc.Lemma, tc.Tags)
+}
+
+// selfTestSample выбирает контрольную выборку для словаря: первую и среднюю форму
+// у равномерно распределенных по лексикону лексем. Выборка детерминирована.
+func (b *DictBuilder) selfTestSample() []SelfTestCase {
+	step := max(1, len(b.lexemes)/selfTestLexemes)
+	var sample []SelfTestCase
+	for i := 0; i < len(b.lexemes); i += step {
+		lex := b.lexemes[i]
+		for _, j := range []int{0, len(lex.forms) / 2} {
+			f := lex.forms[j]
+			tc := SelfTestCase{Word: f.word, Lemma: lex.lemma, Tags: b.tagsPool[f.tagsID]}
+			if len(sample) == 0 || sample[len(sample)-1] != tc {
+				sample = append(sample, tc)
+			}
+		}
+	}
+	return sample
+}
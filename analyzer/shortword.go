@@ -0,0 +1,54 @@
+// shortword.go содержит разбор несловарных слов из одной-двух букв. Для таких слов
+// суффиксные правила предсказателя бессмысленны и дают случайные парадигмы
+// ("ну" -> существительное "на"), поэтому они разбираются по закрытому списку
+// служебных слов и междометий, а остальные считаются неизвестными.
+package analyzer
+
+import "unicode/utf8"
+
+// maxShortWordLen - наибольшая длина слова (в символах), которое не отдается предсказателю.
+const maxShortWordLen = 2
+
+// shortClosedClass - служебные слова и междометия из одной-двух букв с их тегами.
+// У омонимов ("а" - союз, частица и междометие) несколько наборов тегов.
+var shortClosedClass = map[string][]string{
+	// Предлоги.
+	"в": {"Предлог"}, "во": {"Предлог"}, "к": {"Предлог"}, "ко": {"Предлог"},
+	"с": {"Предлог"}, "со": {"Предлог"}, "об": {"Предлог"}, "от": {"Предлог"},
+	"до": {"Предлог"}, "за": {"Предлог"}, "на": {"Предлог"}, "по": {"Предлог"},
+	"из": {"Предлог"}, "о": {"Предлог", "Междометие"}, "у": {"Предлог", "Междометие"},
+
+	// Союзы.
+	"и": {"Союз", "Частица"}, "а": {"Союз", "Частица", "Междометие"}, "но": {"Союз"},
+	"да": {"Союз", "Частица"}, "ли": {"Частица", "Союз"}, "ни": {"Частица", "Союз"},
+	"то": {"Союз", "Частица"},
+
+	// Частицы.
+	"не": {"Частица"}, "же": {"Частица"}, "ж": {"Частица"}, "бы": {"Частица"},
+	"б": {"Частица"}, "ль": {"Частица"}, "уж": {"Частица"}, "ну": {"Частица", "Междометие"},
+
+	// Междометия.
+	"ой": {"Междометие"}, "ай": {"Междометие"}, "ах": {"Междометие"}, "ох": {"Междометие"},
+	"эх": {"Междометие"}, "эй": {"Междометие"}, "ух": {"Междометие"}, "уф": {"Междометие"},
+	"фу": {"Междометие"}, "хм": {"Междометие"}, "ау": {"Междометие"}, "ба": {"Междометие"},
+	"ха": {"Междометие"}, "хе": {"Междометие"}, "хи": {"Междометие"},
+}
+
+// isShortWord сообщает, что слово слишком короткое для суффиксного предсказания.
+func isShortWord(lowerWord string) bool {
+	return utf8.RuneCountInString(lowerWord) <= maxShortWordLen
+}
+
+// parseShortWord разбирает короткое несловарное слово по закрытому списку.
+// Для слов вне списка возвращает nil: слово считается неизвестным.
+func parseShortWord(word, lowerWord string) []*Parsed {
+	tags := shortClosedClass[lowerWord]
+	if len(tags) == 0 {
+		return nil
+	}
+	results := make([]*Parsed, len(tags))
+	for i, t := range tags {
+		results[i] = newParsed(word, lowerWord, t)
+	}
+	return results
+}
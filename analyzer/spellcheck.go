@@ -0,0 +1,112 @@
+// spellcheck.go содержит исправление опечаток поиском по DAWG с ограниченным расстоянием
+// редактирования: строки таблицы Левенштейна считаются по мере спуска по ребрам,
+// и ветви, где расстояние заведомо превысило допустимое, отсекаются.
+package analyzer
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxSuggestDistance ограничивает расстояние в Suggest: при большем расстоянии
+// под ограничение попадает заметная часть словаря, и обход теряет смысл.
+const maxSuggestDistance = 3
+
+// spellMatch - словоформа, найденная Suggest, с расстоянием до исходного слова.
+type spellMatch struct {
+	word     string
+	distance int
+}
+
+// Suggest возвращает словарные словоформы, отличающиеся от word не более чем на maxDist
+// правок: замен, вставок, удалений символов и перестановок соседних символов ("кшока" -> "кошка").
+// Словоформы упорядочены по расстоянию, затем по частоте (см. WithWordFrequencies)
+// и по алфавиту; словарное слово возвращается первым с расстоянием 0.
+// Сравнение идет без учета регистра, результат - в нижнем регистре.
+// maxDist больше 3 считается равным 3; для отрицательного maxDist возвращает nil.
+func (a *MorphAnalyzer) Suggest(word string, maxDist int) []string {
+	target := []rune(strings.ToLower(word))
+	if len(target) == 0 || maxDist < 0 || len(a.nodes) == 0 {
+		return nil
+	}
+	maxDist = min(maxDist, maxSuggestDistance)
+
+	// rows[i] - строка таблицы расстояний для пути длины i: rows[i][j] - расстояние
+	// между путем и первыми j символами слова.
+	rows := [][]int{make([]int, len(target)+1)}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	path := make([]rune, 0, len(target)+maxDist)
+	var matches []spellMatch
+
+	var walk func(nodeIndex uint32)
+	walk = func(nodeIndex uint32) {
+		depth := len(path)
+		row := rows[depth]
+		node := a.nodes[nodeIndex]
+		if node.IsFinal && row[len(target)] <= maxDist {
+			matches = append(matches, spellMatch{word: string(path), distance: row[len(target)]})
+		}
+		if depth == len(rows)-1 {
+			rows = append(rows, make([]int, len(target)+1))
+		}
+		var prev []int
+		var last rune
+		if depth > 0 {
+			prev, last = rows[depth-1], path[depth-1]
+		}
+		next := rows[depth+1]
+		for _, edge := range a.edges[node.EdgesIdx : node.EdgesIdx+uint32(node.EdgesLen)] {
+			// Минимум строки не убывает с глубиной, поэтому ветвь с минимумом
+			// больше maxDist не содержит подходящих слов.
+			if distanceRow(next, row, prev, target, edge.Char, last) > maxDist {
+				continue
+			}
+			path = append(path, edge.Char)
+			walk(edge.NodeID)
+			path = path[:depth]
+		}
+	}
+	walk(0)
+
+	slices.SortFunc(matches, func(x, y spellMatch) int {
+		if x.distance != y.distance {
+			return x.distance - y.distance
+		}
+		if fx, fy := a.wordFrequencies[x.word], a.wordFrequencies[y.word]; fx != fy {
+			if fx > fy {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x.word, y.word)
+	})
+	var result []string
+	for _, m := range matches {
+		result = append(result, m.word)
+	}
+	return result
+}
+
+// distanceRow заполняет next - строку расстояний для пути, продолженного символом char,
+// по строке row самого пути и строке prev пути без последнего символа last
+// (prev равна nil для пустого пути), и возвращает минимум строки.
+func distanceRow(next, row, prev []int, target []rune, char, last rune) int {
+	next[0] = row[0] + 1
+	rowMin := next[0]
+	for j := 1; j <= len(target); j++ {
+		cost := 1
+		if target[j-1] == char {
+			cost = 0
+		}
+		d := min(row[j]+1, next[j-1]+1, row[j-1]+cost)
+		// Перестановка соседних символов.
+		if prev != nil && j > 1 && target[j-2] == char && target[j-1] == last {
+			d = min(d, prev[j-2]+1)
+		}
+		next[j] = d
+		rowMin = min(rowMin, d)
+	}
+	return rowMin
+}
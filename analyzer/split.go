@@ -0,0 +1,103 @@
+// split.go содержит разбиение строк без пробелов (хэштеги, домены, SEO-строки)
+// на словарные слова динамическим программированием по DAWG.
+package analyzer
+
+import (
+	"unicode"
+)
+
+// Стоимости сегментов при разбиении: выигрывает разбиение с наименьшей суммой.
+// Однобуквенные слова дороже, чтобы "ивановаулица" не превращалось в цепочку
+// предлогов и союзов, а символы вне словаря - еще дороже, но допустимы.
+const (
+	splitWordCost        = 2
+	splitOneLetterCost   = 3
+	splitUnknownRuneCost = 10
+)
+
+// SplitConcatenated разбивает слитную строку на словарные слова:
+// "купитьквартирумосква" -> ["купить", "квартиру", "москва"]. Предпочитается разбиение
+// на меньшее число слов; символы, не входящие ни в одно словарное слово, объединяются
+// в отдельные сегменты. Небуквенные символы ("#", ".", цифры) разделяют строку
+// и в результат не попадают. Регистр исходной строки сохраняется.
+func (a *MorphAnalyzer) SplitConcatenated(s string) []string {
+	var result []string
+	runes := []rune(s)
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && unicode.IsLetter(runes[end]) {
+			end++
+		}
+		result = append(result, a.splitLetters(runes[start:end])...)
+		start = end
+	}
+	return result
+}
+
+// splitLetters разбивает последовательность букв. cost[i] - наименьшая стоимость
+// разбиения первых i символов, prev[i] - начало последнего сегмента,
+// unknown[i] - последний сегмент состоит из символа вне словаря.
+func (a *MorphAnalyzer) splitLetters(runes []rune) []string {
+	n := len(runes)
+	lower := make([]rune, n)
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	const unreachable = -1
+	cost := make([]int, n+1)
+	prev := make([]int, n+1)
+	unknown := make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		cost[i] = unreachable
+	}
+
+	relax := func(from, to, c int, isUnknown bool) {
+		if cost[to] == unreachable || c < cost[to] {
+			cost[to], prev[to], unknown[to] = c, from, isUnknown
+		}
+	}
+	for i := 0; i < n; i++ {
+		// Переход через символ вне словаря возможен всегда.
+		relax(i, i+1, cost[i]+splitUnknownRuneCost, true)
+
+		// Все словарные слова, начинающиеся в позиции i, - за один проход по DAWG.
+		nodeIndex := uint32(0)
+		for j := i; j < n; j++ {
+			next, ok := a.findChildGeneral(nodeIndex, lower[j], a.nodes, a.edges, a.rootIndex)
+			if !ok {
+				break
+			}
+			nodeIndex = next
+			if !a.nodes[nodeIndex].IsFinal {
+				continue
+			}
+			c := splitWordCost
+			if j == i {
+				c = splitOneLetterCost
+			}
+			relax(i, j+1, cost[i]+c, false)
+		}
+	}
+
+	// Восстанавливаем сегменты с конца, склеивая соседние символы вне словаря.
+	var segments []string
+	for end := n; end > 0; {
+		start := prev[end]
+		if unknown[end] {
+			for start > 0 && unknown[start] {
+				start = prev[start]
+			}
+		}
+		segments = append(segments, string(runes[start:end]))
+		end = start
+	}
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	return segments
+}
@@ -0,0 +1,192 @@
+// stats.go содержит статистику анализатора и хуки разбора: сколько слов разобрано
+// словарем, предсказателем и другими звеньями, сколько не разобрано вовсе, попадания
+// в кэш и время разбора. Сервисам это нужно, чтобы видеть долю несловарных слов
+// в рабочем трафике. Статистика и хуки включаются опциями, без них разбор не замедляется.
+// Хуки разбора и пакетных операций - точка подключения трассировки и метрик
+// (см. пакет github.com/steosofficial/steosmorphy/telemetry).
+package analyzer
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Операции, о которых сообщают Stats и AnalysisEvent.
+const (
+	OpParse   = "parse"   // Parse и методы поверх него (ParseList, ParseE, ...).
+	OpPredict = "predict" // ParsePredicted.
+	OpAnalyze = "analyze" // Разбор цепочкой звеньев: Analyze, Lemmatize, AnalyzeText, потоковые методы.
+)
+
+// Пакетные операции, о которых сообщает BatchHook.
+const (
+	OpParseList        = "parse-list"         // ParseList и ParseListCtx.
+	OpParseListGrouped = "parse-list-grouped" // ParseListGrouped.
+	OpInflectList      = "inflect-list"       // InflectList, InflectListCtx и InflectListFunc.
+	OpInflectListTo    = "inflect-list-to"    // InflectListTo.
+)
+
+// Stats - накопленная статистика анализатора (см. WithStats).
+type Stats struct {
+	Words          uint64        // Разобранных слов (вызовов Parse, ParsePredicted, Analyze, Lemmatize и т.д.).
+	DictionaryHits uint64        // Слов, разобранных словарем.
+	PredictorHits  uint64        // Слов, разобранных предсказателем.
+	OtherHits      uint64        // Слов, разобранных другими звеньями (числа, латиница, дефис, приставки).
+	Misses         uint64        // Слов, не разобранных ни одним звеном.
+	CacheHits      uint64        // Попаданий в кэш (см. WithCache).
+	CacheMisses    uint64        // Промахов кэша.
+	TotalLatency   time.Duration // Суммарное время разбора.
+}
+
+// AvgLatency возвращает среднее время разбора слова.
+func (s Stats) AvgLatency() time.Duration {
+	if s.Words == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Words)
+}
+
+// OOVRate возвращает долю несловарных слов: разобранных не словарем или не разобранных вовсе.
+func (s Stats) OOVRate() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.Words-s.DictionaryHits) / float64(s.Words)
+}
+
+// AnalysisEvent - сведения об одном разборе слова для хука (см. WithAnalysisHook).
+type AnalysisEvent struct {
+	Op      string        // Операция: OpParse, OpPredict или OpAnalyze.
+	Word    string        // Исходное слово.
+	Method  string        // Имя разобравшего слово звена (UnitDictionary, UnitPredictor, ...); "" - не разобрано.
+	Parses  int           // Число разборов.
+	Latency time.Duration // Время разбора.
+}
+
+// AnalysisHook - функция, вызываемая после каждого разбора слова. Вызывается из горутины,
+// выполнявшей разбор (в том числе из воркеров ParseList), поэтому должна быть
+// потокобезопасной и быстрой.
+type AnalysisHook func(AnalysisEvent)
+
+// BatchHook вызывается в начале пакетной операции op над words словами и возвращает функцию,
+// которую анализатор вызывает по ее окончании с числом результатов и ошибкой (отмена
+// контекста, ошибка emit). ctx - контекст операции (context.Background() для методов
+// без контекста), по нему трассировщик находит родительский спан. Возвращенная функция
+// может быть nil.
+type BatchHook func(ctx context.Context, op string, words int) (done func(results int, err error))
+
+// WithStats включает подсчет статистики, которую возвращает Stats. Счетчики атомарные
+// и общие для всех горутин, поэтому при очень высокой конкуренции статистика немного
+// замедляет разбор.
+func WithStats() Option {
+	return func(a *MorphAnalyzer) {
+		a.stats = &statsCounters{}
+	}
+}
+
+// WithAnalysisHook добавляет хук, вызываемый после каждого разбора слова:
+// для метрик, трассировки и журнала несловарных слов.
+func WithAnalysisHook(hook AnalysisHook) Option {
+	return func(a *MorphAnalyzer) {
+		a.hooks = append(a.hooks, hook)
+	}
+}
+
+// WithBatchHook добавляет хук пакетных операций (ParseList, InflectList и их вариантов).
+func WithBatchHook(hook BatchHook) Option {
+	return func(a *MorphAnalyzer) {
+		a.batchHooks = append(a.batchHooks, hook)
+	}
+}
+
+// Stats возвращает статистику с момента загрузки анализатора. Без опции WithStats
+// возвращает нулевое значение.
+func (a *MorphAnalyzer) Stats() Stats {
+	c := a.stats
+	if c == nil {
+		return Stats{}
+	}
+	return Stats{
+		Words:          c.words.Load(),
+		DictionaryHits: c.dictionaryHits.Load(),
+		PredictorHits:  c.predictorHits.Load(),
+		OtherHits:      c.otherHits.Load(),
+		Misses:         c.misses.Load(),
+		CacheHits:      c.cacheHits.Load(),
+		CacheMisses:    c.cacheMisses.Load(),
+		TotalLatency:   time.Duration(c.latency.Load()),
+	}
+}
+
+// statsCounters - счетчики статистики анализатора.
+type statsCounters struct {
+	words, dictionaryHits, predictorHits, otherHits, misses atomic.Uint64
+	cacheHits, cacheMisses                                  atomic.Uint64
+	latency                                                 atomic.Int64 // Наносекунды.
+}
+
+// observed сообщает, что разборы нужно учитывать: включена статистика или заданы хуки.
+func (a *MorphAnalyzer) observed() bool {
+	return a.stats != nil || len(a.hooks) > 0
+}
+
+// observe учитывает разбор слова word, начатый в started, в статистике и передает его хукам.
+func (a *MorphAnalyzer) observe(op, word string, parses []*Parsed, started time.Time) {
+	latency := time.Since(started)
+	method := ""
+	if len(parses) > 0 {
+		method = parses[0].Method
+	}
+	if c := a.stats; c != nil {
+		c.words.Add(1)
+		c.latency.Add(int64(latency))
+		switch {
+		case len(parses) == 0:
+			c.misses.Add(1)
+		case method == UnitDictionary:
+			c.dictionaryHits.Add(1)
+		case method == UnitPredictor:
+			c.predictorHits.Add(1)
+		default:
+			c.otherHits.Add(1)
+		}
+	}
+	if len(a.hooks) > 0 {
+		event := AnalysisEvent{Op: op, Word: word, Method: method, Parses: len(parses), Latency: latency}
+		for _, hook := range a.hooks {
+			hook(event)
+		}
+	}
+}
+
+// countCache учитывает попадание или промах кэша.
+func (a *MorphAnalyzer) countCache(hit bool) {
+	if c := a.stats; c != nil {
+		if hit {
+			c.cacheHits.Add(1)
+		} else {
+			c.cacheMisses.Add(1)
+		}
+	}
+}
+
+// startBatch сообщает хукам о начале пакетной операции и возвращает функцию,
+// которую нужно вызвать по ее окончании.
+func (a *MorphAnalyzer) startBatch(ctx context.Context, op string, words int) func(results int, err error) {
+	if len(a.batchHooks) == 0 {
+		return func(int, error) {}
+	}
+	dones := make([]func(int, error), 0, len(a.batchHooks))
+	for _, hook := range a.batchHooks {
+		if done := hook(ctx, op, words); done != nil {
+			dones = append(dones, done)
+		}
+	}
+	return func(results int, err error) {
+		// В обратном порядке, как вложенные defer.
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](results, err)
+		}
+	}
+}
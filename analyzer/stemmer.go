@@ -0,0 +1,95 @@
+// stemmer.go содержит звено разбора слов языков без словаря стеммером. В смешанных
+// корпусах попадаются английские, немецкие, арабские слова: без словаря их языка
+// LatinUnit оставляет слово как есть, а суффиксный предсказатель приписал бы им
+// русскую парадигму. Стеммер (например, Snowball из пакета snowball) сводит "running"
+// и "runs" к общей основе "run", и поиск по леммам находит все формы слова.
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// stemTag - тег разбора, лемма которого - основа, полученная стеммером.
+const stemTag = "Основа"
+
+// Stemmer - стеммер языка без словаря. Stem получает слово в нижнем регистре
+// и возвращает его основу. Stem вызывается из нескольких горутин одновременно.
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// StemmerFunc - функция, реализующая Stemmer.
+type StemmerFunc func(word string) string
+
+// Stem вызывает f(word).
+func (f StemmerFunc) Stem(word string) string { return f(word) }
+
+// scriptStemmer - стеммер для слов одного алфавита (см. WithFallbackStemmer).
+type scriptStemmer struct {
+	script  *unicode.RangeTable
+	stemmer Stemmer
+}
+
+// WithFallbackStemmer задает стеммер для несловарных слов алфавита script
+// (unicode.Latin, unicode.Arabic, ...): звено StemmerUnit разбирает их с леммой-основой
+// и тегами "Латиница,Основа" (для других алфавитов - "Неизвестное,Основа") вместо
+// леммы-слова LatinUnit и ForeignUnit. Повторный вызов для того же алфавита заменяет
+// стеммер. Словарные слова по-прежнему разбирает словарь, поэтому стеммер для кириллицы
+// заменяет суффиксный предсказатель только для несловарных слов.
+func WithFallbackStemmer(script *unicode.RangeTable, s Stemmer) Option {
+	return func(a *MorphAnalyzer) {
+		a.fallbackStemmers = slices.DeleteFunc(slices.Clone(a.fallbackStemmers), func(st scriptStemmer) bool {
+			return st.script == script
+		})
+		a.fallbackStemmers = append(a.fallbackStemmers, scriptStemmer{script: script, stemmer: s})
+	}
+}
+
+// StemmerUnit разбирает несловарные слова стеммерами, заданными WithFallbackStemmer.
+// Слово разбирается, если все его буквы принадлежат алфавиту одного из стеммеров
+// (дефисы и апострофы допускаются). Без стеммеров звено ничего не разбирает.
+type StemmerUnit struct{}
+
+// Name возвращает имя звена.
+func (StemmerUnit) Name() string { return UnitStemmer }
+
+// Parse возвращает разбор с леммой-основой слова или nil, если стеммера для алфавита слова нет.
+func (StemmerUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	for _, st := range a.fallbackStemmers {
+		if !isScriptWord(word, st.script) {
+			continue
+		}
+		lower := strings.ToLower(word)
+		stem := st.stemmer.Stem(lower)
+		if stem == "" {
+			stem = lower
+		}
+		tags := unknownTags + "," + stemTag
+		if st.script == unicode.Latin {
+			tags = latinTags + "," + stemTag
+		}
+		return []*Parsed{newParsed(word, stem, tags)}
+	}
+	return nil
+}
+
+// Inflect возвращает nil: словоформы по основе не генерируются.
+func (StemmerUnit) Inflect(*MorphAnalyzer, string, []*Parsed) []*Parsed { return nil }
+
+// isScriptWord сообщает, что все буквы слова принадлежат алфавиту script,
+// а остальные символы - дефисы и апострофы.
+func isScriptWord(word string, script *unicode.RangeTable) bool {
+	hasLetter := false
+	for _, r := range word {
+		switch {
+		case unicode.Is(script, r):
+			hasLetter = true
+		case strings.ContainsRune(wordJoiners, r):
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
@@ -0,0 +1,80 @@
+// stopwords.go содержит встроенный список русских стоп-слов (предлогов, союзов, частиц)
+// для фильтрации служебных слов при индексации текста.
+package analyzer
+
+import (
+	"slices"
+	"strings"
+)
+
+// stopwordTags - части речи служебных слов, которые считаются стоп-словами.
+var stopwordTags = GrammemeSet{
+	"Предлог": {},
+	"Союз":    {},
+	"Частица": {},
+}
+
+// builtinStopwords - служебные слова, которые нужно отбрасывать независимо от словаря:
+// часть из них в словаре отсутствует, а у части есть омонимы знаменательных частей речи
+// ("при" - предлог и форма глагола "переть", "за" - предлог и существительное).
+// Слова из одной-двух букв берутся из shortClosedClass.
+var builtinStopwords = []string{
+	// Предлоги.
+	"без", "безо", "благодаря", "ввиду", "вместо", "вне", "внутри", "вокруг", "вопреки",
+	"вследствие", "для", "из-за", "из-под", "кроме", "между", "меж", "над", "надо", "насчёт",
+	"насчет", "несмотря", "обо", "около", "перед", "передо", "по-над", "под", "подо", "помимо",
+	"посредством", "при", "про", "против", "ради", "сквозь", "среди", "спустя", "через",
+
+	// Союзы.
+	"если", "ежели", "зато", "ибо", "или", "либо", "когда", "как", "однако", "поскольку",
+	"пока", "причём", "причем", "пускай", "пусть", "также", "тоже", "хотя", "хоть", "чем",
+	"что", "чтобы", "чтоб", "будто", "словно", "нежели", "притом", "впрочем",
+
+	// Частицы.
+	"даже", "ещё", "еще", "именно", "лишь", "разве", "неужели", "только", "вот", "вон",
+	"ведь", "уже", "всё-таки", "все-таки",
+}
+
+// stopwordSet - встроенные стоп-слова вместе со служебными словами из shortClosedClass.
+var stopwordSet = func() map[string]struct{} {
+	set := make(map[string]struct{}, len(builtinStopwords)+len(shortClosedClass))
+	for _, w := range builtinStopwords {
+		set[w] = struct{}{}
+	}
+	for w, tags := range shortClosedClass {
+		for _, tag := range tags {
+			if inMap(tag, stopwordTags) {
+				set[w] = struct{}{}
+				break
+			}
+		}
+	}
+	return set
+}()
+
+// Stopwords возвращает встроенный список стоп-слов в алфавитном порядке.
+func Stopwords() []string {
+	words := make([]string, 0, len(stopwordSet))
+	for w := range stopwordSet {
+		words = append(words, w)
+	}
+	slices.Sort(words)
+	return words
+}
+
+// IsStopword сообщает, что слово служебное: входит во встроенный список стоп-слов
+// или все его словарные разборы - предлоги, союзы и частицы.
+func (a *MorphAnalyzer) IsStopword(word string) bool {
+	lowerWord := strings.ToLower(word)
+	if _, ok := stopwordSet[lowerWord]; ok {
+		return true
+	}
+	payloads := a.lookupPayloads(lowerWord)
+	for _, info := range payloads {
+		pos, _, _ := strings.Cut(a.tags.at(info.TagsID), ",")
+		if !inMap(pos, stopwordTags) {
+			return false
+		}
+	}
+	return len(payloads) > 0
+}
@@ -0,0 +1,139 @@
+// stream.go содержит потоковый анализ текста: слова читаются из io.Reader по мере
+// поступления, и результат никогда не накапливается целиком в памяти.
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AnalyzeStream разбивает поток на слова и вызывает fn для каждого разбора каждого слова
+// в порядке следования в тексте. Несловарные слова разбираются предсказателем.
+// Ошибка fn прекращает чтение и возвращается без обертки.
+func (a *MorphAnalyzer) AnalyzeStream(r io.Reader, fn func(*Parsed) error) error {
+	return a.AnalyzeWordStream(r, func(_ string, _ int64, parses []*Parsed) error {
+		for _, p := range parses {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AnalyzeWordStream работает как AnalyzeStream, но вызывает fn один раз на слово со всеми
+// его разборами и смещением в байтах от начала r, с которого продолжается чтение после слова.
+// По смещению можно возобновить обработку с того же места после перезапуска (см. пакет jobs).
+//
+// Последовательности букв, которые заведомо длиннее лимита WithMaxWordLen (base64, минифицированный
+// код), пропускаются без вызова fn, не удерживаясь в памяти целиком; без лимита пропускаются
+// последовательности длиннее maxStreamWordBytes байт.
+func (a *MorphAnalyzer) AnalyzeWordStream(r io.Reader, fn func(word string, end int64, parses []*Parsed) error) error {
+	maxBytes := maxStreamWordBytes
+	if limit := a.maxWordLen; limit >= 0 {
+		if limit == 0 {
+			limit = DefaultMaxWordLen
+		}
+		maxBytes = min(maxBytes, limit*utf8.UTFMax)
+	}
+	var offset int64
+	skipping := false // Пропускается слово длиннее maxBytes.
+	scanner := bufio.NewScanner(r)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		var advance int
+		var token []byte
+		var err error
+		if !skipping {
+			advance, token, err = scanLetterWords(data, atEOF)
+			// Слово не закончилось, а данных уже больше лимита: дальше только пропускаем.
+			skipping = token == nil && err == nil && !atEOF && len(data)-advance > maxBytes
+		}
+		if skipping {
+			n, done := skipLetterWord(data[advance:], atEOF)
+			advance += n
+			skipping = !done
+		}
+		offset += int64(advance)
+		return advance, token, err
+	})
+	for scanner.Scan() {
+		word := scanner.Text()
+		if err := fn(word, offset, a.localize(a.parseOrPredict(word))); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения потока: %w", err)
+	}
+	return nil
+}
+
+// maxStreamWordBytes - наибольшая длина слова в байтах, которое AnalyzeWordStream передает
+// разбору без лимита WithMaxWordLen: вдвое меньше буфера bufio.Scanner по умолчанию.
+const maxStreamWordBytes = bufio.MaxScanTokenSize / 2
+
+// skipLetterWord пропускает буквы, дефисы и апострофы в начале data и сообщает, закончилось ли
+// слово. Неполный символ UTF-8 в конце data не пропускается, пока не придут остальные байты.
+func skipLetterWord(data []byte, atEOF bool) (advance int, done bool) {
+	for advance < len(data) {
+		if !atEOF && !utf8.FullRune(data[advance:]) {
+			return advance, false
+		}
+		r, width := utf8.DecodeRune(data[advance:])
+		if !unicode.IsLetter(r) && !strings.ContainsRune(wordJoiners, r) {
+			return advance, true
+		}
+		advance += width
+	}
+	return advance, atEOF
+}
+
+// scanLetterWords - функция разбиения для bufio.Scanner, выделяющая слова так же, как forEachWord:
+// последовательности букв с внутренними дефисами и апострофами. Остальные символы пропускаются.
+func scanLetterWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	// Пропускаем символы до начала слова.
+	start := 0
+	for start < len(data) {
+		if !atEOF && !utf8.FullRune(data[start:]) {
+			return start, nil, nil
+		}
+		r, width := utf8.DecodeRune(data[start:])
+		if unicode.IsLetter(r) {
+			break
+		}
+		start += width
+	}
+
+	for i := start; i < len(data); {
+		if !atEOF && !utf8.FullRune(data[i:]) {
+			break
+		}
+		r, width := utf8.DecodeRune(data[i:])
+		if unicode.IsLetter(r) {
+			i += width
+			continue
+		}
+		if strings.ContainsRune(wordJoiners, r) {
+			// Чтобы решить судьбу дефиса или апострофа, нужен следующий символ.
+			if !atEOF && !utf8.FullRune(data[i+width:]) {
+				break
+			}
+			next, _ := utf8.DecodeRune(data[i+width:])
+			if unicode.IsLetter(next) {
+				i += width
+				continue
+			}
+		}
+		return i + width, data[start:i], nil
+	}
+
+	if atEOF && start < len(data) {
+		return len(data), data[start:], nil
+	}
+	// Слово не закончилось: запрашиваем больше данных, пропустив разделители.
+	return start, nil, nil
+}
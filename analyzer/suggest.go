@@ -0,0 +1,66 @@
+// suggest.go содержит подготовку записей пользовательского словаря по несловарным словам корпуса:
+// частые несловарные слова группируются по предсказанной лексеме, и для каждой группы
+// предлагается полная парадигма, готовая к проверке и компиляции (см. DictBuilder).
+package analyzer
+
+import (
+	"iter"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DictSuggestion - предлагаемая лексема пользовательского словаря.
+type DictSuggestion struct {
+	Lemma        string     `json:"lemma"`          // Предсказанная лемма
+	PartOfSpeech string     `json:"part_of_speech"` // Предсказанная часть речи
+	Count        int        `json:"count"`          // Вхождений слов группы в корпус
+	Observed     []string   `json:"observed"`       // Формы, встретившиеся в корпусе, по убыванию частоты
+	Entries      []LexEntry `json:"entries"`        // Предлагаемые словоформы (форма, лемма, теги)
+}
+
+// SuggestDictEntries группирует несловарные слова корпуса ("нейросеть", "нейросети", "нейросетями")
+// по предсказанной лексеме и возвращает группы, встретившиеся не менее minCount раз,
+// по убыванию частоты. Записи можно сохранить WriteTSVLexicon и после проверки собрать словарь.
+// Токены без букв (числа, пунктуация) пропускаются.
+func (a *MorphAnalyzer) SuggestDictEntries(words iter.Seq[string], minCount int) []DictSuggestion {
+	_, oov := a.coverage(words)
+
+	type clusterKey struct{ lemma, pos string }
+	clusters := make(map[clusterKey]*DictSuggestion)
+	var order []clusterKey
+	for _, wc := range oov {
+		if strings.IndexFunc(wc.Word, unicode.IsLetter) < 0 {
+			continue
+		}
+		predicted := a.parsePredictedCached(wc.Word)
+		if predicted == nil {
+			continue
+		}
+		key := clusterKey{lemma: predicted[0].Lemma, pos: predicted[0].PartOfSpeech}
+		cluster, ok := clusters[key]
+		if !ok {
+			// oov отсортирован по частоте: парадигму строим от самой частой формы группы.
+			lemma, forms := a.predictedLexeme(wc.Word)
+			cluster = &DictSuggestion{Lemma: lemma, PartOfSpeech: key.pos}
+			for _, f := range forms {
+				cluster.Entries = append(cluster.Entries, LexEntry{Word: f.word, Lemma: lemma, Tags: a.tags.at(f.tagsID)})
+			}
+			clusters[key] = cluster
+			order = append(order, key)
+		}
+		cluster.Count += wc.Count
+		cluster.Observed = append(cluster.Observed, wc.Word)
+	}
+
+	var suggestions []DictSuggestion
+	for _, key := range order {
+		if c := clusters[key]; c.Count >= minCount && len(c.Entries) > 0 {
+			suggestions = append(suggestions, *c)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Count > suggestions[j].Count
+	})
+	return suggestions
+}
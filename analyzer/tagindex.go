@@ -0,0 +1,123 @@
+// tagindex.go содержит обратный поиск словоформ по граммемам ("все существительные
+// женского рода в именительном падеже") для лингвистов и генераторов текста.
+// Компилятор может встроить в словарь индекс "набор тегов -> парадигмы"; без него
+// поиск перебирает все парадигмы словаря.
+package analyzer
+
+import (
+	"iter"
+	"slices"
+)
+
+// SetTagIndex включает встраивание в словарь обратного индекса наборов тегов,
+// который ускоряет FindByTags. Индекс увеличивает словарь и время его загрузки,
+// поэтому по умолчанию не строится.
+func (b *DictBuilder) SetTagIndex(enabled bool) {
+	b.tagIndex = enabled
+}
+
+// buildTagIndex строит индекс: для каждого набора тегов - возрастающие ID парадигм,
+// у которых есть форма с этим набором. ID парадигмы - порядковый номер лексемы.
+func (b *DictBuilder) buildTagIndex() map[uint32][]uint32 {
+	index := make(map[uint32][]uint32, len(b.tagsPool))
+	for pID, lex := range b.lexemes {
+		for _, f := range lex.forms {
+			paradigms := index[f.tagsID]
+			if len(paradigms) == 0 || paradigms[len(paradigms)-1] != uint32(pID) {
+				index[f.tagsID] = append(paradigms, uint32(pID))
+			}
+		}
+	}
+	return index
+}
+
+// HasTagIndex сообщает, что словарь содержит обратный индекс наборов тегов (см. FindByTags).
+func (a *MorphAnalyzer) HasTagIndex() bool {
+	return a.tagIndex != nil
+}
+
+// FindByTags возвращает словарные словоформы, теги которых содержат все граммемы
+// (на любом языке, см. Lang): FindByTags("Существительное", "Женский", "Именительный").
+// Словоформы выдаются по лексемам в порядке словаря; омонимичная форма одной лексемы
+// ("кошки" - Р.п. ед. ч. и И.п. мн. ч.) выдается один раз. Винительный падеж
+// существительных согласуется с одушевленностью (см. ResolveAccusative).
+// Без граммем выдаются все словоформы словаря.
+//
+// Если словарь собран с индексом (см. DictBuilder.SetTagIndex), перебираются только
+// подходящие лексемы, иначе - все лексемы словаря. Результат не накапливается в памяти,
+// поэтому перебор можно прервать в любой момент.
+func (a *MorphAnalyzer) FindByTags(grammemes ...string) iter.Seq[string] {
+	// Граммемы, которой нет в словаре, не содержит ни один набор тегов.
+	query, err := a.MaskOf(grammemes...)
+	matching := make(map[uint32]struct{})
+	for tagsID, mask := range a.tagMasks {
+		if err == nil && mask.Contains(query) {
+			matching[uint32(tagsID)] = struct{}{}
+		}
+	}
+
+	return func(yield func(string) bool) {
+		if len(matching) == 0 {
+			return
+		}
+		for pID := range a.paradigmsWithTags(matching) {
+			for _, word := range a.formsWithTags(pID, matching) {
+				if !yield(word) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// paradigmsWithTags возвращает по возрастанию ID парадигм, у которых могут быть формы
+// с наборами тегов из matching: по индексу или, без него, все парадигмы словаря.
+func (a *MorphAnalyzer) paradigmsWithTags(matching map[uint32]struct{}) iter.Seq[uint32] {
+	if a.tagIndex == nil {
+		return a.paradigms.ids()
+	}
+	var paradigms []uint32
+	for tagsID := range matching {
+		paradigms = append(paradigms, a.tagIndex[tagsID]...)
+	}
+	slices.Sort(paradigms)
+	return slices.Values(slices.Compact(paradigms))
+}
+
+// formsWithTags возвращает различные словоформы парадигмы с наборами тегов из matching.
+func (a *MorphAnalyzer) formsWithTags(pID uint32, matching map[uint32]struct{}) []string {
+	forms := a.lexemeForms(pID)
+	keep := func(f lexemeForm) bool {
+		_, ok := matching[f.tagsID]
+		return ok
+	}
+	if slices.ContainsFunc(forms, func(f lexemeForm) bool {
+		tags := a.tags.at(f.tagsID)
+		return keep(f) && hasGrammeme(tags, "Существительное") && hasGrammeme(tags, "Винительный")
+	}) {
+		// Ложные варианты винительного падежа отбрасываем по разборам всей лексемы.
+		lemmaID, _ := a.paradigms.lemma(pID)
+		parses := make([]*Parsed, len(forms))
+		for i, f := range forms {
+			parses[i] = newParsed(f.word, a.lemmas.at(lemmaID), a.tags.at(f.tagsID))
+		}
+		resolved := make(map[lexemeForm]struct{}, len(forms))
+		for _, p := range ResolveAccusative(parses) {
+			resolved[forms[slices.Index(parses, p)]] = struct{}{}
+		}
+		keep = func(f lexemeForm) bool {
+			_, ok := resolved[f]
+			_, match := matching[f.tagsID]
+			return ok && match
+		}
+	}
+
+	var words []string
+	for _, f := range forms {
+		// Формы упорядочены по слову, поэтому повторы идут подряд.
+		if keep(f) && (len(words) == 0 || words[len(words)-1] != f.word) {
+			words = append(words, f.word)
+		}
+	}
+	return words
+}
@@ -5,6 +5,7 @@
 package analyzer
 
 import (
+	"encoding/json"
 	"strings"
 )
 
@@ -28,6 +29,15 @@ type Parsed struct {
 	Transitivity string      `json:"transitivity"`   // Переходность
 	Voice        string      `json:"voice"`          // Залог
 	OtherTags    GrammemeSet `json:"other_tags"`     // Остальные теги, не вошедшие в основные категории
+
+	// Источник разбора и уверенность в нем. Заполняются у разборов (Parse, ParsePredicted,
+	// Analyze и т.д.), у сгенерированных словоформ пусты.
+	Method     string  `json:"method,omitempty"`     // Имя разобравшего слово звена: "dictionary", "predictor", "prefix", ...
+	Confidence float64 `json:"confidence,omitempty"` // Уверенность от 0 до 1; у словарных разборов 1.
+
+	lazy   bool         // Категории и OtherTags еще не заполнены (см. WithLazyDetails и Details).
+	masked bool         // Маска граммем взята из словаря (см. MorphAnalyzer.Mask).
+	mask   GrammemeMask // Маска граммем, если masked.
 }
 
 // Глобальные переменные, содержащие множества всех возможных граммем для каждой категории.
@@ -134,21 +144,66 @@ var (
 // Он принимает "сырые" данные (слово, лемму и строку тегов) и возвращает
 // полностью заполненный, структурированный объект.
 func newParsed(word, lemma, tagString string) *Parsed {
-	// Создаем базовый объект с основными данными.
-	p := &Parsed{Word: word, Lemma: lemma, Tags: tagString, OtherTags: make(GrammemeSet)}
+	p := &Parsed{OtherTags: make(GrammemeSet)}
+	p.fill(word, lemma, tagString)
+	return p
+}
 
-	// Разбиваем строку тегов на отдельные граммемы.
-	grammemes := strings.Split(tagString, ",")
+// newLazyParsed создает разбор без раскладки граммем по категориям: заполняются только
+// слово, лемма, строка тегов и часть речи (см. WithLazyDetails).
+func newLazyParsed(word, lemma, tagString string) *Parsed {
+	p := &Parsed{Word: word, Lemma: lemma, Tags: tagString, lazy: true}
+	if first, _, _ := strings.Cut(tagString, ","); inMap(first, posTags) {
+		p.PartOfSpeech = first
+	}
+	return p
+}
+
+// Details возвращает разбор с заполненными категориями (Case, Gender, ...) и OtherTags.
+// Разборы анализатора с опцией WithLazyDetails содержат только слово, лемму, теги,
+// часть речи, метод и уверенность; для них Details возвращает новый полный разбор,
+// остальные разборы возвращаются как есть. Исходный разбор не изменяется,
+// поэтому метод безопасен для разборов, разделяемых через кэш.
+func (p *Parsed) Details() *Parsed {
+	if !p.lazy {
+		return p
+	}
+	d := newParsed(p.Word, p.Lemma, p.Tags)
+	d.Method, d.Confidence = p.Method, p.Confidence
+	d.mask, d.masked = p.mask, p.masked
+	return d
+}
+
+// MarshalJSON сериализует разбор с заполненными категориями (см. Details).
+func (p *Parsed) MarshalJSON() ([]byte, error) {
+	type plain Parsed // Без метода MarshalJSON, чтобы не уйти в рекурсию.
+	return json.Marshal((*plain)(p.Details()))
+}
+
+// fill заполняет разбор заново по строке тегов. Карта OtherTags переиспользуется
+// (и создается, если ее нет), поэтому для разбора с картой метод не выделяет память:
+// граммемы - подстроки tagString.
+func (p *Parsed) fill(word, lemma, tagString string) {
+	otherTags := p.OtherTags
+	if otherTags == nil {
+		otherTags = make(GrammemeSet)
+	} else {
+		clear(otherTags)
+	}
+	// Создаем базовый объект с основными данными.
+	*p = Parsed{Word: word, Lemma: lemma, Tags: tagString, OtherTags: otherTags}
 
 	// Обрабатываем `Часть Речи` отдельно, так как она всегда идет первой.
-	if len(grammemes) > 0 {
-		if _, ok := posTags[grammemes[0]]; ok {
-			p.PartOfSpeech = grammemes[0]
-		}
+	first, _, _ := strings.Cut(tagString, ",")
+	if _, ok := posTags[first]; ok {
+		p.PartOfSpeech = first
 	}
 
 	// Проходим по всем граммемам и раскладываем их по соответствующим полям структуры `Parsed`.
-	for _, g := range grammemes {
+	// Граммемы выделяются без strings.Split, чтобы не создавать срез.
+	for rest, more := tagString, true; more; {
+		var g string
+		g, rest, more = strings.Cut(rest, ",")
 		switch {
 		case g == p.PartOfSpeech: // пропускаем, так как уже обработали.
 		case inMap(g, animacyTags):
@@ -177,10 +232,36 @@ func newParsed(word, lemma, tagString string) *Parsed {
 			p.OtherTags[g] = struct{}{}
 		}
 	}
-	return p
 }
 
 func inMap(key string, set GrammemeSet) bool {
 	_, ok := set[key]
 	return ok
 }
+
+// hasGrammeme проверяет, содержит ли строка тегов граммему g (без разбора в объект Parsed).
+func hasGrammeme(tagString, g string) bool {
+	for tagString != "" {
+		var tag string
+		tag, tagString, _ = strings.Cut(tagString, ",")
+		if tag == g {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllGrammemes проверяет, что строка тегов содержит все граммемы из списка.
+func hasAllGrammemes(tagString string, grammemes []string) bool {
+	for _, g := range grammemes {
+		if !hasGrammeme(tagString, g) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNonNormative сообщает, что форма помечена как устаревшая или разговорная.
+func isNonNormative(tagString string) bool {
+	return hasGrammeme(tagString, "Устаревший") || hasGrammeme(tagString, "Разговорный")
+}
@@ -0,0 +1,294 @@
+// text.go содержит лемматизацию целого текста: разбиение на токены (слова, числа,
+// пунктуацию), разбор слов и фильтрацию токенов для поисковой индексации.
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind - тип токена текста.
+type TokenKind int
+
+const (
+	TokenWord        TokenKind = iota // Слово: буквы с внутренними дефисами ("кто-нибудь").
+	TokenNumber                       // Число: цифры с внутренними точками и запятыми ("3,14", "10%").
+	TokenPunctuation                  // Знаки препинания и прочие символы, кроме пробельных.
+)
+
+// String возвращает название типа токена.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenWord:
+		return "word"
+	case TokenNumber:
+		return "number"
+	case TokenPunctuation:
+		return "punctuation"
+	default:
+		return "unknown"
+	}
+}
+
+// Token - токен текста с результатом разбора.
+// Start и End - байтовые смещения в исходной строке (text[Start:End]).
+type Token struct {
+	Text     string    `json:"text"`
+	Start    int       `json:"start"`
+	End      int       `json:"end"`
+	Kind     TokenKind `json:"kind"`
+	Lemma    string    `json:"lemma"`              // Лемма первого разбора; для несловарных слов без разбора, чисел и пунктуации - текст в нижнем регистре.
+	Parses   []*Parsed `json:"parses,omitempty"`   // Разборы слова (словарные или предсказанные); для чисел и пунктуации - nil.
+	Stopword bool      `json:"stopword,omitempty"` // Слово - стоп-слово (заполняется с опцией MarkStopwords).
+}
+
+// textOptions - настройки LemmatizeText.
+type textOptions struct {
+	keepPunctuation  bool
+	dropNumbers      bool
+	builtinStopwords bool // Учитывать встроенный список стоп-слов (см. IsStopword).
+	markStopwords    bool // Помечать стоп-слова вместо отбрасывания.
+	stopwords        map[string]struct{}
+}
+
+// TextOption - функциональная опция для LemmatizeText.
+type TextOption func(*textOptions)
+
+// KeepPunctuation оставляет в результате токены пунктуации (по умолчанию они отбрасываются).
+func KeepPunctuation() TextOption {
+	return func(o *textOptions) {
+		o.keepPunctuation = true
+	}
+}
+
+// DropNumbers отбрасывает числа (по умолчанию они остаются в результате).
+func DropNumbers() TextOption {
+	return func(o *textOptions) {
+		o.dropNumbers = true
+	}
+}
+
+// DropStopwords отбрасывает служебные слова из встроенного списка (см. IsStopword).
+func DropStopwords() TextOption {
+	return func(o *textOptions) {
+		o.builtinStopwords = true
+	}
+}
+
+// MarkStopwords оставляет стоп-слова в результате, выставляя у них Token.Stopword.
+// Учитываются встроенный список и слова из WithStopwords.
+func MarkStopwords() TextOption {
+	return func(o *textOptions) {
+		o.builtinStopwords = true
+		o.markStopwords = true
+	}
+}
+
+// WithStopwords добавляет собственные стоп-слова, которые сравниваются со словоформой
+// и с леммой без учета регистра. Без MarkStopwords такие слова отбрасываются.
+func WithStopwords(words ...string) TextOption {
+	return func(o *textOptions) {
+		if o.stopwords == nil {
+			o.stopwords = make(map[string]struct{}, len(words))
+		}
+		for _, w := range words {
+			o.stopwords[strings.ToLower(w)] = struct{}{}
+		}
+	}
+}
+
+// LemmatizeText разбивает текст на токены и разбирает каждое слово.
+// Токены возвращаются в порядке следования в тексте со смещениями,
+// поэтому результат подходит и для индексации, и для подсветки найденного.
+// По умолчанию в результат попадают слова и числа; состав настраивается опциями.
+// Lemma слова берется из первого разбора; контекст учитывает AnalyzeText.
+func (a *MorphAnalyzer) LemmatizeText(text string, opts ...TextOption) []Token {
+	return a.localizeTokens(a.filterTokens(a.tokenize(text), opts))
+}
+
+// AnalyzeText работает как LemmatizeText, но снимает неоднозначность по контексту
+// (см. Disambiguate): выбранный разбор ставится первым в Parses, а Lemma берется из него.
+// Контекст учитывается до фильтрации, поэтому отброшенные знаки препинания
+// и стоп-слова все равно влияют на выбор разборов соседних слов.
+func (a *MorphAnalyzer) AnalyzeText(text string, opts ...TextOption) []Token {
+	tokens := a.tokenize(text)
+	for i, p := range a.disambiguate(tokens) {
+		if p == nil {
+			continue
+		}
+		// Срезы разборов общие для одинаковых слов текста, поэтому переставляем в копии.
+		parses := make([]*Parsed, 0, len(tokens[i].Parses))
+		parses = append(parses, p)
+		for _, other := range tokens[i].Parses {
+			if other != p {
+				parses = append(parses, other)
+			}
+		}
+		tokens[i].Parses = parses
+		tokens[i].Lemma = p.Lemma
+	}
+	return a.localizeTokens(a.filterTokens(tokens, opts))
+}
+
+// localizeTokens переводит разборы токенов на язык анализатора (см. WithLang).
+// Одинаковые слова текста делят срез разборов, поэтому каждый срез переводится один раз.
+func (a *MorphAnalyzer) localizeTokens(tokens []Token) []Token {
+	if a.lang == LangRussian {
+		return tokens
+	}
+	localized := make(map[*Parsed][]*Parsed)
+	for i := range tokens {
+		parses := tokens[i].Parses
+		if len(parses) == 0 {
+			continue
+		}
+		if _, ok := localized[parses[0]]; !ok {
+			localized[parses[0]] = a.localize(parses)
+		}
+		tokens[i].Parses = localized[parses[0]]
+	}
+	return tokens
+}
+
+// tokenize разбивает текст на все токены (включая знаки препинания) и разбирает слова.
+func (a *MorphAnalyzer) tokenize(text string) []Token {
+	var tokens []Token
+	// Кэш разборов: в тексте одни и те же слова встречаются многократно.
+	parsed := make(map[string][]*Parsed)
+	forEachToken(text, func(start, end int, kind TokenKind) {
+		token := Token{Text: text[start:end], Start: start, End: end, Kind: kind}
+		if kind == TokenWord {
+			lowerWord := strings.ToLower(token.Text)
+			parses, ok := parsed[lowerWord]
+			if !ok {
+				parses = a.parseOrPredict(lowerWord)
+				parsed[lowerWord] = parses
+			}
+			token.Parses = parses
+			if len(parses) > 0 {
+				token.Lemma = parses[0].Lemma
+			}
+		}
+		if token.Lemma == "" {
+			token.Lemma = strings.ToLower(token.Text)
+		}
+		tokens = append(tokens, token)
+	})
+	return tokens
+}
+
+// filterTokens отбрасывает или помечает токены согласно опциям. Срез tokens переиспользуется.
+func (a *MorphAnalyzer) filterTokens(tokens []Token, opts []TextOption) []Token {
+	var o textOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	kept := tokens[:0]
+	for _, token := range tokens {
+		switch token.Kind {
+		case TokenPunctuation:
+			if !o.keepPunctuation {
+				continue
+			}
+		case TokenNumber:
+			if o.dropNumbers {
+				continue
+			}
+		case TokenWord:
+			if a.isTextStopword(&o, strings.ToLower(token.Text), token.Lemma) {
+				if !o.markStopwords {
+					continue
+				}
+				token.Stopword = true
+			}
+		}
+		kept = append(kept, token)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// isTextStopword проверяет словоформу по встроенному списку стоп-слов,
+// а словоформу и лемму - по собственным стоп-словам из опций.
+func (a *MorphAnalyzer) isTextStopword(o *textOptions, lowerWord, lemma string) bool {
+	if o.builtinStopwords && a.IsStopword(lowerWord) {
+		return true
+	}
+	if _, ok := o.stopwords[lowerWord]; ok {
+		return true
+	}
+	_, ok := o.stopwords[lemma]
+	return ok
+}
+
+// forEachToken вызывает fn для каждого токена текста с его байтовыми границами и типом.
+// Слова выделяются так же, как в forEachWord; числа допускают внутренние точки и запятые,
+// за которыми следует цифра, и знак процента после цифр ("10%"); число с окончанием
+// через дефис ("5-й") считается словом; подряд идущие прочие символы ("?!", "...") образуют один токен.
+// Пробельные символы разделяют токены и в результат не попадают.
+func forEachToken(text string, fn func(start, end int, kind TokenKind)) {
+	for i := 0; i < len(text); {
+		r, width := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += width
+		case unicode.IsLetter(r):
+			end := scanToken(text, i, unicode.IsLetter, wordJoiners)
+			fn(i, end, TokenWord)
+			i = end
+		case unicode.IsDigit(r):
+			end := scanToken(text, i, unicode.IsDigit, ".,")
+			kind := TokenNumber
+			next, w := utf8.DecodeRuneInString(text[end:])
+			switch {
+			case strings.ContainsRune(numberSigns, next):
+				end += w
+			case next == '-':
+				// Порядковое числительное цифрами ("5-й") разбирается как слово.
+				if letter, _ := utf8.DecodeRuneInString(text[end+w:]); unicode.IsLetter(letter) {
+					end = scanToken(text, end+w, unicode.IsLetter, "")
+					kind = TokenWord
+				}
+			}
+			fn(i, end, kind)
+			i = end
+		default:
+			end := i + width
+			for end < len(text) {
+				next, w := utf8.DecodeRuneInString(text[end:])
+				if unicode.IsSpace(next) || unicode.IsLetter(next) || unicode.IsDigit(next) {
+					break
+				}
+				end += w
+			}
+			fn(i, end, TokenPunctuation)
+			i = end
+		}
+	}
+}
+
+// scanToken возвращает конец последовательности символов, удовлетворяющих in, начиная со start.
+// Символ из joiners не разрывает последовательность, если за ним снова следует подходящий символ.
+func scanToken(text string, start int, in func(rune) bool, joiners string) int {
+	i := start
+	for i < len(text) {
+		r, width := utf8.DecodeRuneInString(text[i:])
+		if in(r) {
+			i += width
+			continue
+		}
+		if strings.ContainsRune(joiners, r) {
+			next, _ := utf8.DecodeRuneInString(text[i+width:])
+			if in(next) {
+				i += width
+				continue
+			}
+		}
+		break
+	}
+	return i
+}
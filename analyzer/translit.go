@@ -0,0 +1,193 @@
+// translit.go содержит транслитерацию кириллицы латиницей и обратно по нескольким
+// системам (ГОСТ 7.79-2000, ICAO, паспортная, "бытовая") и построение ЧПУ-адресов (slug)
+// из заголовков: слова приводятся к леммам, чтобы "Котам" и "коты" давали одно и то же.
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TranslitScheme - система транслитерации.
+type TranslitScheme int
+
+const (
+	TranslitGOST     TranslitScheme = iota // ГОСТ 7.79-2000, система Б: обратимая ("щука" -> "shhuka", "цирк" -> "cirk").
+	TranslitICAO                           // ICAO Doc 9303, загранпаспорта с 2013 года ("юля" -> "iulia").
+	TranslitPassport                       // Загранпаспорта до 2010 года ("юля" -> "yulya", "щука" -> "shchuka").
+	TranslitInformal                       // "Бытовой" транслит ("щука" -> "schuka", "хлеб" -> "hleb").
+)
+
+// String возвращает название системы транслитерации.
+func (s TranslitScheme) String() string {
+	switch s {
+	case TranslitGOST:
+		return "gost"
+	case TranslitICAO:
+		return "icao"
+	case TranslitPassport:
+		return "passport"
+	case TranslitInformal:
+		return "informal"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTranslitScheme возвращает систему транслитерации по названию (см. TranslitScheme.String).
+func ParseTranslitScheme(name string) (TranslitScheme, bool) {
+	for s := TranslitGOST; s <= TranslitInformal; s++ {
+		if strings.EqualFold(name, s.String()) {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// translitBase - общие для всех систем соответствия строчных букв.
+var translitBase = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'ч': "ch", 'ш': "sh",
+}
+
+// translitSchemes - отличия систем от translitBase. Пустая строка - буква не передается.
+var translitSchemes = map[TranslitScheme]map[rune]string{
+	TranslitGOST: {
+		'ё': "yo", 'й': "j", 'х': "x", 'ц': "cz", 'щ': "shh", 'ъ': "``", 'ы': "y`", 'ь': "`",
+		'э': "e`", 'ю': "yu", 'я': "ya",
+	},
+	TranslitICAO: {
+		'ё': "e", 'й': "i", 'х': "kh", 'ц': "ts", 'щ': "shch", 'ъ': "ie", 'ы': "y", 'ь': "",
+		'э': "e", 'ю': "iu", 'я': "ia",
+	},
+	TranslitPassport: {
+		'ё': "e", 'й': "y", 'х': "kh", 'ц': "ts", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+		'э': "e", 'ю': "yu", 'я': "ya",
+	},
+	TranslitInformal: {
+		'ё': "yo", 'й': "y", 'х': "h", 'ц': "ts", 'щ': "sch", 'ъ': "", 'ы': "y", 'ь': "",
+		'э': "e", 'ю': "yu", 'я': "ya",
+	},
+}
+
+// translitReverseOrder - порядок букв при обратной транслитерации: если несколько букв
+// передаются одинаково ("e" - "е" и "э"), выбирается первая из них.
+const translitReverseOrder = "абвгдежзиклмнопрстуфхцчшщыэюяйёъь"
+
+// translitTable возвращает соответствия строчных букв для системы.
+func translitTable(scheme TranslitScheme) map[rune]string {
+	table := make(map[rune]string, 33)
+	for r, s := range translitBase {
+		table[r] = s
+	}
+	for r, s := range translitSchemes[scheme] {
+		table[r] = s
+	}
+	return table
+}
+
+// Transliterate передает кириллицу латиницей по системе scheme; остальные символы
+// не меняются. Регистр сохраняется: "Щука" -> "Shchuka", "ЩУКА" -> "SHCHUKA".
+// В ГОСТ 7.79-2000 "ц" перед "и", "е", "ы", "й" передается как "c", иначе - "cz".
+func Transliterate(text string, scheme TranslitScheme) string {
+	table := translitTable(scheme)
+	runes := []rune(text)
+	var sb strings.Builder
+	sb.Grow(len(text))
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		s, ok := table[lower]
+		if !ok {
+			sb.WriteRune(r)
+			continue
+		}
+		if scheme == TranslitGOST && lower == 'ц' && i+1 < len(runes) && strings.ContainsRune("иеыйИЕЫЙ", runes[i+1]) {
+			s = "c"
+		}
+		if unicode.IsUpper(r) && s != "" {
+			// Слово в верхнем регистре передается целиком прописными, иначе прописная только первая буква.
+			if (i+1 < len(runes) && unicode.IsUpper(runes[i+1])) || (i > 0 && unicode.IsUpper(runes[i-1])) {
+				s = strings.ToUpper(s)
+			} else {
+				first, size := utf8.DecodeRuneInString(s)
+				s = string(unicode.ToUpper(first)) + s[size:]
+			}
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// Detransliterate восстанавливает кириллицу по латинице системы scheme: буквосочетания
+// разбираются жадно, от самых длинных ("shch" -> "щ", а не "ш" + "ч"). Только ГОСТ 7.79-2000
+// обратим полностью; в остальных системах неразличимые буквы ("е" и "э", "ь" и его отсутствие)
+// восстанавливаются самым частым вариантом. Латиница вне системы не меняется.
+func Detransliterate(text string, scheme TranslitScheme) string {
+	table := translitTable(scheme)
+	reverse := make(map[string]rune, len(table))
+	maxLen := 1
+	for _, r := range translitReverseOrder {
+		s := table[r]
+		if _, ok := reverse[s]; ok || s == "" || (r == 'ъ' && scheme != TranslitGOST) {
+			// ICAO передает "ъ" как "ie", но в латинице это почти всегда "ие".
+			continue
+		}
+		reverse[s] = r
+		maxLen = max(maxLen, len(s))
+	}
+	if scheme == TranslitGOST {
+		reverse["c"] = 'ц'
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text) * 2)
+	for i := 0; i < len(text); {
+		matched := false
+		for n := min(maxLen, len(text)-i); n > 0; n-- {
+			chunk := text[i : i+n]
+			r, ok := reverse[strings.ToLower(chunk)]
+			if !ok {
+				continue
+			}
+			if first, _ := utf8.DecodeRuneInString(chunk); unicode.IsUpper(first) {
+				r = unicode.ToUpper(r)
+			}
+			sb.WriteRune(r)
+			i += n
+			matched = true
+			break
+		}
+		if !matched {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			sb.WriteRune(r)
+			i += size
+		}
+	}
+	return sb.String()
+}
+
+// Slug строит ЧПУ-адрес из текста: слова приводятся к леммам с учетом контекста
+// (см. AnalyzeText) и транслитерируются системой scheme, числа сохраняются,
+// остальные символы отбрасываются, части соединяются дефисом:
+// "Котам нужны игрушки" -> "kot-nuzhnyj-igrushka" (ГОСТ). Одни и те же слова
+// в разных формах дают одинаковый адрес.
+func (a *MorphAnalyzer) Slug(text string, scheme TranslitScheme) string {
+	var parts []string
+	for _, t := range a.AnalyzeText(text) {
+		var sb strings.Builder
+		for _, r := range Transliterate(strings.ToLower(t.Lemma), scheme) {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+				sb.WriteRune(r)
+			case r == '-' && sb.Len() > 0:
+				sb.WriteRune(r)
+			}
+		}
+		if part := strings.Trim(sb.String(), "-"); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,194 @@
+// typeahead.go содержит подсказки при наборе текста: дополнение префикса по DAWG,
+// ранжирование по частоте и исправление одной опечатки в уже набранной части.
+package analyzer
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// typeAheadVisitBudget ограничивает число узлов DAWG, просматриваемых при дополнении
+// префикса: для коротких префиксов ("п") полный обход занял бы сотни миллисекунд.
+// Обход идет в ширину, поэтому в пределах бюджета находятся самые короткие дополнения.
+// Варианты префикса с исправленной опечаткой делят бюджет между собой, но каждый
+// получает не меньше typeAheadMinFuzzyBudget узлов.
+const (
+	typeAheadVisitBudget    = 1500
+	typeAheadMinFuzzyBudget = 100
+)
+
+// Suggestion - вариант дополнения набранного префикса.
+type Suggestion struct {
+	Word      string `json:"word"`      // Словоформа из словаря.
+	Distance  int    `json:"distance"`  // 0 - дополнение префикса как есть, 1 - с исправлением опечатки в префиксе.
+	Frequency uint64 `json:"frequency"` // Частота из WithWordFrequencies (0, если частоты не заданы).
+	IsLemma   bool   `json:"is_lemma"`  // Словоформа является нормальной формой.
+}
+
+// TypeAhead возвращает до limit словарных словоформ, начинающихся с prefix, для поисковых
+// строк и методов ввода. Порядок: сначала дополнения префикса как есть, затем - префикса
+// с одной исправленной опечаткой (замена, пропуск или лишний символ); внутри группы -
+// по частоте, затем нормальные формы, более короткие слова и по алфавиту.
+// Исправление опечаток выполняется, только если точных дополнений меньше limit.
+func (a *MorphAnalyzer) TypeAhead(prefix string, limit int) []Suggestion {
+	lowerPrefix := []rune(strings.ToLower(prefix))
+	if limit <= 0 || len(lowerPrefix) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var exact []Suggestion
+	if node, ok := a.walkPrefix(lowerPrefix); ok {
+		exact = a.completions(node, lowerPrefix, 0, typeAheadVisitBudget, seen)
+	}
+	a.rankSuggestions(exact)
+	if len(exact) >= limit || len(lowerPrefix) < 2 {
+		return exact[:min(limit, len(exact))]
+	}
+
+	var fuzzy []Suggestion
+	prefixes := a.fuzzyPrefixes(lowerPrefix)
+	budget := max(typeAheadMinFuzzyBudget, typeAheadVisitBudget/max(1, len(prefixes)))
+	for _, p := range prefixes {
+		fuzzy = append(fuzzy, a.completions(p.node, p.path, 1, budget, seen)...)
+	}
+	a.rankSuggestions(fuzzy)
+	result := append(exact, fuzzy...)
+	return result[:min(limit, len(result))]
+}
+
+// walkPrefix проходит префикс по основному DAWG и возвращает узел его конца.
+func (a *MorphAnalyzer) walkPrefix(prefix []rune) (uint32, bool) {
+	nodeIndex := uint32(0)
+	for _, char := range prefix {
+		next, ok := a.findChildGeneral(nodeIndex, char, a.nodes, a.edges, a.rootIndex)
+		if !ok {
+			return 0, false
+		}
+		nodeIndex = next
+	}
+	return nodeIndex, true
+}
+
+// completions обходит DAWG в ширину от узла конца префикса и собирает словоформы
+// в пределах budget узлов. Словоформы из seen пропускаются и добавляются в него.
+func (a *MorphAnalyzer) completions(nodeIndex uint32, prefix []rune, distance, budget int, seen map[string]struct{}) []Suggestion {
+	// Очередь хранит для каждого элемента ссылку на родителя: суффикс восстанавливается
+	// только для финальных узлов, без копирования на каждом ребре.
+	type item struct {
+		node   uint32
+		char   rune
+		parent int32
+	}
+	queue := make([]item, 1, budget)
+	queue[0] = item{node: nodeIndex, parent: -1}
+
+	var result []Suggestion
+	buf := make([]rune, 0, len(prefix)+16)
+	for head := 0; head < len(queue); head++ {
+		node := a.nodes[queue[head].node]
+		if node.IsFinal {
+			buf = append(buf[:0], prefix...)
+			for i := int32(head); queue[i].parent >= 0; i = queue[i].parent {
+				buf = append(buf, queue[i].char)
+			}
+			slices.Reverse(buf[len(prefix):])
+			word := string(buf)
+			if _, ok := seen[word]; !ok {
+				seen[word] = struct{}{}
+				result = append(result, Suggestion{
+					Word:      word,
+					Distance:  distance,
+					Frequency: a.wordFrequencies[word],
+					IsLemma:   a.isLemmaNode(node, word),
+				})
+			}
+		}
+		for _, edge := range a.edges[node.EdgesIdx : node.EdgesIdx+uint32(node.EdgesLen)] {
+			if len(queue) == budget {
+				break
+			}
+			queue = append(queue, item{node: edge.NodeID, char: edge.Char, parent: int32(head)})
+		}
+	}
+	return result
+}
+
+// isLemmaNode сообщает, что словоформа финального узла - нормальная форма одного из разборов.
+func (a *MorphAnalyzer) isLemmaNode(node FlatNode, word string) bool {
+	for _, info := range a.payloads[node.PayloadIdx : node.PayloadIdx+uint32(node.PayloadLen)] {
+		if a.lemmas.at(info.LemmaID) == word {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyPrefix - вариант префикса с одной исправленной опечаткой.
+type fuzzyPrefix struct {
+	node uint32
+	path []rune
+}
+
+// fuzzyPrefixes находит в DAWG все пути, отличающиеся от префикса ровно одной правкой:
+// заменой, пропуском или вставкой символа. Первый символ не правится: опечатка в нем
+// редка, а варианты от каждой буквы алфавита сделали бы подсказки шумными.
+func (a *MorphAnalyzer) fuzzyPrefixes(prefix []rune) []fuzzyPrefix {
+	var result []fuzzyPrefix
+	found := make(map[string]struct{})
+	path := make([]rune, 0, len(prefix)+1)
+
+	var walk func(pos int, nodeIndex uint32, edited bool)
+	walk = func(pos int, nodeIndex uint32, edited bool) {
+		if pos == len(prefix) {
+			if key := string(path); edited {
+				if _, ok := found[key]; !ok {
+					found[key] = struct{}{}
+					result = append(result, fuzzyPrefix{node: nodeIndex, path: append([]rune(nil), path...)})
+				}
+			}
+			return
+		}
+		if next, ok := a.findChildGeneral(nodeIndex, prefix[pos], a.nodes, a.edges, a.rootIndex); ok {
+			path = append(path, prefix[pos])
+			walk(pos+1, next, edited)
+			path = path[:len(path)-1]
+		}
+		if edited || pos == 0 {
+			return
+		}
+
+		// Лишний символ в префиксе.
+		walk(pos+1, nodeIndex, true)
+		node := a.nodes[nodeIndex]
+		for _, edge := range a.edges[node.EdgesIdx : node.EdgesIdx+uint32(node.EdgesLen)] {
+			path = append(path, edge.Char)
+			if edge.Char != prefix[pos] {
+				// Замена символа.
+				walk(pos+1, edge.NodeID, true)
+			}
+			// Пропущенный символ.
+			walk(pos, edge.NodeID, true)
+			path = path[:len(path)-1]
+		}
+	}
+	walk(0, 0, false)
+	return result
+}
+
+// rankSuggestions упорядочивает подсказки одной группы.
+func (a *MorphAnalyzer) rankSuggestions(s []Suggestion) {
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].Frequency != s[j].Frequency {
+			return s[i].Frequency > s[j].Frequency
+		}
+		if s[i].IsLemma != s[j].IsLemma {
+			return s[i].IsLemma
+		}
+		if li, lj := len(s[i].Word), len(s[j].Word); li != lj {
+			return li < lj
+		}
+		return s[i].Word < s[j].Word
+	})
+}
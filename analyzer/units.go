@@ -0,0 +1,737 @@
+// units.go содержит цепочку звеньев разбора (как анализаторы в pymorphy2): словарь, числа,
+// латиница, адреса и слова других алфавитов, слова через дефис, известные и неизвестные
+// приставки и суффиксный предсказатель.
+// Analyze, Lemmatize и разбор текста передают слово звеньям по порядку, и результат
+// дает первое разобравшее его звено. Состав и порядок цепочки задаются опциями,
+// поэтому поведение на несловарных словах можно настроить или дополнить своими звеньями.
+package analyzer
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Имена встроенных звеньев для WithoutUnits.
+const (
+	UnitDictionary    = "dictionary"
+	UnitNumber        = "number"
+	UnitStemmer       = "stemmer"
+	UnitLatin         = "latin"
+	UnitForeign       = "foreign"
+	UnitHyphen        = "hyphen"
+	UnitPrefix        = "prefix"
+	UnitUnknownPrefix = "unknown-prefix"
+	UnitPredictor     = "predictor"
+)
+
+// Теги, которые звенья присваивают несловарным токенам.
+const (
+	digitsTags  = "Числительное,Цифры,несклоняемые"
+	latinTags   = "Латиница"
+	emailTags   = "Латиница,Электронная почта"
+	urlTags     = "Латиница,Ссылка"
+	unknownTags = "Неизвестное"
+)
+
+// minPrefixRemainderLen - наименьшая длина (в символах) остатка слова после приставки.
+const minPrefixRemainderLen = 3
+
+// knownPrefixConfidence - уверенность в разборе слова по известной приставке (см. Parsed.Confidence).
+const knownPrefixConfidence = 0.9
+
+// maxUnknownPrefixLen - наибольшая длина начала слова (в символах), которое отбрасывает UnknownPrefixUnit.
+const maxUnknownPrefixLen = 5
+
+// DefaultUnknownPrefixMinConfidence - порог уверенности UnknownPrefixUnit в DefaultUnits:
+// остаток должен быть длиннее отброшенного начала слова.
+const DefaultUnknownPrefixMinConfidence = 0.5
+
+// AnalyzerUnit - звено цепочки разбора.
+type AnalyzerUnit interface {
+	// Name возвращает имя звена, по которому его можно исключить опцией WithoutUnits.
+	Name() string
+	// Parse разбирает слово или возвращает nil, передавая слово следующему звену.
+	Parse(a *MorphAnalyzer, word string) []*Parsed
+	// Inflect возвращает словоформы слова, которое разобрало это звено (parses - результат Parse),
+	// или nil, если слово не изменяется.
+	Inflect(a *MorphAnalyzer, word string, parses []*Parsed) []*Parsed
+}
+
+// DefaultUnits возвращает цепочку звеньев по умолчанию: словарь, числа, стеммеры
+// языков без словаря (см. WithFallbackStemmer), латиница, адреса и слова других
+// алфавитов, слова через дефис, известные приставки, неизвестные приставки,
+// суффиксный предсказатель.
+func DefaultUnits() []AnalyzerUnit {
+	return []AnalyzerUnit{
+		DictionaryUnit{},
+		NumberUnit{},
+		StemmerUnit{},
+		LatinUnit{},
+		ForeignUnit{},
+		HyphenUnit{},
+		KnownPrefixUnit{},
+		NewUnknownPrefixUnit(DefaultUnknownPrefixMinConfidence),
+		SuffixPredictorUnit{},
+	}
+}
+
+// defaultUnits - цепочка для анализаторов, загруженных без WithUnits и WithoutUnits.
+var defaultUnits = DefaultUnits()
+
+// predictingUnits - звенья, которые строят разборы по образцу, а не по словарю
+// (их исключает WithDictionaryOnly).
+var predictingUnits = []string{UnitHyphen, UnitPrefix, UnitUnknownPrefix, UnitPredictor}
+
+// WithUnits заменяет цепочку звеньев разбора. Чтобы дополнить стандартную цепочку
+// своим звеном, передайте его вместе с DefaultUnits() в нужном месте.
+func WithUnits(units ...AnalyzerUnit) Option {
+	return func(a *MorphAnalyzer) {
+		a.units = append([]AnalyzerUnit{}, units...)
+	}
+}
+
+// WithoutUnits исключает из цепочки звенья с указанными именами
+// (например, WithoutUnits(UnitPrefix) отключает разбор по известным приставкам).
+func WithoutUnits(names ...string) Option {
+	return func(a *MorphAnalyzer) {
+		units := a.units
+		if units == nil {
+			units = defaultUnits
+		}
+		a.units = slices.DeleteFunc(slices.Clone(units), func(u AnalyzerUnit) bool {
+			return slices.Contains(names, u.Name())
+		})
+	}
+}
+
+// parseWithUnits передает слово звеньям цепочки и возвращает первое разобравшее его звено
+// вместе с разборами или nil, если слово не разобрало ни одно звено.
+func (a *MorphAnalyzer) parseWithUnits(word string) (AnalyzerUnit, []*Parsed) {
+	word, ok := a.cleanWord(word)
+	if !ok {
+		return nil, nil
+	}
+	units := a.units
+	if units == nil {
+		units = defaultUnits
+	}
+	for _, unit := range units {
+		if parses := unit.Parse(a, word); len(parses) > 0 {
+			// Разборы своих звеньев, не указавших источник, считаются надежными.
+			for _, p := range parses {
+				if p.Method == "" {
+					p.Method, p.Confidence = unit.Name(), 1
+				}
+			}
+			return unit, parses
+		}
+	}
+	return nil, nil
+}
+
+// DictionaryUnit разбирает словарные слова (см. Parse).
+type DictionaryUnit struct{}
+
+// Name возвращает имя звена.
+func (DictionaryUnit) Name() string { return UnitDictionary }
+
+// Parse ищет слово в словаре.
+func (DictionaryUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	return a.parseDict(word)
+}
+
+// Inflect возвращает словарные словоформы слова.
+func (DictionaryUnit) Inflect(a *MorphAnalyzer, word string, _ []*Parsed) []*Parsed {
+	return a.inflectDict(word)
+}
+
+// ordinalTemplate - порядковое числительное, по формам которого разбираются
+// порядковые числительные, записанные цифрами с окончанием ("5-й", "2-го").
+const ordinalTemplate = "пятый"
+
+// numberSigns - знаки, которые могут следовать за числом ("10%").
+const numberSigns = "%‰"
+
+// NumberUnit разбирает числа, записанные цифрами ("2024", "3,14", "10%"), как несклоняемые
+// числительные, а числа с окончанием через дефис ("5-й", "2-го", "90-х") - как формы
+// порядкового числительного с леммой в именительном падеже мужского рода ("5-й").
+// Число с другим окончанием ("5-ти") разбирается как несклоняемое числительное,
+// а не передается предсказателю.
+type NumberUnit struct{}
+
+// Name возвращает имя звена.
+func (NumberUnit) Name() string { return UnitNumber }
+
+// Parse разбирает слово, если оно состоит из цифр с внутренними точками и запятыми
+// и, возможно, знака процента или окончания порядкового числительного.
+func (NumberUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	number, suffix, ok := splitNumber(word)
+	switch {
+	case !ok:
+		return nil
+	case suffix == "" || strings.ContainsAny(suffix, numberSigns) && utf8.RuneCountInString(suffix) == 1:
+		return []*Parsed{newParsed(word, word, digitsTags)}
+	case !isNumberEnding(suffix):
+		return nil
+	}
+	ending := strings.ToLower(suffix[1:])
+	var results []*Parsed
+	for _, f := range a.ordinalForms() {
+		if f.abbrev == ending || f.ending == ending {
+			results = append(results, newParsed(word, number+"-й", f.tags))
+		}
+	}
+	if len(results) == 0 {
+		return []*Parsed{newParsed(word, strings.ToLower(word), digitsTags)}
+	}
+	return results
+}
+
+// Inflect возвращает nil для чисел, а для порядковых числительных - формы
+// с сокращенными окончаниями ("5-й", "5-го", "5-му", ...).
+func (NumberUnit) Inflect(a *MorphAnalyzer, word string, parses []*Parsed) []*Parsed {
+	if !hasGrammeme(parses[0].Tags, "Порядковое") {
+		return nil
+	}
+	number, _, _ := splitNumber(word)
+	var results []*Parsed
+	for _, f := range a.ordinalForms() {
+		results = append(results, newParsed(number+"-"+f.abbrev, number+"-й", f.tags))
+	}
+	a.sortForms(results)
+	return nilIfEmpty(results)
+}
+
+// splitNumber делит слово на число из цифр с внутренними точками и запятыми и остаток.
+func splitNumber(word string) (number, suffix string, ok bool) {
+	first, _ := utf8.DecodeRuneInString(word)
+	if !unicode.IsDigit(first) {
+		return "", "", false
+	}
+	end := scanToken(word, 0, unicode.IsDigit, ".,")
+	return word[:end], word[end:], true
+}
+
+// isNumberEnding сообщает, что остаток после цифр - окончание через дефис из букв ("-й").
+func isNumberEnding(suffix string) bool {
+	ending, ok := strings.CutPrefix(suffix, "-")
+	if !ok || ending == "" {
+		return false
+	}
+	for _, r := range ending {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ordinalForm - форма порядкового числительного с окончанием в полном и сокращенном виде.
+type ordinalForm struct {
+	ending string // Окончание: "ого".
+	abbrev string // Сокращенное окончание, которое пишется после цифр: "го".
+	tags   string
+}
+
+// ordinalForms возвращает формы порядкового числительного-образца (см. ordinalTemplate),
+// начиная с начальной, или nil, если его нет в словаре. Окончание сокращается по правилам записи
+// порядковых числительных цифрами: гласные перед последней согласной отбрасываются
+// ("ый" -> "й", "ого" -> "го", "ыми" -> "ми"), от окончания из одних гласных
+// остается последняя буква ("ая" -> "я").
+func (a *MorphAnalyzer) ordinalForms() []ordinalForm {
+	stem := strings.TrimSuffix(ordinalTemplate, "ый")
+	var forms []ordinalForm
+	for _, info := range a.lookupPayloads(ordinalTemplate) {
+		tags := a.tags.at(info.TagsID)
+		if !hasGrammeme(tags, "Порядковое") {
+			continue
+		}
+		for _, f := range a.lexemeForms(info.ParadigmID) {
+			ending, ok := strings.CutPrefix(f.word, stem)
+			if !ok || ending == "" {
+				continue
+			}
+			abbrev := strings.TrimLeft(ending, ordinalVowels)
+			if abbrev == "" {
+				_, size := utf8.DecodeLastRuneInString(ending)
+				abbrev = ending[len(ending)-size:]
+			}
+			form := ordinalForm{ending: ending, abbrev: abbrev, tags: a.tags.at(f.tagsID)}
+			if f.tagsID == info.TagsID && f.word == ordinalTemplate {
+				forms = slices.Insert(forms, 0, form)
+			} else {
+				forms = append(forms, form)
+			}
+		}
+		break
+	}
+	return forms
+}
+
+// ordinalVowels - гласные, которые отбрасываются при сокращении окончания порядкового числительного.
+const ordinalVowels = "аеёиоуыэюя"
+
+// LatinUnit разбирает слова, записанные латиницей ("GitHub"): суффиксный предсказатель
+// построен по кириллице и дал бы для них случайную парадигму.
+type LatinUnit struct{}
+
+// Name возвращает имя звена.
+func (LatinUnit) Name() string { return UnitLatin }
+
+// Parse разбирает слово из латинских букв (допускаются дефисы и апострофы) с леммой в нижнем регистре.
+func (LatinUnit) Parse(_ *MorphAnalyzer, word string) []*Parsed {
+	if !isLatinWord(word) {
+		return nil
+	}
+	return []*Parsed{newParsed(word, strings.ToLower(word), latinTags)}
+}
+
+// isLatinWord сообщает, что слово состоит из латинских букв, дефисов и апострофов.
+func isLatinWord(word string) bool {
+	hasLetter := false
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			hasLetter = true
+		case r == '-' || r == '\'':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// Inflect возвращает nil: словоформы латинских слов не генерируются.
+func (LatinUnit) Inflect(*MorphAnalyzer, string, []*Parsed) []*Parsed { return nil }
+
+// ForeignUnit разбирает токены без кириллицы, кроме слов латиницей (их разбирает LatinUnit):
+// адреса электронной почты ("info@example.com"), ссылки ("https://go.dev", "www.ya.ru"),
+// латиницу с цифрами и знаками ("x86", "C++") и слова других алфавитов ("東京").
+// Без него такие токены попадают в суффиксный предсказатель и получают случайную парадигму.
+type ForeignUnit struct{}
+
+// Name возвращает имя звена.
+func (ForeignUnit) Name() string { return UnitForeign }
+
+// Parse разбирает токен, в котором есть буквы, но нет кириллических, и который
+// не состоит из одних латинских букв. Лемма ссылки совпадает с токеном (путь в ней
+// чувствителен к регистру), остальные леммы - в нижнем регистре.
+func (ForeignUnit) Parse(_ *MorphAnalyzer, word string) []*Parsed {
+	if isLatinWord(word) {
+		return nil
+	}
+	hasLatin, hasOther := false, false
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			return nil
+		case unicode.Is(unicode.Latin, r):
+			hasLatin = true
+		case unicode.IsLetter(r):
+			hasOther = true
+		}
+	}
+	switch {
+	case isEmail(word):
+		return []*Parsed{newParsed(word, strings.ToLower(word), emailTags)}
+	case isURL(word):
+		return []*Parsed{newParsed(word, word, urlTags)}
+	case hasOther:
+		return []*Parsed{newParsed(word, strings.ToLower(word), unknownTags)}
+	case hasLatin:
+		return []*Parsed{newParsed(word, strings.ToLower(word), latinTags)}
+	}
+	return nil
+}
+
+// Inflect возвращает nil: словоформы таких токенов не генерируются.
+func (ForeignUnit) Inflect(*MorphAnalyzer, string, []*Parsed) []*Parsed { return nil }
+
+// isEmail сообщает, что токен похож на адрес электронной почты: имя@домен.зона.
+func isEmail(word string) bool {
+	local, domain, ok := strings.Cut(word, "@")
+	if !ok || local == "" || strings.ContainsAny(local, " \"<>") {
+		return false
+	}
+	return isDomain(domain)
+}
+
+// isURL сообщает, что токен - ссылка со схемой ("https://go.dev/doc") или начинается с "www.".
+func isURL(word string) bool {
+	if rest, ok := strings.CutPrefix(strings.ToLower(word), "www."); ok {
+		host, _, _ := strings.Cut(rest, "/")
+		return isDomain(host)
+	}
+	u, err := url.Parse(word)
+	return err == nil && u.Host != "" && slices.Contains([]string{"http", "https", "ftp"}, strings.ToLower(u.Scheme))
+}
+
+// isDomain сообщает, что строка - доменное имя не менее чем из двух частей
+// из латинских букв, цифр и дефисов; зона состоит только из букв.
+func isDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) || r == '-') {
+				return false
+			}
+		}
+	}
+	for _, r := range labels[len(labels)-1] {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// hyphenParticles - частицы, присоединяемые через дефис ("скажи-ка", "он-то").
+var hyphenParticles = map[string]struct{}{
+	"то": {}, "ка": {}, "таки": {}, "де": {}, "с": {}, "либо": {}, "нибудь": {},
+}
+
+// HyphenUnit разбирает несловарные слова через дефис. Частица после дефиса отбрасывается
+// при разборе и добавляется к лемме и словоформам ("скажи-ка" -> "сказать-ка").
+// В составном слове разбирается последняя часть, а первая либо остается неизменной
+// ("интернет-магазина" -> "интернет-магазин"), либо изменяется вместе с последней,
+// если стоит с ней в одном падеже и числе не в начальной форме ("человека-паука" -> "человек-паук").
+// Составные слова в начальной форме склоняются только по последней части.
+type HyphenUnit struct{}
+
+// Name возвращает имя звена.
+func (HyphenUnit) Name() string { return UnitHyphen }
+
+// Parse разбирает слово по частям, если в нем есть дефис между двумя непустыми частями.
+// Уверенность в разборе равна уверенности в разборе изменяемой части.
+func (HyphenUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	head, tail, ok := splitHyphen(word)
+	if !ok {
+		return nil
+	}
+	lowerHead, lowerTail := strings.ToLower(head), strings.ToLower(tail)
+
+	if _, ok := hyphenParticles[lowerTail]; ok {
+		var results []*Parsed
+		_, headParses := a.parseWithUnits(head)
+		for _, p := range headParses {
+			results = append(results, hyphenParsed(word, p.Lemma+"-"+lowerTail, p))
+		}
+		return results
+	}
+
+	headParses := a.parseDict(head)
+	_, tailParses := a.parseWithUnits(tail)
+	var results []*Parsed
+	for _, p := range tailParses {
+		lemmaHead := lowerHead
+		if hp := agreeingHead(headParses, lowerHead, p); hp != nil {
+			lemmaHead = hp.Lemma
+		}
+		results = append(results, hyphenParsed(word, lemmaHead+"-"+p.Lemma, p))
+	}
+	return results
+}
+
+// hyphenParsed возвращает разбор слова через дефис с тегами и уверенностью разбора части part.
+func hyphenParsed(word, lemma string, part *Parsed) *Parsed {
+	p := newParsed(word, lemma, part.Tags)
+	p.Method, p.Confidence = UnitHyphen, part.Confidence
+	return p
+}
+
+// Inflect возвращает словоформы: формы первой части с частицей или формы последней части
+// с неизменной либо согласованной с ней первой частью.
+func (HyphenUnit) Inflect(a *MorphAnalyzer, word string, parses []*Parsed) []*Parsed {
+	head, tail, _ := splitHyphen(word)
+	lowerHead, lowerTail := strings.ToLower(head), strings.ToLower(tail)
+
+	if _, ok := hyphenParticles[lowerTail]; ok {
+		_, headForms := a.analyzeWord(head)
+		results := make([]*Parsed, 0, len(headForms))
+		for _, f := range headForms {
+			results = append(results, newParsed(f.Word+"-"+lowerTail, f.Lemma+"-"+lowerTail, f.Tags))
+		}
+		return nilIfEmpty(results)
+	}
+
+	// Леммы обеих частей берем из разборов: первая часть лемм, отличная от исходной, изменяется.
+	tailLemmas := make(map[string]string) // Лемма последней части -> лемма первой части.
+	for _, p := range parses {
+		if i := strings.LastIndex(p.Lemma, "-"); i >= 0 {
+			tailLemmas[p.Lemma[i+1:]] = p.Lemma[:i]
+		}
+	}
+	var headForms []*Parsed
+	for _, lemmaHead := range tailLemmas {
+		if lemmaHead != lowerHead {
+			headForms = a.inflectDict(head)
+			break
+		}
+	}
+
+	_, tailForms := a.analyzeWord(tail)
+	var results []*Parsed
+	for _, f := range tailForms {
+		lemmaHead, ok := tailLemmas[f.Lemma]
+		if !ok {
+			continue
+		}
+		formHead := lowerHead
+		if lemmaHead != lowerHead {
+			hp := matchingForm(headForms, lemmaHead, f)
+			if hp == nil {
+				continue
+			}
+			formHead = hp.Word
+		}
+		results = append(results, newParsed(formHead+"-"+f.Word, lemmaHead+"-"+f.Lemma, f.Tags))
+	}
+	a.sortForms(results)
+	return nilIfEmpty(results)
+}
+
+// splitHyphen делит слово по последнему дефису на две непустые части.
+func splitHyphen(word string) (string, string, bool) {
+	i := strings.LastIndex(word, "-")
+	if i <= 0 || i == len(word)-1 {
+		return "", "", false
+	}
+	return word[:i], word[i+1:], true
+}
+
+// agreeingHead возвращает разбор первой части составного слова, которая изменяется вместе
+// с последней: существительное в том же падеже и числе, что и tail, но не в начальной форме.
+func agreeingHead(headParses []*Parsed, lowerHead string, tail *Parsed) *Parsed {
+	tail = tail.Details()
+	if tail.PartOfSpeech != "Существительное" || tail.Case == "" {
+		return nil
+	}
+	for _, hp := range headParses {
+		if hp.PartOfSpeech != "Существительное" || hp.Lemma == lowerHead {
+			continue
+		}
+		if d := hp.Details(); d.Case == tail.Case && d.Number == tail.Number {
+			return hp
+		}
+	}
+	return nil
+}
+
+// matchingForm выбирает среди словоформ лексемы lemma форму в падеже и числе формы f.
+func matchingForm(forms []*Parsed, lemma string, f *Parsed) *Parsed {
+	for _, hf := range forms {
+		if hf.Lemma == lemma && hf.Case == f.Case && hf.Number == f.Number {
+			return hf
+		}
+	}
+	return nil
+}
+
+// knownPrefixes - продуктивные приставки и первые части сложных слов, с которыми
+// образуются несловарные слова ("суперкот", "видеоурок", "антивирусный").
+var knownPrefixes = []string{
+	"авиа", "авто", "агро", "анти", "аудио", "био", "видео", "гео", "гипер", "интернет",
+	"квази", "кибер", "контр", "кросс", "макро", "медиа", "мега", "микро", "мини", "мото",
+	"мульти", "нано", "нео", "недо", "пост", "псевдо", "радио", "сверх", "спец",
+	"супер", "теле", "транс", "турбо", "ультра", "фото", "эко", "экс", "электро", "энерго",
+}
+
+// productiveTags - части речи, которые образуются присоединением приставки к словарному слову.
+var productiveTags = GrammemeSet{
+	"Существительное": {},
+	"Прилагательное":  {},
+	"Глагол":          {},
+	"Причастие":       {},
+	"Деепричастие":    {},
+	"Наречие":         {},
+}
+
+// KnownPrefixUnit разбирает несловарные слова, состоящие из известной приставки
+// (или нескольких) и словарного слова ("суперкота" -> "суперкот", "мегасуперкот").
+// Prefixes задает свой список приставок (nil - встроенный); выбирается самая длинная
+// приставка, после которой остается разбираемое слово.
+type KnownPrefixUnit struct {
+	Prefixes []string
+}
+
+// Name возвращает имя звена.
+func (KnownPrefixUnit) Name() string { return UnitPrefix }
+
+// Parse разбирает остаток слова после приставки по словарю.
+// Учитываются только разборы знаменательных частей речи.
+func (u KnownPrefixUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	prefix, parses := u.split(a, strings.ToLower(word))
+	results := make([]*Parsed, 0, len(parses))
+	for _, p := range parses {
+		r := newParsed(word, prefix+p.Lemma, p.Tags)
+		r.Method, r.Confidence = UnitPrefix, knownPrefixConfidence
+		results = append(results, r)
+	}
+	return nilIfEmpty(results)
+}
+
+// Inflect присоединяет приставку к словоформам остатка слова.
+func (u KnownPrefixUnit) Inflect(a *MorphAnalyzer, word string, parses []*Parsed) []*Parsed {
+	lowerWord := strings.ToLower(word)
+	prefix, _ := u.split(a, lowerWord)
+	return prefixedForms(a, prefix, lowerWord, parses)
+}
+
+// prefixedForms возвращает словарные словоформы остатка слова после приставки prefix
+// для лемм разборов parses, присоединяя к ним приставку.
+func prefixedForms(a *MorphAnalyzer, prefix, lowerWord string, parses []*Parsed) []*Parsed {
+	lemmas := make(map[string]struct{}, len(parses))
+	for _, p := range parses {
+		lemmas[strings.TrimPrefix(p.Lemma, prefix)] = struct{}{}
+	}
+
+	var results []*Parsed
+	for _, f := range a.inflectDict(lowerWord[len(prefix):]) {
+		if _, ok := lemmas[f.Lemma]; ok {
+			results = append(results, newParsed(prefix+f.Word, prefix+f.Lemma, f.Tags))
+		}
+	}
+	return nilIfEmpty(results)
+}
+
+// split находит самую длинную известную приставку, после которой остается словарное слово,
+// и возвращает ее вместе с разборами остатка знаменательных частей речи. Если остаток
+// не словарный, он сам может начинаться с приставки ("суперантивирус" -> "супер" + "анти" + "вирус"):
+// тогда возвращается цепочка приставок целиком.
+func (u KnownPrefixUnit) split(a *MorphAnalyzer, lowerWord string) (string, []*Parsed) {
+	prefixes := u.Prefixes
+	if prefixes == nil {
+		prefixes = knownPrefixes
+	}
+	best := ""
+	var bestParses []*Parsed
+	for _, prefix := range prefixes {
+		prefix = strings.ToLower(prefix)
+		if len(prefix) <= len(best) || !strings.HasPrefix(lowerWord, prefix) ||
+			utf8.RuneCountInString(lowerWord[len(prefix):]) < minPrefixRemainderLen {
+			continue
+		}
+		rest := lowerWord[len(prefix):]
+		var parses []*Parsed
+		for _, p := range a.parseDict(rest) {
+			if inMap(p.PartOfSpeech, productiveTags) {
+				parses = append(parses, p)
+			}
+		}
+		if len(parses) == 0 {
+			var restPrefix string
+			if restPrefix, parses = u.split(a, rest); len(parses) > 0 {
+				prefix += restPrefix
+			}
+		}
+		if len(parses) > 0 && len(prefix) > len(best) {
+			best, bestParses = prefix, parses
+		}
+	}
+	return best, bestParses
+}
+
+// UnknownPrefixUnit разбирает несловарные слова, у которых после отбрасывания от 1 до 5
+// первых символов остается словарное слово ("ютубканалами" -> "ютубканал"),
+// как и pymorphy2. Такой разбор ненадежен: уверенность в нем - доля слова, которую
+// занимает словарный остаток, и разбор с уверенностью не выше порога не принимается,
+// а слово передается предсказателю. Из подходящих остатков выбирается самый длинный.
+// Звено создается функцией NewUnknownPrefixUnit; UnknownPrefixUnit{} равно NewUnknownPrefixUnit(0).
+type UnknownPrefixUnit struct {
+	minConfidence float64
+}
+
+// NewUnknownPrefixUnit создает звено с порогом уверенности minConfidence: 0 принимает
+// любой остаток не короче трех букв, DefaultUnknownPrefixMinConfidence - порог цепочки
+// по умолчанию.
+func NewUnknownPrefixUnit(minConfidence float64) UnknownPrefixUnit {
+	return UnknownPrefixUnit{minConfidence: minConfidence}
+}
+
+// Name возвращает имя звена.
+func (UnknownPrefixUnit) Name() string { return UnitUnknownPrefix }
+
+// Parse разбирает словарный остаток слова. Учитываются только разборы знаменательных частей речи.
+func (u UnknownPrefixUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	lowerWord := strings.ToLower(word)
+	prefix, parses := u.split(a, lowerWord)
+	total := utf8.RuneCountInString(lowerWord)
+	confidence := float64(total-utf8.RuneCountInString(prefix)) / float64(total)
+	results := make([]*Parsed, 0, len(parses))
+	for _, p := range parses {
+		r := newParsed(word, prefix+p.Lemma, p.Tags)
+		r.Method, r.Confidence = UnitUnknownPrefix, confidence
+		results = append(results, r)
+	}
+	return nilIfEmpty(results)
+}
+
+// Inflect присоединяет отброшенное начало к словоформам остатка слова.
+func (u UnknownPrefixUnit) Inflect(a *MorphAnalyzer, word string, parses []*Parsed) []*Parsed {
+	lowerWord := strings.ToLower(word)
+	prefix, _ := u.split(a, lowerWord)
+	return prefixedForms(a, prefix, lowerWord, parses)
+}
+
+// split находит самое короткое начало слова (не длиннее maxUnknownPrefixLen символов),
+// после которого остается словарное слово знаменательной части речи с достаточной
+// уверенностью, и возвращает его вместе с разборами остатка.
+func (u UnknownPrefixUnit) split(a *MorphAnalyzer, lowerWord string) (string, []*Parsed) {
+	total := utf8.RuneCountInString(lowerWord)
+	offset := 0
+	for n := 1; n <= maxUnknownPrefixLen; n++ {
+		r, size := utf8.DecodeRuneInString(lowerWord[offset:])
+		if !unicode.IsLetter(r) {
+			break
+		}
+		offset += size
+		rest := total - n
+		if rest < minPrefixRemainderLen || float64(rest)/float64(total) <= u.minConfidence {
+			break
+		}
+		var parses []*Parsed
+		for _, p := range a.parseDict(lowerWord[offset:]) {
+			if inMap(p.PartOfSpeech, productiveTags) {
+				parses = append(parses, p)
+			}
+		}
+		if len(parses) > 0 {
+			return lowerWord[:offset], parses
+		}
+	}
+	return "", nil
+}
+
+// SuffixPredictorUnit предсказывает разбор по самому длинному совпадающему суффиксу
+// (см. ParsePredicted); слова из одной-двух букв разбираются по закрытому списку.
+type SuffixPredictorUnit struct{}
+
+// Name возвращает имя звена.
+func (SuffixPredictorUnit) Name() string { return UnitPredictor }
+
+// Parse предсказывает разбор несловарного слова.
+func (SuffixPredictorUnit) Parse(a *MorphAnalyzer, word string) []*Parsed {
+	return a.parsePredictedCached(word)
+}
+
+// Inflect генерирует словоформы по парадигме-образцу (см. Predict).
+func (SuffixPredictorUnit) Inflect(a *MorphAnalyzer, word string, parses []*Parsed) []*Parsed {
+	return a.predict(word, parses[0].Lemma)
+}
+
+// nilIfEmpty возвращает nil вместо пустого среза, как остальные методы анализатора.
+func nilIfEmpty(parses []*Parsed) []*Parsed {
+	if len(parses) == 0 {
+		return nil
+	}
+	return parses
+}
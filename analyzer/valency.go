@@ -0,0 +1,73 @@
+// valency.go содержит работу с валентными рамками (моделями управления) глаголов.
+// Рамки хранятся в необязательном блоке словаря и добавляются при сборке методом
+// DictBuilder.AddValency: если словарь собран без них, API просто возвращает пустой результат.
+package analyzer
+
+import (
+	"strings"
+)
+
+// ValencyArg - одна валентность глагола (актант).
+type ValencyArg struct {
+	Case        string `json:"case"`                  // Падеж актанта ("Винительный", "Дательный", ...).
+	Preposition string `json:"preposition,omitempty"` // Предлог, если актант предложный ("о", "на", ...).
+	Optional    bool   `json:"optional,omitempty"`    // Может ли актант быть опущен.
+}
+
+// ValencyFrame - валентная рамка глагола: падеж подлежащего и набор дополнений.
+// Например, для "рассказать" одна из рамок: Именительный + Дательный + "о" Предложный.
+type ValencyFrame struct {
+	Subject string       `json:"subject"` // Падеж подлежащего (обычно "Именительный").
+	Objects []ValencyArg `json:"objects"` // Дополнения в порядке их типичного следования.
+}
+
+// verbLikeTags - части речи, лемма которых является глаголом (инфинитивом).
+var verbLikeTags = GrammemeSet{
+	"Глагол":       {},
+	"Причастие":    {},
+	"Деепричастие": {},
+}
+
+// Valency возвращает валентные рамки для глагола. Принимает любую форму глагола,
+// причастия или деепричастия: рамки ищутся по лемме.
+// Возвращает nil, если слово не является словарным глаголом или словарь не содержит рамок.
+func (a *MorphAnalyzer) Valency(verb string) []ValencyFrame {
+	if len(a.valency) == 0 {
+		return nil
+	}
+
+	var frames []ValencyFrame
+	seen := make(map[uint32]struct{})
+	for _, info := range a.lookupPayloads(strings.ToLower(verb)) {
+		if _, ok := seen[info.LemmaID]; ok {
+			continue
+		}
+		// Часть речи всегда идет первой в строке тегов.
+		pos, _, _ := strings.Cut(a.tags.at(info.TagsID), ",")
+		if !inMap(pos, verbLikeTags) {
+			continue
+		}
+		seen[info.LemmaID] = struct{}{}
+		frames = append(frames, a.valency[info.LemmaID]...)
+	}
+	return frames
+}
+
+// lookupPayloads проходит по основному DAWG и возвращает payload-ы финального узла слова.
+// Возвращает nil, если слова в словаре нет. Слово должно быть уже приведено к нижнему регистру.
+func (a *MorphAnalyzer) lookupPayloads(lowerWord string) []MorphInfo {
+	currentNodeIndex := uint32(0)
+	for _, char := range lowerWord {
+		childNodeIndex, found := a.findChildGeneral(currentNodeIndex, char, a.nodes, a.edges, a.rootIndex)
+		if !found {
+			return nil
+		}
+		currentNodeIndex = childNodeIndex
+	}
+
+	node := a.nodes[currentNodeIndex]
+	if !node.IsFinal {
+		return nil
+	}
+	return a.payloads[node.PayloadIdx : node.PayloadIdx+uint32(node.PayloadLen)]
+}
@@ -0,0 +1,210 @@
+// vesum.go содержит чтение украинского словаря ВЕСУМ (проект dict_uk) в формате
+// для LanguageTool (dict_corp_lt.txt): строка "словоформа лемма теги", теги через
+// двоеточие ("noun:anim:m:v_kly"). Теги ВЕСУМ переводятся в теги словаря, поэтому
+// украинский словарь разбирается, склоняется и экспортируется теми же методами,
+// что и русский.
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// vesumGrammemes сопоставляет тегам ВЕСУМ теги словаря. Части речи, которые
+// зависят от флагов (&adjp, &pron, &numr), определяет vesumTags.
+// Теги, которых нет в таблице, при компиляции отбрасываются.
+var vesumGrammemes = map[string][]string{
+	// Одушевленность.
+	"anim":   {"Одушевленное"},
+	"inanim": {"Неодушевленное"},
+	"unanim": {"одушевленное и неодушевленное"},
+
+	// Род и число: у форм единственного числа ВЕСУМ указывает только род.
+	"m": {"Мужской", "Единственное число"},
+	"f": {"Женский", "Единственное число"},
+	"n": {"Средний", "Единственное число"},
+	"s": {"Единственное число"},
+	"p": {"Множественное число"},
+
+	// Падеж. Кличний (звательный) в украинском - живой падеж с регулярными формами
+	// ("пане", "мамо"), местный соответствует предложному падежу словаря.
+	"v_naz": {"Именительный"},
+	"v_rod": {"Родительный"},
+	"v_dav": {"Дательный"},
+	"v_zna": {"Винительный"},
+	"v_oru": {"Творительный"},
+	"v_mis": {"Предложный"},
+	"v_kly": {"Звательный"},
+	"nv":    {"несклоняемые"},
+
+	// Глагольные категории.
+	"perf":   {"Совершенный"},
+	"imperf": {"Несовершенный"},
+	"pres":   {"Настоящее"},
+	"futr":   {"Будущее"},
+	"past":   {"Прошедшее"},
+	"impr":   {"Повелительное"},
+	"impers": {"нет лица"},
+	"1":      {"1-е лицо"},
+	"2":      {"2-е лицо"},
+	"3":      {"3-е лицо"},
+	"rev":    {"Возвратный"},
+	"actv":   {"Действительный"},
+	"pasv":   {"Страдательный"},
+
+	// Степени сравнения и краткие формы прилагательных.
+	"compb": {"Сравнительная"},
+	"comps": {"Превосходная"},
+	"short": {"Краткая"},
+
+	// Разряды местоимений (после флага &pron).
+	"pers": {"личное местоимение"},
+	"refl": {"возвратное местоимение"},
+	"pos":  {"притяжательное местоимение"},
+	"dem":  {"указательные местоимения"},
+	"int":  {"Вопросительное"},
+	"rel":  {"относительные местоимения"},
+	"neg":  {"отрицательные местоимения"},
+	"ind":  {"неопределённые местоимения"},
+	"def":  {"определительные местоимения"},
+
+	// Прочие пометы.
+	"prop":  {"Собственное"},
+	"fname": {"Собственное"},
+	"lname": {"Собственное"},
+	"pname": {"Собственное"},
+	"geo":   {"Собственное"},
+	"org":   {"Собственное"},
+	"coll":  {"Разговорный"},
+	"slang": {"Сленг"},
+	"arch":  {"Устаревший"},
+}
+
+// vesumPOS сопоставляет части речи ВЕСУМ части речи словаря (с уточняющими тегами, как ADJF).
+var vesumPOS = map[string][]string{
+	"noun":   {"Существительное"},
+	"adj":    {"Прилагательное"},
+	"verb":   {"Глагол"},
+	"advp":   {"Деепричастие"},
+	"adv":    {"Наречие"},
+	"predic": {"Наречие"},
+	"numr":   {"Числительное"},
+	"prep":   {"Предлог"},
+	"conj":   {"Союз"},
+	"part":   {"Частица"},
+	"intj":   {"Междометие"},
+	"onomat": {"Междометие"},
+	"insert": {"Вводное слово"},
+}
+
+// vesumTags переводит теги ВЕСУМ ("adj:m:v_naz:&adjp:pasv:perf") в строку тегов словаря.
+// Часть речи уточняется флагами: &adjp делает прилагательное причастием, &pron -
+// существительное местоимением, а прилагательное - местоименным. Возвращает false,
+// если часть речи ВЕСУМ неизвестна (например, noninfl).
+func vesumTags(tags string) (string, bool) {
+	fields := strings.Split(tags, ":")
+	pos, ok := vesumPOS[fields[0]]
+	if !ok {
+		return "", false
+	}
+	has := make(map[string]bool, len(fields))
+	for _, f := range fields[1:] {
+		has[f] = true
+	}
+
+	var extra []string
+	switch fields[0] {
+	case "noun":
+		if has["&pron"] {
+			pos = []string{"Местоимение"}
+		}
+	case "adj":
+		switch {
+		case has["&adjp"]:
+			pos = []string{"Причастие"}
+		case has["&pron"]:
+			extra = append(extra, "Местоименное")
+		case has["&numr"]:
+			extra = append(extra, "Порядковое")
+		}
+		if !has["compb"] {
+			if has["short"] {
+				pos = append(pos, "Краткая")
+			} else {
+				pos = append(pos, "Полная")
+			}
+		}
+	case "verb":
+		if has["inf"] {
+			pos = append(pos, "Инфинитив")
+		} else {
+			pos = append(pos, "Не инфинитив")
+		}
+	}
+
+	result := append([]string(nil), pos...)
+	seen := make(map[string]struct{}, len(fields)+len(pos))
+	for _, tag := range pos {
+		seen[tag] = struct{}{}
+	}
+	add := func(tag string) {
+		if _, ok := seen[tag]; !ok {
+			seen[tag] = struct{}{}
+			result = append(result, tag)
+		}
+	}
+	for _, tag := range extra {
+		add(tag)
+	}
+	for _, f := range fields[1:] {
+		for _, tag := range vesumGrammemes[f] {
+			add(tag)
+		}
+	}
+	return strings.Join(result, ","), true
+}
+
+// ReadVESUM читает украинский словарь ВЕСУМ в формате LanguageTool (dict_corp_lt.txt)
+// и вызывает fn для каждой словоформы. Лексема определяется леммой, частью речи
+// и номером омонима ВЕСУМ (xp1, xp2, ...). Записи неизвестных частей речи пропускаются.
+//
+// Буквы "ё" в украинском нет, поэтому словоформы не требуют замен "е"/"ё", а апостроф
+// ("м'ясо", "п’ять") сохраняется в словоформе: при сборке и разборе все его варианты
+// приводятся к U+0027 (см. NormalizeApostrophes).
+func ReadVESUM(r io.Reader, fn func(LexEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return fmt.Errorf("строка %d: ожидалось 3 поля (словоформа, лемма, теги), получено %d", lineNum, len(fields))
+		}
+		tags, ok := vesumTags(fields[2])
+		if !ok {
+			continue
+		}
+		pos, _, _ := strings.Cut(tags, ",")
+		lexeme := "vesum:" + NormalizeApostrophes(fields[1]) + ":" + pos
+		for _, f := range strings.Split(fields[2], ":") {
+			if strings.HasPrefix(f, "xp") {
+				lexeme += ":" + f
+			}
+		}
+		entry := LexEntry{Word: fields[0], Lemma: fields[1], Tags: tags, Lexeme: lexeme}
+		if err := fn(entry); err != nil {
+			return fmt.Errorf("строка %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения словаря ВЕСУМ: %w", err)
+	}
+	return nil
+}
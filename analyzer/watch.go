@@ -0,0 +1,276 @@
+// watch.go содержит горячую перезагрузку словаря: файл отслеживается через fsnotify,
+// и при его замене (например, при обновлении configmap) новый словарь загружается,
+// проверяется и подменяет текущий без остановки приложения.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce - пауза после последнего события файловой системы перед перезагрузкой:
+// замена файла порождает серию событий (создание, запись, переименование).
+const reloadDebounce = 200 * time.Millisecond
+
+// ReloadEvent - результат попытки перезагрузки словаря.
+type ReloadEvent struct {
+	Path     string         // Путь к файлу словаря.
+	Analyzer *MorphAnalyzer // Новый анализатор (nil при ошибке).
+	Err      error          // Ошибка загрузки или проверки; текущий анализатор при этом сохраняется.
+}
+
+// DictWatcher отслеживает файл словаря и подменяет анализатор при замене файла.
+// Анализатор берется через Acquire: старый словарь освобождается (munmap) только после
+// того, как все взявшие его вызывающие вернули ссылку, поэтому подмена безопасна
+// для конкурентных запросов.
+//
+// Файл нужно заменять атомарно (запись во временный файл и rename, как это делает
+// Kubernetes для configmap): перезапись файла на месте портит уже отображенный словарь.
+type DictWatcher struct {
+	path     string
+	opts     []Option
+	onReload func(ReloadEvent)
+	watcher  *fsnotify.Watcher
+
+	mu       sync.Mutex // Защищает current, refs и path (path меняется также под reloadMu).
+	current  *watchedAnalyzer
+	info     os.FileInfo // Файл, из которого загружен текущий словарь.
+	reloadMu sync.Mutex  // Сериализует перезагрузки.
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// watchedAnalyzer - анализатор со счетчиком ссылок.
+type watchedAnalyzer struct {
+	analyzer *MorphAnalyzer
+	refs     int
+	retired  bool // Анализатор подменен и закрывается, как только refs станет нулем.
+}
+
+// WatchDictionary загружает словарь из path с опциями opts и начинает отслеживать файл.
+// onReload (может быть nil) вызывается после каждой попытки перезагрузки - успешной или нет.
+func WatchDictionary(path string, onReload func(ReloadEvent), opts ...Option) (*DictWatcher, error) {
+	analyzer, info, err := loadValidated(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		analyzer.Close()
+		return nil, fmt.Errorf("ошибка создания fsnotify.Watcher: %w", err)
+	}
+	// Отслеживаем директорию, а не файл: при атомарной замене файл получает новый inode,
+	// и наблюдение за старым inode перестало бы срабатывать.
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		analyzer.Close()
+		return nil, fmt.Errorf("ошибка отслеживания директории словаря: %w", err)
+	}
+
+	w := &DictWatcher{
+		path:     path,
+		opts:     opts,
+		onReload: onReload,
+		watcher:  watcher,
+		current:  &watchedAnalyzer{analyzer: analyzer},
+		info:     info,
+		done:     make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.loop()
+	return w, nil
+}
+
+// Acquire возвращает текущий анализатор и функцию, которую нужно вызвать после
+// окончания работы с ним. До вызова release анализатор не будет закрыт даже после подмены.
+func (w *DictWatcher) Acquire() (analyzer *MorphAnalyzer, release func()) {
+	w.mu.Lock()
+	current := w.current
+	current.refs++
+	w.mu.Unlock()
+
+	var once sync.Once
+	return current.analyzer, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			current.refs--
+			closeNow := current.retired && current.refs == 0
+			w.mu.Unlock()
+			if closeNow {
+				current.analyzer.Close()
+			}
+		})
+	}
+}
+
+// Reload загружает и проверяет словарь из path и подменяет им текущий, даже если файл
+// не изменился. Пустой path перезагружает отслеживаемый файл, другой путь после успешной
+// загрузки становится отслеживаемым. Запросы, взявшие прежний анализатор через Acquire,
+// дорабатывают на нем. При ошибке текущий анализатор сохраняется.
+func (w *DictWatcher) Reload(path string) error {
+	return w.reload(path, true)
+}
+
+// Path возвращает путь к отслеживаемому файлу словаря.
+func (w *DictWatcher) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path
+}
+
+// Close прекращает отслеживание и закрывает текущий анализатор, как только
+// будут возвращены все ссылки на него. После Close вызывать Acquire и Reload нельзя.
+func (w *DictWatcher) Close() error {
+	close(w.done)
+	err := w.watcher.Close()
+	w.wg.Wait()
+
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	w.retire(nil)
+	if err != nil {
+		return fmt.Errorf("ошибка закрытия fsnotify.Watcher: %w", err)
+	}
+	return nil
+}
+
+// loop обрабатывает события файловой системы, пока наблюдатель не закрыт.
+func (w *DictWatcher) loop() {
+	defer w.wg.Done()
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// Configmap обновляется подменой симлинка ..data, поэтому реагируем на любые
+			// изменения в директории, а реальную замену файла проверяем при перезагрузке.
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) || event.Has(fsnotify.Rename) {
+				timer.Reset(reloadDebounce)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.notify(ReloadEvent{Path: w.Path(), Err: fmt.Errorf("ошибка отслеживания словаря: %w", err)})
+		case <-timer.C:
+			_ = w.reload("", false)
+		}
+	}
+}
+
+// reload загружает и проверяет словарь из path (пустой - отслеживаемый файл) и подменяет
+// им текущий. Без force перезагрузка пропускается, если путь указывает на тот же файл,
+// что и раньше.
+func (w *DictWatcher) reload(path string, force bool) error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	if path == "" {
+		path = w.path
+	}
+	if !force {
+		info, err := os.Stat(path)
+		if err == nil && os.SameFile(info, w.info) && info.ModTime().Equal(w.info.ModTime()) && info.Size() == w.info.Size() {
+			return nil
+		}
+	}
+
+	analyzer, info, err := loadValidated(path, w.opts)
+	if err == nil && path != w.path {
+		if err = w.follow(path); err != nil {
+			analyzer.Close()
+		}
+	}
+	if err != nil {
+		w.mu.Lock()
+		logger := w.current.analyzer.log()
+		w.mu.Unlock()
+		logger.Warn("не удалось перезагрузить словарь, остается прежний", "path", path, "error", err)
+		w.notify(ReloadEvent{Path: path, Err: err})
+		return err
+	}
+	w.info = info
+	w.retire(&watchedAnalyzer{analyzer: analyzer})
+	analyzer.log().Info("словарь перезагружен", "path", path)
+	w.notify(ReloadEvent{Path: path, Analyzer: analyzer})
+	return nil
+}
+
+// follow переключает отслеживание на файл path. Вызывается под reloadMu.
+func (w *DictWatcher) follow(path string) error {
+	oldDir, newDir := filepath.Dir(w.path), filepath.Dir(path)
+	if newDir != oldDir {
+		if err := w.watcher.Add(newDir); err != nil {
+			return fmt.Errorf("ошибка отслеживания директории словаря: %w", err)
+		}
+		_ = w.watcher.Remove(oldDir)
+	}
+	w.mu.Lock()
+	w.path = path
+	w.mu.Unlock()
+	return nil
+}
+
+// retire подменяет текущий анализатор на next (nil - при закрытии наблюдателя)
+// и закрывает прежний, если на него не осталось ссылок.
+func (w *DictWatcher) retire(next *watchedAnalyzer) {
+	w.mu.Lock()
+	old := w.current
+	if next != nil {
+		w.current = next
+	}
+	old.retired = true
+	closeNow := old.refs == 0
+	w.mu.Unlock()
+	if closeNow {
+		old.analyzer.Close()
+	}
+}
+
+// notify передает событие обработчику, если он задан.
+func (w *DictWatcher) notify(event ReloadEvent) {
+	if w.onReload != nil {
+		w.onReload(event)
+	}
+}
+
+// loadValidated загружает словарь и проверяет, что он пригоден для работы:
+// в нем есть леммы, пути почти всех выбранных лемм находятся в DAWG
+// (в пуле бывают служебные строки, которых нет среди словоформ),
+// и контрольная выборка словаря, если она есть, проходит SelfTest.
+func loadValidated(path string, opts []Option) (*MorphAnalyzer, os.FileInfo, error) {
+	// Запоминаем файл до загрузки: если его заменят во время загрузки, следующее событие
+	// увидит отличие и перезагрузит словарь еще раз.
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка доступа к словарю: %w", err)
+	}
+	analyzer, err := loadWithOptions(path, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка загрузки словаря: %w", err)
+	}
+	words := analyzer.sampleWords(64)
+	if len(words) == 0 || analyzer.walkAll(words, nil)*10 < len(words)*9 {
+		analyzer.Close()
+		return nil, nil, fmt.Errorf("%w: словарь не прошел проверку: леммы не находятся в DAWG", ErrDictCorrupted)
+	}
+	if err := analyzer.SelfTest(); err != nil && !errors.Is(err, ErrNoSelfTestSample) {
+		analyzer.Close()
+		return nil, nil, err
+	}
+	return analyzer, info, nil
+}
@@ -0,0 +1,101 @@
+// wordforms.go содержит экспорт словаря в файл словоформ Sphinx и Manticore Search
+// (wordforms, строки "словоформа > лемма"): поисковый движок нормализует слова
+// по тем же леммам, что и анализатор, без морфологического модуля.
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// wordformsOptions - настройки WriteWordforms.
+type wordformsOptions struct {
+	pos          map[string]struct{}
+	minFrequency uint64
+}
+
+// WordformsOption - функциональная опция WriteWordforms.
+type WordformsOption func(*wordformsOptions)
+
+// WordformsPOS оставляет только словоформы указанных частей речи. Части речи
+// записываются на любом языке граммем ("Существительное", "NOUN", "noun").
+func WordformsPOS(pos ...string) WordformsOption {
+	return func(o *wordformsOptions) {
+		if o.pos == nil {
+			o.pos = make(map[string]struct{}, len(pos))
+		}
+		for _, p := range pos {
+			o.pos[canonicalGrammeme(strings.TrimSpace(p))] = struct{}{}
+		}
+	}
+}
+
+// WordformsMinFrequency оставляет только словоформы с частотой не меньше n
+// по частотам WithWordFrequencies.
+func WordformsMinFrequency(n uint64) WordformsOption {
+	return func(o *wordformsOptions) {
+		o.minFrequency = n
+	}
+}
+
+// WriteWordforms записывает словарь в формате wordforms Sphinx и Manticore Search:
+// строка "словоформа > лемма" на каждую словоформу, по алфавиту. Омонимичная
+// словоформа сопоставляется лемме первого подходящего разбора, потому что движок
+// допускает одну нормальную форму; словоформы, которые сами являются леммой
+// ("стекло" - и существительное, и форма глагола "стечь"), не записываются.
+// Возвращает число записанных строк.
+//
+// Без частот (WithWordFrequencies) опция WordformsMinFrequency возвращает ошибку,
+// а неизвестная часть речи в WordformsPOS - ошибку, как MaskOf.
+func (a *MorphAnalyzer) WriteWordforms(w io.Writer, opts ...WordformsOption) (int, error) {
+	var o wordformsOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for pos := range o.pos {
+		if _, err := a.MaskOf(pos); err != nil {
+			return 0, fmt.Errorf("ошибка экспорта wordforms: %w", err)
+		}
+	}
+	if o.minFrequency > 0 && a.wordFrequencies == nil {
+		return 0, fmt.Errorf("ошибка экспорта wordforms: не заданы частоты словоформ (WithWordFrequencies)")
+	}
+
+	out := bufio.NewWriter(w)
+	lines := 0
+	word, lemma := "", "" // Текущая словоформа и лемма ее первого подходящего разбора.
+	isLemma := false      // Один из подходящих разборов словоформы - сама лемма.
+	flush := func() {
+		// Пробелы и ">" - разделители формата.
+		if lemma != "" && !isLemma && !strings.ContainsAny(word+lemma, " \t>") {
+			out.WriteString(word + " > " + lemma + "\n")
+			lines++
+		}
+	}
+	for e := range a.Words() {
+		if e.Word != word {
+			flush()
+			word, lemma, isLemma = e.Word, "", false
+		}
+		if o.pos != nil {
+			pos, _, _ := strings.Cut(e.Tags, ",")
+			if _, ok := o.pos[pos]; !ok {
+				continue
+			}
+		}
+		if o.minFrequency > 0 && a.wordFrequencies[e.Word] < o.minFrequency {
+			continue
+		}
+		if lemma == "" {
+			lemma = e.Lemma
+		}
+		isLemma = isLemma || e.Word == e.Lemma
+	}
+	flush()
+	if err := out.Flush(); err != nil {
+		return lines, fmt.Errorf("ошибка записи wordforms: %w", err)
+	}
+	return lines, nil
+}
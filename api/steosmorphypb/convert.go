@@ -0,0 +1,46 @@
+// convert.go содержит преобразование результатов анализатора в сообщения protobuf.
+// Используется gRPC-сервисом и бинарным C API.
+package steosmorphypb
+
+import (
+	"sort"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// FromParsedList преобразует разборы в сообщения protobuf.
+func FromParsedList(parses []*steosmorphy.Parsed) []*Parsed {
+	result := make([]*Parsed, len(parses))
+	for i, p := range parses {
+		result[i] = FromParsed(p)
+	}
+	return result
+}
+
+// FromParsed преобразует разбор в сообщение protobuf. OtherTags сортируются для стабильного вывода.
+func FromParsed(p *steosmorphy.Parsed) *Parsed {
+	p = p.Details()
+	otherTags := make([]string, 0, len(p.OtherTags))
+	for tag := range p.OtherTags {
+		otherTags = append(otherTags, tag)
+	}
+	sort.Strings(otherTags)
+
+	return &Parsed{
+		Word:         p.Word,
+		Lemma:        p.Lemma,
+		Tags:         p.Tags,
+		PartOfSpeech: p.PartOfSpeech,
+		Animacy:      p.Animacy,
+		Aspect:       p.Aspect,
+		Case:         p.Case,
+		Gender:       p.Gender,
+		Mood:         p.Mood,
+		Number:       p.Number,
+		Person:       p.Person,
+		Tense:        p.Tense,
+		Transitivity: p.Transitivity,
+		Voice:        p.Voice,
+		OtherTags:    otherTags,
+	}
+}
@@ -0,0 +1,751 @@
+// steosmorphy.proto описывает gRPC-сервис морфологического анализатора и бинарный формат
+// результатов C API (функции *_pb в bindings/c).
+// Go-код генерируется командой (из корня репозитория):
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//          --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//          api/steosmorphypb/steosmorphy.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: api/steosmorphypb/steosmorphy.proto
+
+package steosmorphypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Parsed - морфологический разбор словоформы, повторяет analyzer.Parsed.
+type Parsed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Lemma         string                 `protobuf:"bytes,2,opt,name=lemma,proto3" json:"lemma,omitempty"`
+	Tags          string                 `protobuf:"bytes,3,opt,name=tags,proto3" json:"tags,omitempty"`
+	PartOfSpeech  string                 `protobuf:"bytes,4,opt,name=part_of_speech,json=partOfSpeech,proto3" json:"part_of_speech,omitempty"`
+	Animacy       string                 `protobuf:"bytes,5,opt,name=animacy,proto3" json:"animacy,omitempty"`
+	Aspect        string                 `protobuf:"bytes,6,opt,name=aspect,proto3" json:"aspect,omitempty"`
+	Case          string                 `protobuf:"bytes,7,opt,name=case,proto3" json:"case,omitempty"`
+	Gender        string                 `protobuf:"bytes,8,opt,name=gender,proto3" json:"gender,omitempty"`
+	Mood          string                 `protobuf:"bytes,9,opt,name=mood,proto3" json:"mood,omitempty"`
+	Number        string                 `protobuf:"bytes,10,opt,name=number,proto3" json:"number,omitempty"`
+	Person        string                 `protobuf:"bytes,11,opt,name=person,proto3" json:"person,omitempty"`
+	Tense         string                 `protobuf:"bytes,12,opt,name=tense,proto3" json:"tense,omitempty"`
+	Transitivity  string                 `protobuf:"bytes,13,opt,name=transitivity,proto3" json:"transitivity,omitempty"`
+	Voice         string                 `protobuf:"bytes,14,opt,name=voice,proto3" json:"voice,omitempty"`
+	OtherTags     []string               `protobuf:"bytes,15,rep,name=other_tags,json=otherTags,proto3" json:"other_tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Parsed) Reset() {
+	*x = Parsed{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Parsed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Parsed) ProtoMessage() {}
+
+func (x *Parsed) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Parsed.ProtoReflect.Descriptor instead.
+func (*Parsed) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Parsed) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *Parsed) GetLemma() string {
+	if x != nil {
+		return x.Lemma
+	}
+	return ""
+}
+
+func (x *Parsed) GetTags() string {
+	if x != nil {
+		return x.Tags
+	}
+	return ""
+}
+
+func (x *Parsed) GetPartOfSpeech() string {
+	if x != nil {
+		return x.PartOfSpeech
+	}
+	return ""
+}
+
+func (x *Parsed) GetAnimacy() string {
+	if x != nil {
+		return x.Animacy
+	}
+	return ""
+}
+
+func (x *Parsed) GetAspect() string {
+	if x != nil {
+		return x.Aspect
+	}
+	return ""
+}
+
+func (x *Parsed) GetCase() string {
+	if x != nil {
+		return x.Case
+	}
+	return ""
+}
+
+func (x *Parsed) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *Parsed) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *Parsed) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Parsed) GetPerson() string {
+	if x != nil {
+		return x.Person
+	}
+	return ""
+}
+
+func (x *Parsed) GetTense() string {
+	if x != nil {
+		return x.Tense
+	}
+	return ""
+}
+
+func (x *Parsed) GetTransitivity() string {
+	if x != nil {
+		return x.Transitivity
+	}
+	return ""
+}
+
+func (x *Parsed) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+func (x *Parsed) GetOtherTags() []string {
+	if x != nil {
+		return x.OtherTags
+	}
+	return nil
+}
+
+type ParseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseRequest) Reset() {
+	*x = ParseRequest{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseRequest) ProtoMessage() {}
+
+func (x *ParseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseRequest.ProtoReflect.Descriptor instead.
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParseRequest) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+type ParseResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Word  string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	// Словарные разборы, а для несловарного слова - предсказанные.
+	Parses        []*Parsed `protobuf:"bytes,2,rep,name=parses,proto3" json:"parses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseResponse) Reset() {
+	*x = ParseResponse{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResponse) ProtoMessage() {}
+
+func (x *ParseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ParseResponse) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *ParseResponse) GetParses() []*Parsed {
+	if x != nil {
+		return x.Parses
+	}
+	return nil
+}
+
+type InflectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InflectRequest) Reset() {
+	*x = InflectRequest{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InflectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InflectRequest) ProtoMessage() {}
+
+func (x *InflectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InflectRequest.ProtoReflect.Descriptor instead.
+func (*InflectRequest) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InflectRequest) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+type InflectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Forms         []*Parsed              `protobuf:"bytes,2,rep,name=forms,proto3" json:"forms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InflectResponse) Reset() {
+	*x = InflectResponse{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InflectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InflectResponse) ProtoMessage() {}
+
+func (x *InflectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InflectResponse.ProtoReflect.Descriptor instead.
+func (*InflectResponse) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InflectResponse) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *InflectResponse) GetForms() []*Parsed {
+	if x != nil {
+		return x.Forms
+	}
+	return nil
+}
+
+type AnalyzeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Word  string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	// Генерировать ли все словоформы (дорого для больших парадигм).
+	WithForms     bool `protobuf:"varint,2,opt,name=with_forms,json=withForms,proto3" json:"with_forms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AnalyzeRequest) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetWithForms() bool {
+	if x != nil {
+		return x.WithForms
+	}
+	return false
+}
+
+type AnalyzeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Word          string                 `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Parses        []*Parsed              `protobuf:"bytes,2,rep,name=parses,proto3" json:"parses,omitempty"`
+	Forms         []*Parsed              `protobuf:"bytes,3,rep,name=forms,proto3" json:"forms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeResponse) Reset() {
+	*x = AnalyzeResponse{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResponse) ProtoMessage() {}
+
+func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AnalyzeResponse) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+func (x *AnalyzeResponse) GetParses() []*Parsed {
+	if x != nil {
+		return x.Parses
+	}
+	return nil
+}
+
+func (x *AnalyzeResponse) GetForms() []*Parsed {
+	if x != nil {
+		return x.Forms
+	}
+	return nil
+}
+
+// ParsedList - список разборов: результат steosmorphy_parse_pb и подобных функций C API.
+type ParsedList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Parses        []*Parsed              `protobuf:"bytes,1,rep,name=parses,proto3" json:"parses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParsedList) Reset() {
+	*x = ParsedList{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParsedList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParsedList) ProtoMessage() {}
+
+func (x *ParsedList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParsedList.ProtoReflect.Descriptor instead.
+func (*ParsedList) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ParsedList) GetParses() []*Parsed {
+	if x != nil {
+		return x.Parses
+	}
+	return nil
+}
+
+// AnalyzeTokensRequest - заранее токенизированный текст для steosmorphy_analyze_tokens_pb.
+type AnalyzeTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []string               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeTokensRequest) Reset() {
+	*x = AnalyzeTokensRequest{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeTokensRequest) ProtoMessage() {}
+
+func (x *AnalyzeTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeTokensRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeTokensRequest) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AnalyzeTokensRequest) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// AnalyzeTokensResponse - разборы токенов, выровненные по запросу: i-й элемент
+// соответствует i-му токену. Токены без букв получают пустой список разборов.
+type AnalyzeTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*ParseResponse       `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeTokensResponse) Reset() {
+	*x = AnalyzeTokensResponse{}
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeTokensResponse) ProtoMessage() {}
+
+func (x *AnalyzeTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_steosmorphypb_steosmorphy_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeTokensResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeTokensResponse) Descriptor() ([]byte, []int) {
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AnalyzeTokensResponse) GetTokens() []*ParseResponse {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+var File_api_steosmorphypb_steosmorphy_proto protoreflect.FileDescriptor
+
+const file_api_steosmorphypb_steosmorphy_proto_rawDesc = "" +
+	"\n" +
+	"#api/steosmorphypb/steosmorphy.proto\x12\x0esteosmorphy.v1\"\xfd\x02\n" +
+	"\x06Parsed\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12\x14\n" +
+	"\x05lemma\x18\x02 \x01(\tR\x05lemma\x12\x12\n" +
+	"\x04tags\x18\x03 \x01(\tR\x04tags\x12$\n" +
+	"\x0epart_of_speech\x18\x04 \x01(\tR\fpartOfSpeech\x12\x18\n" +
+	"\aanimacy\x18\x05 \x01(\tR\aanimacy\x12\x16\n" +
+	"\x06aspect\x18\x06 \x01(\tR\x06aspect\x12\x12\n" +
+	"\x04case\x18\a \x01(\tR\x04case\x12\x16\n" +
+	"\x06gender\x18\b \x01(\tR\x06gender\x12\x12\n" +
+	"\x04mood\x18\t \x01(\tR\x04mood\x12\x16\n" +
+	"\x06number\x18\n" +
+	" \x01(\tR\x06number\x12\x16\n" +
+	"\x06person\x18\v \x01(\tR\x06person\x12\x14\n" +
+	"\x05tense\x18\f \x01(\tR\x05tense\x12\"\n" +
+	"\ftransitivity\x18\r \x01(\tR\ftransitivity\x12\x14\n" +
+	"\x05voice\x18\x0e \x01(\tR\x05voice\x12\x1d\n" +
+	"\n" +
+	"other_tags\x18\x0f \x03(\tR\totherTags\"\"\n" +
+	"\fParseRequest\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\"S\n" +
+	"\rParseResponse\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12.\n" +
+	"\x06parses\x18\x02 \x03(\v2\x16.steosmorphy.v1.ParsedR\x06parses\"$\n" +
+	"\x0eInflectRequest\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\"S\n" +
+	"\x0fInflectResponse\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12,\n" +
+	"\x05forms\x18\x02 \x03(\v2\x16.steosmorphy.v1.ParsedR\x05forms\"C\n" +
+	"\x0eAnalyzeRequest\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12\x1d\n" +
+	"\n" +
+	"with_forms\x18\x02 \x01(\bR\twithForms\"\x83\x01\n" +
+	"\x0fAnalyzeResponse\x12\x12\n" +
+	"\x04word\x18\x01 \x01(\tR\x04word\x12.\n" +
+	"\x06parses\x18\x02 \x03(\v2\x16.steosmorphy.v1.ParsedR\x06parses\x12,\n" +
+	"\x05forms\x18\x03 \x03(\v2\x16.steosmorphy.v1.ParsedR\x05forms\"<\n" +
+	"\n" +
+	"ParsedList\x12.\n" +
+	"\x06parses\x18\x01 \x03(\v2\x16.steosmorphy.v1.ParsedR\x06parses\".\n" +
+	"\x14AnalyzeTokensRequest\x12\x16\n" +
+	"\x06tokens\x18\x01 \x03(\tR\x06tokens\"N\n" +
+	"\x15AnalyzeTokensResponse\x125\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x1d.steosmorphy.v1.ParseResponseR\x06tokens2\xf7\x01\n" +
+	"\rMorphAnalyzer\x12D\n" +
+	"\x05Parse\x12\x1c.steosmorphy.v1.ParseRequest\x1a\x1d.steosmorphy.v1.ParseResponse\x12J\n" +
+	"\aInflect\x12\x1e.steosmorphy.v1.InflectRequest\x1a\x1f.steosmorphy.v1.InflectResponse\x12T\n" +
+	"\rAnalyzeStream\x12\x1e.steosmorphy.v1.AnalyzeRequest\x1a\x1f.steosmorphy.v1.AnalyzeResponse(\x010\x01B8Z6github.com/steosofficial/steosmorphy/api/steosmorphypbb\x06proto3"
+
+var (
+	file_api_steosmorphypb_steosmorphy_proto_rawDescOnce sync.Once
+	file_api_steosmorphypb_steosmorphy_proto_rawDescData []byte
+)
+
+func file_api_steosmorphypb_steosmorphy_proto_rawDescGZIP() []byte {
+	file_api_steosmorphypb_steosmorphy_proto_rawDescOnce.Do(func() {
+		file_api_steosmorphypb_steosmorphy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_steosmorphypb_steosmorphy_proto_rawDesc), len(file_api_steosmorphypb_steosmorphy_proto_rawDesc)))
+	})
+	return file_api_steosmorphypb_steosmorphy_proto_rawDescData
+}
+
+var file_api_steosmorphypb_steosmorphy_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_steosmorphypb_steosmorphy_proto_goTypes = []any{
+	(*Parsed)(nil),                // 0: steosmorphy.v1.Parsed
+	(*ParseRequest)(nil),          // 1: steosmorphy.v1.ParseRequest
+	(*ParseResponse)(nil),         // 2: steosmorphy.v1.ParseResponse
+	(*InflectRequest)(nil),        // 3: steosmorphy.v1.InflectRequest
+	(*InflectResponse)(nil),       // 4: steosmorphy.v1.InflectResponse
+	(*AnalyzeRequest)(nil),        // 5: steosmorphy.v1.AnalyzeRequest
+	(*AnalyzeResponse)(nil),       // 6: steosmorphy.v1.AnalyzeResponse
+	(*ParsedList)(nil),            // 7: steosmorphy.v1.ParsedList
+	(*AnalyzeTokensRequest)(nil),  // 8: steosmorphy.v1.AnalyzeTokensRequest
+	(*AnalyzeTokensResponse)(nil), // 9: steosmorphy.v1.AnalyzeTokensResponse
+}
+var file_api_steosmorphypb_steosmorphy_proto_depIdxs = []int32{
+	0, // 0: steosmorphy.v1.ParseResponse.parses:type_name -> steosmorphy.v1.Parsed
+	0, // 1: steosmorphy.v1.InflectResponse.forms:type_name -> steosmorphy.v1.Parsed
+	0, // 2: steosmorphy.v1.AnalyzeResponse.parses:type_name -> steosmorphy.v1.Parsed
+	0, // 3: steosmorphy.v1.AnalyzeResponse.forms:type_name -> steosmorphy.v1.Parsed
+	0, // 4: steosmorphy.v1.ParsedList.parses:type_name -> steosmorphy.v1.Parsed
+	2, // 5: steosmorphy.v1.AnalyzeTokensResponse.tokens:type_name -> steosmorphy.v1.ParseResponse
+	1, // 6: steosmorphy.v1.MorphAnalyzer.Parse:input_type -> steosmorphy.v1.ParseRequest
+	3, // 7: steosmorphy.v1.MorphAnalyzer.Inflect:input_type -> steosmorphy.v1.InflectRequest
+	5, // 8: steosmorphy.v1.MorphAnalyzer.AnalyzeStream:input_type -> steosmorphy.v1.AnalyzeRequest
+	2, // 9: steosmorphy.v1.MorphAnalyzer.Parse:output_type -> steosmorphy.v1.ParseResponse
+	4, // 10: steosmorphy.v1.MorphAnalyzer.Inflect:output_type -> steosmorphy.v1.InflectResponse
+	6, // 11: steosmorphy.v1.MorphAnalyzer.AnalyzeStream:output_type -> steosmorphy.v1.AnalyzeResponse
+	9, // [9:12] is the sub-list for method output_type
+	6, // [6:9] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_steosmorphypb_steosmorphy_proto_init() }
+func file_api_steosmorphypb_steosmorphy_proto_init() {
+	if File_api_steosmorphypb_steosmorphy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_steosmorphypb_steosmorphy_proto_rawDesc), len(file_api_steosmorphypb_steosmorphy_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_steosmorphypb_steosmorphy_proto_goTypes,
+		DependencyIndexes: file_api_steosmorphypb_steosmorphy_proto_depIdxs,
+		MessageInfos:      file_api_steosmorphypb_steosmorphy_proto_msgTypes,
+	}.Build()
+	File_api_steosmorphypb_steosmorphy_proto = out.File
+	file_api_steosmorphypb_steosmorphy_proto_goTypes = nil
+	file_api_steosmorphypb_steosmorphy_proto_depIdxs = nil
+}
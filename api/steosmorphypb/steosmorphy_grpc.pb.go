@@ -0,0 +1,210 @@
+// steosmorphy.proto описывает gRPC-сервис морфологического анализатора и бинарный формат
+// результатов C API (функции *_pb в bindings/c).
+// Go-код генерируется командой (из корня репозитория):
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//          --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//          api/steosmorphypb/steosmorphy.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: api/steosmorphypb/steosmorphy.proto
+
+package steosmorphypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MorphAnalyzer_Parse_FullMethodName         = "/steosmorphy.v1.MorphAnalyzer/Parse"
+	MorphAnalyzer_Inflect_FullMethodName       = "/steosmorphy.v1.MorphAnalyzer/Inflect"
+	MorphAnalyzer_AnalyzeStream_FullMethodName = "/steosmorphy.v1.MorphAnalyzer/AnalyzeStream"
+)
+
+// MorphAnalyzerClient is the client API for MorphAnalyzer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MorphAnalyzer - сервис морфологического анализа.
+type MorphAnalyzerClient interface {
+	// Parse разбирает одно слово.
+	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// Inflect возвращает все словоформы словарного слова.
+	Inflect(ctx context.Context, in *InflectRequest, opts ...grpc.CallOption) (*InflectResponse, error)
+	// AnalyzeStream разбирает поток слов. Ответы приходят в порядке запросов.
+	AnalyzeStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AnalyzeRequest, AnalyzeResponse], error)
+}
+
+type morphAnalyzerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMorphAnalyzerClient(cc grpc.ClientConnInterface) MorphAnalyzerClient {
+	return &morphAnalyzerClient{cc}
+}
+
+func (c *morphAnalyzerClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, MorphAnalyzer_Parse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *morphAnalyzerClient) Inflect(ctx context.Context, in *InflectRequest, opts ...grpc.CallOption) (*InflectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InflectResponse)
+	err := c.cc.Invoke(ctx, MorphAnalyzer_Inflect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *morphAnalyzerClient) AnalyzeStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AnalyzeRequest, AnalyzeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MorphAnalyzer_ServiceDesc.Streams[0], MorphAnalyzer_AnalyzeStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AnalyzeRequest, AnalyzeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MorphAnalyzer_AnalyzeStreamClient = grpc.BidiStreamingClient[AnalyzeRequest, AnalyzeResponse]
+
+// MorphAnalyzerServer is the server API for MorphAnalyzer service.
+// All implementations must embed UnimplementedMorphAnalyzerServer
+// for forward compatibility.
+//
+// MorphAnalyzer - сервис морфологического анализа.
+type MorphAnalyzerServer interface {
+	// Parse разбирает одно слово.
+	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+	// Inflect возвращает все словоформы словарного слова.
+	Inflect(context.Context, *InflectRequest) (*InflectResponse, error)
+	// AnalyzeStream разбирает поток слов. Ответы приходят в порядке запросов.
+	AnalyzeStream(grpc.BidiStreamingServer[AnalyzeRequest, AnalyzeResponse]) error
+	mustEmbedUnimplementedMorphAnalyzerServer()
+}
+
+// UnimplementedMorphAnalyzerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMorphAnalyzerServer struct{}
+
+func (UnimplementedMorphAnalyzerServer) Parse(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Parse not implemented")
+}
+func (UnimplementedMorphAnalyzerServer) Inflect(context.Context, *InflectRequest) (*InflectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inflect not implemented")
+}
+func (UnimplementedMorphAnalyzerServer) AnalyzeStream(grpc.BidiStreamingServer[AnalyzeRequest, AnalyzeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AnalyzeStream not implemented")
+}
+func (UnimplementedMorphAnalyzerServer) mustEmbedUnimplementedMorphAnalyzerServer() {}
+func (UnimplementedMorphAnalyzerServer) testEmbeddedByValue()                       {}
+
+// UnsafeMorphAnalyzerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MorphAnalyzerServer will
+// result in compilation errors.
+type UnsafeMorphAnalyzerServer interface {
+	mustEmbedUnimplementedMorphAnalyzerServer()
+}
+
+func RegisterMorphAnalyzerServer(s grpc.ServiceRegistrar, srv MorphAnalyzerServer) {
+	// If the following call pancis, it indicates UnimplementedMorphAnalyzerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MorphAnalyzer_ServiceDesc, srv)
+}
+
+func _MorphAnalyzer_Parse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MorphAnalyzerServer).Parse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MorphAnalyzer_Parse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MorphAnalyzerServer).Parse(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MorphAnalyzer_Inflect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InflectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MorphAnalyzerServer).Inflect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MorphAnalyzer_Inflect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MorphAnalyzerServer).Inflect(ctx, req.(*InflectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MorphAnalyzer_AnalyzeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MorphAnalyzerServer).AnalyzeStream(&grpc.GenericServerStream[AnalyzeRequest, AnalyzeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MorphAnalyzer_AnalyzeStreamServer = grpc.BidiStreamingServer[AnalyzeRequest, AnalyzeResponse]
+
+// MorphAnalyzer_ServiceDesc is the grpc.ServiceDesc for MorphAnalyzer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MorphAnalyzer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "steosmorphy.v1.MorphAnalyzer",
+	HandlerType: (*MorphAnalyzerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Parse",
+			Handler:    _MorphAnalyzer_Parse_Handler,
+		},
+		{
+			MethodName: "Inflect",
+			Handler:    _MorphAnalyzer_Inflect_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AnalyzeStream",
+			Handler:       _MorphAnalyzer_AnalyzeStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/steosmorphypb/steosmorphy.proto",
+}
@@ -0,0 +1,74 @@
+// batch.go содержит функции C API, принимающие массив слов: один вызов через FFI
+// на пакет слов вместо вызова на каждое слово (см. обертку bindings/java).
+//
+// Правила владения памятью: входные строки - NUL-терминированные UTF-8, принадлежат
+// вызывающему и копируются библиотекой до возврата из функции. Результат выделяется
+// библиотекой и освобождается вызывающим через steosmorphy_free_string или
+// steosmorphy_free_buffer. Строка не в UTF-8 дает STEOSMORPHY_ERR_INVALID_ARGUMENT.
+package main
+
+/*
+#include <stddef.h>
+
+#include "steosmorphy_status.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// steosmorphy_parse_batch разбирает массив words из count слов. Результат - JSON-массив
+// длины count в формате steosmorphy_analyze_tokens: i-й элемент - {"token", "parses"}.
+//
+//export steosmorphy_parse_batch
+func steosmorphy_parse_batch(handle unsafe.Pointer, words **C.char, count C.size_t, out **C.char, errOut **C.char) C.steosmorphy_status {
+	analyzer, status := resolve(handle, out, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return status
+	}
+	tokens, err := goStrings(words, count)
+	if err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, err)
+	}
+	return succeed(out, errOut, analyzeTokens(analyzer, tokens))
+}
+
+// steosmorphy_parse_batch_pb - steosmorphy_parse_batch с результатом AnalyzeTokensResponse
+// (Protocol Buffers) в *out длиной *out_len.
+//
+//export steosmorphy_parse_batch_pb
+func steosmorphy_parse_batch_pb(handle unsafe.Pointer, words **C.char, count C.size_t, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char) C.steosmorphy_status {
+	analyzer, status := resolvePB(handle, out, outLen, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return status
+	}
+	tokens, err := goStrings(words, count)
+	if err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, err)
+	}
+	return succeedPB(out, outLen, errOut, tokensResponse(analyzeTokens(analyzer, tokens)))
+}
+
+// goStrings копирует массив строк C в память Go, проверяя каждую строку.
+func goStrings(words **C.char, count C.size_t) ([]string, error) {
+	if count == 0 {
+		return []string{}, nil
+	}
+	if words == nil {
+		return nil, fmt.Errorf("words равен NULL при count = %d", count)
+	}
+	result := make([]string, count)
+	for i, w := range unsafe.Slice(words, count) {
+		if w == nil {
+			return nil, fmt.Errorf("words[%d] равен NULL", i)
+		}
+		s, err := goString(w)
+		if err != nil {
+			return nil, fmt.Errorf("words[%d]: %w", i, err)
+		}
+		result[i] = s
+	}
+	return result, nil
+}
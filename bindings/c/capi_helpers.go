@@ -0,0 +1,133 @@
+// capi_helpers.go содержит обертки C API с аргументами и результатами в типах Go.
+// Файлы _test.go не могут использовать cgo, поэтому тесты вызывают функции C API через них.
+package main
+
+/*
+#include <stdlib.h>
+
+#include "steosmorphy_status.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// Коды steosmorphy_status в типе int.
+const (
+	statusOK              = int(C.STEOSMORPHY_OK)
+	statusInvalidHandle   = int(C.STEOSMORPHY_ERR_INVALID_HANDLE)
+	statusInvalidArgument = int(C.STEOSMORPHY_ERR_INVALID_ARGUMENT)
+)
+
+// newAnalyzer вызывает steosmorphy_new; пустой dictPath означает словарь по умолчанию.
+func newAnalyzer(dictPath string) (unsafe.Pointer, error) {
+	var path *C.char
+	if dictPath != "" {
+		path = C.CString(dictPath)
+		defer C.free(unsafe.Pointer(path))
+	}
+	var errOut *C.char
+	handle := steosmorphy_new(path, &errOut)
+	if handle == nil {
+		defer C.free(unsafe.Pointer(errOut))
+		return nil, errors.New(C.GoString(errOut))
+	}
+	return handle, nil
+}
+
+// Функции C API над одним словом с JSON-результатом.
+var (
+	parseJSON          = wordJSON(steosmorphy_parse)
+	parsePredictedJSON = wordJSON(steosmorphy_parse_predicted)
+	inflectJSON        = wordJSON(steosmorphy_inflect)
+	lemmatizeJSON      = wordJSON(steosmorphy_lemmatize)
+)
+
+// wordJSON превращает функцию C API над одним словом в функцию, возвращающую код,
+// результат и текст ошибки; строки библиотеки освобождаются.
+func wordJSON(fn func(unsafe.Pointer, *C.char, **C.char, **C.char) C.steosmorphy_status) func(unsafe.Pointer, string) (int, string, string) {
+	return func(handle unsafe.Pointer, word string) (int, string, string) {
+		cword := C.CString(word)
+		defer C.free(unsafe.Pointer(cword))
+		var out, errOut *C.char
+		status := fn(handle, cword, &out, &errOut)
+		return int(status), takeString(out), takeString(errOut)
+	}
+}
+
+// analyzeTokensJSON вызывает steosmorphy_analyze_tokens с JSON-массивом токенов.
+func analyzeTokensJSON(handle unsafe.Pointer, tokensJSON string) (int, string, string) {
+	ctokens := C.CString(tokensJSON)
+	defer C.free(unsafe.Pointer(ctokens))
+	var out, errOut *C.char
+	status := steosmorphy_analyze_tokens(handle, ctokens, &out, &errOut)
+	return int(status), takeString(out), takeString(errOut)
+}
+
+// parsePB вызывает steosmorphy_parse_pb и возвращает код, копию буфера и текст ошибки,
+// освобождая буфер через steosmorphy_free_buffer.
+func parsePB(handle unsafe.Pointer, word string) (int, []byte, string) {
+	cword := C.CString(word)
+	defer C.free(unsafe.Pointer(cword))
+	var (
+		out    unsafe.Pointer
+		outLen C.size_t
+		errOut *C.char
+	)
+	status := steosmorphy_parse_pb(handle, cword, &out, &outLen, &errOut)
+	return int(status), takeBuffer(out, outLen), takeString(errOut)
+}
+
+// analyzeTokensPB вызывает steosmorphy_analyze_tokens_pb с сериализованным AnalyzeTokensRequest.
+func analyzeTokensPB(handle unsafe.Pointer, request []byte) (int, []byte, string) {
+	creq := C.CBytes(request)
+	defer C.free(creq)
+	var (
+		out    unsafe.Pointer
+		outLen C.size_t
+		errOut *C.char
+	)
+	status := steosmorphy_analyze_tokens_pb(handle, creq, C.size_t(len(request)), &out, &outLen, &errOut)
+	return int(status), takeBuffer(out, outLen), takeString(errOut)
+}
+
+// parseBatchPB вызывает steosmorphy_parse_batch_pb с массивом строк C.
+func parseBatchPB(handle unsafe.Pointer, words []string) (int, []byte, string) {
+	var cwords **C.char
+	if len(words) > 0 {
+		cwords = (**C.char)(C.malloc(C.size_t(len(words)) * C.size_t(unsafe.Sizeof((*C.char)(nil)))))
+		defer C.free(unsafe.Pointer(cwords))
+		array := unsafe.Slice(cwords, len(words))
+		for i, w := range words {
+			array[i] = C.CString(w)
+			defer C.free(unsafe.Pointer(array[i]))
+		}
+	}
+	var (
+		out    unsafe.Pointer
+		outLen C.size_t
+		errOut *C.char
+	)
+	status := steosmorphy_parse_batch_pb(handle, cwords, C.size_t(len(words)), &out, &outLen, &errOut)
+	return int(status), takeBuffer(out, outLen), takeString(errOut)
+}
+
+// takeString копирует строку библиотеки в память Go и освобождает ее.
+func takeString(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	defer steosmorphy_free_string(s)
+	return C.GoString(s)
+}
+
+// takeBuffer копирует буфер библиотеки в память Go и освобождает его.
+func takeBuffer(buf unsafe.Pointer, n C.size_t) []byte {
+	if buf == nil {
+		return nil
+	}
+	defer steosmorphy_free_buffer(buf)
+	return C.GoBytes(buf, C.int(n))
+}
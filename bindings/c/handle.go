@@ -0,0 +1,279 @@
+// handle.go содержит C API на основе дескрипторов: каждый анализатор создается
+// и уничтожается явно, ошибки возвращаются кодом и текстом.
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+#include "steosmorphy_status.h"
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime/cgo"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	_ "github.com/steosofficial/steosmorphy/dict"
+)
+
+// wordResult - результат анализа одного слова.
+type wordResult struct {
+	Parses []*steosmorphy.Parsed `json:"parses"`
+	Forms  []*steosmorphy.Parsed `json:"forms,omitempty"`
+}
+
+// tokenResult - результат анализа одного токена, выровненный по входному массиву.
+type tokenResult struct {
+	Token  string                `json:"token"`
+	Parses []*steosmorphy.Parsed `json:"parses"`
+}
+
+// errInvalidHandle возвращается при передаче нулевого или уже освобожденного дескриптора.
+var errInvalidHandle = errors.New("недействительный дескриптор анализатора")
+
+// handles - живые дескрипторы: адрес, выданный steosmorphy_new, -> cgo.Handle анализатора.
+// Дескриптор ищется по адресу, не читая память C, поэтому освобожденный дескриптор
+// и повторный steosmorphy_free распознаются без обращения к освобожденной памяти.
+var handles sync.Map
+
+// steosmorphy_new загружает анализатор. Пустой или NULL dict_path означает словарь
+// по умолчанию, который ищется как в LoadMorphAnalyzer: переменная окружения
+// STEOSMORPHY_DICT_PATH, словарь, встроенный в библиотеку модулем dict (его части
+// объединяются в DefaultDictDir), и файл в DefaultDictDir, скачанный EnsureDict.
+// При ошибке возвращает NULL и, если err не NULL, текст ошибки в *err.
+//
+//export steosmorphy_new
+func steosmorphy_new(dictPath *C.char, errOut **C.char) unsafe.Pointer {
+	var (
+		analyzer *steosmorphy.MorphAnalyzer
+		err      error
+	)
+	if dictPath == nil || C.GoString(dictPath) == "" {
+		analyzer, err = steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	} else {
+		analyzer, err = steosmorphy.LoadMorphAnalyzerFromFile(C.GoString(dictPath))
+	}
+	if err != nil {
+		setError(errOut, err)
+		return nil
+	}
+
+	// Дескриптор cgo.Handle хранится в памяти C: указатель на нее безопасно передавать наружу.
+	handle := (*C.uintptr_t)(C.malloc(C.size_t(unsafe.Sizeof(C.uintptr_t(0)))))
+	h := cgo.NewHandle(analyzer)
+	*handle = C.uintptr_t(h)
+	handles.Store(uintptr(unsafe.Pointer(handle)), h)
+	return unsafe.Pointer(handle)
+}
+
+// steosmorphy_free освобождает анализатор. NULL и уже освобожденный дескриптор
+// допускаются и игнорируются. Адрес освобожденного дескриптора может быть выдан
+// следующему steosmorphy_new, поэтому после освобождения дескриптор использовать нельзя:
+// вызов с ним вернет STEOSMORPHY_ERR_INVALID_HANDLE или обратится к другому анализатору.
+//
+//export steosmorphy_free
+func steosmorphy_free(handle unsafe.Pointer) {
+	v, ok := handles.LoadAndDelete(uintptr(handle))
+	if !ok {
+		return
+	}
+	h := v.(cgo.Handle)
+	_ = h.Value().(*steosmorphy.MorphAnalyzer).Close()
+	h.Delete()
+	C.free(handle)
+}
+
+// steosmorphy_analyze_word разбирает слово и генерирует все его словоформы.
+// Результат - JSON {"parses": [...], "forms": [...]}. Если нужны только разборы или леммы,
+// используйте steosmorphy_parse или steosmorphy_lemmatize: они не генерируют словоформы.
+//
+//export steosmorphy_analyze_word
+func steosmorphy_analyze_word(handle unsafe.Pointer, word *C.char, out **C.char, errOut **C.char) C.steosmorphy_status {
+	return wordCall(handle, word, out, errOut, func(a *steosmorphy.MorphAnalyzer, w string) any {
+		parses, forms := a.Analyze(w)
+		return wordResult{Parses: parses, Forms: forms}
+	})
+}
+
+// steosmorphy_parse ищет слово в словаре. Результат - JSON-массив разборов
+// (пустой для несловарного слова).
+//
+//export steosmorphy_parse
+func steosmorphy_parse(handle unsafe.Pointer, word *C.char, out **C.char, errOut **C.char) C.steosmorphy_status {
+	return wordCall(handle, word, out, errOut, func(a *steosmorphy.MorphAnalyzer, w string) any {
+		return nonNil(a.Parse(w))
+	})
+}
+
+// steosmorphy_parse_predicted разбирает несловарное слово предсказателем.
+// Результат - JSON-массив разборов.
+//
+//export steosmorphy_parse_predicted
+func steosmorphy_parse_predicted(handle unsafe.Pointer, word *C.char, out **C.char, errOut **C.char) C.steosmorphy_status {
+	return wordCall(handle, word, out, errOut, func(a *steosmorphy.MorphAnalyzer, w string) any {
+		return nonNil(a.ParsePredicted(w))
+	})
+}
+
+// steosmorphy_inflect генерирует все словоформы словарного слова.
+// Результат - JSON-массив разборов словоформ.
+//
+//export steosmorphy_inflect
+func steosmorphy_inflect(handle unsafe.Pointer, word *C.char, out **C.char, errOut **C.char) C.steosmorphy_status {
+	return wordCall(handle, word, out, errOut, func(a *steosmorphy.MorphAnalyzer, w string) any {
+		return nonNil(a.Inflect(w))
+	})
+}
+
+// steosmorphy_lemmatize возвращает леммы слова (несловарные слова - по предсказателю).
+// Результат - JSON-массив строк.
+//
+//export steosmorphy_lemmatize
+func steosmorphy_lemmatize(handle unsafe.Pointer, word *C.char, out **C.char, errOut **C.char) C.steosmorphy_status {
+	return wordCall(handle, word, out, errOut, func(a *steosmorphy.MorphAnalyzer, w string) any {
+		lemmas := a.Lemmatize(w)
+		if lemmas == nil {
+			lemmas = []string{}
+		}
+		return lemmas
+	})
+}
+
+// steosmorphy_analyze_tokens разбирает заранее токенизированный текст (razdel, spaCy, ...)
+// за один вызов. Принимает JSON-массив строк; результат - JSON-массив той же длины,
+// i-й элемент содержит разборы i-го токена. Словоформы не генерируются. Токены без букв
+// (пунктуация, числа) получают пустой список разборов.
+//
+//export steosmorphy_analyze_tokens
+func steosmorphy_analyze_tokens(handle unsafe.Pointer, tokensJSON *C.char, out **C.char, errOut **C.char) C.steosmorphy_status {
+	analyzer, status := resolve(handle, out, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return status
+	}
+	if tokensJSON == nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("tokens_json равен NULL"))
+	}
+
+	var tokens []string
+	if err := json.Unmarshal([]byte(C.GoString(tokensJSON)), &tokens); err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("ожидался JSON-массив строк: "+err.Error()))
+	}
+	return succeed(out, errOut, analyzeTokens(analyzer, tokens))
+}
+
+// steosmorphy_free_string освобождает строку, возвращенную библиотекой.
+//
+//export steosmorphy_free_string
+func steosmorphy_free_string(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// resolve проверяет, что дескриптор выдан steosmorphy_new и не освобожден,
+// и обнуляет выходные параметры.
+func resolve(handle unsafe.Pointer, out **C.char, errOut **C.char) (*steosmorphy.MorphAnalyzer, C.steosmorphy_status) {
+	if out != nil {
+		*out = nil
+	}
+	if errOut != nil {
+		*errOut = nil
+	}
+	v, ok := handles.Load(uintptr(handle))
+	if !ok {
+		return nil, fail(errOut, C.STEOSMORPHY_ERR_INVALID_HANDLE, errInvalidHandle)
+	}
+	return v.(cgo.Handle).Value().(*steosmorphy.MorphAnalyzer), C.STEOSMORPHY_OK
+}
+
+// wordCall - общая часть функций над одним словом: проверяет аргументы,
+// выполняет операцию и сериализует ее результат.
+func wordCall(handle unsafe.Pointer, word *C.char, out **C.char, errOut **C.char, op func(*steosmorphy.MorphAnalyzer, string) any) C.steosmorphy_status {
+	analyzer, status := resolve(handle, out, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return status
+	}
+	if word == nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("word равен NULL"))
+	}
+	w, err := goString(word)
+	if err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, err)
+	}
+	return succeed(out, errOut, op(analyzer, w))
+}
+
+// goString копирует строку C в память Go и проверяет, что она в кодировке UTF-8.
+func goString(s *C.char) (string, error) {
+	str := C.GoString(s)
+	if !utf8.ValidString(str) {
+		return "", errors.New("строка не в кодировке UTF-8")
+	}
+	return str, nil
+}
+
+// nonNil заменяет nil пустым срезом, чтобы в JSON был [], а не null.
+func nonNil(parses []*steosmorphy.Parsed) []*steosmorphy.Parsed {
+	if parses == nil {
+		return []*steosmorphy.Parsed{}
+	}
+	return parses
+}
+
+// succeed сериализует результат в *out.
+func succeed(out **C.char, errOut **C.char, v any) C.steosmorphy_status {
+	if out == nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("out равен NULL"))
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INTERNAL, err)
+	}
+	*out = C.CString(string(data))
+	return C.STEOSMORPHY_OK
+}
+
+// fail записывает текст ошибки в *errOut (если он передан) и возвращает код.
+func fail(errOut **C.char, status C.steosmorphy_status, err error) C.steosmorphy_status {
+	setError(errOut, err)
+	return status
+}
+
+// setError записывает текст ошибки в *errOut, если он передан.
+func setError(errOut **C.char, err error) {
+	if errOut != nil {
+		*errOut = C.CString(err.Error())
+	}
+}
+
+// analyzeTokens разбирает токены, сохраняя выравнивание по входному массиву.
+func analyzeTokens(analyzer *steosmorphy.MorphAnalyzer, tokens []string) []tokenResult {
+	results := make([]tokenResult, len(tokens))
+	for i, token := range tokens {
+		results[i] = tokenResult{Token: token, Parses: []*steosmorphy.Parsed{}}
+		if !hasLetter(token) {
+			continue
+		}
+		parses := analyzer.Parse(token)
+		if len(parses) == 0 {
+			parses = analyzer.ParsePredicted(token)
+		}
+		results[i].Parses = nonNil(parses)
+	}
+	return results
+}
+
+// hasLetter сообщает, что в токене есть хотя бы одна буква.
+func hasLetter(token string) bool {
+	for _, r := range token {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/api/steosmorphypb"
+	"google.golang.org/protobuf/proto"
+)
+
+// testLexiconTSV - крошечный лексикон для словаря тестов C API.
+const testLexiconTSV = `# словоформа	лемма	теги
+кот	кот	NOUN,anim,masc sing,nomn
+кота	кот	NOUN,anim,masc sing,gent
+коту	кот	NOUN,anim,masc sing,datv
+коты	кот	NOUN,anim,masc plur,nomn
+`
+
+// buildTestDict собирает словарь из testLexiconTSV и возвращает путь к нему.
+func buildTestDict(t *testing.T) string {
+	t.Helper()
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), steosmorphy.DictFileName)
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.Build(out); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestFreedHandle проверяет, что повторный steosmorphy_free безопасен,
+// а вызов с освобожденным дескриптором возвращает STEOSMORPHY_ERR_INVALID_HANDLE.
+func TestFreedHandle(t *testing.T) {
+	handle, err := newAnalyzer(buildTestDict(t))
+	if err != nil {
+		t.Fatalf("steosmorphy_new: %v", err)
+	}
+
+	status, out, errText := parseJSON(handle, "коту")
+	if status != statusOK {
+		t.Fatalf("steosmorphy_parse: код %d, ошибка %q", status, errText)
+	}
+	var parses []*steosmorphy.Parsed
+	if err := json.Unmarshal([]byte(out), &parses); err != nil {
+		t.Fatalf("Результат не JSON: %v (%s)", err, out)
+	}
+	if len(parses) == 0 || parses[0].Lemma != "кот" {
+		t.Fatalf("steosmorphy_parse(коту) = %s, ожидалась лемма кот", out)
+	}
+
+	steosmorphy_free(handle)
+	steosmorphy_free(handle)
+	steosmorphy_free(nil)
+
+	status, out, errText = parseJSON(handle, "коту")
+	if status != statusInvalidHandle {
+		t.Errorf("steosmorphy_parse после free: код %d, ожидался %d", status, statusInvalidHandle)
+	}
+	if out != "" || errText == "" {
+		t.Errorf("steosmorphy_parse после free: out = %q, err = %q; ожидались пустой результат и текст ошибки", out, errText)
+	}
+	if status, _, _ := parsePB(handle, "коту"); status != statusInvalidHandle {
+		t.Errorf("steosmorphy_parse_pb после free: код %d, ожидался %d", status, statusInvalidHandle)
+	}
+	if status, _, _ := parsePB(nil, "коту"); status != statusInvalidHandle {
+		t.Errorf("steosmorphy_parse_pb(NULL): код %d, ожидался %d", status, statusInvalidHandle)
+	}
+}
+
+// TestProtobufRoundTrip проверяет, что буферы функций *_pb разбираются схемой steosmorphypb
+// и совпадают с результатом анализатора.
+func TestProtobufRoundTrip(t *testing.T) {
+	path := buildTestDict(t)
+	handle, err := newAnalyzer(path)
+	if err != nil {
+		t.Fatalf("steosmorphy_new: %v", err)
+	}
+	defer steosmorphy_free(handle)
+
+	morph, err := steosmorphy.LoadMorphAnalyzerFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer morph.Close()
+
+	status, buf, errText := parsePB(handle, "коты")
+	if status != statusOK {
+		t.Fatalf("steosmorphy_parse_pb: код %d, ошибка %q", status, errText)
+	}
+	var list steosmorphypb.ParsedList
+	if err := proto.Unmarshal(buf, &list); err != nil {
+		t.Fatalf("Буфер не ParsedList: %v", err)
+	}
+	want := steosmorphypb.FromParsedList(morph.Parse("коты"))
+	if len(want) == 0 {
+		t.Fatal("Анализатор не разобрал слово коты")
+	}
+	if !proto.Equal(&list, &steosmorphypb.ParsedList{Parses: want}) {
+		t.Errorf("steosmorphy_parse_pb(коты) = %v, ожидалось %v", list.GetParses(), want)
+	}
+
+	tokens := []string{"Кота", ",", "коту"}
+	req, err := proto.Marshal(&steosmorphypb.AnalyzeTokensRequest{Tokens: tokens})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, buf, errText = analyzeTokensPB(handle, req)
+	if status != statusOK {
+		t.Fatalf("steosmorphy_analyze_tokens_pb: код %d, ошибка %q", status, errText)
+	}
+	var resp steosmorphypb.AnalyzeTokensResponse
+	if err := proto.Unmarshal(buf, &resp); err != nil {
+		t.Fatalf("Буфер не AnalyzeTokensResponse: %v", err)
+	}
+	if len(resp.GetTokens()) != len(tokens) {
+		t.Fatalf("Получено %d токенов, ожидалось %d", len(resp.GetTokens()), len(tokens))
+	}
+	for i, token := range resp.GetTokens() {
+		if token.GetWord() != tokens[i] {
+			t.Errorf("Токен %d = %q, ожидался %q", i, token.GetWord(), tokens[i])
+		}
+	}
+	if len(resp.GetTokens()[1].GetParses()) != 0 {
+		t.Errorf("Знак препинания получил разборы: %v", resp.GetTokens()[1].GetParses())
+	}
+	if parses := resp.GetTokens()[2].GetParses(); len(parses) == 0 || parses[0].GetLemma() != "кот" {
+		t.Errorf("Токен коту разобран как %v, ожидалась лемма кот", parses)
+	}
+
+	if status, _, _ := analyzeTokensPB(handle, []byte{0xff}); status != statusInvalidArgument {
+		t.Errorf("steosmorphy_analyze_tokens_pb(мусор): код %d, ожидался %d", status, statusInvalidArgument)
+	}
+}
+
+// TestWordFunctions проверяет JSON-функции C API над словом и токенами, включая ошибки аргументов.
+func TestWordFunctions(t *testing.T) {
+	handle, err := newAnalyzer(buildTestDict(t))
+	if err != nil {
+		t.Fatalf("steosmorphy_new: %v", err)
+	}
+	defer steosmorphy_free(handle)
+
+	status, out, errText := lemmatizeJSON(handle, "кота")
+	if status != statusOK || out != `["кот"]` {
+		t.Errorf("steosmorphy_lemmatize(кота) = %d %s %q, ожидалось [\"кот\"]", status, out, errText)
+	}
+
+	status, out, errText = inflectJSON(handle, "кот")
+	if status != statusOK {
+		t.Fatalf("steosmorphy_inflect: код %d, ошибка %q", status, errText)
+	}
+	var forms []*steosmorphy.Parsed
+	if err := json.Unmarshal([]byte(out), &forms); err != nil {
+		t.Fatalf("Результат не JSON: %v (%s)", err, out)
+	}
+	if len(forms) != 4 {
+		t.Errorf("steosmorphy_inflect(кот) вернул %d форм, ожидалось 4: %s", len(forms), out)
+	}
+
+	if status, out, _ := parseJSON(handle, "котище"); status != statusOK || out != "[]" {
+		t.Errorf("steosmorphy_parse(котище) = %d %s, ожидался пустой массив", status, out)
+	}
+	if status, out, errText := parsePredictedJSON(handle, "котище"); status != statusOK || !strings.HasPrefix(out, "[") {
+		t.Errorf("steosmorphy_parse_predicted(котище) = %d %s %q", status, out, errText)
+	}
+	if status, _, errText := parseJSON(handle, "\xffкот"); status != statusInvalidArgument || errText == "" {
+		t.Errorf("steosmorphy_parse(не UTF-8): код %d, ошибка %q; ожидался %d", status, errText, statusInvalidArgument)
+	}
+
+	status, out, errText = analyzeTokensJSON(handle, `["Кота", "!", "коту"]`)
+	if status != statusOK {
+		t.Fatalf("steosmorphy_analyze_tokens: код %d, ошибка %q", status, errText)
+	}
+	var tokens []tokenResult
+	if err := json.Unmarshal([]byte(out), &tokens); err != nil {
+		t.Fatalf("Результат не JSON: %v (%s)", err, out)
+	}
+	if len(tokens) != 3 || tokens[0].Token != "Кота" || len(tokens[1].Parses) != 0 || len(tokens[2].Parses) == 0 {
+		t.Errorf("steosmorphy_analyze_tokens = %s", out)
+	}
+	if status, _, _ := analyzeTokensJSON(handle, `{"tokens": 1}`); status != statusInvalidArgument {
+		t.Errorf("steosmorphy_analyze_tokens(не массив): код %d, ожидался %d", status, statusInvalidArgument)
+	}
+}
+
+// TestParseBatchPB проверяет пакетный разбор массива строк C: выравнивание по входу,
+// пустой массив и ошибку для строки не в UTF-8.
+func TestParseBatchPB(t *testing.T) {
+	handle, err := newAnalyzer(buildTestDict(t))
+	if err != nil {
+		t.Fatalf("steosmorphy_new: %v", err)
+	}
+	defer steosmorphy_free(handle)
+
+	words := []string{"коты", "123", "кота"}
+	status, buf, errText := parseBatchPB(handle, words)
+	if status != statusOK {
+		t.Fatalf("steosmorphy_parse_batch_pb: код %d, ошибка %q", status, errText)
+	}
+	var resp steosmorphypb.AnalyzeTokensResponse
+	if err := proto.Unmarshal(buf, &resp); err != nil {
+		t.Fatalf("Буфер не AnalyzeTokensResponse: %v", err)
+	}
+	if len(resp.GetTokens()) != len(words) {
+		t.Fatalf("Получено %d результатов, ожидалось %d", len(resp.GetTokens()), len(words))
+	}
+	for i, parsed := range []bool{true, false, true} {
+		if got := len(resp.GetTokens()[i].GetParses()); (got > 0) != parsed {
+			t.Errorf("Слово %q получило %d разборов", words[i], got)
+		}
+	}
+
+	status, buf, errText = parseBatchPB(handle, nil)
+	if status != statusOK || len(buf) != 0 {
+		t.Errorf("steosmorphy_parse_batch_pb(пустой массив) = %d, %d байт, %q", status, len(buf), errText)
+	}
+	if status, _, errText := parseBatchPB(handle, []string{"кот", "\xff"}); status != statusInvalidArgument || !strings.Contains(errText, "words[1]") {
+		t.Errorf("steosmorphy_parse_batch_pb(не UTF-8): код %d, ошибка %q", status, errText)
+	}
+}
@@ -0,0 +1,86 @@
+// legacy.go содержит первую версию C API с общим анализатором на процесс.
+// Оставлена для совместимости с существующими обертками; новый код должен
+// использовать steosmorphy_new и функции с дескриптором.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"sync"
+	"unsafe"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+var (
+	legacyAnalyzer     *steosmorphy.MorphAnalyzer
+	legacyAnalyzerErr  error
+	legacyAnalyzerOnce sync.Once
+)
+
+// errorResult возвращается старым API вместо результата при ошибке.
+type errorResult struct {
+	Error string `json:"error"`
+}
+
+// getLegacyAnalyzer лениво загружает общий для всех вызовов анализатор.
+func getLegacyAnalyzer() (*steosmorphy.MorphAnalyzer, error) {
+	legacyAnalyzerOnce.Do(func() {
+		legacyAnalyzer, legacyAnalyzerErr = steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	})
+	return legacyAnalyzer, legacyAnalyzerErr
+}
+
+// toCString сериализует значение в JSON и копирует его в память C.
+func toCString(v any) *C.char {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(errorResult{Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
+
+// Deprecated: используйте steosmorphy_new.
+//
+//export CreateAnalyzer
+func CreateAnalyzer() {
+	_, _ = getLegacyAnalyzer()
+}
+
+// Deprecated: используйте steosmorphy_analyze_word.
+//
+//export AnalyzeWord
+func AnalyzeWord(word *C.char) *C.char {
+	a, err := getLegacyAnalyzer()
+	if err != nil {
+		return toCString(errorResult{Error: err.Error()})
+	}
+	parses, forms := a.Analyze(C.GoString(word))
+	return toCString(wordResult{Parses: parses, Forms: forms})
+}
+
+// Deprecated: используйте steosmorphy_analyze_tokens.
+//
+//export AnalyzeTokens
+func AnalyzeTokens(tokensJSON *C.char) *C.char {
+	a, err := getLegacyAnalyzer()
+	if err != nil {
+		return toCString(errorResult{Error: err.Error()})
+	}
+	var tokens []string
+	if err := json.Unmarshal([]byte(C.GoString(tokensJSON)), &tokens); err != nil {
+		return toCString(errorResult{Error: "ожидался JSON-массив строк: " + err.Error()})
+	}
+	return toCString(analyzeTokens(a, tokens))
+}
+
+// Deprecated: используйте steosmorphy_free_string.
+//
+//export FreeString
+func FreeString(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
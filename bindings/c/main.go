@@ -0,0 +1,13 @@
+// main.go содержит C-обертку анализатора для использования из других языков (Python и др.).
+// Сборка разделяемой библиотеки:
+//
+//	cd bindings/c && go build -buildmode=c-shared -o libsteosmorphy.so .
+//
+// Анализатор создается функцией steosmorphy_new и передается во все остальные функции
+// как непрозрачный указатель; освобождается функцией steosmorphy_free. Функции возвращают
+// код ошибки (steosmorphy_status), результат и текст ошибки - через выходные параметры.
+// Результаты - строки UTF-8 в формате JSON. Все возвращенные строки выделены в куче C
+// и должны освобождаться вызовом steosmorphy_free_string.
+package main
+
+func main() {}
@@ -0,0 +1,121 @@
+// protobuf.go содержит варианты функций C API, возвращающие результат в бинарном формате
+// Protocol Buffers (схема api/steosmorphypb/steosmorphy.proto) вместо JSON: на миллионах слов
+// разбор JSON в Python/Java занимает больше времени, чем сам морфологический анализ.
+package main
+
+/*
+#include <stddef.h>
+#include <stdlib.h>
+
+#include "steosmorphy_status.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/api/steosmorphypb"
+	"google.golang.org/protobuf/proto"
+)
+
+// steosmorphy_parse_pb - steosmorphy_parse с результатом ParsedList в *out длиной *out_len.
+//
+//export steosmorphy_parse_pb
+func steosmorphy_parse_pb(handle unsafe.Pointer, word *C.char, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char) C.steosmorphy_status {
+	return wordCallPB(handle, word, out, outLen, errOut, (*steosmorphy.MorphAnalyzer).Parse)
+}
+
+// steosmorphy_parse_predicted_pb - steosmorphy_parse_predicted с результатом ParsedList.
+//
+//export steosmorphy_parse_predicted_pb
+func steosmorphy_parse_predicted_pb(handle unsafe.Pointer, word *C.char, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char) C.steosmorphy_status {
+	return wordCallPB(handle, word, out, outLen, errOut, (*steosmorphy.MorphAnalyzer).ParsePredicted)
+}
+
+// steosmorphy_inflect_pb - steosmorphy_inflect с результатом ParsedList.
+//
+//export steosmorphy_inflect_pb
+func steosmorphy_inflect_pb(handle unsafe.Pointer, word *C.char, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char) C.steosmorphy_status {
+	return wordCallPB(handle, word, out, outLen, errOut, (*steosmorphy.MorphAnalyzer).Inflect)
+}
+
+// steosmorphy_analyze_tokens_pb - steosmorphy_analyze_tokens в бинарном формате:
+// принимает сериализованный AnalyzeTokensRequest и возвращает AnalyzeTokensResponse.
+//
+//export steosmorphy_analyze_tokens_pb
+func steosmorphy_analyze_tokens_pb(handle unsafe.Pointer, request unsafe.Pointer, requestLen C.size_t, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char) C.steosmorphy_status {
+	analyzer, status := resolvePB(handle, out, outLen, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return status
+	}
+	if request == nil && requestLen > 0 {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("request равен NULL"))
+	}
+
+	var req steosmorphypb.AnalyzeTokensRequest
+	if err := proto.Unmarshal(C.GoBytes(request, C.int(requestLen)), &req); err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("ожидался AnalyzeTokensRequest: "+err.Error()))
+	}
+
+	return succeedPB(out, outLen, errOut, tokensResponse(analyzeTokens(analyzer, req.GetTokens())))
+}
+
+// tokensResponse преобразует выровненные результаты токенов в AnalyzeTokensResponse.
+func tokensResponse(results []tokenResult) *steosmorphypb.AnalyzeTokensResponse {
+	resp := &steosmorphypb.AnalyzeTokensResponse{Tokens: make([]*steosmorphypb.ParseResponse, len(results))}
+	for i, r := range results {
+		resp.Tokens[i] = &steosmorphypb.ParseResponse{Word: r.Token, Parses: steosmorphypb.FromParsedList(r.Parses)}
+	}
+	return resp
+}
+
+// steosmorphy_free_buffer освобождает буфер, возвращенный функцией *_pb.
+//
+//export steosmorphy_free_buffer
+func steosmorphy_free_buffer(buf unsafe.Pointer) {
+	C.free(buf)
+}
+
+// wordCallPB - общая часть функций *_pb над одним словом.
+func wordCallPB(handle unsafe.Pointer, word *C.char, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char, op func(*steosmorphy.MorphAnalyzer, string) []*steosmorphy.Parsed) C.steosmorphy_status {
+	analyzer, status := resolvePB(handle, out, outLen, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return status
+	}
+	if word == nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("word равен NULL"))
+	}
+	w, err := goString(word)
+	if err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, err)
+	}
+	list := &steosmorphypb.ParsedList{Parses: steosmorphypb.FromParsedList(op(analyzer, w))}
+	return succeedPB(out, outLen, errOut, list)
+}
+
+// resolvePB проверяет дескриптор и выходные параметры буфера и обнуляет их.
+func resolvePB(handle unsafe.Pointer, out *unsafe.Pointer, outLen *C.size_t, errOut **C.char) (*steosmorphy.MorphAnalyzer, C.steosmorphy_status) {
+	analyzer, status := resolve(handle, nil, errOut)
+	if status != C.STEOSMORPHY_OK {
+		return nil, status
+	}
+	if out == nil || outLen == nil {
+		return nil, fail(errOut, C.STEOSMORPHY_ERR_INVALID_ARGUMENT, errors.New("out или out_len равен NULL"))
+	}
+	*out, *outLen = nil, 0
+	return analyzer, C.STEOSMORPHY_OK
+}
+
+// succeedPB сериализует сообщение в буфер, выделенный в куче C.
+// Пустое сообщение дает буфер нулевой длины, но ненулевой указатель.
+func succeedPB(out *unsafe.Pointer, outLen *C.size_t, errOut **C.char, msg proto.Message) C.steosmorphy_status {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fail(errOut, C.STEOSMORPHY_ERR_INTERNAL, err)
+	}
+	*out = C.CBytes(data)
+	*outLen = C.size_t(len(data))
+	return C.STEOSMORPHY_OK
+}
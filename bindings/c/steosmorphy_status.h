@@ -0,0 +1,14 @@
+/* steosmorphy_status.h содержит коды ошибок C API SteosMorphy.
+ * Подключается сгенерированным libsteosmorphy.h: распространяйте его вместе с ним. */
+#ifndef STEOSMORPHY_STATUS_H
+#define STEOSMORPHY_STATUS_H
+
+typedef enum {
+	STEOSMORPHY_OK = 0,
+	STEOSMORPHY_ERR_INVALID_HANDLE = 1,
+	STEOSMORPHY_ERR_INVALID_ARGUMENT = 2,
+	STEOSMORPHY_ERR_LOAD = 3,
+	STEOSMORPHY_ERR_INTERNAL = 4
+} steosmorphy_status;
+
+#endif
@@ -0,0 +1,40 @@
+package io.github.steosofficial.steosmorphy;
+
+import com.sun.jna.IntegerType;
+import com.sun.jna.Library;
+import com.sun.jna.Native;
+import com.sun.jna.Pointer;
+import com.sun.jna.StringArray;
+import com.sun.jna.ptr.PointerByReference;
+
+/** Отображение функций C API libsteosmorphy (bindings/c) для JNA. */
+interface LibSteosMorphy extends Library {
+    /** Коды steosmorphy_status из steosmorphy_status.h. */
+    int STEOSMORPHY_OK = 0;
+    int STEOSMORPHY_ERR_INVALID_HANDLE = 1;
+    int STEOSMORPHY_ERR_INVALID_ARGUMENT = 2;
+
+    Pointer steosmorphy_new(String dictPath, PointerByReference err);
+
+    void steosmorphy_free(Pointer handle);
+
+    int steosmorphy_parse_pb(Pointer handle, String word, PointerByReference out, Pointer outLen, PointerByReference err);
+
+    int steosmorphy_parse_batch_pb(
+            Pointer handle, StringArray words, SizeT count, PointerByReference out, Pointer outLen, PointerByReference err);
+
+    void steosmorphy_free_string(Pointer s);
+
+    void steosmorphy_free_buffer(Pointer buf);
+
+    /** size_t платформы. */
+    final class SizeT extends IntegerType {
+        public SizeT() {
+            this(0);
+        }
+
+        public SizeT(long value) {
+            super(Native.SIZE_T_SIZE, value, true);
+        }
+    }
+}
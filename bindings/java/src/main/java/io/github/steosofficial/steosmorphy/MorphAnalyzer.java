@@ -0,0 +1,127 @@
+package io.github.steosofficial.steosmorphy;
+
+import com.sun.jna.Library;
+import com.sun.jna.Memory;
+import com.sun.jna.Native;
+import com.sun.jna.Pointer;
+import com.sun.jna.StringArray;
+import com.sun.jna.ptr.PointerByReference;
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Map;
+
+/**
+ * Морфологический анализатор поверх libsteosmorphy. Библиотека ищется JNA по имени
+ * "steosmorphy" (каталог задается системным свойством jna.library.path).
+ *
+ * <p>Экземпляр потокобезопасен и держит словарь до вызова {@link #close()}.
+ * Строки передаются в библиотеку и обратно в UTF-8; результаты копируются в память Java,
+ * а буферы библиотеки освобождаются до возврата из методов.
+ *
+ * <pre>{@code
+ * try (MorphAnalyzer analyzer = new MorphAnalyzer()) {
+ *     for (Parsed p : analyzer.parse(List.of("мама", "мыла", "раму"))) {
+ *         System.out.println(p.getWord() + " -> " + p.getLemma());
+ *     }
+ * }
+ * }</pre>
+ */
+public final class MorphAnalyzer implements AutoCloseable {
+    private static final LibSteosMorphy LIB = Native.load(
+            "steosmorphy", LibSteosMorphy.class, Map.of(Library.OPTION_STRING_ENCODING, "UTF-8"));
+
+    private Pointer handle;
+
+    /** Загружает словарь по умолчанию (переменная окружения STEOSMORPHY_DICT_PATH). */
+    public MorphAnalyzer() {
+        this(null);
+    }
+
+    /** Загружает словарь из файла dictPath (null - словарь по умолчанию). */
+    public MorphAnalyzer(String dictPath) {
+        PointerByReference err = new PointerByReference();
+        handle = LIB.steosmorphy_new(dictPath, err);
+        if (handle == null) {
+            throw new SteosMorphyException(-1, takeString(err));
+        }
+    }
+
+    /** Разборы одного слова; несловарные слова - пустой список. */
+    public List<Parsed> parse(String word) {
+        PointerByReference out = new PointerByReference();
+        Memory outLen = new Memory(Native.SIZE_T_SIZE);
+        PointerByReference err = new PointerByReference();
+        int status = LIB.steosmorphy_parse_pb(handle(), word, out, outLen, err);
+        return ProtoReader.parsedList(takeBuffer(status, out, outLen, err));
+    }
+
+    /**
+     * Разборы всех слов одним вызовом библиотеки - единым списком, как ParseList в Go.
+     * Несловарные слова разбираются предсказателем, токены без букв пропускаются.
+     */
+    public List<Parsed> parse(List<String> words) {
+        List<Parsed> result = new ArrayList<>();
+        for (List<Parsed> parses : parseAligned(words)) {
+            result.addAll(parses);
+        }
+        return result;
+    }
+
+    /** Разборы слов одним вызовом библиотеки: i-й элемент - разборы i-го слова. */
+    public List<List<Parsed>> parseAligned(List<String> words) {
+        if (words.isEmpty()) {
+            return new ArrayList<>();
+        }
+        StringArray array = new StringArray(words.toArray(new String[0]), StandardCharsets.UTF_8.name());
+        PointerByReference out = new PointerByReference();
+        Memory outLen = new Memory(Native.SIZE_T_SIZE);
+        PointerByReference err = new PointerByReference();
+        int status = LIB.steosmorphy_parse_batch_pb(
+                handle(), array, new LibSteosMorphy.SizeT(words.size()), out, outLen, err);
+        return ProtoReader.tokensResponse(takeBuffer(status, out, outLen, err));
+    }
+
+    /** Освобождает словарь; повторный вызов безопасен. */
+    @Override
+    public synchronized void close() {
+        if (handle != null) {
+            LIB.steosmorphy_free(handle);
+            handle = null;
+        }
+    }
+
+    private synchronized Pointer handle() {
+        if (handle == null) {
+            throw new IllegalStateException("анализатор закрыт");
+        }
+        return handle;
+    }
+
+    /** Копирует результат в массив Java и освобождает буфер библиотеки. */
+    private static byte[] takeBuffer(int status, PointerByReference out, Memory outLen, PointerByReference err) {
+        if (status != LibSteosMorphy.STEOSMORPHY_OK) {
+            throw new SteosMorphyException(status, takeString(err));
+        }
+        Pointer buf = out.getValue();
+        try {
+            long length = Native.SIZE_T_SIZE == 8 ? outLen.getLong(0) : outLen.getInt(0) & 0xffffffffL;
+            return buf == null ? new byte[0] : buf.getByteArray(0, (int) length);
+        } finally {
+            LIB.steosmorphy_free_buffer(buf);
+        }
+    }
+
+    /** Копирует строку ошибки и освобождает ее. */
+    private static String takeString(PointerByReference ref) {
+        Pointer s = ref.getValue();
+        if (s == null) {
+            return null;
+        }
+        try {
+            return s.getString(0, StandardCharsets.UTF_8.name());
+        } finally {
+            LIB.steosmorphy_free_string(s);
+        }
+    }
+}
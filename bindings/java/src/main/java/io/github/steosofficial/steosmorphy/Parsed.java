@@ -0,0 +1,95 @@
+package io.github.steosofficial.steosmorphy;
+
+import java.util.Collections;
+import java.util.List;
+
+/** Морфологический разбор словоформы; повторяет analyzer.Parsed из Go. */
+public final class Parsed {
+    String word = "";
+    String lemma = "";
+    String tags = "";
+    String partOfSpeech = "";
+    String animacy = "";
+    String aspect = "";
+    String grammaticalCase = "";
+    String gender = "";
+    String mood = "";
+    String number = "";
+    String person = "";
+    String tense = "";
+    String transitivity = "";
+    String voice = "";
+    List<String> otherTags = Collections.emptyList();
+
+    Parsed() {}
+
+    /** Словоформа в исходном написании. */
+    public String getWord() {
+        return word;
+    }
+
+    /** Нормальная (словарная) форма. */
+    public String getLemma() {
+        return lemma;
+    }
+
+    /** Строка тегов через запятую. */
+    public String getTags() {
+        return tags;
+    }
+
+    public String getPartOfSpeech() {
+        return partOfSpeech;
+    }
+
+    public String getAnimacy() {
+        return animacy;
+    }
+
+    public String getAspect() {
+        return aspect;
+    }
+
+    /** Падеж (в Go - поле Case). */
+    public String getCase() {
+        return grammaticalCase;
+    }
+
+    public String getGender() {
+        return gender;
+    }
+
+    public String getMood() {
+        return mood;
+    }
+
+    public String getNumber() {
+        return number;
+    }
+
+    public String getPerson() {
+        return person;
+    }
+
+    public String getTense() {
+        return tense;
+    }
+
+    public String getTransitivity() {
+        return transitivity;
+    }
+
+    public String getVoice() {
+        return voice;
+    }
+
+    /** Прочие граммемы в алфавитном порядке. */
+    public List<String> getOtherTags() {
+        return otherTags;
+    }
+
+    @Override
+    public String toString() {
+        return word + " (" + lemma + "): " + tags;
+    }
+}
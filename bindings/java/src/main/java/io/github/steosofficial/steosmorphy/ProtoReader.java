@@ -0,0 +1,155 @@
+package io.github.steosofficial.steosmorphy;
+
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.List;
+
+/**
+ * Минимальный декодер сообщений api/steosmorphypb/steosmorphy.proto, которые возвращает C API.
+ * Схема состоит только из строк и вложенных сообщений, поэтому полноценный protobuf-java не нужен.
+ */
+final class ProtoReader {
+    private static final int WIRE_VARINT = 0;
+    private static final int WIRE_FIXED64 = 1;
+    private static final int WIRE_BYTES = 2;
+    private static final int WIRE_FIXED32 = 5;
+
+    private final byte[] data;
+    private int pos;
+    private final int end;
+
+    private ProtoReader(byte[] data, int pos, int end) {
+        this.data = data;
+        this.pos = pos;
+        this.end = end;
+    }
+
+    /** ParsedList: repeated Parsed parses = 1. */
+    static List<Parsed> parsedList(byte[] data) {
+        ProtoReader r = new ProtoReader(data, 0, data.length);
+        List<Parsed> result = new ArrayList<>();
+        while (r.hasMore()) {
+            int tag = r.readTag();
+            if (tag >>> 3 == 1 && (tag & 7) == WIRE_BYTES) {
+                result.add(r.readMessage().parsed());
+            } else {
+                r.skip(tag & 7);
+            }
+        }
+        return result;
+    }
+
+    /** AnalyzeTokensResponse: repeated ParseResponse tokens = 1, ParseResponse.parses = 2. */
+    static List<List<Parsed>> tokensResponse(byte[] data) {
+        ProtoReader r = new ProtoReader(data, 0, data.length);
+        List<List<Parsed>> result = new ArrayList<>();
+        while (r.hasMore()) {
+            int tag = r.readTag();
+            if (tag >>> 3 != 1 || (tag & 7) != WIRE_BYTES) {
+                r.skip(tag & 7);
+                continue;
+            }
+            ProtoReader token = r.readMessage();
+            List<Parsed> parses = new ArrayList<>();
+            while (token.hasMore()) {
+                int tokenTag = token.readTag();
+                if (tokenTag >>> 3 == 2 && (tokenTag & 7) == WIRE_BYTES) {
+                    parses.add(token.readMessage().parsed());
+                } else {
+                    token.skip(tokenTag & 7);
+                }
+            }
+            result.add(parses);
+        }
+        return result;
+    }
+
+    private Parsed parsed() {
+        Parsed p = new Parsed();
+        List<String> otherTags = new ArrayList<>();
+        while (hasMore()) {
+            int tag = readTag();
+            if ((tag & 7) != WIRE_BYTES) {
+                skip(tag & 7);
+                continue;
+            }
+            String value = readString();
+            switch (tag >>> 3) {
+                case 1: p.word = value; break;
+                case 2: p.lemma = value; break;
+                case 3: p.tags = value; break;
+                case 4: p.partOfSpeech = value; break;
+                case 5: p.animacy = value; break;
+                case 6: p.aspect = value; break;
+                case 7: p.grammaticalCase = value; break;
+                case 8: p.gender = value; break;
+                case 9: p.mood = value; break;
+                case 10: p.number = value; break;
+                case 11: p.person = value; break;
+                case 12: p.tense = value; break;
+                case 13: p.transitivity = value; break;
+                case 14: p.voice = value; break;
+                case 15: otherTags.add(value); break;
+                default: break;
+            }
+        }
+        p.otherTags = Collections.unmodifiableList(otherTags);
+        return p;
+    }
+
+    private boolean hasMore() {
+        return pos < end;
+    }
+
+    private int readTag() {
+        return (int) readVarint();
+    }
+
+    private long readVarint() {
+        long result = 0;
+        for (int shift = 0; shift < 64; shift += 7) {
+            if (pos >= end) {
+                throw new IllegalArgumentException("обрезанное сообщение protobuf");
+            }
+            byte b = data[pos++];
+            result |= (long) (b & 0x7f) << shift;
+            if ((b & 0x80) == 0) {
+                return result;
+            }
+        }
+        throw new IllegalArgumentException("слишком длинный varint");
+    }
+
+    private int readLength() {
+        long length = readVarint();
+        if (length < 0 || length > end - pos) {
+            throw new IllegalArgumentException("неверная длина поля protobuf");
+        }
+        return (int) length;
+    }
+
+    private String readString() {
+        int length = readLength();
+        String s = new String(data, pos, length, StandardCharsets.UTF_8);
+        pos += length;
+        return s;
+    }
+
+    private ProtoReader readMessage() {
+        int length = readLength();
+        ProtoReader nested = new ProtoReader(data, pos, pos + length);
+        pos += length;
+        return nested;
+    }
+
+    private void skip(int wireType) {
+        switch (wireType) {
+            case WIRE_VARINT: readVarint(); break;
+            case WIRE_FIXED64: pos += 8; break;
+            case WIRE_BYTES: pos += readLength(); break;
+            case WIRE_FIXED32: pos += 4; break;
+            default: throw new IllegalArgumentException("неизвестный тип поля protobuf: " + wireType);
+        }
+    }
+}
@@ -0,0 +1,16 @@
+package io.github.steosofficial.steosmorphy;
+
+/** Ошибка C API; {@link #getStatus()} - код steosmorphy_status. */
+public class SteosMorphyException extends RuntimeException {
+    private final int status;
+
+    SteosMorphyException(int status, String message) {
+        super(message);
+        this.status = status;
+    }
+
+    /** Код steosmorphy_status или -1 для ошибки загрузки словаря. */
+    public int getStatus() {
+        return status;
+    }
+}
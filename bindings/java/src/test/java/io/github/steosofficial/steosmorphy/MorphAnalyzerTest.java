@@ -0,0 +1,80 @@
+package io.github.steosofficial.steosmorphy;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNotNull;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+import com.sun.jna.Library;
+import com.sun.jna.Memory;
+import com.sun.jna.Native;
+import com.sun.jna.Pointer;
+import com.sun.jna.ptr.PointerByReference;
+import java.util.List;
+import java.util.Map;
+import org.junit.jupiter.api.Test;
+
+/**
+ * Тесты обертки на словаре по умолчанию. Перед запуском соберите библиотеку:
+ * {@code cd bindings/c && go build -buildmode=c-shared -o libsteosmorphy.so .}
+ */
+class MorphAnalyzerTest {
+    private static final LibSteosMorphy LIB = Native.load(
+            "steosmorphy", LibSteosMorphy.class, Map.of(Library.OPTION_STRING_ENCODING, "UTF-8"));
+
+    @Test
+    void parseWord() {
+        try (MorphAnalyzer analyzer = new MorphAnalyzer()) {
+            List<Parsed> parses = analyzer.parse("мыла");
+            assertFalse(parses.isEmpty());
+            assertTrue(parses.stream().anyMatch(p -> p.getLemma().equals("мыть")));
+        }
+    }
+
+    @Test
+    void parseAlignedKeepsInputOrder() {
+        try (MorphAnalyzer analyzer = new MorphAnalyzer()) {
+            List<List<Parsed>> aligned = analyzer.parseAligned(List.of("мама", ",", "раму"));
+            assertEquals(3, aligned.size());
+            assertEquals("мама", aligned.get(0).get(0).getLemma());
+            assertTrue(aligned.get(1).isEmpty());
+            assertEquals("рама", aligned.get(2).get(0).getLemma());
+            assertTrue(analyzer.parseAligned(List.of()).isEmpty());
+        }
+    }
+
+    @Test
+    void closedAnalyzer() {
+        MorphAnalyzer analyzer = new MorphAnalyzer();
+        analyzer.close();
+        analyzer.close();
+        assertThrows(IllegalStateException.class, () -> analyzer.parse("мама"));
+    }
+
+    @Test
+    void invalidDictPath() {
+        SteosMorphyException e = assertThrows(
+                SteosMorphyException.class, () -> new MorphAnalyzer("/nonexistent/morph.dawg"));
+        assertEquals(-1, e.getStatus());
+        assertNotNull(e.getMessage());
+    }
+
+    @Test
+    void freedHandle() {
+        PointerByReference err = new PointerByReference();
+        Pointer handle = LIB.steosmorphy_new(null, err);
+        assertNotNull(handle);
+        LIB.steosmorphy_free(handle);
+        LIB.steosmorphy_free(handle);
+
+        PointerByReference out = new PointerByReference();
+        Memory outLen = new Memory(Native.SIZE_T_SIZE);
+        int status = LIB.steosmorphy_parse_pb(handle, "мама", out, outLen, err);
+        assertEquals(LibSteosMorphy.STEOSMORPHY_ERR_INVALID_HANDLE, status);
+        assertNull(out.getValue());
+        assertNotNull(err.getValue());
+        LIB.steosmorphy_free_string(err.getValue());
+    }
+}
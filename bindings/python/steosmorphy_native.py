@@ -0,0 +1,154 @@
+"""Тонкая ctypes-обертка над libsteosmorphy (см. bindings/c).
+
+Сборка библиотеки:
+
+    cd bindings/c && go build -buildmode=c-shared -o libsteosmorphy.so .
+"""
+
+import ctypes
+import json
+import os
+
+STEOSMORPHY_OK = 0
+
+
+class SteosMorphyError(RuntimeError):
+    """Ошибка C API: code - значение steosmorphy_status."""
+
+    def __init__(self, code, message):
+        super().__init__(message)
+        self.code = code
+
+
+class MorphAnalyzer:
+    def __init__(self, lib_path=None, dict_path=None):
+        lib_path = lib_path or os.environ.get("STEOSMORPHY_LIB", "libsteosmorphy.so")
+        self._lib = lib = ctypes.CDLL(lib_path)
+
+        lib.steosmorphy_new.argtypes = [ctypes.c_char_p, ctypes.POINTER(ctypes.c_void_p)]
+        lib.steosmorphy_new.restype = ctypes.c_void_p
+        lib.steosmorphy_free.argtypes = [ctypes.c_void_p]
+        lib.steosmorphy_free_string.argtypes = [ctypes.c_void_p]
+        for name in (
+            "steosmorphy_analyze_word",
+            "steosmorphy_analyze_tokens",
+            "steosmorphy_parse",
+            "steosmorphy_parse_predicted",
+            "steosmorphy_inflect",
+            "steosmorphy_lemmatize",
+        ):
+            fn = getattr(lib, name)
+            fn.argtypes = [
+                ctypes.c_void_p,
+                ctypes.c_char_p,
+                ctypes.POINTER(ctypes.c_void_p),
+                ctypes.POINTER(ctypes.c_void_p),
+            ]
+            fn.restype = ctypes.c_int
+
+        pb_args = [ctypes.POINTER(ctypes.c_void_p), ctypes.POINTER(ctypes.c_size_t), ctypes.POINTER(ctypes.c_void_p)]
+        for name in ("steosmorphy_parse_pb", "steosmorphy_parse_predicted_pb", "steosmorphy_inflect_pb"):
+            fn = getattr(lib, name)
+            fn.argtypes = [ctypes.c_void_p, ctypes.c_char_p] + pb_args
+            fn.restype = ctypes.c_int
+        lib.steosmorphy_analyze_tokens_pb.argtypes = [ctypes.c_void_p, ctypes.c_char_p, ctypes.c_size_t] + pb_args
+        lib.steosmorphy_analyze_tokens_pb.restype = ctypes.c_int
+        lib.steosmorphy_free_buffer.argtypes = [ctypes.c_void_p]
+
+        err = ctypes.c_void_p()
+        path = dict_path.encode("utf-8") if dict_path else None
+        self._handle = lib.steosmorphy_new(path, ctypes.byref(err))
+        if not self._handle:
+            raise SteosMorphyError(None, self._take_string(err))
+
+    def close(self):
+        """Освобождает анализатор; повторный вызов безопасен."""
+        if self._handle:
+            self._lib.steosmorphy_free(self._handle)
+            self._handle = None
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        self.close()
+
+    def __del__(self):
+        self.close()
+
+    def _take_string(self, ptr):
+        if not ptr.value:
+            return None
+        try:
+            return ctypes.string_at(ptr.value).decode("utf-8")
+        finally:
+            self._lib.steosmorphy_free_string(ptr)
+
+    def _call(self, fn, arg):
+        out, err = ctypes.c_void_p(), ctypes.c_void_p()
+        code = fn(self._handle, arg.encode("utf-8"), ctypes.byref(out), ctypes.byref(err))
+        result, message = self._take_string(out), self._take_string(err)
+        if code != STEOSMORPHY_OK:
+            raise SteosMorphyError(code, message)
+        return json.loads(result)
+
+    def analyze(self, word):
+        """Разбор слова и все его словоформы: {"parses": [...], "forms": [...]}."""
+        return self._call(self._lib.steosmorphy_analyze_word, word)
+
+    def parse(self, word):
+        """Разборы словарного слова (без генерации словоформ)."""
+        return self._call(self._lib.steosmorphy_parse, word)
+
+    def parse_predicted(self, word):
+        """Разборы несловарного слова по правилам предсказателя."""
+        return self._call(self._lib.steosmorphy_parse_predicted, word)
+
+    def inflect(self, word):
+        """Все словоформы словарного слова."""
+        return self._call(self._lib.steosmorphy_inflect, word)
+
+    def lemmatize(self, word):
+        """Леммы слова - самая дешевая операция, если формы не нужны."""
+        return self._call(self._lib.steosmorphy_lemmatize, word)
+
+    def analyze_tokens(self, tokens):
+        """Разборы заранее токенизированного текста за один вызов.
+
+        Результат выровнен по tokens: i-й элемент - {"token": ..., "parses": [...]}.
+        """
+        return self._call(
+            self._lib.steosmorphy_analyze_tokens,
+            json.dumps(list(tokens), ensure_ascii=False),
+        )
+
+    def _call_pb(self, fn, *args):
+        out, out_len, err = ctypes.c_void_p(), ctypes.c_size_t(), ctypes.c_void_p()
+        code = fn(self._handle, *args, ctypes.byref(out), ctypes.byref(out_len), ctypes.byref(err))
+        message = self._take_string(err)
+        if code != STEOSMORPHY_OK:
+            raise SteosMorphyError(code, message)
+        try:
+            return ctypes.string_at(out.value, out_len.value)
+        finally:
+            self._lib.steosmorphy_free_buffer(out)
+
+    def parse_pb(self, word):
+        """Разборы слова в виде сериализованного ParsedList (api/steosmorphypb/steosmorphy.proto)."""
+        return self._call_pb(self._lib.steosmorphy_parse_pb, word.encode("utf-8"))
+
+    def parse_predicted_pb(self, word):
+        """Предсказанные разборы в виде сериализованного ParsedList."""
+        return self._call_pb(self._lib.steosmorphy_parse_predicted_pb, word.encode("utf-8"))
+
+    def inflect_pb(self, word):
+        """Словоформы в виде сериализованного ParsedList."""
+        return self._call_pb(self._lib.steosmorphy_inflect_pb, word.encode("utf-8"))
+
+    def analyze_tokens_pb(self, request):
+        """Принимает сериализованный AnalyzeTokensRequest, возвращает AnalyzeTokensResponse.
+
+        Классы сообщений генерируются из схемы:
+        protoc --python_out=. api/steosmorphypb/steosmorphy.proto
+        """
+        return self._call_pb(self._lib.steosmorphy_analyze_tokens_pb, request, len(request))
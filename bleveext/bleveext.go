@@ -0,0 +1,234 @@
+// Package bleveext подключает анализатор к поисковому движку Bleve: LemmaFilter -
+// фильтр токенов (analysis.TokenFilter), заменяющий слова их леммами, чтобы запрос
+// "кошка" находил документы с "кошки" и "кошкам". Пакет вынесен в отдельный модуль,
+// чтобы ядро не зависело от Bleve.
+//
+//	err := bleveext.Register(bleveext.Name, analyzer, bleveext.DropStopwords())
+//	err = indexMapping.AddCustomAnalyzer("ru", map[string]interface{}{
+//		"type":          custom.Name,
+//		"tokenizer":     unicode.Name,
+//		"token_filters": []string{bleveext.Name},
+//	})
+package bleveext
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// Name - имя фильтра по умолчанию для Register.
+const Name = "steosmorphy_lemma"
+
+// OOVMode - обработка несловарных слов (слов, которых нет в словаре).
+type OOVMode int
+
+const (
+	OOVPredict OOVMode = iota // Заменять предсказанной леммой (как Lemmatize).
+	OOVKeep                   // Оставлять слово как есть, в нижнем регистре.
+	OOVDrop                   // Отбрасывать токен.
+)
+
+// stopworder - анализатор, умеющий распознавать служебные слова (см. MorphAnalyzer.IsStopword).
+type stopworder interface {
+	IsStopword(word string) bool
+}
+
+// LemmaFilter - фильтр токенов Bleve, заменяющий слова леммами. Токены с KeyWord
+// не изменяются. Безопасен для параллельного использования.
+type LemmaFilter struct {
+	morph         steosmorphy.Morph
+	oov           OOVMode
+	allLemmas     bool
+	dropStopwords bool
+	stopwords     map[string]struct{}
+}
+
+// Option - функциональная опция LemmaFilter.
+type Option func(*LemmaFilter)
+
+// WithOOV задает обработку несловарных слов (по умолчанию - OOVPredict).
+func WithOOV(mode OOVMode) Option {
+	return func(f *LemmaFilter) {
+		f.oov = mode
+	}
+}
+
+// AllLemmas индексирует все леммы омонимичного слова ("стали" - "сталь" и "стать")
+// токенами в одной позиции. По умолчанию остается лемма первого разбора.
+func AllLemmas() Option {
+	return func(f *LemmaFilter) {
+		f.allLemmas = true
+	}
+}
+
+// DropStopwords отбрасывает служебные слова: встроенный список стоп-слов
+// (см. steosmorphy.Stopwords) и, если анализатор это умеет, слова, все разборы
+// которых - предлоги, союзы и частицы (см. MorphAnalyzer.IsStopword).
+func DropStopwords() Option {
+	return func(f *LemmaFilter) {
+		f.dropStopwords = true
+	}
+}
+
+// WithStopwords добавляет собственные стоп-слова, которые сравниваются со словоформой
+// и с леммой без учета регистра.
+func WithStopwords(words ...string) Option {
+	return func(f *LemmaFilter) {
+		if f.stopwords == nil {
+			f.stopwords = make(map[string]struct{}, len(words))
+		}
+		for _, w := range words {
+			f.stopwords[strings.ToLower(w)] = struct{}{}
+		}
+	}
+}
+
+// NewLemmaFilter создает фильтр лемм поверх анализатора morph.
+func NewLemmaFilter(morph steosmorphy.Morph, opts ...Option) *LemmaFilter {
+	f := &LemmaFilter{morph: morph}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.dropStopwords {
+		if f.stopwords == nil {
+			f.stopwords = make(map[string]struct{})
+		}
+		for _, w := range steosmorphy.Stopwords() {
+			f.stopwords[w] = struct{}{}
+		}
+	}
+	return f
+}
+
+// Filter заменяет слова потока их леммами. Токены, не содержащие букв (числа),
+// проходят без изменений.
+func (f *LemmaFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	output := input[:0:0]
+	for _, token := range input {
+		if token.KeyWord || !hasLetter(token.Term) {
+			output = append(output, token)
+			continue
+		}
+		word := strings.ToLower(string(token.Term))
+		if f.isStopword(word) {
+			continue
+		}
+		lemmas := f.lemmas(word)
+		if len(lemmas) == 0 {
+			continue
+		}
+		if _, ok := f.stopwords[lemmas[0]]; ok {
+			continue
+		}
+		token.Term = []byte(lemmas[0])
+		output = append(output, token)
+		for _, lemma := range lemmas[1:] {
+			extra := *token
+			extra.Term = []byte(lemma)
+			output = append(output, &extra)
+		}
+	}
+	return output
+}
+
+// isStopword сообщает, что слово нужно отбросить как стоп-слово.
+func (f *LemmaFilter) isStopword(word string) bool {
+	if _, ok := f.stopwords[word]; ok {
+		return true
+	}
+	if f.dropStopwords {
+		if s, ok := f.morph.(stopworder); ok {
+			return s.IsStopword(word)
+		}
+	}
+	return false
+}
+
+// lemmas возвращает леммы слова для индекса с учетом OOVMode и AllLemmas; nil - отбросить токен.
+func (f *LemmaFilter) lemmas(word string) []string {
+	if f.oov != OOVPredict && len(f.morph.Parse(word)) == 0 {
+		if f.oov == OOVDrop {
+			return nil
+		}
+		return []string{word}
+	}
+	lemmas := f.morph.Lemmatize(word)
+	if len(lemmas) == 0 {
+		return []string{word}
+	}
+	if !f.allLemmas {
+		lemmas = lemmas[:1]
+	}
+	return lemmas
+}
+
+// hasLetter сообщает, что в терме есть хотя бы одна буква.
+func hasLetter(term []byte) bool {
+	return bytes.IndexFunc(term, unicode.IsLetter) >= 0
+}
+
+// Register регистрирует в реестре Bleve фильтр name поверх анализатора morph, чтобы
+// на него можно было сослаться в token_filters собственного анализатора индекса.
+// Опции opts задают настройки по умолчанию; в конфигурации фильтра (AddCustomTokenFilter)
+// их можно переопределить ключами "oov" ("predict", "keep", "drop"), "all_lemmas",
+// "drop_stopwords" и "stopwords" (список слов).
+func Register(name string, morph steosmorphy.Morph, opts ...Option) error {
+	return registry.RegisterTokenFilter(name, func(config map[string]interface{}, _ *registry.Cache) (analysis.TokenFilter, error) {
+		configOpts, err := configOptions(config)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка настройки фильтра %s: %w", name, err)
+		}
+		return NewLemmaFilter(morph, append(opts[:len(opts):len(opts)], configOpts...)...), nil
+	})
+}
+
+// errStopwordsConfig - ошибка конфигурации фильтра с неверным списком стоп-слов.
+var errStopwordsConfig = errors.New("stopwords должен быть списком строк")
+
+// configOptions переводит конфигурацию фильтра Bleve в опции.
+func configOptions(config map[string]interface{}) ([]Option, error) {
+	var opts []Option
+	if v, ok := config["oov"]; ok {
+		switch v {
+		case "predict":
+			opts = append(opts, WithOOV(OOVPredict))
+		case "keep":
+			opts = append(opts, WithOOV(OOVKeep))
+		case "drop":
+			opts = append(opts, WithOOV(OOVDrop))
+		default:
+			return nil, fmt.Errorf("неизвестный режим oov %v", v)
+		}
+	}
+	if v, ok := config["all_lemmas"].(bool); ok && v {
+		opts = append(opts, AllLemmas())
+	}
+	if v, ok := config["drop_stopwords"].(bool); ok && v {
+		opts = append(opts, DropStopwords())
+	}
+	switch list := config["stopwords"].(type) {
+	case nil:
+	case []string:
+		opts = append(opts, WithStopwords(list...))
+	case []interface{}:
+		words := make([]string, 0, len(list))
+		for _, w := range list {
+			s, ok := w.(string)
+			if !ok {
+				return nil, errStopwordsConfig
+			}
+			words = append(words, s)
+		}
+		opts = append(opts, WithStopwords(words...))
+	default:
+		return nil, errStopwordsConfig
+	}
+	return opts, nil
+}
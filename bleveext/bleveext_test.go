@@ -0,0 +1,102 @@
+package bleveext_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/bleveext"
+	"github.com/steosofficial/steosmorphy/testdict"
+)
+
+// terms возвращает термы потока.
+func terms(stream analysis.TokenStream) []string {
+	var result []string
+	for _, token := range stream {
+		result = append(result, string(token.Term))
+	}
+	return result
+}
+
+// TestLemmaFilter проверяет замену слов леммами, стоп-слова и обработку несловарных слов.
+func TestLemmaFilter(t *testing.T) {
+	morph := testdict.Load(t)
+	tokenizer := unicode.NewUnicodeTokenizer()
+	text := []byte("Мама и кошка не читали книгу на окне 2")
+	predicted := morph.Lemmatize("книгу")[0] // "Книга" нет в тестовом словаре.
+
+	tests := []struct {
+		name string
+		opts []bleveext.Option
+		want []string
+	}{
+		{"по умолчанию", nil, []string{"мама", "и", "кошка", "не", "читать", predicted, "на", "окно", "2"}},
+		{"стоп-слова", []bleveext.Option{bleveext.DropStopwords()}, []string{"мама", "кошка", "читать", predicted, "окно", "2"}},
+		{"свои стоп-слова", []bleveext.Option{bleveext.WithStopwords("Окно", "читать")}, []string{"мама", "и", "кошка", "не", predicted, "на", "2"}},
+		{"несловарные как есть", []bleveext.Option{bleveext.WithOOV(bleveext.OOVKeep)}, []string{"мама", "и", "кошка", "не", "читать", "книгу", "на", "окно", "2"}},
+		{"без несловарных", []bleveext.Option{bleveext.WithOOV(bleveext.OOVDrop)}, []string{"мама", "и", "кошка", "не", "читать", "на", "окно", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := bleveext.NewLemmaFilter(morph, tt.opts...)
+			if got := terms(filter.Filter(tokenizer.Tokenize(text))); !slices.Equal(got, tt.want) {
+				t.Errorf("Filter() = %v; ожидали %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("все леммы", func(t *testing.T) {
+		fake := steosmorphy.NewFakeAnalyzer(
+			steosmorphy.LexEntry{Word: "стали", Lemma: "сталь", Tags: "Существительное,Род"},
+			steosmorphy.LexEntry{Word: "стали", Lemma: "стать", Tags: "Глагол,Прошедшее"},
+		)
+		stream := bleveext.NewLemmaFilter(fake, bleveext.AllLemmas()).Filter(tokenizer.Tokenize([]byte("стали")))
+		if got := terms(stream); !slices.Equal(got, []string{"сталь", "стать"}) || stream[0].Position != stream[1].Position {
+			t.Errorf("Filter(стали) = %v; ожидали несколько лемм в одной позиции", terms(stream))
+		}
+	})
+}
+
+// TestRegister проверяет поиск по леммам в индексе Bleve с зарегистрированным фильтром.
+func TestRegister(t *testing.T) {
+	if err := bleveext.Register(bleveext.Name, testdict.Load(t), bleveext.DropStopwords()); err != nil {
+		t.Fatal(err)
+	}
+	if err := bleveext.Register(bleveext.Name, testdict.Load(t)); err == nil {
+		t.Error("Повторная регистрация фильтра не вернула ошибку")
+	}
+
+	indexMapping := bleve.NewIndexMapping()
+	err := indexMapping.AddCustomAnalyzer("ru", map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{bleveext.Name},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexMapping.DefaultAnalyzer = "ru"
+	index, err := bleve.NewMemOnly(indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	if err := index.Index("1", map[string]string{"text": "Коты сидели на столах"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Index("2", map[string]string{"text": "Мама читает"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := index.Search(bleve.NewSearchRequest(bleve.NewMatchQuery("кот на столе")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Hits[0].ID != "1" {
+		t.Errorf("Поиск \"кот на столе\" нашел %d документов; ожидали документ 1", result.Total)
+	}
+}
@@ -0,0 +1,282 @@
+// steosmorphy-build компилирует словарь morph.dawg из исходного лексикона:
+// XML-дампа OpenCorpora (в том числе сжатого .bz2), TSV-файла, словаря pymorphy2
+// или украинского словаря ВЕСУМ.
+//
+// Примеры:
+//
+//	steosmorphy-build -opencorpora dict.opcorpora.xml.bz2 -o morph.dawg
+//	steosmorphy-build -tsv lexicon.tsv -o morph.dawg
+//	steosmorphy-build -pymorphy2 .venv/lib/python3.12/site-packages/pymorphy2_dicts_ru/data -o morph.dawg
+//	steosmorphy-build -vesum dict_corp_lt.txt.bz2 -o morph-uk.dawg
+//	steosmorphy-build -tsv lexicon.tsv -license-name "CC BY 4.0" -attribution "..." -o morph.dawg
+//	steosmorphy-build -opencorpora dict.opcorpora.xml.bz2 -tag-index -o morph.dawg
+//	steosmorphy-build -tsv lexicon.tsv -relations relations.tsv -o morph.dawg
+//	steosmorphy-build -convert old.dawg -o morph.dawg
+//	steosmorphy-build -opencorpora dict.opcorpora.xml.bz2 -source-revision 417150 -o morph.dawg
+//	steosmorphy-build -tag-corpus analyzer/tagcorpus.tsv -o analyzer/tagmodel.tsv
+//
+// Лицензия лексикона встраивается в словарь (см. MorphAnalyzer.License). Для OpenCorpora
+// и словаря pymorphy2 (построенного по OpenCorpora), а также для ВЕСУМ она задается
+// автоматически, флаги -license-* и -attribution переопределяют ее поля. Ревизия корпуса словаря pymorphy2
+// берется из его meta.json, если не задан флаг -source-revision.
+// Флаг -tag-index встраивает обратный индекс наборов тегов для MorphAnalyzer.FindByTags.
+// Видовые пары глаголов из связей OpenCorpora встраиваются всегда; флаг -relations добавляет
+// связи лемм из TSV-файла (см. ReadTSVRelations) для MorphAnalyzer.AspectPair и Derivations.
+// Флаг -source-revision записывает в заголовок словаря ревизию исходного корпуса
+// (см. MorphAnalyzer.DictInfo). Флаг -convert вместо сборки переписывает словарь прежнего формата в текущий
+// платформонезависимый формат (см. ConvertDict). Флаг -tag-corpus вместо словаря обучает модель
+// переходов для Disambiguate по размеченному корпусу (см. ReadTaggedCorpus и TagModel.AddTaggedSentence).
+package main
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+func main() {
+	openCorporaPath := flag.String("opencorpora", "", "путь к XML-дампу OpenCorpora (.xml или .xml.bz2)")
+	tsvPath := flag.String("tsv", "", "путь к TSV-лексикону (словоформа, лемма, теги[, id лексемы])")
+	vesumPath := flag.String("vesum", "", "путь к украинскому словарю ВЕСУМ в формате LanguageTool (dict_corp_lt.txt или .txt.bz2)")
+	pymorphy2Path := flag.String("pymorphy2", "", "путь к каталогу словаря pymorphy2 (words.dawg, paradigms.array, ...)")
+	outputPath := flag.String("o", steosmorphy.DictFileName, "путь к создаваемому словарю")
+	licenseName := flag.String("license-name", "", "название лицензии лексикона")
+	licenseURL := flag.String("license-url", "", "ссылка на текст лицензии лексикона")
+	licenseFile := flag.String("license-file", "", "файл с полным текстом лицензии лексикона")
+	attribution := flag.String("attribution", "", "текст указания авторства при распространении словаря")
+	tagIndex := flag.Bool("tag-index", false, "встроить обратный индекс наборов тегов (ускоряет FindByTags)")
+	relationsPath := flag.String("relations", "", "путь к TSV-файлу связей лемм (лемма, лемма, aspect|derivation)")
+	convertPath := flag.String("convert", "", "путь к словарю прежнего формата для преобразования в текущий")
+	tagCorpusPath := flag.String("tag-corpus", "", "путь к размеченному корпусу (токен, часть речи) для обучения модели переходов")
+	sourceRevision := flag.String("source-revision", "", "ревизия исходного корпуса (до 64 байт) для заголовка словаря")
+	compression := flag.String("compression", string(steosmorphy.CompressionGzip), "сжатие сложного блока: gzip или zstd (быстрее загрузка)")
+	flag.Parse()
+
+	sources := 0
+	for _, path := range []string{*openCorporaPath, *tsvPath, *vesumPath, *pymorphy2Path, *convertPath, *tagCorpusPath} {
+		if path != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		fmt.Fprintln(os.Stderr, "Укажите ровно один источник: -opencorpora, -tsv, -vesum, -pymorphy2, -convert или -tag-corpus")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *convertPath != "" {
+		if err := convert(*convertPath, *outputPath); err != nil {
+			log.Fatalf("Ошибка преобразования словаря: %v", err)
+		}
+		return
+	}
+	if *tagCorpusPath != "" {
+		if err := trainTagModel(*tagCorpusPath, *outputPath); err != nil {
+			log.Fatalf("Ошибка обучения модели переходов: %v", err)
+		}
+		return
+	}
+
+	license := steosmorphy.DictLicense{}
+	if *openCorporaPath != "" || *pymorphy2Path != "" {
+		license = steosmorphy.OpenCorporaLicense
+	}
+	if *vesumPath != "" {
+		license = steosmorphy.VESUMLicense
+	}
+	if *licenseName != "" {
+		license.Name = *licenseName
+	}
+	if *licenseURL != "" {
+		license.URL = *licenseURL
+	}
+	if *attribution != "" {
+		license.Attribution = *attribution
+	}
+	if *licenseFile != "" {
+		text, err := os.ReadFile(*licenseFile)
+		if err != nil {
+			log.Fatalf("Ошибка чтения текста лицензии: %v", err)
+		}
+		license.Text = string(text)
+	}
+
+	if err := run(*openCorporaPath, *tsvPath, *vesumPath, *pymorphy2Path, *relationsPath, *outputPath, *sourceRevision, steosmorphy.Compression(*compression), license, *tagIndex); err != nil {
+		log.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+}
+
+// run читает лексикон и связи лемм (если relationsPath не пуст), компилирует словарь
+// с лицензией license, ревизией корпуса sourceRevision и сжатием сложного блока
+// compression (и обратным индексом тегов, если tagIndex) и записывает его в outputPath.
+func run(openCorporaPath, tsvPath, vesumPath, pymorphy2Path, relationsPath, outputPath, sourceRevision string, compression steosmorphy.Compression, license steosmorphy.DictLicense, tagIndex bool) error {
+	builder := steosmorphy.NewDictBuilder()
+	builder.SetLicense(license)
+	builder.SetTagIndex(tagIndex)
+	if err := builder.SetSourceRevision(sourceRevision); err != nil {
+		return err
+	}
+	if err := builder.SetCompression(compression); err != nil {
+		return err
+	}
+
+	if pymorphy2Path != "" {
+		log.Printf("Чтение словаря pymorphy2 %s...", pymorphy2Path)
+		meta, err := steosmorphy.ReadPymorphy2Dict(os.DirFS(pymorphy2Path), builder.Add)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения словаря pymorphy2: %w", err)
+		}
+		if sourceRevision == "" {
+			if err := builder.SetSourceRevision(meta.SourceRevision); err != nil {
+				return err
+			}
+		}
+		return build(builder, relationsPath, outputPath)
+	}
+
+	sourcePath := openCorporaPath + tsvPath + vesumPath // Задан ровно один из путей.
+	source, err := openSource(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	log.Printf("Чтение лексикона %s...", sourcePath)
+	switch {
+	case openCorporaPath != "":
+		err = steosmorphy.ReadOpenCorporaXMLWithRelations(source, builder.Add, builder.AddRelation)
+	case vesumPath != "":
+		err = steosmorphy.ReadVESUM(source, builder.Add)
+	default:
+		err = steosmorphy.ReadTSVLexicon(source, builder.Add)
+	}
+	if err != nil {
+		return err
+	}
+	return build(builder, relationsPath, outputPath)
+}
+
+// build добавляет связи лемм (если relationsPath не пуст), компилирует словарь и записывает его в outputPath.
+func build(builder *steosmorphy.DictBuilder, relationsPath, outputPath string) error {
+	if relationsPath != "" {
+		if err := readRelations(relationsPath, builder); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Компиляция словаря в %s...", outputPath)
+	if err := writeDict(outputPath, builder.Build); err != nil {
+		return err
+	}
+	log.Printf("Словарь успешно собран: %s", outputPath)
+	return nil
+}
+
+// convert переписывает словарь прежнего формата из inputPath в outputPath.
+func convert(inputPath, outputPath string) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения словаря: %w", err)
+	}
+	log.Printf("Преобразование словаря %s в %s...", inputPath, outputPath)
+	if err := writeDict(outputPath, func(w io.Writer) error { return steosmorphy.ConvertDict(w, data) }); err != nil {
+		return err
+	}
+	log.Printf("Словарь успешно преобразован: %s", outputPath)
+	return nil
+}
+
+// trainTagModel обучает модель переходов по размеченному корпусу corpusPath
+// и записывает ее в outputPath с заголовком, указывающим источник частот.
+func trainTagModel(corpusPath, outputPath string) error {
+	corpus, err := openSource(corpusPath)
+	if err != nil {
+		return err
+	}
+	defer corpus.Close()
+
+	log.Printf("Чтение корпуса %s...", corpusPath)
+	model := steosmorphy.NewTagModel()
+	sentences := 0
+	err = steosmorphy.ReadTaggedCorpus(corpus, func(words, tags []string) {
+		model.AddTaggedSentence(words, tags)
+		sentences++
+	})
+	if err != nil {
+		return err
+	}
+
+	header := "# Модель переходов между частями речи для Disambiguate: \"предыдущая<TAB>следующая<TAB>частота\".\n" +
+		"# \"^\" - начало предложения, \"Пунктуация\" - знаки препинания внутри предложения.\n" +
+		fmt.Sprintf("# Частоты - число переходов в корпусе %s (предложений: %d), посчитанное TagModel.AddTaggedSentence;\n", filepath.Base(corpusPath), sentences) +
+		"# файл создан командой steosmorphy-build -tag-corpus.\n"
+	if err := writeDict(outputPath, func(w io.Writer) error {
+		if _, err := io.WriteString(w, header); err != nil {
+			return err
+		}
+		_, err := model.WriteTo(w)
+		return err
+	}); err != nil {
+		return err
+	}
+	log.Printf("Модель переходов обучена, предложений: %d: %s", sentences, outputPath)
+	return nil
+}
+
+// writeDict создает файл outputPath и записывает в него словарь функцией write.
+func writeDict(outputPath string, write func(io.Writer) error) error {
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла %s: %w", outputPath, err)
+	}
+	writer := bufio.NewWriter(out)
+	if err := write(writer); err != nil {
+		out.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		out.Close()
+		return fmt.Errorf("ошибка записи словаря: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("ошибка записи словаря: %w", err)
+	}
+	return nil
+}
+
+// readRelations добавляет в builder связи лемм из TSV-файла.
+func readRelations(path string, builder *steosmorphy.DictBuilder) error {
+	log.Printf("Чтение связей лемм %s...", path)
+	file, err := openSource(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := steosmorphy.ReadTSVRelations(file, builder.AddRelation); err != nil {
+		return fmt.Errorf("ошибка чтения связей лемм: %w", err)
+	}
+	return nil
+}
+
+// openSource открывает файл лексикона, прозрачно распаковывая .bz2.
+func openSource(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия лексикона: %w", err)
+	}
+	if !strings.HasSuffix(path, ".bz2") {
+		return file, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{bufio.NewReader(bzip2.NewReader(file)), file}, nil
+}
@@ -0,0 +1,117 @@
+// steosmorphy-server запускает gRPC-сервис морфологического анализатора.
+// Словарь загружается в фоне: сервис сразу слушает адрес, а стандартная проверка
+// здоровья gRPC (grpc.health.v1) отвечает NOT_SERVING, пока словарь не загружен.
+// С флагом -otel сервис записывает спаны запросов gRPC, пакетных операций и метрики
+// разбора (см. пакет telemetry) и отправляет их по OTLP/gRPC; адрес коллектора задается
+// стандартными переменными окружения OTEL_EXPORTER_OTLP_*.
+// С флагом -http запускается и HTTP-сервис фильтра токенов (/tokenize, /lemmatize)
+// для Logstash и других конвейеров индексации (см. server.TokenFilterHandler).
+//
+// Пример:
+//
+//	steosmorphy-server -grpc :50051
+//	steosmorphy-server -http :8080
+//	OTEL_EXPORTER_OTLP_ENDPOINT=http://collector:4317 steosmorphy-server -otel
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/api/steosmorphypb"
+	_ "github.com/steosofficial/steosmorphy/dict"
+	"github.com/steosofficial/steosmorphy/server"
+	"github.com/steosofficial/steosmorphy/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func main() {
+	grpcAddr := flag.String("grpc", ":50051", "адрес gRPC-сервиса")
+	httpAddr := flag.String("http", "", "адрес HTTP-сервиса фильтра токенов; пусто - не запускать")
+	withOtel := flag.Bool("otel", false, "экспортировать трассы и метрики по OTLP/gRPC")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := []steosmorphy.Option{steosmorphy.WithAutoMerge()}
+	var serverOpts []grpc.ServerOption
+	if *withOtel {
+		shutdown, err := setupOpenTelemetry(ctx)
+		if err != nil {
+			log.Fatalf("Ошибка настройки OpenTelemetry: %v", err)
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Printf("Ошибка остановки OpenTelemetry: %v", err)
+			}
+		}()
+		opts = append(opts, telemetry.WithOpenTelemetry())
+		serverOpts = append(serverOpts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+
+	loader := steosmorphy.LoadMorphAnalyzerAsync(opts...)
+
+	listener, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatalf("Ошибка открытия адреса %s: %v", *grpcAddr, err)
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	steosmorphypb.RegisterMorphAnalyzerServer(grpcServer, server.NewGRPCServerAsync(loader))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(steosmorphypb.MorphAnalyzer_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		<-loader.Ready()
+		if err := loader.Err(); err != nil {
+			log.Fatalf("Ошибка загрузки словаря: %v", err)
+		}
+		healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		healthServer.SetServingStatus(steosmorphypb.MorphAnalyzer_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+		log.Printf("Словарь загружен")
+	}()
+
+	if *httpAddr != "" {
+		httpServer := &http.Server{
+			Addr:              *httpAddr,
+			Handler:           server.NewTokenFilterHandlerAsync(loader),
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       2 * time.Minute, // Конвейеры держат соединения открытыми между пакетами.
+		}
+		go func() {
+			log.Printf("HTTP-сервис фильтра токенов слушает %s", *httpAddr)
+			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Ошибка HTTP-сервиса: %v", err)
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			httpServer.Shutdown(context.Background())
+		}()
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Printf("Остановка gRPC-сервиса")
+		grpcServer.GracefulStop()
+	}()
+
+	log.Printf("gRPC-сервис слушает %s", *grpcAddr)
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("Ошибка gRPC-сервиса: %v", err)
+	}
+}
@@ -0,0 +1,624 @@
+// steosmorphy - консольный интерфейс морфологического анализатора.
+// Слова читаются из файлов или стандартного ввода (по словам, разделенным пробелами),
+// результат пишется в стандартный вывод в формате JSON Lines или TSV.
+//
+// Примеры:
+//
+//	echo "мама мыла раму" | steosmorphy parse
+//	steosmorphy lemmatize -format tsv words.txt
+//	steosmorphy inflect -words кот
+//	steosmorphy predict -format tsv -words нейросеть
+//	steosmorphy parse -lang en -words кошки
+//	steosmorphy lemmatize -stemmer german,arabic -words Häuser
+//	steosmorphy table -format html кошка
+//	steosmorphy bench -duration 2s
+//	steosmorphy license
+//	steosmorphy info
+//	steosmorphy selftest
+//	steosmorphy verify morph.dawg
+//	steosmorphy feedback -fixes lexicon-fixes.tsv reports.jsonl > user-dict.tsv
+//	steosmorphy dump > lexicon.tsv
+//	steosmorphy translit -scheme icao Юлия Щукина
+//	steosmorphy translit -slug "Котам нужны игрушки"
+//	steosmorphy wordforms -pos Существительное,Глагол > wordforms.txt
+//	steosmorphy hunspell -out /usr/share/postgresql/17/tsearch_data/steosmorphy
+//	steosmorphy logstash -url http://morph:8080 -source body > steosmorphy.conf
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	_ "github.com/steosofficial/steosmorphy/dict"
+	"github.com/steosofficial/steosmorphy/server"
+	"github.com/steosofficial/steosmorphy/snowball"
+)
+
+// Форматы вывода.
+const (
+	formatJSON = "json"
+	formatTSV  = "tsv"
+)
+
+// commands - поддерживаемые подкоманды и их описания.
+var commands = map[string]string{
+	"parse":     "разбор слов (несловарные слова разбираются предсказателем)",
+	"lemmatize": "леммы слов",
+	"inflect":   "все словоформы словарных слов",
+	"predict":   "разбор и словоформы по правилам предсказателя",
+	"table":     "таблица словоизменения (text, html или json)",
+	"bench":     "микробенчмарки горячего пути на текущем оборудовании",
+	"license":   "лицензия исходного лексикона словаря (JSON)",
+	"info":      "версия формата, дата сборки и размеры словаря (JSON)",
+	"selftest":  "самопроверка словаря по встроенной контрольной выборке",
+	"verify":    "сверка контрольных сумм файла словаря (по умолчанию из STEOSMORPHY_DICT_PATH)",
+	"merge":     "объединение частей словаря morph_a* из директории в файл: merge <dir> <out>",
+	"feedback":  "записи словаря (TSV) по отчетам о неверных разборах",
+	"dump":      "все словоформы словаря (TSV) или леммы (-lemmas)",
+	"translit":  "транслитерация текста (gost, icao, passport, informal) или ЧПУ-адрес (-slug)",
+	"wordforms": "словоформы и леммы (form > lemma) для Sphinx и Manticore Search",
+	"hunspell":  "экспорт словаря в Hunspell (.affix и .dict) для полнотекстового поиска PostgreSQL",
+	"logstash":  "конфигурация Logstash для лемматизации поля сервисом steosmorphy-server -http",
+}
+
+// wordResult - строка вывода в формате JSON Lines.
+type wordResult struct {
+	Word   string                `json:"word"`
+	Lemmas []string              `json:"lemmas,omitempty"`
+	Parses []*steosmorphy.Parsed `json:"parses,omitempty"`
+	Forms  []*steosmorphy.Parsed `json:"forms,omitempty"`
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	command := os.Args[1]
+	if _, ok := commands[command]; !ok {
+		fmt.Fprintf(os.Stderr, "Неизвестная команда %q\n", command)
+		usage()
+		os.Exit(2)
+	}
+	switch command {
+	case "table":
+		runTable(os.Args[2:])
+		return
+	case "bench":
+		runBench(os.Args[2:])
+		return
+	case "license":
+		runLicense()
+		return
+	case "info":
+		runInfo()
+		return
+	case "selftest":
+		runSelfTest()
+		return
+	case "verify":
+		runVerify(os.Args[2:])
+		return
+	case "merge":
+		runMerge(os.Args[2:])
+		return
+	case "feedback":
+		runFeedback(os.Args[2:])
+		return
+	case "dump":
+		runDump(os.Args[2:])
+		return
+	case "translit":
+		runTranslit(os.Args[2:])
+		return
+	case "wordforms":
+		runWordforms(os.Args[2:])
+		return
+	case "hunspell":
+		runHunspell(os.Args[2:])
+		return
+	case "logstash":
+		runLogstash(os.Args[2:])
+		return
+	}
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	format := flags.String("format", formatJSON, "формат вывода: json (JSON Lines) или tsv")
+	wordsArg := flags.Bool("words", false, "аргументы - слова, а не пути к файлам")
+	langArg := flags.String("lang", "ru", "язык граммем: ru, en или codes")
+	stemmerArg := flags.String("stemmer", "", "языки стеммеров Snowball для несловарных слов через запятую, по одному на алфавит (english, arabic, ...)")
+	_ = flags.Parse(os.Args[2:])
+
+	if *format != formatJSON && *format != formatTSV {
+		log.Fatalf("Неизвестный формат вывода %q", *format)
+	}
+	lang, err := steosmorphy.ParseLang(*langArg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := []steosmorphy.Option{steosmorphy.WithLang(lang), steosmorphy.WithAutoMerge()}
+	if *stemmerArg != "" {
+		scripts := make(map[*unicode.RangeTable]string)
+		for _, name := range strings.Split(*stemmerArg, ",") {
+			if other, ok := scripts[snowball.Script(name)]; ok {
+				log.Fatalf("Языки стеммеров %s и %s пишутся одним алфавитом", other, name)
+			}
+			scripts[snowball.Script(name)] = name
+			opt, err := snowball.Option(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			opts = append(opts, opt)
+		}
+	}
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(opts...)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	emit := newEmitter(out, *format)
+	process := func(word string) error {
+		return emit(handle(analyzer, command, word))
+	}
+
+	switch {
+	case *wordsArg:
+		for _, word := range flags.Args() {
+			if err := process(word); err != nil {
+				log.Fatalf("Ошибка вывода: %v", err)
+			}
+		}
+	case flags.NArg() == 0:
+		err = processReader(os.Stdin, process)
+	default:
+		for _, path := range flags.Args() {
+			if err = processFile(path, process); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		out.Flush()
+		log.Fatal(err)
+	}
+}
+
+// usage печатает справку по подкомандам.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Использование: steosmorphy <команда> [-format json|tsv] [-lang ru|en|codes] [-stemmer english,...] [-words] [файлы или слова...]")
+	fmt.Fprintln(os.Stderr, "Без файлов слова читаются из стандартного ввода. Команды:")
+	for _, name := range []string{"parse", "lemmatize", "inflect", "predict", "table", "bench", "license", "info", "selftest", "verify", "merge", "feedback", "dump", "translit", "wordforms", "hunspell", "logstash"} {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name])
+	}
+}
+
+// runTable печатает таблицы словоизменения слов (см. MorphAnalyzer.DeclensionTable).
+func runTable(args []string) {
+	flags := flag.NewFlagSet("table", flag.ExitOnError)
+	format := flags.String("format", "text", "формат вывода: text, html или json")
+	langArg := flags.String("lang", "ru", "язык граммем: ru, en или codes")
+	_ = flags.Parse(args)
+
+	lang, err := steosmorphy.ParseLang(*langArg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLang(lang), steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	for _, word := range flags.Args() {
+		table := analyzer.DeclensionTable(word)
+		if table == nil {
+			log.Printf("Для слова %q таблица словоизменения не строится", word)
+			continue
+		}
+		switch *format {
+		case "text":
+			err = table.WriteText(os.Stdout)
+		case "html":
+			err = table.WriteHTML(os.Stdout)
+		case "json":
+			err = table.WriteJSON(os.Stdout)
+		default:
+			log.Fatalf("Неизвестный формат вывода %q", *format)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// runBench выполняет микробенчмарки и печатает их результаты вместе со стратегией поиска,
+// выбранной при загрузке словаря.
+func runBench(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	duration := flags.Duration("duration", time.Second, "длительность каждого бенчмарка")
+	_ = flags.Parse(args)
+
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+
+	fmt.Printf("%s/%s, CPU: %d\n", runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+	fastest := steosmorphy.BenchResult{}
+	for _, r := range analyzer.MicroBenchmarks(*duration) {
+		fmt.Printf("%-24s %12d %10.1f нс/оп\n", r.Name, r.Iterations, r.NsPerOp)
+		if strings.HasPrefix(r.Name, "dawg/") && (fastest.Name == "" || r.NsPerOp < fastest.NsPerOp) {
+			fastest = r
+		}
+	}
+	fmt.Printf("Стратегия поиска, выбранная при загрузке: %s\n", analyzer.LookupStrategy())
+	fmt.Printf("Самая быстрая по замеру: %s\n", strings.TrimPrefix(fastest.Name, "dawg/"))
+}
+
+// runLicense печатает лицензию лексикона, встроенную в словарь.
+func runLicense() {
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	license := analyzer.License()
+	if license.IsZero() {
+		log.Fatal("Словарь не содержит сведений о лицензии лексикона")
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(license); err != nil {
+		log.Fatalf("Ошибка вывода: %v", err)
+	}
+}
+
+// runInfo печатает сведения о словаре из его заголовка.
+func runInfo() {
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(analyzer.Info()); err != nil {
+		log.Fatalf("Ошибка вывода: %v", err)
+	}
+}
+
+// runDump выводит содержимое словаря: словоформы лексиконом TSV (словоформа, лемма, теги),
+// который читает steosmorphy-build, или леммы по одной в строке.
+func runDump(args []string) {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	lemmas := flags.Bool("lemmas", false, "выводить только леммы")
+	_ = flags.Parse(args)
+
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	out := bufio.NewWriter(os.Stdout)
+	if *lemmas {
+		for lemma := range analyzer.Lemmas() {
+			out.WriteString(lemma + "\n")
+		}
+	} else {
+		for e := range analyzer.Words() {
+			out.WriteString(e.Word + "\t" + e.Lemma + "\t" + e.Tags + "\n")
+		}
+	}
+	if err := out.Flush(); err != nil {
+		log.Fatalf("Ошибка вывода: %v", err)
+	}
+}
+
+// runTranslit транслитерирует аргументы (или строки стандартного ввода), восстанавливает
+// по ним кириллицу (-reverse) или строит ЧПУ-адреса (-slug, см. MorphAnalyzer.Slug).
+func runTranslit(args []string) {
+	flags := flag.NewFlagSet("translit", flag.ExitOnError)
+	schemeArg := flags.String("scheme", "gost", "система транслитерации: gost, icao, passport или informal")
+	reverse := flags.Bool("reverse", false, "восстановить кириллицу по латинице")
+	slug := flags.Bool("slug", false, "строить ЧПУ-адрес из лемм слов")
+	_ = flags.Parse(args)
+
+	scheme, ok := steosmorphy.ParseTranslitScheme(*schemeArg)
+	if !ok {
+		log.Fatalf("Неизвестная система транслитерации %q", *schemeArg)
+	}
+	convert := func(text string) string {
+		if *reverse {
+			return steosmorphy.Detransliterate(text, scheme)
+		}
+		return steosmorphy.Transliterate(text, scheme)
+	}
+	if *slug {
+		analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+		if err != nil {
+			log.Fatalf("Ошибка загрузки словаря: %v", err)
+		}
+		convert = func(text string) string {
+			return analyzer.Slug(text, scheme)
+		}
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	if flags.NArg() > 0 {
+		out.WriteString(convert(strings.Join(flags.Args(), " ")) + "\n")
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			out.WriteString(convert(scanner.Text()) + "\n")
+		}
+		if err := scanner.Err(); err != nil {
+			log.Fatalf("Ошибка чтения: %v", err)
+		}
+	}
+	if err := out.Flush(); err != nil {
+		log.Fatalf("Ошибка вывода: %v", err)
+	}
+}
+
+// runWordforms печатает словоформы словаря в формате wordforms (см. MorphAnalyzer.WriteWordforms).
+func runWordforms(args []string) {
+	flags := flag.NewFlagSet("wordforms", flag.ExitOnError)
+	posArg := flags.String("pos", "", "части речи через запятую (по умолчанию все)")
+	freqPath := flags.String("freq", "", "файл частот словоформ (словоформа<TAB>частота)")
+	minFreq := flags.Uint64("min-freq", 1, "минимальная частота словоформы (с -freq)")
+	_ = flags.Parse(args)
+
+	opts := []steosmorphy.Option{steosmorphy.WithAutoMerge()}
+	var exportOpts []steosmorphy.WordformsOption
+	if *posArg != "" {
+		exportOpts = append(exportOpts, steosmorphy.WordformsPOS(strings.Split(*posArg, ",")...))
+	}
+	if *freqPath != "" {
+		f, err := os.Open(*freqPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		freq, err := steosmorphy.ReadWordFrequencies(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Ошибка чтения частот: %v", err)
+		}
+		opts = append(opts, steosmorphy.WithWordFrequencies(freq))
+		exportOpts = append(exportOpts, steosmorphy.WordformsMinFrequency(*minFreq))
+	}
+
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(opts...)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	if _, err := analyzer.WriteWordforms(os.Stdout, exportOpts...); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runHunspell записывает словарь в файлы <out>.affix и <out>.dict (см. MorphAnalyzer.WriteHunspell).
+func runHunspell(args []string) {
+	flags := flag.NewFlagSet("hunspell", flag.ExitOnError)
+	out := flags.String("out", "steosmorphy", "путь к файлам без расширения")
+	_ = flags.Parse(args)
+
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	aff, err := os.Create(*out + ".affix")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer aff.Close()
+	dic, err := os.Create(*out + ".dict")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dic.Close()
+	stats, err := analyzer.WriteHunspell(aff, dic)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := errors.Join(aff.Close(), dic.Close()); err != nil {
+		log.Fatalf("Ошибка записи: %v", err)
+	}
+	log.Printf("Лемм: %d, парадигм: %d, правил: %d, пропущено словоформ: %d", stats.Lemmas, stats.Flags, stats.Rules, stats.Skipped)
+}
+
+// runLogstash печатает эталонную конфигурацию Logstash (см. server.WriteLogstashPipeline).
+func runLogstash(args []string) {
+	flags := flag.NewFlagSet("logstash", flag.ExitOnError)
+	var cfg server.PipelineConfig
+	flags.StringVar(&cfg.FilterURL, "url", "http://localhost:8080", "адрес HTTP-сервиса фильтра токенов")
+	flags.StringVar(&cfg.SourceField, "source", "message", "поле с текстом")
+	flags.StringVar(&cfg.TargetField, "target", "", "поле для лемм (по умолчанию <source>_lemmas)")
+	flags.BoolVar(&cfg.Stopwords, "stopwords", false, "отбрасывать служебные слова")
+	flags.StringVar(&cfg.Elasticsearch, "es", "http://localhost:9200", "адрес Elasticsearch")
+	flags.StringVar(&cfg.Index, "index", "documents", "индекс документов")
+	_ = flags.Parse(args)
+
+	if err := server.WriteLogstashPipeline(os.Stdout, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runSelfTest проверяет словарь по встроенной контрольной выборке (см. MorphAnalyzer.SelfTest).
+func runSelfTest() {
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		log.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	if err := analyzer.SelfTest(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Словарь прошел самопроверку")
+}
+
+// runVerify сверяет контрольные суммы файла словаря, переданного аргументом
+// или заданного переменной окружения STEOSMORPHY_DICT_PATH.
+func runVerify(args []string) {
+	path := os.Getenv(steosmorphy.EnvDictPath)
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		log.Fatalf("Укажите файл словаря аргументом или переменной окружения %s", steosmorphy.EnvDictPath)
+	}
+	if err := steosmorphy.VerifyDict(path); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Контрольные суммы словаря совпадают")
+}
+
+// runMerge объединяет части словаря из директории в файл.
+func runMerge(args []string) {
+	if len(args) != 2 {
+		log.Fatal("Использование: steosmorphy merge <директория частей> <файл словаря>")
+	}
+	if err := steosmorphy.MergeDictParts(args[0], args[1]); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Словарь объединен в %s\n", args[1])
+}
+
+// runFeedback читает отчеты о неверных разборах (см. MorphAnalyzer.ReportMisparse)
+// и печатает записи пользовательского словаря в TSV; исправления словарных слов
+// записываются в файл -fixes для исходного лексикона компилятора.
+func runFeedback(args []string) {
+	flags := flag.NewFlagSet("feedback", flag.ExitOnError)
+	fixesPath := flags.String("fixes", "", "файл TSV для исправлений словарных слов (по умолчанию не сохраняются)")
+	_ = flags.Parse(args)
+
+	var reports []steosmorphy.MisparseReport
+	collect := func(r steosmorphy.MisparseReport) error {
+		reports = append(reports, r)
+		return nil
+	}
+	if flags.NArg() == 0 {
+		if err := steosmorphy.ReadMisparseReports(os.Stdin, collect); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for _, path := range flags.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Ошибка открытия %s: %v", path, err)
+		}
+		err = steosmorphy.ReadMisparseReports(f, collect)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	userDict, fixes := steosmorphy.MisparseFixes(reports)
+	if err := steosmorphy.WriteTSVLexicon(os.Stdout, userDict); err != nil {
+		log.Fatal(err)
+	}
+	if *fixesPath == "" {
+		if len(fixes) > 0 {
+			log.Printf("Пропущено исправлений словарных слов: %d (см. флаг -fixes)", len(fixes))
+		}
+		return
+	}
+	out, err := os.Create(*fixesPath)
+	if err != nil {
+		log.Fatalf("Ошибка создания %s: %v", *fixesPath, err)
+	}
+	if err := steosmorphy.WriteTSVLexicon(out, fixes); err != nil {
+		log.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// handle выполняет команду для одного слова.
+func handle(analyzer *steosmorphy.MorphAnalyzer, command, word string) wordResult {
+	result := wordResult{Word: word}
+	switch command {
+	case "parse":
+		result.Parses = analyzer.Parse(word)
+		if len(result.Parses) == 0 {
+			result.Parses = analyzer.ParsePredicted(word)
+		}
+	case "lemmatize":
+		result.Lemmas = analyzer.Lemmatize(word)
+	case "inflect":
+		result.Forms = analyzer.Inflect(word)
+	case "predict":
+		result.Parses = analyzer.ParsePredicted(word)
+		if len(result.Parses) > 0 {
+			result.Forms = analyzer.Predict(word, result.Parses[0].Lemma)
+		}
+	}
+	return result
+}
+
+// newEmitter возвращает функцию записи результата в выбранном формате.
+// В TSV каждая строка - одна лемма, разбор или словоформа: "слово<TAB>лемма[<TAB>форма<TAB>теги]".
+func newEmitter(w io.Writer, format string) func(wordResult) error {
+	if format == formatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return func(result wordResult) error {
+			return encoder.Encode(result)
+		}
+	}
+	return func(result wordResult) error {
+		for _, lemma := range result.Lemmas {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", result.Word, lemma); err != nil {
+				return err
+			}
+		}
+		for _, p := range result.Parses {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", result.Word, p.Lemma, p.Tags); err != nil {
+				return err
+			}
+		}
+		for _, p := range result.Forms {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Word, p.Lemma, p.Word, p.Tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// processFile обрабатывает все слова файла.
+func processFile(path string, process func(string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+	return processReader(file, process)
+}
+
+// processReader обрабатывает слова, разделенные пробельными символами.
+func processReader(r io.Reader, process func(string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		for _, word := range strings.Fields(scanner.Text()) {
+			if err := process(word); err != nil {
+				return fmt.Errorf("ошибка вывода: %w", err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения ввода: %w", err)
+	}
+	return nil
+}
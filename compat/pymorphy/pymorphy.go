@@ -0,0 +1,202 @@
+// Package pymorphy повторяет устройство API pymorphy2 поверх анализатора SteosMorphy,
+// чтобы код и тесты, написанные для pymorphy2, переносились на Go почти построчно:
+//
+//	morph = pymorphy2.MorphAnalyzer()        morph := pymorphy.New(analyzer)
+//	p = morph.parse('стали')[0]              p := morph.Parse("стали")[0]
+//	p.normal_form, p.score                   p.NormalForm, p.Score
+//	'VERB' in p.tag, p.tag.case              p.Tag.Contains("VERB"), p.Tag.Case()
+//	p.inflect({'gent', 'plur'}).word         p.Inflect("gent", "plur").Word
+//	p.lexeme, p.normalized                   p.Lexeme(), p.Normalized()
+//	p.make_agree_with_number(5)              p.MakeAgreeWithNumber(5)
+//
+// Граммемы записываются кодами OpenCorpora, как в pymorphy2 (NOUN, ADJF, gent, plur, ...;
+// см. analyzer.OpenCorporaGrammemes). Набор граммем определяется словарем SteosMorphy,
+// поэтому отдельные пометы pymorphy2 (например, indc у глаголов) могут отсутствовать,
+// а оценки Score - это нормированная уверенность разборов, а не частоты корпуса.
+package pymorphy
+
+import (
+	"slices"
+	"strings"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// MorphAnalyzer - аналог pymorphy2.MorphAnalyzer. Безопасен для одновременного
+// использования, как и анализатор, поверх которого построен.
+type MorphAnalyzer struct {
+	morph *steosmorphy.MorphAnalyzer
+}
+
+// New возвращает фасад pymorphy2 для анализатора morph.
+func New(morph *steosmorphy.MorphAnalyzer) *MorphAnalyzer {
+	return &MorphAnalyzer{morph: morph}
+}
+
+// Parse - аналог morph.parse(word): разборы слова, несловарные слова разбираются
+// звеньями разбора и предсказателем (см. analyzer.MorphAnalyzer.Analyze).
+// Оценки Score разборов в сумме дают 1.
+func (m *MorphAnalyzer) Parse(word string) []*Parse {
+	parsed, _ := m.morph.Analyze(word)
+	if len(parsed) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, p := range parsed {
+		total += p.Confidence
+	}
+	parses := make([]*Parse, len(parsed))
+	for i, p := range parsed {
+		score := 1 / float64(len(parsed))
+		if total > 0 {
+			score = p.Confidence / total
+		}
+		parses[i] = m.newParse(strings.ToLower(word), p, score)
+	}
+	return parses
+}
+
+// Tag - аналог morph.tag(word): теги разборов слова.
+func (m *MorphAnalyzer) Tag(word string) []Tag {
+	var tags []Tag
+	for _, p := range m.Parse(word) {
+		tags = append(tags, p.Tag)
+	}
+	return tags
+}
+
+// NormalForms - аналог morph.normal_forms(word): нормальные формы разборов без повторов.
+func (m *MorphAnalyzer) NormalForms(word string) []string {
+	var forms []string
+	for _, p := range m.Parse(word) {
+		if !slices.Contains(forms, p.NormalForm) {
+			forms = append(forms, p.NormalForm)
+		}
+	}
+	return forms
+}
+
+// WordIsKnown - аналог morph.word_is_known(word): слово есть в словаре.
+func (m *MorphAnalyzer) WordIsKnown(word string) bool {
+	return m.morph.IsKnown(word)
+}
+
+// Parse - аналог разбора pymorphy2 (pymorphy2.analyzer.Parse).
+type Parse struct {
+	Word       string  // Словоформа в нижнем регистре.
+	Tag        Tag     // Граммемы разбора.
+	NormalForm string  // Нормальная форма (лемма).
+	Score      float64 // Оценка разбора от 0 до 1.
+	Method     string  // Звено, разобравшее слово (аналог methods_stack): "dictionary", "predictor", ...
+
+	morph *MorphAnalyzer
+	tags  string // Теги разбора в словарном виде для поиска формы в лексеме.
+}
+
+// newParse создает разбор фасада из разбора анализатора.
+func (m *MorphAnalyzer) newParse(word string, p *steosmorphy.Parsed, score float64) *Parse {
+	canonical := p.Localize(steosmorphy.LangRussian)
+	return &Parse{
+		Word:       word,
+		Tag:        newTag(canonical),
+		NormalForm: p.Lemma,
+		Score:      score,
+		Method:     p.Method,
+		morph:      m,
+		tags:       canonical.Tags,
+	}
+}
+
+// IsKnown - аналог p.is_known: словоформа есть в словаре.
+func (p *Parse) IsKnown() bool {
+	return p.morph.morph.IsKnown(p.Word)
+}
+
+// Lexeme - аналог p.lexeme: все словоформы лексемы разбора с его оценкой.
+func (p *Parse) Lexeme() []*Parse {
+	lex := p.lexeme()
+	if lex == nil {
+		return nil
+	}
+	forms := make([]*Parse, len(lex.Forms))
+	for i, f := range lex.Forms {
+		forms[i] = p.morph.newParse(strings.ToLower(f.Word), f, p.Score)
+		forms[i].Method = p.Method
+	}
+	return forms
+}
+
+// Inflect - аналог p.inflect(required_grammemes): форма той же лексемы, содержащая
+// все граммемы required и как можно больше остальных граммем разбора
+// (Inflect("plur") сохраняет падеж). Возвращает nil, если такой формы нет.
+func (p *Parse) Inflect(required ...string) *Parse {
+	return p.closestForm(func(f *Parse) bool { return f.Tag.Contains(required...) })
+}
+
+// Normalized - аналог p.normalized: разбор нормальной формы той же лексемы
+// (именительного падежа или инфинитива, если нормальная форма совпадает с другими).
+func (p *Parse) Normalized() *Parse {
+	normal := p.closestForm(func(f *Parse) bool {
+		return f.Word == p.NormalForm && (f.Tag.Contains("nomn") || f.Tag.POS() == "INFN")
+	})
+	if normal != nil {
+		return normal
+	}
+	return p.closestForm(func(f *Parse) bool { return f.Word == p.NormalForm })
+}
+
+// MakeAgreeWithNumber - аналог p.make_agree_with_number(n): форма существительного,
+// согласованная с числом n (1 кот, 2 кота, 5 котов). Возвращает nil для других частей
+// речи и слов без подходящей формы.
+func (p *Parse) MakeAgreeWithNumber(n int) *Parse {
+	if p.Tag.POS() != "NOUN" {
+		return nil
+	}
+	word := p.morph.morph.MakeAgreeWithNumber(p.Word, n)
+	if word == "" {
+		return nil
+	}
+	return p.closestForm(func(f *Parse) bool { return f.Word == word })
+}
+
+// closestForm возвращает подходящую форму лексемы разбора, у которой больше всего
+// общих с разбором граммем, или nil.
+func (p *Parse) closestForm(match func(*Parse) bool) *Parse {
+	var best *Parse
+	bestShared := -1
+	for _, f := range p.Lexeme() {
+		if !match(f) {
+			continue
+		}
+		shared := 0
+		for _, g := range p.Tag.grammemes {
+			if f.Tag.Contains(g) {
+				shared++
+			}
+		}
+		if shared > bestShared {
+			best, bestShared = f, shared
+		}
+	}
+	return best
+}
+
+// lexeme находит лексему анализатора, к которой относится разбор.
+func (p *Parse) lexeme() *steosmorphy.Lexeme {
+	lexemes := p.morph.morph.Lexemes(p.Word)
+	var fallback *steosmorphy.Lexeme
+	for _, lex := range lexemes {
+		if lex.Lemma != p.NormalForm {
+			continue
+		}
+		if slices.ContainsFunc(lex.Forms, func(f *steosmorphy.Parsed) bool {
+			return strings.EqualFold(f.Word, p.Word) && f.Localize(steosmorphy.LangRussian).Tags == p.tags
+		}) {
+			return lex
+		}
+		if fallback == nil {
+			fallback = lex
+		}
+	}
+	return fallback
+}
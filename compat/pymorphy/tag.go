@@ -0,0 +1,112 @@
+// tag.go содержит аналог OpencorporaTag pymorphy2: граммемы разбора кодами OpenCorpora
+// с доступом по категориям и проверкой вхождения ('NOUN' in tag).
+
+package pymorphy
+
+import (
+	"slices"
+	"strings"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// Tag - аналог OpencorporaTag pymorphy2.
+type Tag struct {
+	grammemes []string // Граммемы OpenCorpora, часть речи первой.
+	formLevel []string // Граммемы словоформы (падеж, число, ...), в строке тега - после пробела.
+
+	pos, animacy, aspect, grammaticalCase, gender, mood, number, person, tense, transitivity, voice string
+}
+
+// newTag создает тег из разбора в словарном виде.
+func newTag(p *steosmorphy.Parsed) Tag {
+	t := Tag{grammemes: steosmorphy.OpenCorporaGrammemes(p.Tags)}
+	if p.PartOfSpeech != "" && len(t.grammemes) > 0 {
+		t.pos = t.grammemes[0]
+	}
+	t.animacy, t.aspect, t.grammaticalCase = code(p.Animacy), code(p.Aspect), code(p.Case)
+	t.gender, t.mood, t.number, t.person = code(p.Gender), code(p.Mood), code(p.Number), code(p.Person)
+	t.tense, t.transitivity, t.voice = code(p.Tense), code(p.Transitivity), code(p.Voice)
+
+	// Как в OpenCorpora: род существительных и местоимений и время причастий
+	// относятся к лексеме, а не к словоформе.
+	t.formLevel = []string{t.grammaticalCase, t.number, t.person, t.mood}
+	if t.pos != "NOUN" && t.pos != "NPRO" {
+		t.formLevel = append(t.formLevel, t.gender)
+	}
+	if t.pos == "VERB" || t.pos == "GRND" {
+		t.formLevel = append(t.formLevel, t.tense)
+	}
+	return t
+}
+
+// code переводит тег словаря в граммему OpenCorpora.
+func code(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	return steosmorphy.OpenCorporaGrammemes(tag)[0]
+}
+
+// POS - аналог tag.POS: часть речи (NOUN, ADJF, INFN, ...).
+func (t Tag) POS() string { return t.pos }
+
+// Animacy - аналог tag.animacy (anim, inan).
+func (t Tag) Animacy() string { return t.animacy }
+
+// Aspect - аналог tag.aspect (perf, impf).
+func (t Tag) Aspect() string { return t.aspect }
+
+// Case - аналог tag.case (nomn, gent, ...).
+func (t Tag) Case() string { return t.grammaticalCase }
+
+// Gender - аналог tag.gender (masc, femn, neut).
+func (t Tag) Gender() string { return t.gender }
+
+// Mood - аналог tag.mood (impr).
+func (t Tag) Mood() string { return t.mood }
+
+// Number - аналог tag.number (sing, plur).
+func (t Tag) Number() string { return t.number }
+
+// Person - аналог tag.person (1per, 2per, 3per).
+func (t Tag) Person() string { return t.person }
+
+// Tense - аналог tag.tense (pres, past, futr).
+func (t Tag) Tense() string { return t.tense }
+
+// Transitivity - аналог tag.transitivity (tran, intr).
+func (t Tag) Transitivity() string { return t.transitivity }
+
+// Voice - аналог tag.voice (actv, pssv).
+func (t Tag) Voice() string { return t.voice }
+
+// Grammemes - аналог tag.grammemes: все граммемы тега.
+func (t Tag) Grammemes() []string { return slices.Clone(t.grammemes) }
+
+// Contains - аналог проверки {'NOUN', 'sing'} in tag: тег содержит все граммемы.
+func (t Tag) Contains(grammemes ...string) bool {
+	for _, g := range grammemes {
+		if !slices.Contains(t.grammemes, g) {
+			return false
+		}
+	}
+	return true
+}
+
+// String - аналог str(tag): граммемы лексемы и словоформы через пробел
+// ("NOUN,anim,femn sing,nomn").
+func (t Tag) String() string {
+	var lexeme, form []string
+	for _, g := range t.grammemes {
+		if slices.Contains(t.formLevel, g) {
+			form = append(form, g)
+		} else {
+			lexeme = append(lexeme, g)
+		}
+	}
+	if len(form) == 0 {
+		return strings.Join(lexeme, ",")
+	}
+	return strings.Join(lexeme, ",") + " " + strings.Join(form, ",")
+}
@@ -0,0 +1,68 @@
+// Package dict поставляет словарь SteosMorphy. Словарь вынесен в отдельный модуль,
+// чтобы сотни мегабайт данных скачивали только те, кому нужен именно этот словарь:
+// анализатору достаточно импортировать пакет ради побочного эффекта,
+//
+//	import _ "github.com/steosofficial/steosmorphy/dict"
+//
+// после чего LoadMorphAnalyzer найдет словарь без переменных окружения.
+// Словарь поставляется частями ("morph_aa", "morph_ab", ...). Пакет ничего не пишет
+// рядом с ними: LoadMorphAnalyzer загружает словарь прямо из частей, а объединяются они
+// в DefaultDictDir при загрузке с опцией WithAutoMerge или вызовом Path, либо заранее
+// в любой файл функцией MergeDictParts. Объединенный morph.dawg рядом с частями
+// используется без объединения.
+// Со сборочным тегом steosmorphy_embed словарь встраивается в бинарник.
+package dict
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+func init() {
+	steosmorphy.RegisterDictLocator(func() (steosmorphy.DictSource, error) {
+		if embeddedDict != nil {
+			return steosmorphy.DictSource{Data: embeddedDict}, nil
+		}
+		dir, err := packageDir()
+		if err != nil {
+			return steosmorphy.DictSource{}, err
+		}
+		if path := filepath.Join(dir, steosmorphy.DictFileName); fileExists(path) {
+			return steosmorphy.DictSource{Path: path}, nil
+		}
+		return steosmorphy.DictSource{Parts: dir}, nil
+	})
+}
+
+// Path возвращает путь к объединенному файлу словаря, при необходимости объединяя части
+// в DefaultDictDir (см. MergeDictPartsToCache). Путь можно передать в
+// LoadMorphAnalyzerFromFile или в WatchDictionary.
+func Path() (string, error) {
+	dir, err := packageDir()
+	if err != nil {
+		return "", err
+	}
+	if path := filepath.Join(dir, steosmorphy.DictFileName); fileExists(path) {
+		return path, nil
+	}
+	return steosmorphy.MergeDictPartsToCache(dir)
+}
+
+// fileExists сообщает, что path - существующий обычный файл.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// packageDir возвращает директорию исходников пакета, рядом с которыми лежат части словаря.
+func packageDir() (string, error) {
+	_, currentFilePath, _, ok := runtime.Caller(0)
+	if !ok {
+		return "", errors.New("не удалось определить путь к пакету dict")
+	}
+	return filepath.Dir(currentFilePath), nil
+}
@@ -0,0 +1,18 @@
+//go:build steosmorphy_embed
+
+// embed.go встраивает словарь morph.dawg прямо в исполняемый файл.
+// Сборка с тегом steosmorphy_embed дает один статический бинарник, которому не нужны
+// внешние файлы словаря, объединение частей и переменные окружения:
+//
+//	cat dict/morph_a* > dict/morph.dawg
+//	go build -tags steosmorphy_embed ./...
+package dict
+
+import (
+	_ "embed"
+)
+
+// embeddedDict - содержимое встроенного словаря.
+//
+//go:embed morph.dawg
+var embeddedDict []byte
@@ -0,0 +1,6 @@
+//go:build !steosmorphy_embed
+
+package dict
+
+// embeddedDict пуст в обычной сборке: словарь объединяется из частей на диске.
+var embeddedDict []byte
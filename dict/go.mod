@@ -0,0 +1,13 @@
+module github.com/steosofficial/steosmorphy/dict
+
+go 1.24.2
+
+require github.com/steosofficial/steosmorphy v0.1.0
+
+require (
+	github.com/edsrzf/mmap-go v1.2.0 // indirect
+	github.com/fsnotify/fsnotify v1.9.0 // indirect
+	github.com/klauspost/compress v1.18.5 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+)
@@ -2,6 +2,11 @@ module github.com/steosofficial/steosmorphy
 
 go 1.24.2
 
-require github.com/edsrzf/mmap-go v1.2.0
-
-require golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
+require (
+	github.com/blevesearch/snowballstem v0.9.0
+	github.com/edsrzf/mmap-go v1.2.0
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/klauspost/compress v1.18.5
+	golang.org/x/sys v0.30.0
+	golang.org/x/text v0.22.0
+)
@@ -0,0 +1,207 @@
+// Package jobs содержит выполнение многочасовых заданий по разбору корпусов с контрольными
+// точками: прогресс (смещение во входном файле и длина результата) периодически
+// сохраняется на диск, и после сбоя задание продолжается с последней контрольной точки.
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// DefaultInterval - количество слов между контрольными точками по умолчанию.
+const DefaultInterval = 10000
+
+// Checkpoint - сохраненный прогресс задания.
+type Checkpoint struct {
+	InputOffset  int64 `json:"input_offset"`  // Смещение во входном файле, с которого продолжается чтение.
+	OutputOffset int64 `json:"output_offset"` // Длина результата, записанного к этому моменту.
+	Words        int64 `json:"words"`         // Количество обработанных слов.
+	Done         bool  `json:"done"`          // Задание завершено.
+}
+
+// Record - строка результата в формате JSON Lines.
+type Record struct {
+	Word   string                `json:"word"`
+	Offset int64                 `json:"offset"` // Смещение во входном файле после слова.
+	Parses []*steosmorphy.Parsed `json:"parses"`
+}
+
+// Job описывает задание: разбор всех слов входного файла с записью результата в JSON Lines.
+type Job struct {
+	Input      string // Путь к входному тексту.
+	Output     string // Путь к файлу результата.
+	Checkpoint string // Путь к файлу контрольной точки; по умолчанию Output + ".checkpoint".
+	Interval   int    // Количество слов между контрольными точками; по умолчанию DefaultInterval.
+
+	// OnCheckpoint, если задан, вызывается после сохранения каждой контрольной точки.
+	OnCheckpoint func(Checkpoint)
+}
+
+// checkpointPath возвращает путь к файлу контрольной точки.
+func (j *Job) checkpointPath() string {
+	if j.Checkpoint != "" {
+		return j.Checkpoint
+	}
+	return j.Output + ".checkpoint"
+}
+
+// Run выполняет задание или продолжает его с последней контрольной точки.
+// Результат, записанный после контрольной точки (например, до сбоя), отбрасывается
+// и формируется заново. Для завершенного задания Run ничего не делает.
+// При отмене ctx сохраняет контрольную точку и возвращает ctx.Err().
+func (j *Job) Run(ctx context.Context, analyzer *steosmorphy.MorphAnalyzer) error {
+	cp, err := ReadCheckpoint(j.checkpointPath())
+	if err != nil {
+		return err
+	}
+	if cp.Done {
+		return nil
+	}
+
+	input, err := os.Open(j.Input)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия входного файла: %w", err)
+	}
+	defer input.Close()
+	if _, err := input.Seek(cp.InputOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("ошибка перехода к контрольной точке: %w", err)
+	}
+
+	output, err := os.OpenFile(j.Output, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла результата: %w", err)
+	}
+	defer output.Close()
+	if err := output.Truncate(cp.OutputOffset); err != nil {
+		return fmt.Errorf("ошибка отката результата к контрольной точке: %w", err)
+	}
+	if _, err := output.Seek(cp.OutputOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("ошибка отката результата к контрольной точке: %w", err)
+	}
+
+	interval := j.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	counter := &countingWriter{w: output, n: cp.OutputOffset}
+	buffered := bufio.NewWriter(counter)
+	encoder := json.NewEncoder(buffered)
+	encoder.SetEscapeHTML(false)
+
+	// save сбрасывает результат на диск и только после этого записывает контрольную точку,
+	// чтобы она никогда не ссылалась на незаписанные данные.
+	save := func() error {
+		if err := buffered.Flush(); err != nil {
+			return fmt.Errorf("ошибка записи результата: %w", err)
+		}
+		if err := output.Sync(); err != nil {
+			return fmt.Errorf("ошибка записи результата: %w", err)
+		}
+		cp.OutputOffset = counter.n
+		if err := writeCheckpoint(j.checkpointPath(), cp); err != nil {
+			return err
+		}
+		if j.OnCheckpoint != nil {
+			j.OnCheckpoint(cp)
+		}
+		return nil
+	}
+
+	base := cp.InputOffset
+	sinceSave := 0
+	err = analyzer.AnalyzeWordStream(input, func(word string, end int64, parses []*steosmorphy.Parsed) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if parses == nil {
+			parses = []*steosmorphy.Parsed{}
+		}
+		if err := encoder.Encode(Record{Word: word, Offset: base + end, Parses: parses}); err != nil {
+			return fmt.Errorf("ошибка записи результата: %w", err)
+		}
+		cp.InputOffset = base + end
+		cp.Words++
+		sinceSave++
+		if sinceSave < interval {
+			return nil
+		}
+		sinceSave = 0
+		return save()
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			if saveErr := save(); saveErr != nil {
+				return saveErr
+			}
+		}
+		return err
+	}
+
+	cp.Done = true
+	return save()
+}
+
+// ReadCheckpoint читает контрольную точку. Отсутствие файла означает задание,
+// которое еще не начиналось.
+func ReadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, fmt.Errorf("ошибка чтения контрольной точки: %w", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("ошибка разбора контрольной точки: %w", err)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint атомарно заменяет файл контрольной точки: пишет во временный файл
+// и переименовывает его, чтобы сбой во время записи не повредил предыдущую точку.
+func writeCheckpoint(path string, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации контрольной точки: %w", err)
+	}
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("ошибка записи контрольной точки: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("ошибка записи контрольной точки: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("ошибка записи контрольной точки: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("ошибка записи контрольной точки: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("ошибка записи контрольной точки: %w", err)
+	}
+	return nil
+}
+
+// countingWriter считает байты, записанные в w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
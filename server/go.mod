@@ -0,0 +1,21 @@
+module github.com/steosofficial/steosmorphy/server
+
+go 1.24.2
+
+require (
+	github.com/steosofficial/steosmorphy v0.1.0
+	github.com/steosofficial/steosmorphy/api v0.1.0
+	github.com/steosofficial/steosmorphy/dict v0.1.0
+	google.golang.org/grpc v1.72.0
+)
+
+require (
+	github.com/edsrzf/mmap-go v1.2.0 // indirect
+	github.com/fsnotify/fsnotify v1.9.0 // indirect
+	github.com/klauspost/compress v1.18.5 // indirect
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
@@ -0,0 +1,111 @@
+// Package server содержит сетевые интерфейсы морфологического анализатора.
+//
+// grpc.go реализует gRPC-сервис MorphAnalyzer (см. api/steosmorphypb/steosmorphy.proto),
+// tokenfilter.go - HTTP-сервис фильтра токенов для конвейеров индексации.
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/api/steosmorphypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer - реализация gRPC-сервиса поверх анализатора.
+// Анализатор потокобезопасен, поэтому сервер обслуживает запросы и потоки параллельно.
+type GRPCServer struct {
+	steosmorphypb.UnimplementedMorphAnalyzerServer
+	analyzer *steosmorphy.MorphAnalyzer
+	loader   *steosmorphy.AsyncAnalyzer // Анализатор, загружаемый в фоне (nil, если задан analyzer).
+}
+
+// NewGRPCServer создает gRPC-сервис. Зарегистрируйте его на grpc.Server:
+//
+//	steosmorphypb.RegisterMorphAnalyzerServer(grpcServer, server.NewGRPCServer(analyzer))
+func NewGRPCServer(analyzer *steosmorphy.MorphAnalyzer) *GRPCServer {
+	return &GRPCServer{analyzer: analyzer}
+}
+
+// NewGRPCServerAsync создает gRPC-сервис поверх анализатора, загружаемого в фоне
+// (см. LoadMorphAnalyzerAsync): пока словарь загружается, запросы завершаются
+// с кодом Unavailable, и клиенты могут повторить их позже.
+func NewGRPCServerAsync(loader *steosmorphy.AsyncAnalyzer) *GRPCServer {
+	return &GRPCServer{loader: loader}
+}
+
+// morph возвращает анализатор или ошибку Unavailable, если он еще не загружен.
+func (s *GRPCServer) morph() (*steosmorphy.MorphAnalyzer, error) {
+	if s.loader == nil {
+		return s.analyzer, nil
+	}
+	analyzer, err := s.loader.Analyzer()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return analyzer, nil
+}
+
+// Parse разбирает одно слово; несловарные слова разбираются предсказателем.
+func (s *GRPCServer) Parse(_ context.Context, req *steosmorphypb.ParseRequest) (*steosmorphypb.ParseResponse, error) {
+	if req.GetWord() == "" {
+		return nil, status.Error(codes.InvalidArgument, "пустое слово")
+	}
+	analyzer, err := s.morph()
+	if err != nil {
+		return nil, err
+	}
+	return &steosmorphypb.ParseResponse{Word: req.GetWord(), Parses: steosmorphypb.FromParsedList(parse(analyzer, req.GetWord()))}, nil
+}
+
+// Inflect возвращает все словоформы словарного слова.
+func (s *GRPCServer) Inflect(_ context.Context, req *steosmorphypb.InflectRequest) (*steosmorphypb.InflectResponse, error) {
+	if req.GetWord() == "" {
+		return nil, status.Error(codes.InvalidArgument, "пустое слово")
+	}
+	analyzer, err := s.morph()
+	if err != nil {
+		return nil, err
+	}
+	return &steosmorphypb.InflectResponse{Word: req.GetWord(), Forms: steosmorphypb.FromParsedList(analyzer.Inflect(req.GetWord()))}, nil
+}
+
+// AnalyzeStream разбирает поток слов: на каждый запрос отправляется ответ в том же порядке.
+// Поток завершается, когда клиент закрывает свою сторону.
+func (s *GRPCServer) AnalyzeStream(stream steosmorphypb.MorphAnalyzer_AnalyzeStreamServer) error {
+	analyzer, err := s.morph()
+	if err != nil {
+		return err
+	}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &steosmorphypb.AnalyzeResponse{Word: req.GetWord()}
+		if req.GetWithForms() {
+			parses, forms := analyzer.Analyze(req.GetWord())
+			resp.Parses, resp.Forms = steosmorphypb.FromParsedList(parses), steosmorphypb.FromParsedList(forms)
+		} else {
+			resp.Parses = steosmorphypb.FromParsedList(parse(analyzer, req.GetWord()))
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// parse возвращает словарные разборы слова, а для несловарного - предсказанные.
+func parse(analyzer *steosmorphy.MorphAnalyzer, word string) []*steosmorphy.Parsed {
+	if parses := analyzer.Parse(word); len(parses) > 0 {
+		return parses
+	}
+	return analyzer.ParsePredicted(word)
+}
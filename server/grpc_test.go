@@ -0,0 +1,127 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/api/steosmorphypb"
+	_ "github.com/steosofficial/steosmorphy/dict"
+	"github.com/steosofficial/steosmorphy/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var analyzer *steosmorphy.MorphAnalyzer
+
+// TestMain загружает словарь один раз для всех тестов сервиса.
+func TestMain(m *testing.M) {
+	var err error
+	analyzer, err = steosmorphy.LoadMorphAnalyzer()
+	if err != nil {
+		log.Fatalf("Не удалось загрузить анализатор для тестов: %v", err)
+	}
+	os.Exit(m.Run())
+}
+
+// newGRPCClient поднимает gRPC-сервис в памяти и возвращает клиента к нему.
+func newGRPCClient(t *testing.T) steosmorphypb.MorphAnalyzerClient {
+	t.Helper()
+	return newGRPCClientFor(t, server.NewGRPCServer(analyzer))
+}
+
+// newGRPCClientFor поднимает в памяти gRPC-сервис srv и возвращает клиента к нему.
+func newGRPCClientFor(t *testing.T, srv *server.GRPCServer) steosmorphypb.MorphAnalyzerClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	steosmorphypb.RegisterMorphAnalyzerServer(grpcServer, srv)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Не удалось создать клиента: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return steosmorphypb.NewMorphAnalyzerClient(conn)
+}
+
+// TestGRPCServer проверяет унарные методы и потоковый разбор gRPC-сервиса.
+func TestGRPCServer(t *testing.T) {
+	client := newGRPCClient(t)
+	ctx := context.Background()
+
+	parsed, err := client.Parse(ctx, &steosmorphypb.ParseRequest{Word: "нейросети"})
+	if err != nil {
+		t.Fatalf("Ошибка Parse: %v", err)
+	}
+	if len(parsed.Parses) == 0 || parsed.Parses[0].Lemma != "нейросеть" {
+		t.Errorf("Ожидали предсказанную лемму 'нейросеть', получили %v", parsed.Parses)
+	}
+
+	inflected, err := client.Inflect(ctx, &steosmorphypb.InflectRequest{Word: "кот"})
+	if err != nil || len(inflected.Forms) < 10 {
+		t.Errorf("Ожидали формы слова 'кот', получили %d (ошибка: %v)", len(inflected.GetForms()), err)
+	}
+
+	if _, err := client.Parse(ctx, &steosmorphypb.ParseRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Для пустого слова ожидали InvalidArgument, получили %v", err)
+	}
+
+	stream, err := client.AnalyzeStream(ctx)
+	if err != nil {
+		t.Fatalf("Ошибка открытия потока: %v", err)
+	}
+	words := []string{"мама", "мыла", "раму"}
+	for _, word := range words {
+		if err := stream.Send(&steosmorphypb.AnalyzeRequest{Word: word, WithForms: word == "раму"}); err != nil {
+			t.Fatalf("Ошибка отправки: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			if i != len(words) {
+				t.Errorf("Ожидали %d ответов, получили %d", len(words), i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("Ошибка получения: %v", err)
+		}
+		if resp.Word != words[i] || len(resp.Parses) == 0 {
+			t.Errorf("Ответ %d: ожидали разборы '%s', получили %+v", i, words[i], resp)
+		}
+		if hasForms := len(resp.Forms) > 0; hasForms != (resp.Word == "раму") {
+			t.Errorf("Ответ %d: словоформы должны быть только по запросу", i)
+		}
+	}
+}
+
+// TestGRPCServerAsync проверяет, что сервис поверх фоновой загрузки отвечает Unavailable,
+// если словарь не загрузился, а не падает.
+func TestGRPCServerAsync(t *testing.T) {
+	loader := steosmorphy.LoadMorphAnalyzerFromFileAsync(filepath.Join(t.TempDir(), "missing.dawg"))
+	<-loader.Ready()
+	client := newGRPCClientFor(t, server.NewGRPCServerAsync(loader))
+	if _, err := client.Parse(context.Background(), &steosmorphypb.ParseRequest{Word: "кот"}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Без словаря ожидали Unavailable, получили %v", err)
+	}
+}
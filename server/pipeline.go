@@ -0,0 +1,90 @@
+// pipeline.go содержит генератор эталонной конфигурации Logstash, которая лемматизирует
+// поле документа через HTTP-сервис фильтра токенов (см. TokenFilterHandler) и отправляет
+// документ в Elasticsearch.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// PipelineConfig - параметры конфигурации Logstash (см. WriteLogstashPipeline).
+// Незаданные поля получают значения по умолчанию.
+type PipelineConfig struct {
+	FilterURL     string // Адрес сервиса фильтра токенов (по умолчанию http://localhost:8080).
+	SourceField   string // Поле с текстом (по умолчанию message).
+	TargetField   string // Поле для лемм через пробел (по умолчанию <SourceField>_lemmas).
+	Stopwords     bool   // Отбрасывать служебные слова.
+	Elasticsearch string // Адрес Elasticsearch (по умолчанию http://localhost:9200).
+	Index         string // Индекс документов (по умолчанию documents).
+}
+
+// logstashPipeline - шаблон конфигурации Logstash.
+var logstashPipeline = template.Must(template.New("logstash").Parse(`# Конфигурация Logstash: лемматизация поля {{.SourceField}} сервисом steosmorphy-server -http.
+# Создана командой "steosmorphy logstash". Поле {{.TargetField}} индексируйте
+# анализатором whitespace: "mappings": {"properties": {"{{.TargetField}}": {"type": "text", "analyzer": "whitespace"}}}.
+
+input {
+  # Замените на свой источник документов.
+  beats {
+    port => 5044
+  }
+}
+
+filter {
+  if [{{.SourceField}}] {
+    http {
+      url => "{{.FilterURL}}/lemmatize{{if .Stopwords}}?stopwords=true{{end}}"
+      verb => "POST"
+      body_format => "json"
+      body => { "text" => "%{[{{.SourceField}}]}" }
+      target_body => "[@metadata][steosmorphy]"
+      keepalive => true
+      automatic_retries => 3
+    }
+    mutate {
+      copy => { "[@metadata][steosmorphy][text]" => "[{{.TargetField}}]" }
+    }
+  }
+}
+
+output {
+  elasticsearch {
+    hosts => ["{{.Elasticsearch}}"]
+    index => "{{.Index}}"
+  }
+}
+`))
+
+// WriteLogstashPipeline пишет в w конфигурацию Logstash, которая для каждого документа
+// вызывает /lemmatize сервиса фильтра токенов и сохраняет леммы поля SourceField в TargetField.
+func WriteLogstashPipeline(w io.Writer, cfg PipelineConfig) error {
+	if cfg.FilterURL == "" {
+		cfg.FilterURL = "http://localhost:8080"
+	}
+	cfg.FilterURL = strings.TrimSuffix(cfg.FilterURL, "/")
+	if cfg.SourceField == "" {
+		cfg.SourceField = "message"
+	}
+	if cfg.TargetField == "" {
+		cfg.TargetField = cfg.SourceField + "_lemmas"
+	}
+	if cfg.Elasticsearch == "" {
+		cfg.Elasticsearch = "http://localhost:9200"
+	}
+	if cfg.Index == "" {
+		cfg.Index = "documents"
+	}
+	for _, value := range []string{cfg.FilterURL, cfg.SourceField, cfg.TargetField, cfg.Elasticsearch, cfg.Index} {
+		if strings.ContainsAny(value, "\"[]\n") {
+			return fmt.Errorf("недопустимое значение %q в конфигурации Logstash", value)
+		}
+	}
+	if err := logstashPipeline.Execute(w, cfg); err != nil {
+		return fmt.Errorf("ошибка записи конфигурации Logstash: %w", err)
+	}
+	return nil
+}
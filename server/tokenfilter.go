@@ -0,0 +1,169 @@
+// tokenfilter.go реализует HTTP-сервис фильтра токенов для конвейеров индексации
+// (Logstash, ingest-процессы Elasticsearch): текст разбивается на токены и приводится
+// к леммам. Запрос и ответ - JSON Lines, по строке на документ, поэтому один запрос
+// может нести пакет документов, а одиночный документ - обычный JSON-объект.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// Ограничения сервиса фильтра токенов.
+const (
+	maxTokenFilterLine  = 1 << 20 // Максимальная длина строки запроса (документа), байт.
+	tokenFilterBatchLen = 100     // Ответ отправляется клиенту пакетами по столько строк.
+)
+
+// TokenFilterRequest - строка запроса к фильтру токенов.
+type TokenFilterRequest struct {
+	ID   json.RawMessage `json:"id,omitempty"` // Идентификатор документа, возвращается в ответе как есть.
+	Text string          `json:"text"`
+}
+
+// TokenFilterResponse - строка ответа фильтра токенов, по одной на строку запроса.
+type TokenFilterResponse struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Tokens []FilterToken   `json:"tokens"`
+	Text   string          `json:"text"`            // Токены через пробел - для поля документа с анализатором whitespace.
+	Error  string          `json:"error,omitempty"` // Ошибка разбора строки запроса; остальные строки обрабатываются.
+}
+
+// FilterToken - токен в формате ответа _analyze Elasticsearch.
+type FilterToken struct {
+	Token       string `json:"token"`
+	StartOffset int    `json:"start_offset"` // Байтовое смещение в тексте документа.
+	EndOffset   int    `json:"end_offset"`
+	Type        string `json:"type"` // "word" или "number".
+	Position    int    `json:"position"`
+}
+
+// TokenFilterHandler - HTTP-сервис фильтра токенов:
+//
+//	POST /tokenize   - токены текста как есть;
+//	POST /lemmatize  - леммы слов текста.
+//
+// Тело запроса - JSON Lines с TokenFilterRequest, ответ - JSON Lines с TokenFilterResponse
+// в том же порядке. Параметр stopwords=true отбрасывает служебные слова (см. steosmorphy.DropStopwords).
+// Соединения переиспользуются (keep-alive HTTP/1.1), поэтому конвейер может отправлять
+// документы по одному без затрат на установку соединения.
+type TokenFilterHandler struct {
+	analyzer *steosmorphy.MorphAnalyzer
+	loader   *steosmorphy.AsyncAnalyzer // Анализатор, загружаемый в фоне (nil, если задан analyzer).
+	mux      *http.ServeMux
+}
+
+// NewTokenFilterHandler создает HTTP-сервис фильтра токенов:
+//
+//	http.ListenAndServe(":8080", server.NewTokenFilterHandler(analyzer))
+func NewTokenFilterHandler(analyzer *steosmorphy.MorphAnalyzer) *TokenFilterHandler {
+	return newTokenFilterHandler(&TokenFilterHandler{analyzer: analyzer})
+}
+
+// NewTokenFilterHandlerAsync создает HTTP-сервис фильтра токенов поверх анализатора,
+// загружаемого в фоне: пока словарь загружается, запросы завершаются с кодом 503.
+func NewTokenFilterHandlerAsync(loader *steosmorphy.AsyncAnalyzer) *TokenFilterHandler {
+	return newTokenFilterHandler(&TokenFilterHandler{loader: loader})
+}
+
+// newTokenFilterHandler регистрирует маршруты сервиса.
+func newTokenFilterHandler(h *TokenFilterHandler) *TokenFilterHandler {
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("POST /tokenize", func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, false)
+	})
+	h.mux.HandleFunc("POST /lemmatize", func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, true)
+	})
+	return h
+}
+
+// ServeHTTP реализует http.Handler.
+func (h *TokenFilterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// morph возвращает анализатор или ошибку, если он еще не загружен.
+func (h *TokenFilterHandler) morph() (*steosmorphy.MorphAnalyzer, error) {
+	if h.loader == nil {
+		return h.analyzer, nil
+	}
+	return h.loader.Analyzer()
+}
+
+// serve обрабатывает строки запроса и пишет ответ пакетами по tokenFilterBatchLen строк.
+func (h *TokenFilterHandler) serve(w http.ResponseWriter, r *http.Request, lemmatize bool) {
+	analyzer, err := h.morph()
+	if err != nil {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	var opts []steosmorphy.TextOption
+	if r.URL.Query().Get("stopwords") == "true" {
+		opts = append(opts, steosmorphy.DropStopwords())
+	}
+	// Клиент может читать ответ, еще отправляя запрос; без полного дуплекса HTTP/1.1
+	// сервер не читает тело после начала ответа.
+	_ = http.NewResponseController(w).EnableFullDuplex()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTokenFilterLine)
+	lines := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := enc.Encode(filterLine(analyzer, line, lemmatize, opts)); err != nil {
+			return // Клиент закрыл соединение.
+		}
+		if lines++; lines%tokenFilterBatchLen == 0 {
+			if out.Flush() != nil {
+				return
+			}
+			http.NewResponseController(w).Flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		enc.Encode(TokenFilterResponse{Error: fmt.Sprintf("ошибка чтения запроса: %v", err)})
+	}
+	out.Flush()
+}
+
+// filterLine разбирает строку запроса и возвращает строку ответа.
+func filterLine(analyzer *steosmorphy.MorphAnalyzer, line []byte, lemmatize bool, opts []steosmorphy.TextOption) TokenFilterResponse {
+	var req TokenFilterRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return TokenFilterResponse{Error: fmt.Sprintf("ошибка разбора строки запроса: %v", err)}
+	}
+	tokens := analyzer.LemmatizeText(req.Text, opts...)
+	resp := TokenFilterResponse{ID: req.ID, Tokens: make([]FilterToken, 0, len(tokens))}
+	terms := make([]string, 0, len(tokens))
+	for i, tok := range tokens {
+		term := tok.Text
+		if lemmatize {
+			term = tok.Lemma
+		}
+		resp.Tokens = append(resp.Tokens, FilterToken{
+			Token:       term,
+			StartOffset: tok.Start,
+			EndOffset:   tok.End,
+			Type:        tok.Kind.String(),
+			Position:    i,
+		})
+		terms = append(terms, term)
+	}
+	resp.Text = strings.Join(terms, " ")
+	return resp
+}
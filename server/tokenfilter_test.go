@@ -0,0 +1,115 @@
+package server_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/server"
+)
+
+// postLines отправляет строки JSON Lines на адрес url и возвращает строки ответа.
+func postLines(t *testing.T, client *http.Client, url, body string) []server.TokenFilterResponse {
+	t.Helper()
+	resp, err := client.Post(url, "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Код ответа %d; ожидали 200", resp.StatusCode)
+	}
+	var lines []server.TokenFilterResponse
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line server.TokenFilterResponse
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Ошибка разбора ответа %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// TestTokenFilterHandler проверяет лемматизацию пакета документов, ошибку в строке
+// запроса и токенизацию без лемматизации.
+func TestTokenFilterHandler(t *testing.T) {
+	srv := httptest.NewServer(server.NewTokenFilterHandler(analyzer))
+	defer srv.Close()
+	client := srv.Client()
+
+	body := `{"id": 1, "text": "Кошки ловили рыбу"}
+не JSON
+
+{"id": "doc-2", "text": "Кот и пёс"}
+`
+	lines := postLines(t, client, srv.URL+"/lemmatize?stopwords=true", body)
+	if len(lines) != 3 {
+		t.Fatalf("Получили %d строк ответа; ожидали 3", len(lines))
+	}
+	if string(lines[0].ID) != "1" || lines[0].Text != "кошка ловить рыба" {
+		t.Errorf("Строка 1: id %s, текст %q; ожидали 1, \"кошка ловить рыба\"", lines[0].ID, lines[0].Text)
+	}
+	if tok := lines[0].Tokens[1]; tok.Token != "ловить" || tok.StartOffset != 11 || tok.EndOffset != 23 || tok.Position != 1 || tok.Type != "word" {
+		t.Errorf("Токен 2 строки 1: %+v", tok)
+	}
+	if lines[1].Error == "" {
+		t.Error("Строка 2 (не JSON): нет ошибки")
+	}
+	if string(lines[2].ID) != `"doc-2"` || lines[2].Text != "кот пёс" {
+		t.Errorf("Строка 3: id %s, текст %q; ожидали \"doc-2\", \"кот пёс\"", lines[2].ID, lines[2].Text)
+	}
+
+	// Второй запрос идет по тому же соединению (keep-alive).
+	lines = postLines(t, client, srv.URL+"/tokenize", `{"text": "Кошки ловили мышей"}`)
+	if len(lines) != 1 || lines[0].Text != "Кошки ловили мышей" {
+		t.Errorf("tokenize: %+v; ожидали токены как есть", lines)
+	}
+
+	resp, err := client.Get(srv.URL + "/lemmatize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /lemmatize: код %d; ожидали 405", resp.StatusCode)
+	}
+}
+
+// TestTokenFilterHandlerAsync проверяет код 503, если словарь не загрузился.
+func TestTokenFilterHandlerAsync(t *testing.T) {
+	loader := steosmorphy.LoadMorphAnalyzerFromFileAsync(filepath.Join(t.TempDir(), "missing.dawg"))
+	<-loader.Ready()
+	srv := httptest.NewServer(server.NewTokenFilterHandlerAsync(loader))
+	defer srv.Close()
+	resp, err := srv.Client().Post(srv.URL+"/lemmatize", "application/x-ndjson", strings.NewReader(`{"text": "кот"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Без словаря код %d; ожидали 503", resp.StatusCode)
+	}
+}
+
+// TestWriteLogstashPipeline проверяет подстановку параметров в конфигурацию Logstash.
+func TestWriteLogstashPipeline(t *testing.T) {
+	var b strings.Builder
+	err := server.WriteLogstashPipeline(&b, server.PipelineConfig{FilterURL: "http://morph:8080/", SourceField: "body", Stopwords: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`url => "http://morph:8080/lemmatize?stopwords=true"`, `"%{[body]}"`, `=> "[body_lemmas]"`, `index => "documents"`} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("В конфигурации нет %s:\n%s", want, b.String())
+		}
+	}
+	if err := server.WriteLogstashPipeline(&b, server.PipelineConfig{SourceField: `a"]`}); err == nil {
+		t.Error("Недопустимое имя поля не вернуло ошибку")
+	}
+}
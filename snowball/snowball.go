@@ -0,0 +1,123 @@
+// Package snowball предоставляет стеммеры Snowball (https://snowballstem.org) для
+// несловарных слов языков, у которых нет словаря SteosMorphy (см. analyzer.WithFallbackStemmer):
+//
+//	stemmer, err := snowball.New("english")
+//	...
+//	morph, err := analyzer.LoadMorphAnalyzer(analyzer.WithFallbackStemmer(snowball.Script("english"), stemmer))
+//
+// Для английского слова "running" лемматизация вернет основу "run" вместо русской парадигмы
+// предсказателя. Option собирает ту же опцию по названию языка.
+package snowball
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	snowballRuntime "github.com/blevesearch/snowballstem"
+	"github.com/blevesearch/snowballstem/arabic"
+	"github.com/blevesearch/snowballstem/danish"
+	"github.com/blevesearch/snowballstem/dutch"
+	"github.com/blevesearch/snowballstem/english"
+	"github.com/blevesearch/snowballstem/finnish"
+	"github.com/blevesearch/snowballstem/french"
+	"github.com/blevesearch/snowballstem/german"
+	"github.com/blevesearch/snowballstem/hungarian"
+	"github.com/blevesearch/snowballstem/irish"
+	"github.com/blevesearch/snowballstem/italian"
+	"github.com/blevesearch/snowballstem/norwegian"
+	"github.com/blevesearch/snowballstem/portuguese"
+	"github.com/blevesearch/snowballstem/romanian"
+	"github.com/blevesearch/snowballstem/russian"
+	"github.com/blevesearch/snowballstem/spanish"
+	"github.com/blevesearch/snowballstem/swedish"
+	"github.com/blevesearch/snowballstem/tamil"
+	"github.com/blevesearch/snowballstem/turkish"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// stemmers - алгоритмы Snowball по названиям языков.
+var stemmers = map[string]func(*snowballRuntime.Env) bool{
+	"arabic":     arabic.Stem,
+	"danish":     danish.Stem,
+	"dutch":      dutch.Stem,
+	"english":    english.Stem,
+	"finnish":    finnish.Stem,
+	"french":     french.Stem,
+	"german":     german.Stem,
+	"hungarian":  hungarian.Stem,
+	"irish":      irish.Stem,
+	"italian":    italian.Stem,
+	"norwegian":  norwegian.Stem,
+	"portuguese": portuguese.Stem,
+	"romanian":   romanian.Stem,
+	"russian":    russian.Stem,
+	"spanish":    spanish.Stem,
+	"swedish":    swedish.Stem,
+	"tamil":      tamil.Stem,
+	"turkish":    turkish.Stem,
+}
+
+// scripts - алфавиты языков, которые пишутся не латиницей.
+var scripts = map[string]*unicode.RangeTable{
+	"arabic":  unicode.Arabic,
+	"russian": unicode.Cyrillic,
+	"tamil":   unicode.Tamil,
+}
+
+// Stemmer - стеммер Snowball одного языка. Безопасен для одновременного использования.
+type Stemmer struct {
+	lang string
+	stem func(*snowballRuntime.Env) bool
+}
+
+// New возвращает стеммер языка lang ("english", "german", ...; см. Languages).
+func New(lang string) (*Stemmer, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	stem, ok := stemmers[lang]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный язык стеммера Snowball %q (доступны: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	return &Stemmer{lang: lang, stem: stem}, nil
+}
+
+// Language возвращает язык стеммера.
+func (s *Stemmer) Language() string { return s.lang }
+
+// Stem возвращает основу слова в нижнем регистре.
+func (s *Stemmer) Stem(word string) string {
+	env := snowballRuntime.NewEnv(word)
+	s.stem(env)
+	return env.Current()
+}
+
+// Languages возвращает названия поддерживаемых языков по алфавиту.
+func Languages() []string {
+	langs := make([]string, 0, len(stemmers))
+	for lang := range stemmers {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Script возвращает алфавит языка lang: unicode.Arabic, unicode.Cyrillic или unicode.Tamil
+// для арабского, русского и тамильского, unicode.Latin для остальных.
+func Script(lang string) *unicode.RangeTable {
+	if script, ok := scripts[strings.ToLower(strings.TrimSpace(lang))]; ok {
+		return script
+	}
+	return unicode.Latin
+}
+
+// Option возвращает опцию анализатора, которая разбирает несловарные слова алфавита
+// языка lang стеммером этого языка (см. analyzer.WithFallbackStemmer).
+func Option(lang string) (steosmorphy.Option, error) {
+	stemmer, err := New(lang)
+	if err != nil {
+		return nil, err
+	}
+	return steosmorphy.WithFallbackStemmer(Script(lang), stemmer), nil
+}
@@ -0,0 +1,166 @@
+// Package telemetry подключает анализатор к OpenTelemetry: пакетные операции (ParseList,
+// InflectList и их варианты) записываются спанами, а каждый разбор слова - в метрики,
+// по которым считаются скорость разбора (слов в секунду) и доля несловарных слов.
+// Пакет вынесен в отдельный модуль, чтобы ядро не зависело от OpenTelemetry.
+//
+//	analyzer, err := steosmorphy.LoadMorphAnalyzer(telemetry.WithOpenTelemetry())
+//
+// Метрики:
+//   - steosmorphy.words - счетчик разобранных слов с атрибутами steosmorphy.op (операция,
+//     см. steosmorphy.OpParse), steosmorphy.method (разобравшее слово звено, "" - не разобрано)
+//     и steosmorphy.oov (слово не разобрано словарем);
+//   - steosmorphy.analysis.duration - гистограмма времени разбора слова, с;
+//   - steosmorphy.batch.duration - гистограмма времени пакетной операции, с.
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName - имя инструментирующей библиотеки для трассировщика и счетчиков.
+const ScopeName = "github.com/steosofficial/steosmorphy"
+
+// Ключи атрибутов спанов и метрик.
+const (
+	AttrOp      = attribute.Key("steosmorphy.op")
+	AttrMethod  = attribute.Key("steosmorphy.method")
+	AttrOOV     = attribute.Key("steosmorphy.oov")
+	AttrWords   = attribute.Key("steosmorphy.words")
+	AttrResults = attribute.Key("steosmorphy.results")
+)
+
+// config - настройки WithOpenTelemetry.
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option - функциональная опция WithOpenTelemetry.
+type Option func(*config)
+
+// WithTracerProvider задает поставщика трассировщиков (по умолчанию - глобальный, otel.GetTracerProvider).
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider задает поставщика счетчиков (по умолчанию - глобальный, otel.GetMeterProvider).
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// instruments - трассировщик и счетчики одного вызова WithOpenTelemetry.
+type instruments struct {
+	tracer        trace.Tracer
+	words         metric.Int64Counter
+	duration      metric.Float64Histogram
+	batchDuration metric.Float64Histogram
+
+	mu    sync.RWMutex
+	attrs map[attrKey]metric.MeasurementOption
+}
+
+// attrKey - операция и звено, по которым различаются атрибуты метрик разбора.
+type attrKey struct{ op, method string }
+
+// WithOpenTelemetry возвращает опцию анализатора, записывающую спаны пакетных операций
+// и метрики разбора слов (см. описание пакета). Инструменты создаются сразу; если
+// поставщик не смог их создать, используется соответствующий no-op инструмент.
+func WithOpenTelemetry(opts ...Option) steosmorphy.Option {
+	c := config{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	if c.meterProvider == nil {
+		c.meterProvider = otel.GetMeterProvider()
+	}
+
+	meter := c.meterProvider.Meter(ScopeName)
+	inst := &instruments{
+		tracer: c.tracerProvider.Tracer(ScopeName),
+		attrs:  make(map[attrKey]metric.MeasurementOption),
+	}
+	var err error
+	inst.words, err = meter.Int64Counter("steosmorphy.words",
+		metric.WithDescription("Разобранные слова"), metric.WithUnit("{word}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.duration, err = meter.Float64Histogram("steosmorphy.analysis.duration",
+		metric.WithDescription("Время разбора слова"), metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.batchDuration, err = meter.Float64Histogram("steosmorphy.batch.duration",
+		metric.WithDescription("Время пакетной операции"), metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	analysisHook := steosmorphy.WithAnalysisHook(inst.observe)
+	batchHook := steosmorphy.WithBatchHook(inst.startBatch)
+	return func(a *steosmorphy.MorphAnalyzer) {
+		analysisHook(a)
+		batchHook(a)
+	}
+}
+
+// observe записывает разбор слова в метрики.
+func (inst *instruments) observe(e steosmorphy.AnalysisEvent) {
+	ctx := context.Background()
+	attrs := inst.attributes(e.Op, e.Method)
+	inst.words.Add(ctx, 1, attrs)
+	inst.duration.Record(ctx, e.Latency.Seconds(), attrs)
+}
+
+// attributes возвращает атрибуты метрик разбора. Наборов немного (операция x звено),
+// поэтому они создаются один раз и не выделяют память при каждом разборе.
+func (inst *instruments) attributes(op, method string) metric.MeasurementOption {
+	key := attrKey{op, method}
+	inst.mu.RLock()
+	attrs, ok := inst.attrs[key]
+	inst.mu.RUnlock()
+	if ok {
+		return attrs
+	}
+	attrs = metric.WithAttributeSet(attribute.NewSet(
+		AttrOp.String(op),
+		AttrMethod.String(method),
+		AttrOOV.Bool(method != steosmorphy.UnitDictionary),
+	))
+	inst.mu.Lock()
+	inst.attrs[key] = attrs
+	inst.mu.Unlock()
+	return attrs
+}
+
+// startBatch открывает спан пакетной операции и возвращает функцию, закрывающую его.
+func (inst *instruments) startBatch(ctx context.Context, op string, words int) func(int, error) {
+	ctx, span := inst.tracer.Start(ctx, "steosmorphy."+op,
+		trace.WithAttributes(AttrOp.String(op), AttrWords.Int(words)))
+	started := time.Now()
+	return func(results int, err error) {
+		span.SetAttributes(AttrResults.Int(results))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		inst.batchDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(AttrOp.String(op)))
+	}
+}
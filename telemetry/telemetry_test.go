@@ -0,0 +1,78 @@
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/telemetry"
+	"github.com/steosofficial/steosmorphy/testdict"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestWithOpenTelemetry проверяет спаны пакетных операций и метрики разбора слов.
+func TestWithOpenTelemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	morph := testdict.Load(t, telemetry.WithOpenTelemetry(
+		telemetry.WithTracerProvider(tp),
+		telemetry.WithMeterProvider(mp),
+	))
+
+	morph.ParseList([]string{"кошку", "столы", "мышку"})
+	morph.Parse("кота")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := morph.InflectListCtx(ctx, []string{"кот"}); err == nil {
+		t.Fatal("InflectListCtx с отмененным контекстом не вернул ошибку")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("Записано %d спанов; ожидали 2", len(ended))
+	}
+	if span := ended[0]; span.Name() != "steosmorphy."+steosmorphy.OpParseList ||
+		!hasAttribute(span.Attributes(), telemetry.AttrWords.Int(3)) {
+		t.Errorf("Спан ParseList: %s %v", span.Name(), span.Attributes())
+	}
+	if span := ended[1]; span.Status().Code != codes.Error {
+		t.Errorf("Спан отмененного InflectListCtx: статус %v; ожидали ошибку", span.Status())
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatal(err)
+	}
+	words := map[bool]int64{} // oov -> число слов.
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "steosmorphy.words" {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				oov, _ := point.Attributes.Value(telemetry.AttrOOV)
+				words[oov.AsBool()] += point.Value
+			}
+		}
+	}
+	if words[false] != 3 || words[true] != 1 {
+		t.Errorf("steosmorphy.words: словарных %d, несловарных %d; ожидали 3 и 1", words[false], words[true])
+	}
+}
+
+// hasAttribute сообщает, что среди attrs есть attr.
+func hasAttribute(attrs []attribute.KeyValue, attr attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Package testdict содержит мини-словарь для модульных тестов: пару сотен словоформ
+// нескольких существительных, прилагательного, глаголов, местоимения и служебных слов
+// (см. lexicon.tsv). Словарь собирается в памяти за миллисекунды, поэтому код, зависящий
+// от анализатора, можно тестировать без полного словаря:
+//
+//	func TestLemmas(t *testing.T) {
+//		morph := testdict.Load(t)
+//		...
+//	}
+//
+// Словоформы взяты из словаря OpenCorpora (CC BY-SA 3.0).
+package testdict
+
+import (
+	_ "embed"
+	"strings"
+	"testing"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+//go:embed lexicon.tsv
+var lexiconTSV string
+
+// Lexicon возвращает словоформы мини-словаря.
+func Lexicon() []steosmorphy.LexEntry {
+	var entries []steosmorphy.LexEntry
+	err := steosmorphy.ReadTSVLexicon(strings.NewReader(lexiconTSV), func(e steosmorphy.LexEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		// Лексикон встроен в пакет и проверяется тестами репозитория.
+		panic("testdict: " + err.Error())
+	}
+	return entries
+}
+
+// New собирает анализатор из мини-словаря (см. BuildFromLexicon).
+func New(opts ...steosmorphy.Option) (*steosmorphy.MorphAnalyzer, error) {
+	return steosmorphy.BuildFromLexicon(Lexicon(), opts...)
+}
+
+// Fake возвращает заглушку анализатора (см. FakeAnalyzer) с лексиконом мини-словаря:
+// для тестов кода, зависящего от интерфейса Morph, которым не нужен даже мини-словарь.
+func Fake() *steosmorphy.FakeAnalyzer {
+	return steosmorphy.NewFakeAnalyzer(Lexicon()...)
+}
+
+// Load собирает анализатор из мини-словаря для теста tb и закрывает его по окончании теста.
+// Ошибка сборки завершает тест.
+func Load(tb testing.TB, opts ...steosmorphy.Option) *steosmorphy.MorphAnalyzer {
+	tb.Helper()
+	morph, err := New(opts...)
+	if err != nil {
+		tb.Fatalf("Не удалось собрать мини-словарь: %v", err)
+	}
+	tb.Cleanup(func() { morph.Close() })
+	return morph
+}
@@ -111,6 +111,51 @@ func BenchmarkAnalyzeSequential(b *testing.B) {
 	}
 }
 
+// BenchmarkParseInto сравнивает Parse с разбором в переиспользуемый буфер.
+func BenchmarkParseInto(b *testing.B) {
+	analyzer := getTestAnalyzer()
+	words := loadWords(1_000)
+
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, word := range words {
+				benchmarkResult = analyzer.Parse(word)
+			}
+		}
+	})
+	b.Run("ParseInto", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]steosmorphy.Parsed, 0, 16)
+		for i := 0; i < b.N; i++ {
+			for _, word := range words {
+				buf = analyzer.ParseInto(word, buf)
+			}
+		}
+		benchmarkResult = buf
+	})
+}
+
+// BenchmarkLazyDetails сравнивает лемматизацию с полной и отложенной раскладкой граммем.
+func BenchmarkLazyDetails(b *testing.B) {
+	lazy, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLazyDetails())
+	if err != nil {
+		b.Fatal(err)
+	}
+	words := loadWords(1_000)
+
+	for name, analyzer := range map[string]*steosmorphy.MorphAnalyzer{"Full": getTestAnalyzer(), "Lazy": lazy} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, word := range words {
+					benchmarkResult = analyzer.Lemmatize(word)
+				}
+			}
+		})
+	}
+}
+
 // BenchmarkParseList измеряет производительность пакетной обработки разбора слов.
 func BenchmarkParseList(b *testing.B) {
 	analyzer := getTestAnalyzer()
@@ -149,7 +194,7 @@ func BenchmarkParseList(b *testing.B) {
 // BenchmarkInflectList измеряет производительность пакетной обработки поиска словоформ у слов.
 func BenchmarkInflectList(b *testing.B) {
 	analyzer := getTestAnalyzer()
-	wordCounts := []int{10_000} // 1_000_000 слов разом InflectList слишком накладно для ОЗУ
+	wordCounts := []int{10_000} // 1_000_000 слов разом InflectList слишком накладно для ОЗУ, см. BenchmarkInflectListFunc
 
 	for _, count := range wordCounts {
 		b.Run(fmt.Sprintf("%d_words", count), func(b *testing.B) {
@@ -180,3 +225,68 @@ func BenchmarkInflectList(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkInflectListFunc измеряет порционную генерацию словоформ с ограничением памяти.
+func BenchmarkInflectListFunc(b *testing.B) {
+	budget := steosmorphy.NewMemoryBudget(64 << 20)
+	analyzer, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithMemoryBudget(budget))
+	if err != nil {
+		b.Fatalf("Не удалось загрузить анализатор: %v", err)
+	}
+	wordCounts := []int{10_000, 100_000}
+
+	for _, count := range wordCounts {
+		b.Run(fmt.Sprintf("%d_words", count), func(b *testing.B) {
+
+			words := loadWords(count)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				totalForms := 0
+				_ = analyzer.InflectListFunc(words, func(forms []*steosmorphy.Parsed) error {
+					totalForms += len(forms)
+					return nil
+				})
+			}
+		})
+	}
+}
+
+// BenchmarkInflectCompact сравнивает аллокации Inflect и InflectCompact на одних и тех же словах.
+func BenchmarkInflectCompact(b *testing.B) {
+	analyzer := getTestAnalyzer()
+	words := loadWords(1_000)
+
+	b.Run("Inflect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, word := range words {
+				_ = analyzer.Inflect(word)
+			}
+		}
+	})
+	b.Run("InflectCompact", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, word := range words {
+				_ = analyzer.InflectCompact(word)
+			}
+		}
+	})
+}
+
+// BenchmarkTypeAhead измеряет задержку подсказок при наборе: дополнение короткого
+// и длинного префикса и исправление опечатки.
+func BenchmarkTypeAhead(b *testing.B) {
+	analyzer := getTestAnalyzer()
+	for _, prefix := range []string{"п", "прив", "превет"} {
+		b.Run(prefix, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchmarkResult = analyzer.TypeAhead(prefix, 10)
+			}
+		})
+	}
+}
@@ -0,0 +1,1514 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/testdict"
+)
+
+// testLexiconTSV - крошечный лексикон: два существительных одного склонения и глагол.
+const testLexiconTSV = `# словоформа	лемма	теги
+кот	кот	Существительное,Одушевленное,Мужской,Единственное число,Именительный
+кота	кот	Существительное,Одушевленное,Мужской,Единственное число,Родительный
+кота	кот	Существительное,Одушевленное,Мужской,Единственное число,Винительный
+коту	кот	Существительное,Одушевленное,Мужской,Единственное число,Дательный
+коты	кот	Существительное,Одушевленное,Мужской,Множественное число,Именительный
+котов	кот	Существительное,Одушевленное,Мужской,Множественное число,Родительный
+стол	стол	Существительное,Неодушевленное,Мужской,Единственное число,Именительный
+стола	стол	Существительное,Неодушевленное,Мужской,Единственное число,Родительный
+столу	стол	Существительное,Неодушевленное,Мужской,Единственное число,Дательный
+столы	стол	Существительное,Неодушевленное,Мужской,Множественное число,Именительный
+столов	стол	Существительное,Неодушевленное,Мужской,Множественное число,Родительный
+идти	идти	Глагол,Инфинитив,Несовершенный
+иду	идти	Глагол,Не инфинитив,Несовершенный,Единственное число,1-е лицо,Настоящее
+шёл	идти	Глагол,Не инфинитив,Несовершенный,Мужской,Единственное число,Прошедшее
+шла	идти	Глагол,Не инфинитив,Несовершенный,Женский,Единственное число,Прошедшее
+`
+
+// buildTestDict компилирует лексикон во временный файл и загружает его.
+func buildTestDict(t *testing.T, builder *steosmorphy.DictBuilder) *steosmorphy.MorphAnalyzer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.Build(out); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(steosmorphy.EnvDictPath, path)
+	morph, err := steosmorphy.LoadMorphAnalyzer()
+	if err != nil {
+		t.Fatalf("Не удалось загрузить собранный словарь: %v", err)
+	}
+	return morph
+}
+
+// TestDictBuilder_TSV проверяет, что собранный из TSV словарь разбирает, склоняет и предсказывает слова.
+func TestDictBuilder_TSV(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	morph := buildTestDict(t, builder)
+
+	if !morph.License().IsZero() {
+		t.Errorf("Словарь собран без лицензии, получили %+v", morph.License())
+	}
+
+	parses := morph.Parse("кота")
+	if len(parses) != 2 || parses[0].Lemma != "кот" {
+		t.Fatalf("Ожидали 2 разбора 'кота' с леммой 'кот', получили %d", len(parses))
+	}
+
+	// Супплетивные формы с разными основами должны попасть в лексему.
+	forms := morph.Inflect("иду")
+	words := make([]string, 0, len(forms))
+	for _, p := range forms {
+		words = append(words, p.Word)
+	}
+	if strings.Join(words, " ") != "идти иду шла шёл" {
+		t.Errorf("Неверная лексема 'идти': %v", words)
+	}
+
+	// Несловарное слово склоняется по образцу "кот"/"стол".
+	predicted, predictedForms := morph.Analyze("бота")
+	if len(predicted) != 1 || predicted[0].Lemma != "бот" {
+		t.Fatalf("Ожидали предсказанную лемму 'бот', получили %+v", predicted)
+	}
+	if findForm(predictedForms, "ботов") == nil {
+		t.Error("Среди предсказанных форм нет 'ботов'")
+	}
+}
+
+// TestValency проверяет, что валентные рамки, добавленные AddValency, сохраняются в словаре
+// и находятся по любой форме глагола и причастия.
+func TestValency(t *testing.T) {
+	lexicon := testLexiconTSV + "идущий\tидти\tПричастие,Полная,Несовершенный,Настоящее,Действительный,Мужской,Единственное число,Именительный\n"
+	load := func(addFrames func(*steosmorphy.DictBuilder)) *steosmorphy.MorphAnalyzer {
+		builder := steosmorphy.NewDictBuilder()
+		if err := steosmorphy.ReadTSVLexicon(strings.NewReader(lexicon), builder.Add); err != nil {
+			t.Fatalf("Ошибка чтения TSV: %v", err)
+		}
+		addFrames(builder)
+		return buildTestDict(t, builder)
+	}
+
+	motion := steosmorphy.ValencyFrame{
+		Subject: "Именительный",
+		Objects: []steosmorphy.ValencyArg{{Case: "Винительный", Preposition: "в", Optional: true}},
+	}
+	company := steosmorphy.ValencyFrame{
+		Subject: "Именительный",
+		Objects: []steosmorphy.ValencyArg{{Case: "Творительный", Preposition: "с"}},
+	}
+	morph := load(func(b *steosmorphy.DictBuilder) {
+		b.AddValency("Идти", motion)
+		b.AddValency("идти", company)           // Повторный вызов дополняет рамки.
+		b.AddValency("кот", motion)             // Не глагол: Valency("кота") рамок не вернет.
+		b.AddValency("лететь", motion, company) // Леммы нет в лексиконе: рамки не сохраняются.
+	})
+
+	want, _ := json.Marshal([]steosmorphy.ValencyFrame{motion, company})
+	for _, word := range []string{"идти", "Шёл", "иду", "идущий"} {
+		got, _ := json.Marshal(morph.Valency(word))
+		if !bytes.Equal(got, want) {
+			t.Errorf("Valency(%q) = %s; ожидали %s", word, got, want)
+		}
+	}
+	for _, word := range []string{"кота", "лететь", "бота"} {
+		if frames := morph.Valency(word); frames != nil {
+			t.Errorf("Valency(%q) = %+v; ожидали nil", word, frames)
+		}
+	}
+
+	// Словарь без рамок собирается и загружается без необязательного блока.
+	if frames := load(func(*steosmorphy.DictBuilder) {}).Valency("иду"); frames != nil {
+		t.Errorf("Словарь без рамок вернул %+v", frames)
+	}
+}
+
+// TestDictLongForms проверяет генерацию словоформ, путь к которым в DAWG длиннее
+// начальных буферов обхода.
+func TestDictLongForms(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	long := "к" + strings.Repeat("о", 80)
+	for _, e := range []steosmorphy.LexEntry{
+		{Word: long + "т", Lemma: long + "т", Tags: "Существительное,Неодушевленное,Мужской,Единственное число,Именительный"},
+		{Word: long + "та", Lemma: long + "т", Tags: "Существительное,Неодушевленное,Мужской,Единственное число,Родительный"},
+		{Word: long + "тов", Lemma: long + "т", Tags: "Существительное,Неодушевленное,Мужской,Множественное число,Родительный"},
+		{Word: "к", Lemma: long + "т", Tags: "Существительное,Неодушевленное,Мужской,Множественное число,Именительный"},
+	} {
+		if err := builder.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	morph := buildTestDict(t, builder)
+
+	var words []string
+	for _, f := range morph.Inflect(long + "та") {
+		words = append(words, f.Word)
+	}
+	slices.Sort(words)
+	if want := []string{"к", long + "т", long + "та", long + "тов"}; !slices.Equal(words, want) {
+		t.Errorf("Неверная лексема длинного слова: %v", words)
+	}
+	// Соседние ветви обхода не должны портить словоформы друг друга.
+	if words := slices.Collect(morph.FindByTags("Существительное", "Родительный")); !slices.Contains(words, long+"тов") || !slices.Contains(words, "котов") {
+		t.Errorf("Родительный падеж существительных: %v", words)
+	}
+}
+
+// TestDictBuilder_OpenCorpora проверяет чтение XML OpenCorpora с объединением связанных лемм.
+func TestDictBuilder_OpenCorpora(t *testing.T) {
+	const dump = `<?xml version="1.0" encoding="utf-8"?>
+<dictionary version="0.92" revision="1">
+<lemmata>
+<lemma id="1" rev="1"><l t="сделать"><g v="INFN"/><g v="perf"/><g v="tran"/></l><f t="сделать"/></lemma>
+<lemma id="2" rev="2"><l t="сделал"><g v="VERB"/><g v="perf"/><g v="tran"/></l><f t="сделал"><g v="masc"/><g v="sing"/><g v="past"/><g v="indc"/></f><f t="сделала"><g v="femn"/><g v="sing"/><g v="past"/><g v="indc"/></f></lemma>
+<lemma id="3" rev="3"><l t="сделавший"><g v="PRTF"/><g v="perf"/><g v="tran"/><g v="past"/><g v="actv"/></l><f t="сделавший"><g v="masc"/><g v="sing"/><g v="nomn"/></f></lemma>
+</lemmata>
+<link_types>
+<type id="3">INFN-VERB</type>
+<type id="4">INFN-PRTF</type>
+</link_types>
+<links>
+<link id="1" from="1" to="2" type="3"/>
+<link id="2" from="1" to="3" type="4"/>
+</links>
+</dictionary>`
+
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadOpenCorporaXML(strings.NewReader(dump), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения OpenCorpora: %v", err)
+	}
+	builder.SetLicense(steosmorphy.OpenCorporaLicense)
+	morph := buildTestDict(t, builder)
+
+	if license := morph.License(); license != steosmorphy.OpenCorporaLicense {
+		t.Errorf("Встроенная лицензия не совпадает: %+v", license)
+	}
+
+	p := findParse(morph.Parse("сделавший"), "сделать", "Причастие")
+	if p == nil {
+		t.Fatal("Не найден разбор причастия 'сделавший' с леммой 'сделать'")
+	}
+	if p.Case != "Именительный" || p.Voice != "Действительный" {
+		t.Errorf("Неверные теги причастия: %s", p.Tags)
+	}
+	if len(morph.Inflect("сделала")) != 4 {
+		t.Errorf("Ожидали 4 формы в объединенной лексеме, получили %d", len(morph.Inflect("сделала")))
+	}
+}
+
+// TestDictRelations проверяет видовые пары и производные слова из TSV и связей OpenCorpora.
+func TestDictRelations(t *testing.T) {
+	const lexicon = testLexiconTSV + `котик	котик	Существительное,Одушевленное,Мужской,Единственное число,Именительный
+делать	делать	Глагол,Инфинитив,Несовершенный
+делаю	делать	Глагол,Не инфинитив,Несовершенный,Единственное число,1-е лицо,Настоящее
+сделать	сделать	Глагол,Инфинитив,Совершенный
+`
+	const relations = `# лемма	лемма	тип
+делать	сделать	aspect
+кот	котик	derivation
+кот	котяра	derivation
+`
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(lexicon), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	if err := steosmorphy.ReadTSVRelations(strings.NewReader(relations), builder.AddRelation); err != nil {
+		t.Fatalf("Ошибка чтения связей: %v", err)
+	}
+	morph := buildTestDict(t, builder)
+
+	if !morph.HasRelations() {
+		t.Fatal("Словарь должен содержать связи лемм")
+	}
+	if pair := morph.AspectPair("делаю"); !slices.Equal(pair, []string{"сделать"}) {
+		t.Errorf("Ожидали видовую пару 'сделать', получили %v", pair)
+	}
+	if pair := morph.AspectPair("сделать"); !slices.Equal(pair, []string{"делать"}) {
+		t.Errorf("Видовая пара должна быть симметричной, получили %v", pair)
+	}
+	// Леммы вне лексикона ("котяра") отбрасываются, связь направлена от производящего слова.
+	if derived := morph.Derivations("коту"); !slices.Equal(derived, []string{"котик"}) {
+		t.Errorf("Ожидали производное 'котик', получили %v", derived)
+	}
+	if derived := morph.Derivations("котик"); derived != nil {
+		t.Errorf("У 'котик' нет производных, получили %v", derived)
+	}
+	if pair := morph.AspectPair("кот"); pair != nil {
+		t.Errorf("У существительного нет видовой пары, получили %v", pair)
+	}
+
+	if err := steosmorphy.ReadTSVRelations(strings.NewReader("кот\tкотик\tsynonym\n"), builder.AddRelation); err == nil {
+		t.Error("Ожидали ошибку для неизвестного типа связи")
+	}
+
+	const dump = `<?xml version="1.0" encoding="utf-8"?>
+<dictionary version="0.92" revision="1">
+<lemmata>
+<lemma id="1" rev="1"><l t="сделать"><g v="INFN"/><g v="perf"/></l><f t="сделать"/></lemma>
+<lemma id="2" rev="2"><l t="делать"><g v="INFN"/><g v="impf"/></l><f t="делать"/></lemma>
+</lemmata>
+<link_types><type id="11">PERF-IMPF</type></link_types>
+<links><link id="1" from="1" to="2" type="11"/></links>
+</dictionary>`
+	var got []steosmorphy.LexRelation
+	err := steosmorphy.ReadOpenCorporaXMLWithRelations(strings.NewReader(dump), func(steosmorphy.LexEntry) error { return nil },
+		func(rel steosmorphy.LexRelation) error {
+			got = append(got, rel)
+			return nil
+		})
+	if err != nil || len(got) != 1 || got[0] != (steosmorphy.LexRelation{From: "сделать", To: "делать", Kind: steosmorphy.RelationAspect}) {
+		t.Errorf("Ожидали видовую пару из связей OpenCorpora (%v): %+v", err, got)
+	}
+}
+
+// findForm ищет словоформу в срезе результатов.
+func findForm(forms []*steosmorphy.Parsed, word string) *steosmorphy.Parsed {
+	for _, p := range forms {
+		if p.Word == word {
+			return p
+		}
+	}
+	return nil
+}
+
+// TestDictSelfTest проверяет самопроверку по контрольной выборке, встроенной компилятором.
+func TestDictSelfTest(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	morph := buildTestDict(t, builder)
+	if err := morph.SelfTest(); err != nil {
+		t.Errorf("Собранный словарь должен проходить самопроверку: %v", err)
+	}
+
+	// Поставляемый словарь собран до появления контрольной выборки.
+	if err := analyzer.SelfTest(); err != nil && !errors.Is(err, steosmorphy.ErrNoSelfTestSample) {
+		t.Errorf("Поставляемый словарь не прошел самопроверку: %v", err)
+	}
+}
+
+// TestDictMisaligned проверяет загрузку словаря из памяти, начало которой не выровнено
+// под элементы массивов DAWG (как у словаря, встроенного в исполняемый файл).
+func TestDictMisaligned(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(0)
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	misaligned := buf.Bytes()[1:]
+
+	var enabled atomic.Bool
+	steosmorphy.RegisterDictLocator(func() (steosmorphy.DictSource, error) {
+		if !enabled.Load() {
+			return steosmorphy.DictSource{}, errors.New("тестовый источник отключен")
+		}
+		return steosmorphy.DictSource{Data: misaligned}, nil
+	})
+	enabled.Store(true)
+	defer enabled.Store(false)
+	t.Setenv(steosmorphy.EnvDictPath, "")
+
+	morph, err := steosmorphy.LoadMorphAnalyzer()
+	if err != nil {
+		t.Fatalf("Не удалось загрузить невыровненный словарь: %v", err)
+	}
+	if parses := morph.Parse("котов"); len(parses) == 0 || parses[0].Lemma != "кот" {
+		t.Errorf("Parse(котов) = %v", parses)
+	}
+	if forms := morph.Inflect("стол"); len(forms) != 5 {
+		t.Errorf("Inflect(стол): %d форм; ожидали 5", len(forms))
+	}
+}
+
+// TestShippedDictParts проверяет, что части словаря модуля dict нарезаны с размером,
+// кратным 64 КиБ, и отображаются в память без копирования на платформах со страницами до 64 КиБ.
+func TestShippedDictParts(t *testing.T) {
+	parts, _ := filepath.Glob(filepath.Join("..", "dict", "morph_a*"))
+	if len(parts) == 0 {
+		t.Skip("части словаря не найдены")
+	}
+	for _, part := range parts[:len(parts)-1] {
+		info, err := os.Stat(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size()%(64<<10) != 0 {
+			t.Errorf("Размер %s (%d байт) не кратен 64 КиБ", filepath.Base(part), info.Size())
+		}
+	}
+}
+
+// TestDictParts проверяет загрузку словаря прямо из частей и объединение частей только
+// с опцией WithAutoMerge - и никогда в директории частей.
+func TestDictParts(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	data := buf.Bytes()
+	split := func(sizes ...int) string {
+		dir := t.TempDir()
+		rest := data
+		for i, size := range append(sizes, len(data)) {
+			size = min(size, len(rest))
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("morph_a%c", 'a'+i)), rest[:size], 0o644); err != nil {
+				t.Fatal(err)
+			}
+			rest = rest[size:]
+		}
+		return dir
+	}
+	load := func(dir string, wantMode steosmorphy.LoadMode, opts ...steosmorphy.Option) {
+		t.Helper()
+		morph, err := steosmorphy.LoadMorphAnalyzerFromParts(dir, opts...)
+		if err != nil {
+			t.Fatalf("LoadMorphAnalyzerFromParts: %v", err)
+		}
+		defer morph.Close()
+		if mode := morph.Info().LoadMode; mode != wantMode {
+			t.Errorf("LoadMode = %q; ожидали %q", mode, wantMode)
+		}
+		if parses := morph.Parse("котов"); len(parses) == 0 || parses[0].Lemma != "кот" {
+			t.Errorf("Parse(котов) = %v", parses)
+		}
+		if forms := morph.Inflect("стол"); len(forms) != 5 {
+			t.Errorf("Inflect(стол): %d форм; ожидали 5", len(forms))
+		}
+	}
+
+	// Части произвольного размера читаются в кучу.
+	partsDir := split(len(data)/3, len(data)/3)
+	load(partsDir, steosmorphy.LoadModeHeap)
+	// Части, кратные размеру страницы, отображаются в память подряд.
+	if pageSize := os.Getpagesize(); len(data) > pageSize && (runtime.GOOS == "linux" || runtime.GOOS == "darwin") {
+		load(split(pageSize), steosmorphy.LoadModeParts, steosmorphy.WithPrefault())
+		load(split(pageSize), steosmorphy.LoadModeHeap, steosmorphy.WithHeapLoad())
+	}
+
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+	t.Setenv(steosmorphy.EnvDictPath, "")
+
+	var enabled atomic.Bool
+	steosmorphy.RegisterDictLocator(func() (steosmorphy.DictSource, error) {
+		if !enabled.Load() {
+			return steosmorphy.DictSource{}, errors.New("тестовый источник отключен")
+		}
+		return steosmorphy.DictSource{Parts: partsDir}, nil
+	})
+	enabled.Store(true)
+	defer enabled.Store(false)
+
+	written := func() int {
+		n := 0
+		_ = filepath.WalkDir(cacheDir, func(_ string, d fs.DirEntry, _ error) error {
+			if d != nil && !d.IsDir() {
+				n++
+			}
+			return nil
+		})
+		return n
+	}
+	morph, err := steosmorphy.LoadMorphAnalyzer()
+	if err != nil {
+		t.Fatalf("Загрузка частей без WithAutoMerge: %v", err)
+	}
+	_ = morph.Close()
+	if n := written(); n != 0 {
+		t.Errorf("Без WithAutoMerge в кэш записано файлов: %d", n)
+	}
+
+	morph, err = steosmorphy.LoadMorphAnalyzer(steosmorphy.WithAutoMerge())
+	if err != nil {
+		t.Fatalf("WithAutoMerge: %v", err)
+	}
+	if mode := morph.Info().LoadMode; mode != steosmorphy.LoadModeMmap {
+		t.Errorf("WithAutoMerge: LoadMode = %q; ожидали %q", mode, steosmorphy.LoadModeMmap)
+	}
+	_ = morph.Close()
+	if entries, _ := os.ReadDir(partsDir); len(entries) != 3 {
+		t.Errorf("В директории частей появились файлы: %v", entries)
+	}
+
+	// Объединенный ранее словарь используется и без опции.
+	morph, err = steosmorphy.LoadMorphAnalyzer()
+	if err != nil {
+		t.Fatalf("Повторная загрузка без WithAutoMerge: %v", err)
+	}
+	if mode := morph.Info().LoadMode; mode != steosmorphy.LoadModeMmap {
+		t.Errorf("Повторная загрузка: LoadMode = %q; ожидали %q", mode, steosmorphy.LoadModeMmap)
+	}
+	_ = morph.Close()
+}
+
+// TestConvertDict проверяет преобразование словаря прежнего формата в текущий.
+func TestConvertDict(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	current := buf.Bytes()
+
+	// Словарь текущей версии переписывается без изменений.
+	var same bytes.Buffer
+	if err := steosmorphy.ConvertDict(&same, current); err != nil {
+		t.Fatalf("ConvertDict(текущая версия): %v", err)
+	}
+	if !bytes.Equal(same.Bytes(), current) {
+		t.Error("ConvertDict изменил словарь текущей версии")
+	}
+
+	var converted bytes.Buffer
+	if err := steosmorphy.ConvertDict(&converted, legacyDict(current)); err != nil {
+		t.Fatalf("ConvertDict(DAW9): %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	if err := os.WriteFile(path, converted.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	morph, err := steosmorphy.LoadMorphAnalyzerFromFile(path)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить преобразованный словарь: %v", err)
+	}
+	if err := morph.SelfTest(); err != nil {
+		t.Errorf("SelfTest преобразованного словаря: %v", err)
+	}
+	if info := morph.Info(); info.Version != 11 || info.Words != 14 || info.Lemmas != 3 {
+		t.Errorf("Info преобразованного словаря: %+v", info)
+	}
+	if forms := morph.Inflect("котов"); len(forms) != 5 {
+		t.Errorf("Inflect(котов): %d форм; ожидали 5", len(forms))
+	}
+
+	if err := steosmorphy.ConvertDict(io.Discard, current[:len(current)/2]); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+		t.Errorf("ConvertDict(обрезанный): ожидали ErrDictCorrupted, получили %v", err)
+	}
+}
+
+// TestDictCompression проверяет словарь со сложным блоком, сжатым zstd, и словарь,
+// целиком сжатый zstd.
+func TestDictCompression(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	if err := builder.SetCompression("lz4"); err == nil {
+		t.Error("SetCompression(lz4): ожидали ошибку")
+	}
+	if err := builder.SetCompression(steosmorphy.CompressionZstd); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	data := buf.Bytes()
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	check := func(path string, wantMode steosmorphy.LoadMode, wantSize int, opts ...steosmorphy.Option) {
+		t.Helper()
+		morph, err := steosmorphy.LoadMorphAnalyzerFromFile(path, opts...)
+		if err != nil {
+			t.Fatalf("Загрузка %s: %v", filepath.Base(path), err)
+		}
+		defer morph.Close()
+		if info := morph.Info(); info.LoadMode != wantMode || info.FileSize != int64(wantSize) || info.Words != 14 {
+			t.Errorf("Info(%s) = %+v; ожидали LoadMode %q, FileSize %d", filepath.Base(path), info, wantMode, wantSize)
+		}
+		if err := morph.SelfTest(); err != nil {
+			t.Errorf("SelfTest(%s): %v", filepath.Base(path), err)
+		}
+		if parses := morph.Parse("котов"); len(parses) == 0 || parses[0].Lemma != "кот" {
+			t.Errorf("Parse(котов) из %s = %v", filepath.Base(path), parses)
+		}
+	}
+
+	blockPath := write("block.dawg", data)
+	check(blockPath, steosmorphy.LoadModeMmap, len(data))
+	if err := steosmorphy.VerifyDict(blockPath); err != nil {
+		t.Errorf("VerifyDict(блок zstd): %v", err)
+	}
+
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed := encoder.EncodeAll(data, nil)
+	filePath := write("morph.dawg.zst", packed)
+	check(filePath, steosmorphy.LoadModeZstd, len(packed))
+	check(filePath, steosmorphy.LoadModeZstd, len(packed), steosmorphy.WithHeapLoad(), steosmorphy.WithPrefault())
+	if err := steosmorphy.VerifyDict(filePath); err != nil {
+		t.Errorf("VerifyDict(файл zstd): %v", err)
+	}
+	var unpacked bytes.Buffer
+	if err := steosmorphy.ConvertDict(&unpacked, packed); err != nil {
+		t.Fatalf("ConvertDict(файл zstd): %v", err)
+	}
+	if !bytes.Equal(unpacked.Bytes(), data) {
+		t.Error("ConvertDict не распаковал файл, сжатый zstd")
+	}
+	if _, err := steosmorphy.LoadMorphAnalyzerFromFile(write("truncated.zst", packed[:len(packed)/2])); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+		t.Errorf("Обрезанный файл zstd: ожидали ErrDictCorrupted, получили %v", err)
+	}
+}
+
+// legacyDict переписывает заголовок словаря текущей версии в заголовок версии 9:
+// сигнатура "DAW9", карта файла без версии и метаданных и следом PoolsHeader.
+// Секции остаются на своих местах. На little-endian платформе это и есть файл версии 9.
+func legacyDict(current []byte) []byte {
+	legacy := slices.Clone(current)
+	copy(legacy, "DAW9")
+	// Карта файла: 14 смещений и длин после сигнатуры и версии.
+	copy(legacy[4:], current[8:8+14*8])
+	pools := binary.Size(steosmorphy.Header{})
+	copy(legacy[4+14*8:], current[pools:pools+binary.Size(steosmorphy.PoolsHeader{})])
+	return legacy
+}
+
+// TestDictVersion проверяет метаданные заголовка и ошибки для неподдерживаемых версий.
+func TestDictVersion(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	built := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	builder.SetBuildTime(built)
+	if err := builder.SetSourceRevision("417150"); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.SetSourceRevision(strings.Repeat("x", 65)); err == nil {
+		t.Error("SetSourceRevision принял ревизию длиннее 64 байт")
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	data := buf.Bytes()
+
+	load := func(data []byte) (*steosmorphy.MorphAnalyzer, error) {
+		path := filepath.Join(t.TempDir(), "morph.dawg")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return steosmorphy.LoadMorphAnalyzerFromFile(path)
+	}
+	morph, err := load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := steosmorphy.DictInfo{
+		Version:        11,
+		BuildTime:      built,
+		SourceRevision: "417150",
+		Words:          14,
+		Lemmas:         3,
+		Paradigms:      3,
+		TagSets:        15,
+		FileSize:       int64(len(data)),
+		LoadMode:       steosmorphy.LoadModeMmap,
+	}
+	if info := morph.Info(); info != want {
+		t.Errorf("Info() = %+v; ожидали %+v", info, want)
+	}
+
+	// Словарь версии 9 читается, метаданных в нем нет.
+	morph, err = load(legacyDict(data))
+	if err != nil {
+		t.Fatalf("Не удалось загрузить словарь версии 9: %v", err)
+	}
+	if info := morph.Info(); info.Version != 9 || !info.BuildTime.IsZero() || info.Lemmas != 3 {
+		t.Errorf("Info() словаря версии 9 = %+v", info)
+	}
+	if info := analyzer.Info(); info.Version < 7 || info.Lemmas == 0 || info.Words < info.Lemmas || info.FileSize == 0 {
+		t.Errorf("Info() поставляемого словаря = %+v", info)
+	}
+
+	tooNew := slices.Clone(data)
+	binary.LittleEndian.PutUint32(tooNew[4:], 12)
+	tooOld := append([]byte("DAW6"), legacyDict(data)[4:]...)
+	for name, data := range map[string][]byte{"новее": tooNew, "старше": tooOld} {
+		if _, err := load(data); !errors.Is(err, steosmorphy.ErrDictVersion) {
+			t.Errorf("Версия %s поддерживаемых: ожидали ErrDictVersion, получили %v", name, err)
+		}
+	}
+}
+
+// TestVerifyDict проверяет контрольные суммы словаря: VerifyDict, WithVerifyOnLoad
+// и объединение частей в неверном порядке.
+func TestVerifyDict(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	data := buf.Bytes()
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if err := steosmorphy.VerifyDict(write("ok.dawg", data)); err != nil {
+		t.Errorf("VerifyDict(целый словарь): %v", err)
+	}
+	if err := steosmorphy.VerifyDict(write("legacy.dawg", legacyDict(data))); !errors.Is(err, steosmorphy.ErrNoChecksums) {
+		t.Errorf("VerifyDict(версия 9): ожидали ErrNoChecksums, получили %v", err)
+	}
+
+	// Последний байт файла - в масках граммем: загрузчик его не проверяет.
+	flipped := slices.Clone(data)
+	flipped[len(flipped)-1] ^= 0xFF
+	path := write("flipped.dawg", flipped)
+	if err := steosmorphy.VerifyDict(path); !errors.Is(err, steosmorphy.ErrDictCorrupted) || !strings.Contains(err.Error(), "маски граммем") {
+		t.Errorf("VerifyDict(испорченный байт): ожидали ErrDictCorrupted в масках граммем, получили %v", err)
+	}
+	if morph, err := steosmorphy.LoadMorphAnalyzerFromFile(path); err != nil {
+		t.Errorf("Без проверки словарь должен загрузиться: %v", err)
+	} else {
+		_ = morph.Close()
+	}
+	if _, err := steosmorphy.LoadMorphAnalyzerFromFile(path, steosmorphy.WithVerifyOnLoad()); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+		t.Errorf("WithVerifyOnLoad: ожидали ErrDictCorrupted, получили %v", err)
+	}
+	if _, err := steosmorphy.LoadMorphAnalyzerFromFile(write("verified.dawg", data), steosmorphy.WithVerifyOnLoad()); err != nil {
+		t.Errorf("WithVerifyOnLoad(целый словарь): %v", err)
+	}
+
+	if err := steosmorphy.VerifyDict(write("truncated.dawg", data[:len(data)-8])); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+		t.Errorf("VerifyDict(обрезанный): ожидали ErrDictCorrupted, получили %v", err)
+	}
+
+	// Части, переименованные так, что объединяются в неверном порядке.
+	partsDir := t.TempDir()
+	third := len(data) / 3
+	for name, part := range map[string][]byte{"morph_aa": data[:third], "morph_ab": data[2*third:], "morph_ac": data[third : 2*third]} {
+		if err := os.WriteFile(filepath.Join(partsDir, name), part, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := steosmorphy.MergeDictParts(partsDir, filepath.Join(partsDir, steosmorphy.DictFileName)); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+		t.Errorf("MergeDictParts(части не по порядку): ожидали ErrDictCorrupted, получили %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(partsDir, steosmorphy.DictFileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Поврежденный объединенный словарь не должен оставаться на диске: %v", err)
+	}
+}
+
+// TestDictCorrupted проверяет, что поврежденный файл словаря дает ErrDictCorrupted, а не панику.
+func TestDictCorrupted(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	data := buf.Bytes()
+
+	cases := map[string][]byte{
+		"обрезанный":      data[:len(data)/2],
+		"без заголовка":   data[:8],
+		"чужая сигнатура": append([]byte("XXXX"), data[4:]...),
+	}
+	// Количество лемм (Lemmas.Count) в заголовке пулов больше, чем есть в файле.
+	pools := binary.Size(steosmorphy.Header{})
+	cases["испорченный пул лемм"] = slices.Clone(data)
+	binary.LittleEndian.PutUint64(cases["испорченный пул лемм"][pools+24:], 1<<40)
+	// Таблица парадигм короче, чем указано в заголовке.
+	cases["испорченная таблица парадигм"] = slices.Clone(data)
+	binary.LittleEndian.PutUint64(cases["испорченная таблица парадигм"][pools+4*32+2*16+8:], 1)
+	for name, corrupted := range cases {
+		path := filepath.Join(t.TempDir(), "morph.dawg")
+		if err := os.WriteFile(path, corrupted, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := steosmorphy.LoadMorphAnalyzerFromFile(path); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+			t.Errorf("%s: ожидали ErrDictCorrupted, получили %v", name, err)
+		}
+	}
+}
+
+// TestDictCorruptedPayloads проверяет, что выход за границы массивов при разборе поврежденного
+// словаря возвращается ErrDictCorrupted, а паника пользовательского кода не маскируется под нее.
+func TestDictCorruptedPayloads(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Build(&buf); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	data := buf.Bytes()
+	var header steosmorphy.Header
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		t.Fatal(err)
+	}
+	// Наборы тегов всех словоформ указывают за пределы таблицы тегов.
+	payloadSize := int64(binary.Size(steosmorphy.MorphInfo{}))
+	for i := range header.PayloadsCount {
+		binary.LittleEndian.PutUint32(data[header.PayloadsOffset+i*payloadSize+4:], 1<<30)
+	}
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	morph, err := steosmorphy.LoadMorphAnalyzerFromFile(path)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить словарь: %v", err)
+	}
+	if _, err := morph.ParseE("кота"); !errors.Is(err, steosmorphy.ErrDictCorrupted) {
+		t.Errorf("ParseE на поврежденном словаре: ожидали ErrDictCorrupted, получили %v", err)
+	}
+
+	broken := steosmorphy.WithUnits(panicUnit{})
+	custom, err := steosmorphy.LoadMorphAnalyzerFromFile(path, broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Паника пользовательского звена перехвачена как поврежденный словарь")
+		}
+	}()
+	_, _, err = custom.AnalyzeE("кот")
+	t.Errorf("AnalyzeE вернул %v вместо паники звена", err)
+}
+
+// panicUnit - звено с ошибкой выхода за границы массива.
+type panicUnit struct{}
+
+func (panicUnit) Name() string { return "panic" }
+
+func (panicUnit) Parse(_ *steosmorphy.MorphAnalyzer, word string) []*steosmorphy.Parsed {
+	var parses []*steosmorphy.Parsed
+	return parses[len(word):len(word)+1]
+}
+
+func (panicUnit) Inflect(*steosmorphy.MorphAnalyzer, string, []*steosmorphy.Parsed) []*steosmorphy.Parsed {
+	return nil
+}
+
+// TestDictResultsAfterClose проверяет, что строки разборов не ссылаются на отображенный
+// в память файл словаря и остаются корректными после Close.
+func TestDictResultsAfterClose(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	morph := buildTestDict(t, builder)
+	parses := morph.Parse("котов")
+	forms := morph.Inflect("кот")
+	if len(parses) == 0 || len(forms) == 0 {
+		t.Fatalf("Parse(котов) = %v, Inflect(кот) = %v", parses, forms)
+	}
+	if err := morph.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if parses[0].Lemma != "кот" || !strings.Contains(parses[0].Tags, "Родительный") {
+		t.Errorf("После Close: лемма %q, теги %q", parses[0].Lemma, parses[0].Tags)
+	}
+	if !slices.ContainsFunc(forms, func(p *steosmorphy.Parsed) bool {
+		return p.Word == "коту" && strings.Contains(p.Tags, "Дательный")
+	}) {
+		t.Errorf("После Close формы испорчены: %v", forms)
+	}
+}
+
+// TestLoadModes проверяет загрузку словаря в кучу, прогрев отображения и mlock.
+func TestLoadModes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	writeTestDict(t, path, testLexiconTSV)
+
+	heap, err := steosmorphy.LoadMorphAnalyzerFromFile(path, steosmorphy.WithHeapLoad(), steosmorphy.WithPrefault())
+	if err != nil {
+		t.Fatalf("WithHeapLoad: %v", err)
+	}
+	if mode := heap.Info().LoadMode; mode != steosmorphy.LoadModeHeap {
+		t.Errorf("LoadMode = %q; ожидали %q", mode, steosmorphy.LoadModeHeap)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if parses := heap.Parse("котов"); len(parses) == 0 || parses[0].Lemma != "кот" {
+		t.Errorf("Parse(котов) после удаления файла: %v", parses)
+	}
+	if err := heap.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	writeTestDict(t, path, testLexiconTSV)
+	warm, err := steosmorphy.LoadMorphAnalyzerFromFile(path, steosmorphy.WithPrefault())
+	if err != nil {
+		t.Fatalf("WithPrefault: %v", err)
+	}
+	if mode := warm.Info().LoadMode; mode != steosmorphy.LoadModeMmap {
+		t.Errorf("LoadMode = %q; ожидали %q", mode, steosmorphy.LoadModeMmap)
+	}
+	if parses := warm.Parse("котов"); len(parses) == 0 {
+		t.Error("Parse(котов) после прогрева не нашел разборов")
+	}
+	if err := warm.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	for _, heapLoad := range []bool{false, true} {
+		opts := []steosmorphy.Option{steosmorphy.WithMlock()}
+		if heapLoad {
+			opts = append(opts, steosmorphy.WithHeapLoad())
+		}
+		locked, err := steosmorphy.LoadMorphAnalyzerFromFile(path, opts...)
+		if errors.Is(err, errors.ErrUnsupported) || errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOMEM) {
+			t.Skipf("mlock недоступен: %v", err)
+		}
+		if err != nil {
+			t.Fatalf("WithMlock (в кучу: %v): %v", heapLoad, err)
+		}
+		if parses := locked.Parse("котов"); len(parses) == 0 {
+			t.Errorf("Parse(котов) с mlock (в кучу: %v) не нашел разборов", heapLoad)
+		}
+		if err := locked.Close(); err != nil {
+			t.Errorf("Close с mlock (в кучу: %v): %v", heapLoad, err)
+		}
+	}
+}
+
+// TestAsyncLoad проверяет фоновую загрузку словаря и сигнал готовности.
+func TestAsyncLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	writeTestDict(t, path, testLexiconTSV)
+
+	loader := steosmorphy.LoadMorphAnalyzerFromFileAsync(path)
+	select {
+	case <-loader.Ready():
+	case <-time.After(10 * time.Second):
+		t.Fatal("Словарь не загрузился за 10 секунд")
+	}
+	if err := loader.Err(); err != nil {
+		t.Fatalf("Err после загрузки: %v", err)
+	}
+	loaded, err := loader.Analyzer()
+	if err != nil {
+		t.Fatalf("Analyzer: %v", err)
+	}
+	if parses := loaded.Parse("котов"); len(parses) == 0 || parses[0].Lemma != "кот" {
+		t.Errorf("Parse(котов) после фоновой загрузки: %v", parses)
+	}
+	if err := loader.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	missing := steosmorphy.LoadMorphAnalyzerFromFileAsync(filepath.Join(t.TempDir(), "missing.dawg"))
+	if _, err := missing.Wait(context.Background()); err == nil {
+		t.Error("Wait для несуществующего файла не вернул ошибку")
+	}
+	if err := missing.Err(); err == nil || errors.Is(err, steosmorphy.ErrLoading) {
+		t.Errorf("Err для несуществующего файла = %v; ожидали ошибку загрузки", err)
+	}
+	if _, err := missing.Analyzer(); err == nil {
+		t.Error("Analyzer для несуществующего файла не вернул ошибку")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pending := steosmorphy.LoadMorphAnalyzerFromFileAsync(path)
+	defer pending.Close()
+	if _, err := pending.Wait(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait с отмененным контекстом: %v", err)
+	}
+}
+
+// TestBuildFromLexicon проверяет сборку анализатора в памяти и мини-словарь testdict.
+func TestBuildFromLexicon(t *testing.T) {
+	var entries []steosmorphy.LexEntry
+	err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), func(e steosmorphy.LexEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	morph, err := steosmorphy.BuildFromLexicon(entries)
+	if err != nil {
+		t.Fatalf("BuildFromLexicon: %v", err)
+	}
+	defer morph.Close()
+	if mode := morph.Info().LoadMode; mode != steosmorphy.LoadModeHeap {
+		t.Errorf("LoadMode = %q; ожидали %q", mode, steosmorphy.LoadModeHeap)
+	}
+	if parses := morph.Parse("котов"); len(parses) == 0 || parses[0].Lemma != "кот" {
+		t.Errorf("Parse(котов) = %v", parses)
+	}
+	if _, err := steosmorphy.BuildFromLexicon(nil); err == nil {
+		t.Error("BuildFromLexicon для пустого лексикона не вернул ошибку")
+	}
+
+	mini := testdict.Load(t)
+	for word, lemma := range map[string]string{"кошку": "кошка", "красивыми": "красивый", "шёл": "идти", "окна": "окно"} {
+		if parses := mini.Parse(word); len(parses) == 0 || parses[0].Lemma != lemma {
+			t.Errorf("testdict: Parse(%s) = %v; ожидали лемму %q", word, parses, lemma)
+		}
+	}
+	if forms := mini.Inflect("стол"); len(forms) < 10 {
+		t.Errorf("testdict: Inflect(стол) вернул %d форм", len(forms))
+	}
+}
+
+// TestFakeAnalyzer проверяет, что заглушка анализатора отвечает как анализатор,
+// собранный из того же лексикона.
+func TestFakeAnalyzer(t *testing.T) {
+	var fake steosmorphy.Morph = testdict.Fake()
+	var built steosmorphy.Morph = testdict.Load(t)
+	for _, word := range []string{"кошку", "Стола", "шёл", "я", "в", "красивыми"} {
+		fakeLemmas, builtLemmas := fake.Lemmatize(word), built.Lemmatize(word)
+		if !slices.Equal(fakeLemmas, builtLemmas) {
+			t.Errorf("Lemmatize(%s) = %v; анализатор: %v", word, fakeLemmas, builtLemmas)
+		}
+		fakeParses, fakeForms := fake.Analyze(word)
+		builtParses, builtForms := built.Analyze(word)
+		if len(fakeParses) != len(builtParses) || len(fakeForms) != len(builtForms) {
+			t.Errorf("Analyze(%s): %d разборов и %d форм; анализатор: %d и %d",
+				word, len(fakeParses), len(fakeForms), len(builtParses), len(builtForms))
+		}
+		if len(fakeParses) > 0 && fakeParses[0].Method != steosmorphy.UnitDictionary {
+			t.Errorf("Parse(%s).Method = %q", word, fakeParses[0].Method)
+		}
+	}
+	if parses, forms := fake.Analyze("нейросеть"); parses != nil || forms != nil {
+		t.Errorf("Analyze(нейросеть) у заглушки = %v, %v; ожидали nil", parses, forms)
+	}
+}
+
+// TestFindByTags проверяет обратный поиск словоформ по граммемам с индексом и без него.
+func TestFindByTags(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		builder := steosmorphy.NewDictBuilder()
+		if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+			t.Fatalf("Ошибка чтения TSV: %v", err)
+		}
+		builder.SetTagIndex(indexed)
+		morph := buildTestDict(t, builder)
+		if morph.HasTagIndex() != indexed {
+			t.Errorf("HasTagIndex() = %v; ожидали %v", morph.HasTagIndex(), indexed)
+		}
+
+		if words := slices.Collect(morph.FindByTags("Существительное", "Родительный")); !slices.Equal(words, []string{"кота", "котов", "стола", "столов"}) {
+			t.Errorf("Индекс %v: родительный падеж существительных: %v", indexed, words)
+		}
+		if words := slices.Collect(morph.FindByTags("verb", "past")); !slices.Equal(words, []string{"шла", "шёл"}) {
+			t.Errorf("Индекс %v: прошедшее время глаголов: %v", indexed, words)
+		}
+		if words := slices.Collect(morph.FindByTags("Звательный")); words != nil {
+			t.Errorf("Индекс %v: форм в звательном падеже нет, получили %v", indexed, words)
+		}
+	}
+
+	// Перебор поставляемого словаря можно прервать, не дожидаясь конца.
+	var words []string
+	for word := range analyzer.FindByTags("Существительное", "Женский", "Именительный", "Единственное число") {
+		if words = append(words, word); len(words) == 100 {
+			break
+		}
+	}
+	for _, word := range words {
+		if !slices.ContainsFunc(analyzer.Parse(word), func(p *steosmorphy.Parsed) bool { return p.PartOfSpeech == "Существительное" }) {
+			t.Errorf("Словоформа %q не разбирается как существительное", word)
+		}
+	}
+	if len(words) != 100 {
+		t.Errorf("Ожидали 100 словоформ, получили %d", len(words))
+	}
+}
+
+// TestDictGrammemeMasks проверяет маски граммем собранного словаря.
+func TestDictGrammemeMasks(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	morph := buildTestDict(t, builder)
+
+	if grammemes := morph.Grammemes(); len(grammemes) != 18 || grammemes[0] != "Существительное" {
+		t.Errorf("Граммемы словаря: %v", grammemes)
+	}
+	past, err := morph.MaskOf("Глагол", "Прошедшее")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var matched []string
+	for _, word := range []string{"кот", "иду", "шёл", "шла"} {
+		for _, p := range morph.Parse(word) {
+			if morph.Mask(p).Contains(past) {
+				matched = append(matched, p.Word)
+			}
+		}
+	}
+	if !slices.Equal(matched, []string{"шёл", "шла"}) {
+		t.Errorf("Формы прошедшего времени: %v", matched)
+	}
+	if _, err := morph.MaskOf("Звательный"); err == nil {
+		t.Error("Граммемы, которой нет в лексиконе, не должно быть в словаре")
+	}
+}
+
+// TestDictEnumeration проверяет перебор лемм и словоформ словаря.
+func TestDictEnumeration(t *testing.T) {
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(testLexiconTSV), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	morph := buildTestDict(t, builder)
+
+	if lemmas := slices.Collect(morph.Lemmas()); !slices.Equal(lemmas, []string{"кот", "стол", "идти"}) {
+		t.Errorf("Lemmas() = %v", lemmas)
+	}
+	entries := slices.Collect(morph.Words())
+	if len(entries) != 15 {
+		t.Fatalf("Ожидали 15 словоформ с разборами, получили %d", len(entries))
+	}
+	if !slices.IsSortedFunc(entries, func(x, y steosmorphy.WordEntry) int { return strings.Compare(x.Word, y.Word) }) {
+		t.Error("Словоформы должны идти по алфавиту")
+	}
+	genitive := steosmorphy.WordEntry{Word: "кота", Lemma: "кот", Tags: "Существительное,Одушевленное,Мужской,Единственное число,Родительный"}
+	if !slices.Contains(entries, genitive) {
+		t.Errorf("Среди словоформ нет %+v", genitive)
+	}
+
+	// Выгрузка словаря - лексикон, из которого собирается такой же словарь.
+	var lexicon strings.Builder
+	for _, e := range entries {
+		lexicon.WriteString(e.Word + "\t" + e.Lemma + "\t" + e.Tags + "\n")
+	}
+	rebuilt := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(lexicon.String()), rebuilt.Add); err != nil {
+		t.Fatalf("Ошибка чтения выгрузки: %v", err)
+	}
+	if again := slices.Collect(buildTestDict(t, rebuilt).Words()); !slices.Equal(again, entries) {
+		t.Errorf("Словарь из выгрузки отличается: %v", again)
+	}
+
+	// Перебор поставляемого словаря прерывается без обхода всего графа.
+	count := 0
+	for e := range analyzer.Words() {
+		if e.Word == "" {
+			t.Fatalf("Запись без словоформы: %+v", e)
+		}
+		if count++; count == 1000 {
+			break
+		}
+	}
+}
+
+// TestWriteHunspell проверяет экспорт в Hunspell: правила SFX, примененные к леммам .dict,
+// дают словоформы словаря, кроме пропущенных форм без общего начала с леммой.
+func TestWriteHunspell(t *testing.T) {
+	morph := testdict.Load(t)
+	var aff, dic bytes.Buffer
+	stats, err := morph.WriteHunspell(&aff, &dic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Правила по флагам: strip, add.
+	rules := make(map[string][][2]string)
+	for _, line := range strings.Split(aff.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[0] != "SFX" {
+			continue
+		}
+		strip, add := strings.TrimPrefix(fields[2], "0"), strings.TrimPrefix(fields[3], "0")
+		rules[fields[1]] = append(rules[fields[1]], [2]string{strip, add})
+	}
+	if len(rules) != stats.Flags {
+		t.Errorf("В .affix %d флагов; WriteHunspell сообщил %d", len(rules), stats.Flags)
+	}
+
+	// Словоформа -> леммы, которые из нее получит PostgreSQL.
+	lemmas := make(map[string][]string)
+	dicLines := strings.Split(strings.TrimSpace(dic.String()), "\n")
+	if dicLines[0] != fmt.Sprint(stats.Lemmas) || len(dicLines) != stats.Lemmas+1 {
+		t.Fatalf("Первая строка .dict %q; ожидали число лемм %d", dicLines[0], stats.Lemmas)
+	}
+	for _, line := range dicLines[1:] {
+		lemma, flag, _ := strings.Cut(line, "/")
+		lemmas[lemma] = append(lemmas[lemma], lemma)
+		for _, rule := range rules[flag] {
+			if !strings.HasSuffix(lemma, rule[0]) {
+				t.Fatalf("Правило %v флага %s не подходит к лемме %s", rule, flag, lemma)
+			}
+			form := strings.TrimSuffix(lemma, rule[0]) + rule[1]
+			lemmas[form] = append(lemmas[form], lemma)
+		}
+	}
+
+	skipped := 0
+	for e := range morph.Words() {
+		if !slices.Contains(lemmas[e.Word], e.Lemma) {
+			skipped++
+		}
+	}
+	if skipped != stats.Skipped {
+		t.Errorf("Не выводятся из .affix/.dict %d словоформ; WriteHunspell сообщил о %d пропущенных", skipped, stats.Skipped)
+	}
+	if got := lemmas["шёл"]; len(got) != 0 || !slices.Contains(lemmas["кошками"], "кошка") || !slices.Contains(lemmas["красивейшего"], "красивый") {
+		t.Errorf("Леммы: шёл %v, кошками %v, красивейшего %v", got, lemmas["кошками"], lemmas["красивейшего"])
+	}
+}
+
+// TestWriteWordforms проверяет экспорт wordforms для Sphinx и Manticore Search.
+func TestWriteWordforms(t *testing.T) {
+	morph := testdict.Load(t, steosmorphy.WithWordFrequencies(map[string]uint64{"кошками": 10, "кошке": 1, "читали": 5}))
+
+	var out strings.Builder
+	n, err := morph.WriteWordforms(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != n || !slices.IsSorted(lines) {
+		t.Errorf("Записано %d строк, сообщено %d; строки должны идти по алфавиту", len(lines), n)
+	}
+	for _, want := range []string{"кошками > кошка", "читали > читать"} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("Нет строки %q", want)
+		}
+	}
+	for _, line := range lines {
+		if word, lemma, _ := strings.Cut(line, " > "); word == lemma || strings.HasPrefix(line, "кошка ") {
+			t.Errorf("Лемма не должна сопоставляться: %q", line)
+		}
+	}
+
+	out.Reset()
+	if _, err := morph.WriteWordforms(&out, steosmorphy.WordformsPOS("NOUN"), steosmorphy.WordformsMinFrequency(5)); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "кошками > кошка\n" {
+		t.Errorf("Существительные с частотой от 5: %q", out.String())
+	}
+
+	if _, err := morph.WriteWordforms(&out, steosmorphy.WordformsPOS("Фрукт")); err == nil {
+		t.Error("Неизвестная часть речи не вернула ошибку")
+	}
+	if _, err := testdict.Load(t).WriteWordforms(&out, steosmorphy.WordformsMinFrequency(1)); err == nil {
+		t.Error("Порог частоты без частот не вернул ошибку")
+	}
+}
+
+// dawgDicFixture собирает словарь dawgdic с путеводителем (формат words.dawg pymorphy2)
+// из ключей: простое префиксное дерево, уложенное в двойной массив с переходами по XOR.
+func dawgDicFixture(keys [][]byte) []byte {
+	type node struct {
+		children map[byte]*node
+		terminal bool
+	}
+	root := &node{children: map[byte]*node{}}
+	for _, key := range keys {
+		n := root
+		for _, c := range key {
+			child, ok := n.children[c]
+			if !ok {
+				child = &node{children: map[byte]*node{}}
+				n.children[c] = child
+			}
+			n = child
+		}
+		n.terminal = true
+	}
+
+	units := make([]uint32, 1)
+	guide := make([]byte, 2)
+	used := map[uint32]bool{0: true}
+	grow := func(i uint32) {
+		for uint32(len(units)) <= i {
+			units = append(units, 0)
+			guide = append(guide, 0, 0)
+		}
+	}
+	var place func(n *node, index uint32)
+	place = func(n *node, index uint32) {
+		labels := slices.Sorted(maps.Keys(n.children))
+		slots := labels
+		if n.terminal {
+			slots = append([]byte{0}, labels...)
+		}
+		if len(slots) == 0 {
+			return
+		}
+		offset := uint32(1)
+		for ; ; offset++ {
+			free := true
+			for _, l := range slots {
+				if used[index^offset^uint32(l)] {
+					free = false
+					break
+				}
+			}
+			if free {
+				break
+			}
+		}
+		units[index] |= offset << 10
+		if n.terminal {
+			units[index] |= 1 << 8
+			leaf := index ^ offset
+			grow(leaf)
+			used[leaf] = true
+			units[leaf] = 1 << 31
+		}
+		for _, l := range labels {
+			child := index ^ offset ^ uint32(l)
+			grow(child)
+			used[child] = true
+			units[child] = uint32(l)
+		}
+		if len(labels) > 0 {
+			guide[2*index] = labels[0]
+		}
+		for i, l := range labels {
+			child := index ^ offset ^ uint32(l)
+			if i+1 < len(labels) {
+				guide[2*child+1] = labels[i+1]
+			}
+			place(n.children[l], child)
+		}
+	}
+	place(root, 0)
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, uint32(len(units)))
+	binary.Write(&b, binary.LittleEndian, units)
+	binary.Write(&b, binary.LittleEndian, uint32(len(units)))
+	b.Write(guide)
+	return b.Bytes()
+}
+
+// pymorphy2Fixture собирает каталог словаря pymorphy2 из двух парадигм: "кот"
+// и "красивый" с превосходной степенью на "наи-".
+func pymorphy2Fixture(t *testing.T, formatVersion string) fstest.MapFS {
+	t.Helper()
+	suffixes := []string{"", "а", "ы", "ый", "ая", "ейший"}
+	gramtab := []string{
+		"NOUN,anim,masc sing,nomn", "NOUN,anim,masc sing,gent", "NOUN,anim,masc plur,nomn",
+		"ADJF,Qual masc,sing,nomn", "ADJF,Qual femn,sing,nomn", "ADJF,Supr,Qual masc,sing,nomn",
+	}
+	paradigms := [][]uint16{
+		{0, 1, 2, 0, 1, 2, 0, 0, 0}, // Окончания, наборы граммем, приставки.
+		{3, 4, 5, 3, 4, 5, 0, 0, 2},
+	}
+	words := []struct {
+		word            string
+		paradigm, index uint16
+	}{
+		{"кот", 0, 0}, {"кота", 0, 1}, {"коты", 0, 2},
+		{"красивый", 1, 0}, {"красивая", 1, 1}, {"наикрасивейший", 1, 2},
+	}
+
+	var keys [][]byte
+	for _, w := range words {
+		record := binary.BigEndian.AppendUint16(binary.BigEndian.AppendUint16(nil, w.paradigm), w.index)
+		keys = append(keys, append([]byte(w.word+"\x01"), base64.StdEncoding.EncodeToString(record)...))
+	}
+	var paradigmsArray bytes.Buffer
+	binary.Write(&paradigmsArray, binary.LittleEndian, uint16(len(paradigms)))
+	for _, p := range paradigms {
+		binary.Write(&paradigmsArray, binary.LittleEndian, uint16(len(p)))
+		binary.Write(&paradigmsArray, binary.LittleEndian, p)
+	}
+	toJSON := func(v any) *fstest.MapFile {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &fstest.MapFile{Data: data}
+	}
+	return fstest.MapFS{
+		"meta.json": toJSON([][]any{
+			{"format_version", formatVersion},
+			{"language_code", "ru"},
+			{"source_revision", 417127},
+			{"compile_options", map[string]any{"paradigm_prefixes": []string{"", "по", "наи"}}},
+		}),
+		"suffixes.json":                toJSON(suffixes),
+		"gramtab-opencorpora-int.json": toJSON(gramtab),
+		"paradigms.array":              &fstest.MapFile{Data: paradigmsArray.Bytes()},
+		"words.dawg":                   &fstest.MapFile{Data: dawgDicFixture(keys)},
+	}
+}
+
+// TestReadPymorphy2Dict проверяет импорт словаря pymorphy2.
+func TestReadPymorphy2Dict(t *testing.T) {
+	var entries []steosmorphy.LexEntry
+	meta, err := steosmorphy.ReadPymorphy2Dict(pymorphy2Fixture(t, "2.4"), func(e steosmorphy.LexEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta != (steosmorphy.Pymorphy2Meta{FormatVersion: "2.4", Language: "ru", SourceRevision: "417127"}) {
+		t.Errorf("Сведения о словаре: %+v", meta)
+	}
+	words := make([]string, 0, len(entries))
+	for _, e := range entries {
+		words = append(words, e.Word)
+	}
+	if want := []string{"кот", "кота", "коты", "красивая", "красивый", "наикрасивейший"}; !slices.Equal(words, want) {
+		t.Errorf("Словоформы %v; ожидали %v", words, want)
+	}
+
+	morph, err := steosmorphy.BuildFromLexicon(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer morph.Close()
+	if parses := morph.Parse("наикрасивейший"); len(parses) == 0 || parses[0].Lemma != "красивый" || !strings.HasPrefix(parses[0].Tags, "Прилагательное") {
+		t.Errorf("Parse(наикрасивейший) = %v", parses)
+	}
+	if parses := morph.Parse("кота"); len(parses) == 0 || parses[0].Lemma != "кот" || !strings.Contains(parses[0].Tags, "Родительный") {
+		t.Errorf("Parse(кота) = %v", parses)
+	}
+	if forms := morph.Inflect("коты"); len(forms) != 3 {
+		t.Errorf("Inflect(коты) = %v; ожидали 3 формы", forms)
+	}
+
+	if _, err := steosmorphy.ReadPymorphy2Dict(pymorphy2Fixture(t, "1.0"), func(steosmorphy.LexEntry) error { return nil }); err == nil {
+		t.Error("Словарь неподдерживаемого формата прочитан без ошибки")
+	}
+}
+
+// vesumFixture - фрагмент словаря ВЕСУМ в формате LanguageTool.
+const vesumFixture = `# Фрагмент dict_corp_lt.txt
+мама мама noun:anim:f:v_naz
+мами мама noun:anim:f:v_rod
+мамо мама noun:anim:f:v_kly
+мами мама noun:anim:p:v_naz
+м'ясо м'ясо noun:inanim:n:v_naz
+м'яса м'ясо noun:inanim:n:v_rod
+п’ять п’ять numr:p:v_naz
+купити купити verb:perf:inf
+купіть купити verb:perf:impr:p:2
+куплений куплений adj:m:v_naz:&adjp:pasv:perf
+я я noun:anim:s:v_naz:&pron:pers:1
+тест тест noninfl
+`
+
+// TestReadVESUM проверяет перевод тегов ВЕСУМ, звательный падеж в таблице словоформ
+// и разбор слов с разными вариантами апострофа.
+func TestReadVESUM(t *testing.T) {
+	var entries []steosmorphy.LexEntry
+	err := steosmorphy.ReadVESUM(strings.NewReader(vesumFixture), func(e steosmorphy.LexEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 11 {
+		t.Fatalf("Прочитано %d словоформ; ожидали 11 (noninfl пропускается)", len(entries))
+	}
+	tags := map[string]string{}
+	for _, e := range entries {
+		tags[e.Word+" "+e.Lemma] = e.Tags
+	}
+	for key, want := range map[string]string{
+		"мамо мама":         "Существительное,Одушевленное,Женский,Единственное число,Звательный",
+		"купіть купити":     "Глагол,Не инфинитив,Совершенный,Повелительное,Множественное число,2-е лицо",
+		"куплений куплений": "Причастие,Полная,Мужской,Единственное число,Именительный,Страдательный,Совершенный",
+		"я я":               "Местоимение,Одушевленное,Единственное число,Именительный,личное местоимение,1-е лицо",
+		"п’ять п’ять":       "Числительное,Множественное число,Именительный",
+	} {
+		if tags[key] != want {
+			t.Errorf("Теги %s: %q; ожидали %q", key, tags[key], want)
+		}
+	}
+
+	morph, err := steosmorphy.BuildFromLexicon(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer morph.Close()
+	if parses := morph.Parse("мамо"); len(parses) == 0 || parses[0].Lemma != "мама" || parses[0].Case != "Звательный" {
+		t.Errorf("Parse(мамо) = %v", parses)
+	}
+	if table := morph.DeclensionTable("мама"); table == nil || !slices.ContainsFunc(table.Rows, func(r steosmorphy.TableRow) bool { return r.Label == "Звательный" }) {
+		t.Errorf("В таблице словоформ мамы нет звательного падежа: %+v", table)
+	}
+	for _, word := range []string{"м'яса", "м’яса", "мʼяса", "П’ять"} {
+		if lemmas := morph.Lemmatize(word); len(lemmas) == 0 || !strings.Contains(lemmas[0], "'") {
+			t.Errorf("Lemmatize(%s) = %v; ожидали лемму с апострофом U+0027", word, lemmas)
+		}
+	}
+	tokens := morph.LemmatizeText("Мамо, купіть м’яса!")
+	var lemmas []string
+	for _, token := range tokens {
+		lemmas = append(lemmas, token.Lemma)
+	}
+	if want := []string{"мама", "купити", "м'ясо"}; !slices.Equal(lemmas, want) {
+		t.Errorf("LemmatizeText: леммы %v; ожидали %v", lemmas, want)
+	}
+
+	if err := steosmorphy.ReadVESUM(strings.NewReader("мама мама\n"), func(steosmorphy.LexEntry) error { return nil }); err == nil {
+		t.Error("Строка без тегов прочитана без ошибки")
+	}
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// newReleaseServer поднимает фейковый сервер релизов, отдающий словарь с поддержкой Range.
+func newReleaseServer(t *testing.T, content []byte, checksum string) *httptest.Server {
+	t.Helper()
+	return newReleaseServerFunc(t, func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "morph.dawg", time.Time{}, bytes.NewReader(content))
+	}, checksum)
+}
+
+// newReleaseServerFunc поднимает фейковый сервер релизов, отдающий словарь обработчиком serveDict.
+func newReleaseServerFunc(t *testing.T, serveDict http.HandlerFunc, checksum string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/morph.dawg", serveDict)
+	mux.HandleFunc("/v1/morph.dawg.sha256", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(checksum + "  morph.dawg\n"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	oldURL := steosmorphy.DictReleaseURL
+	steosmorphy.DictReleaseURL = server.URL
+	t.Cleanup(func() { steosmorphy.DictReleaseURL = oldURL })
+	return server
+}
+
+// TestEnsureDict проверяет загрузку, докачку и проверку контрольной суммы словаря.
+func TestEnsureDict(t *testing.T) {
+	content := []byte(strings.Repeat("steosmorphy-dictionary-", 1000))
+	sum := sha256.Sum256(content)
+	newReleaseServer(t, content, hex.EncodeToString(sum[:]))
+
+	t.Run("Загрузка с нуля", func(t *testing.T) {
+		dir := t.TempDir()
+		path, err := steosmorphy.EnsureDict(context.Background(), "v1", dir)
+		if err != nil {
+			t.Fatalf("EnsureDict вернул ошибку: %v", err)
+		}
+		got, _ := os.ReadFile(path)
+		if !bytes.Equal(got, content) {
+			t.Error("Содержимое скачанного словаря не совпадает с релизом")
+		}
+	})
+
+	t.Run("Докачка прерванной загрузки", func(t *testing.T) {
+		dir := t.TempDir()
+		partPath := filepath.Join(dir, "morph.dawg.part")
+		if err := os.WriteFile(partPath, content[:len(content)/3], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		path, err := steosmorphy.EnsureDict(context.Background(), "v1", dir)
+		if err != nil {
+			t.Fatalf("EnsureDict вернул ошибку: %v", err)
+		}
+		got, _ := os.ReadFile(path)
+		if !bytes.Equal(got, content) {
+			t.Error("Содержимое докачанного словаря не совпадает с релизом")
+		}
+		if _, err := os.Stat(partPath); !os.IsNotExist(err) {
+			t.Error("Временный файл .part не был удален после загрузки")
+		}
+	})
+}
+
+// TestEnsureDict_WrongRange проверяет, что ответ 206 с диапазоном не от места остановки
+// не дописывается в прерванную загрузку.
+func TestEnsureDict_WrongRange(t *testing.T) {
+	content := []byte(strings.Repeat("steosmorphy-dictionary-", 1000))
+	sum := sha256.Sum256(content)
+	const skew = 10
+	requests := 0
+	newReleaseServerFunc(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Range") == "" {
+			_, _ = w.Write(content)
+			return
+		}
+		// Сервер игнорирует запрошенное начало и отдает диапазон с другого места.
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", skew, len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(content[skew:])
+	}, hex.EncodeToString(sum[:]))
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "morph.dawg.part"), content[:len(content)/3], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path, err := steosmorphy.EnsureDict(context.Background(), "v1", dir)
+	if err != nil {
+		t.Fatalf("EnsureDict вернул ошибку: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+		t.Error("Содержимое словаря не совпадает с релизом")
+	}
+	if requests != 2 {
+		t.Errorf("Ожидали повторную загрузку с начала (2 запроса), получили %d", requests)
+	}
+}
+
+// TestEnsureDict_ChecksumMismatch проверяет, что поврежденный словарь не попадает на место.
+func TestEnsureDict_ChecksumMismatch(t *testing.T) {
+	newReleaseServer(t, []byte("corrupted"), strings.Repeat("0", 64))
+
+	dir := t.TempDir()
+	if _, err := steosmorphy.EnsureDict(context.Background(), "v1", dir); err == nil {
+		t.Fatal("Ожидали ошибку несовпадения контрольной суммы")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "morph.dawg")); !os.IsNotExist(err) {
+		t.Error("Словарь с неверной контрольной суммой не должен быть сохранен")
+	}
+}
+
+// TestDictNotFound проверяет, что без словаря LoadMorphAnalyzer подсказывает, как его подключить.
+func TestDictNotFound(t *testing.T) {
+	t.Setenv(steosmorphy.EnvDictPath, "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := steosmorphy.LoadMorphAnalyzer()
+	if !errors.Is(err, steosmorphy.ErrDictNotFound) {
+		t.Fatalf("Ожидали ErrDictNotFound, получили %v", err)
+	}
+	for _, hint := range []string{"github.com/steosofficial/steosmorphy/dict", "EnsureDict", steosmorphy.EnvDictPath} {
+		if !strings.Contains(err.Error(), hint) {
+			t.Errorf("В ошибке нет подсказки %q: %v", hint, err)
+		}
+	}
+}
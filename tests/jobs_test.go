@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steosofficial/steosmorphy/jobs"
+)
+
+// TestJobResume проверяет, что прерванное задание продолжается с контрольной точки
+// и дает тот же результат, что и непрерывный запуск.
+func TestJobResume(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "corpus.txt")
+	text := strings.Repeat("Мама мыла раму, кот спал на столе.\n", 5)
+	if err := os.WriteFile(input, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reference := &jobs.Job{Input: input, Output: filepath.Join(dir, "reference.jsonl")}
+	if err := reference.Run(context.Background(), analyzer); err != nil {
+		t.Fatalf("Ошибка непрерывного запуска: %v", err)
+	}
+	want, _ := os.ReadFile(reference.Output)
+	if got := bytes.Count(want, []byte("\n")); got != 35 {
+		t.Fatalf("Ожидали 35 записей, получили %d", got)
+	}
+
+	// Прерываем задание после первой контрольной точки.
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &jobs.Job{
+		Input:        input,
+		Output:       filepath.Join(dir, "resumed.jsonl"),
+		Interval:     4,
+		OnCheckpoint: func(jobs.Checkpoint) { cancel() },
+	}
+	if err := job.Run(ctx, analyzer); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Ожидали context.Canceled, получили %v", err)
+	}
+	cp, err := jobs.ReadCheckpoint(job.Output + ".checkpoint")
+	if err != nil || cp.Done || cp.Words == 0 {
+		t.Fatalf("Неверная контрольная точка после отмены: %+v, %v", cp, err)
+	}
+
+	// Имитируем сбой: после контрольной точки успела записаться часть строки.
+	file, err := os.OpenFile(job.Output, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"word":"обор`)
+	file.Close()
+
+	job.OnCheckpoint = nil
+	if err := job.Run(context.Background(), analyzer); err != nil {
+		t.Fatalf("Ошибка продолжения задания: %v", err)
+	}
+	got, _ := os.ReadFile(job.Output)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Результат продолженного задания отличается от непрерывного:\n%s\n---\n%s", got, want)
+	}
+	if cp, _ := jobs.ReadCheckpoint(job.Output + ".checkpoint"); !cp.Done || cp.Words != 35 {
+		t.Errorf("Ожидали завершенное задание из 35 слов, получили %+v", cp)
+	}
+}
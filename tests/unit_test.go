@@ -2,18 +2,49 @@
 package tests
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+	"github.com/steosofficial/steosmorphy/compat/pymorphy"
+	"github.com/steosofficial/steosmorphy/snowball"
+	"github.com/steosofficial/steosmorphy/testdict"
+	"io"
 	"log"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var analyzer *steosmorphy.MorphAnalyzer
 
 // TestMain - это специальная функция, которая запускается один раз перед всеми тестами в пакете.
+// Словарь берется из частей в каталоге dict: модуль dict отдельный, и тесты ядра его не импортируют.
 func TestMain(m *testing.M) {
+	if os.Getenv(steosmorphy.EnvDictPath) == "" {
+		dictPath := filepath.Join("..", "dict", steosmorphy.DictFileName)
+		if _, err := os.Stat(dictPath); err != nil {
+			if err := steosmorphy.MergeDictParts(filepath.Dir(dictPath), dictPath); err != nil {
+				log.Fatalf("Не удалось собрать словарь для тестов: %v", err)
+			}
+		}
+		os.Setenv(steosmorphy.EnvDictPath, dictPath)
+	}
+
 	var err error
 	analyzer, err = steosmorphy.LoadMorphAnalyzer()
 	if err != nil {
@@ -296,6 +327,11 @@ func TestAnalyze_OOVWords(t *testing.T) {
 			expectedForms:       []string{"пкауйкйцк", "пкауйкйцка", "пкауйкйцками", "пкауйкйцках", "пкауйкйцку"},
 			shouldBePredictable: true,
 		},
+		{
+			name:                "Короткий шум",
+			word:                "кф",
+			shouldBePredictable: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -341,6 +377,84 @@ func TestAnalyze_OOVWords(t *testing.T) {
 	}
 }
 
+// TestParsePredicted_ShortWords проверяет, что слова из одной-двух букв разбираются
+// по закрытому списку служебных слов, а не по случайным суффиксным правилам.
+func TestParsePredicted_ShortWords(t *testing.T) {
+	parses := analyzer.ParsePredicted("Ну")
+	if len(parses) != 2 || parses[0].PartOfSpeech != "Частица" || parses[1].PartOfSpeech != "Междометие" {
+		t.Fatalf("Ожидали частицу и междометие для 'Ну', получили %+v", parses)
+	}
+	if parses[0].Word != "Ну" || parses[0].Lemma != "ну" {
+		t.Errorf("Неверные слово и лемма: %q, %q", parses[0].Word, parses[0].Lemma)
+	}
+
+	// Слово "о" отсутствует в словаре, но Analyze должен узнать в нем предлог.
+	if parses, forms := analyzer.Analyze("о"); findParse(parses, "о", "Предлог") == nil || forms != nil {
+		t.Errorf("Ожидали предлог 'о' без словоформ, получили %+v, %d форм", parses, len(forms))
+	}
+	if analyzer.IsIndeclinable("ы") || analyzer.Predict("ы", "ы") != nil {
+		t.Error("Для неизвестного короткого слова не должно быть предсказанной парадигмы")
+	}
+}
+
+// TestParsePredictedN проверяет варианты предсказания с оценками: первый совпадает
+// с ParsePredicted, оценки не возрастают, омонимы не теряются.
+func TestParsePredictedN(t *testing.T) {
+	best := analyzer.ParsePredicted("глокая")
+	predictions := analyzer.ParsePredictedN("глокая", 0)
+	if len(best) == 0 || len(predictions) < 2 {
+		t.Fatalf("Ожидали несколько вариантов, получили %+v", predictions)
+	}
+	if predictions[0].Parse.Lemma != best[0].Lemma || predictions[0].Parse.Tags != best[0].Tags {
+		t.Errorf("Первый вариант %+v не совпадает с ParsePredicted %+v", predictions[0].Parse, best[0])
+	}
+	var hasAdjective bool
+	for i, p := range predictions {
+		if p.Score <= 0 || p.Score > 1 || (i > 0 && p.Score > predictions[i-1].Score) {
+			t.Errorf("Неверная оценка варианта %d: %v", i, p.Score)
+		}
+		hasAdjective = hasAdjective || (p.Parse.Lemma == "глокий" && p.Parse.PartOfSpeech == "Прилагательное")
+	}
+	if !hasAdjective {
+		t.Error("Ожидали среди вариантов прилагательное 'глокий'")
+	}
+	if got := analyzer.ParsePredictedN("глокая", 2); len(got) != 2 {
+		t.Errorf("Ожидали 2 варианта, получили %d", len(got))
+	}
+	if got := analyzer.ParsePredictedN("ну", 0); len(got) != 2 || got[0].Score != 1 {
+		t.Errorf("Ожидали 2 разбора 'ну' с оценкой 1, получили %+v", got)
+	}
+}
+
+// TestParseInto проверяет разбор в переиспользуемый буфер без выделения памяти.
+func TestParseInto(t *testing.T) {
+	buf := make([]steosmorphy.Parsed, 0, 8)
+	buf = analyzer.ParseInto("стали", buf)
+	parses := analyzer.Parse("стали")
+	if len(buf) != len(parses) {
+		t.Fatalf("Ожидали %d разборов, получили %d", len(parses), len(buf))
+	}
+	for i := range buf {
+		p, q := &buf[i], parses[i]
+		if p.Lemma != q.Lemma || p.Tags != q.Tags || p.Case != q.Case || len(p.OtherTags) != len(q.OtherTags) || p.Method != q.Method {
+			t.Errorf("Разбор %d: %+v не совпадает с Parse: %+v", i, *p, *q)
+		}
+	}
+
+	// Буфер переиспользуется: теги предыдущего слова не остаются в разборе.
+	buf = analyzer.ParseInto("кот", buf)
+	if len(buf) == 0 || buf[0].Lemma != "кот" || buf[0].Tense != "" {
+		t.Errorf("Неверный разбор 'кот' в переиспользованном буфере: %+v", buf)
+	}
+	if got := analyzer.ParseInto("нейросетями", buf); len(got) != 0 {
+		t.Errorf("Для несловарного слова ожидали пустой результат, получили %+v", got)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = analyzer.ParseInto("стали", buf) }); allocs != 0 {
+		t.Errorf("ParseInto выделяет память: %v аллокаций на вызов", allocs)
+	}
+}
+
 // TestParseList проверяет корректность работы метода пакетной обработки разбора слов.
 func TestParseList(t *testing.T) {
 	words := []string{"мама", "стали", "коту", "нейросети", "сёрчив"}
@@ -384,6 +498,34 @@ func TestParseList(t *testing.T) {
 	}
 }
 
+// TestParseListGrouped проверяет, что разборы сгруппированы по входным словам в их порядке.
+func TestParseListGrouped(t *testing.T) {
+	words := []string{"стали", "кф", "коту", "стали"}
+	groups := analyzer.ParseListGrouped(words)
+	if len(groups) != len(words) {
+		t.Fatalf("Ожидали %d групп, получили %d", len(words), len(groups))
+	}
+	if findParse(groups[0], "сталь", "Существительное") == nil || findParse(groups[0], "стать", "Глагол") == nil {
+		t.Errorf("Ожидали оба разбора 'стали', получили %+v", groups[0])
+	}
+	if groups[1] != nil {
+		t.Errorf("Для неразобранного слова ожидали nil, получили %+v", groups[1])
+	}
+	if len(groups[2]) == 0 || groups[2][0].Lemma != "кот" || len(groups[3]) != len(groups[0]) {
+		t.Errorf("Неверные группы: %+v", groups)
+	}
+
+	many := make([]string, 2500)
+	for i := range many {
+		many[i] = []string{"мама", "коту"}[i%2]
+	}
+	for i, g := range analyzer.ParseListGrouped(many) {
+		if len(g) == 0 || g[0].Word != many[i] {
+			t.Fatalf("Группа %d не соответствует слову %q: %+v", i, many[i], g)
+		}
+	}
+}
+
 // TestInflectList проверяет корректность работы метода пакетной обработки поиска словоформ.
 func TestInflectList(t *testing.T) {
 	words := []string{"мама", "бежать", "нейросети", "лучший"}
@@ -443,13 +585,2177 @@ func TestInflectList(t *testing.T) {
 	}
 }
 
-// findParse ищет в срезе разборов тот, который соответствует ожиданиям.
-// Необходимо для неоднозначных слов.
-func findParse(parses []*steosmorphy.Parsed, lemma, pos string) *steosmorphy.Parsed {
-	for _, p := range parses {
-		if p.Lemma == lemma && p.PartOfSpeech == pos {
-			return p
+// TestInflectCompact проверяет, что компактная таблица содержит те же формы, что и Inflect.
+func TestInflectCompact(t *testing.T) {
+	table := analyzer.InflectCompact("котами")
+	if table.Len() == 0 {
+		t.Fatal("Таблица форм для 'котами' пуста")
+	}
+
+	// "котами" - формы двух лексем: "кот" и "коты" (обувь).
+	lemmas := make(map[string]bool)
+	for _, p := range analyzer.Parse("котами") {
+		lemmas[p.Lemma] = true
+	}
+
+	words := make(map[string]bool)
+	accusative := false
+	for i := 0; i < table.Len(); i++ {
+		words[table.Words[i]] = true
+		p := table.Parsed(i)
+		if !lemmas[p.Lemma] || p.Tags != table.Tags(i) {
+			t.Errorf("Строка %d: неверный разбор %+v", i, p)
+		}
+		if p.Word == "кота" && p.Lemma == "кот" && p.Case == "Винительный" {
+			accusative = true
 		}
 	}
-	return nil
+	for _, p := range analyzer.Inflect("котами") {
+		if !words[p.Word] {
+			t.Errorf("Форма '%s' из Inflect отсутствует в таблице", p.Word)
+		}
+	}
+	if !accusative {
+		t.Error("Омонимичная форма 'кота' (В.п.) должна сохраниться в таблице")
+	}
+
+	if empty := analyzer.InflectCompact("нейросеть"); empty.Len() != 0 {
+		t.Errorf("Для несловарного слова ожидали пустую таблицу, получили %d форм", empty.Len())
+	}
+}
+
+// TestListCtx проверяет отмену пакетной обработки через контекст.
+func TestListCtx(t *testing.T) {
+	words := []string{"мама", "мыла", "раму"}
+
+	results, err := analyzer.ParseListCtx(context.Background(), words)
+	if err != nil || len(results) != len(analyzer.ParseList(words)) {
+		t.Fatalf("ParseListCtx без отмены должен совпадать с ParseList, ошибка: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := analyzer.ParseListCtx(ctx, words); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseListCtx: ожидали context.Canceled, получили %v", err)
+	}
+	if _, err := analyzer.InflectListCtx(ctx, words); !errors.Is(err, context.Canceled) {
+		t.Errorf("InflectListCtx: ожидали context.Canceled, получили %v", err)
+	}
+}
+
+// TestBatchOptions проверяет настройки пакетной обработки: порядок, повторы, воркеры и пакеты.
+func TestBatchOptions(t *testing.T) {
+	words := []string{"раму", "мама", "коту", "мама"}
+
+	ordered := analyzer.ParseList(words, steosmorphy.PreserveOrder(), steosmorphy.BatchWorkers(3), steosmorphy.BatchChunkSize(1))
+	var got []string
+	for _, p := range ordered {
+		if len(got) == 0 || got[len(got)-1] != p.Word {
+			got = append(got, p.Word)
+		}
+	}
+	if !slices.Equal(got, words) {
+		t.Errorf("Ожидали порядок входных слов %v, получили %v", words, got)
+	}
+
+	sorted := analyzer.ParseList(words, steosmorphy.BatchWorkers(1))
+	if !sort.SliceIsSorted(sorted, func(i, j int) bool { return sorted[i].Word < sorted[j].Word }) || len(sorted) != len(ordered) {
+		t.Error("Без PreserveOrder результат должен быть отсортирован")
+	}
+
+	deduped := analyzer.ParseList(words, steosmorphy.Deduplicate())
+	single := analyzer.ParseList([]string{"мама"})
+	if len(deduped) != len(sorted)-len(single) {
+		t.Errorf("Deduplicate должен убрать разборы повторного слова: %d из %d", len(deduped), len(sorted))
+	}
+
+	forms := analyzer.InflectList([]string{"кот", "мама"}, steosmorphy.PreserveOrder())
+	if len(forms) == 0 || forms[0].Lemma != "кот" || forms[len(forms)-1].Lemma != "мама" {
+		t.Error("InflectList с PreserveOrder должен выдавать формы по словам в порядке входа")
+	}
+}
+
+// TestInflectListFunc проверяет порционную выдачу словоформ в рамках бюджета памяти.
+func TestInflectListFunc(t *testing.T) {
+	budget := steosmorphy.NewMemoryBudget(1) // Меньше любой порции: порции выдаются строго по одной.
+	limited, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithMemoryBudget(budget))
+	if err != nil {
+		t.Fatalf("Не удалось загрузить анализатор: %v", err)
+	}
+
+	words := make([]string, 0, 3000)
+	for len(words) < cap(words) {
+		words = append(words, "мама", "кот", "стол")
+	}
+
+	total, chunks := 0, 0
+	err = limited.InflectListFunc(words, func(forms []*steosmorphy.Parsed) error {
+		chunks++
+		total += len(forms)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if expected := len(analyzer.InflectList(words)); total != expected {
+		t.Errorf("Ожидали %d словоформ, получили %d", expected, total)
+	}
+	if chunks != 3 {
+		t.Errorf("Ожидали 3 порции, получили %d", chunks)
+	}
+	if budget.Used() != 0 {
+		t.Errorf("После завершения бюджет должен быть свободен, занято %d байт", budget.Used())
+	}
+
+	// Ошибка обработчика прерывает генерацию и возвращается вызывающему.
+	stopErr := fmt.Errorf("стоп")
+	err = limited.InflectListFunc(words, func([]*steosmorphy.Parsed) error { return stopErr })
+	if err != stopErr {
+		t.Errorf("Ожидали ошибку обработчика, получили %v", err)
+	}
+	if budget.Used() != 0 {
+		t.Errorf("После ошибки бюджет должен быть свободен, занято %d байт", budget.Used())
+	}
+}
+
+// TestInflectEach проверяет поштучную выдачу словоформ в порядке слов и остановку по ошибке.
+func TestInflectEach(t *testing.T) {
+	words := []string{"кот", "кф", "мама"}
+	var got []string
+	err := analyzer.InflectEach(words, func(word string, forms []*steosmorphy.Parsed) error {
+		got = append(got, word)
+		if word == "кф" && forms != nil {
+			t.Errorf("Для неразобранного слова ожидали nil, получили %d форм", len(forms))
+		}
+		if word == "кот" && !slices.ContainsFunc(forms, func(p *steosmorphy.Parsed) bool { return p.Word == "котами" }) {
+			t.Error("Среди форм 'кот' нет 'котами'")
+		}
+		return nil
+	})
+	if err != nil || !slices.Equal(got, words) {
+		t.Errorf("Ожидали слова %v без ошибки, получили %v, %v", words, got, err)
+	}
+
+	stop := errors.New("стоп")
+	calls := 0
+	err = analyzer.InflectEach(words, func(string, []*steosmorphy.Parsed) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Ошибка должна прекращать обработку: %v, вызовов %d", err, calls)
+	}
+}
+
+// TestInflectListSorted проверяет внешнюю сортировку словоформ: при крошечном лимите
+// каждая порция сбрасывается на диск, а слитый поток совпадает с InflectList.
+func TestInflectListSorted(t *testing.T) {
+	words := make([]string, 0, 3000)
+	for len(words) < cap(words) {
+		words = append(words, "стали", "кот", "мама")
+	}
+	var expected strings.Builder
+	for _, p := range analyzer.InflectList(words) {
+		fmt.Fprintf(&expected, "%s %s %s\n", p.Word, p.Lemma, p.Tags)
+	}
+
+	tempDir := t.TempDir()
+	for _, memLimit := range []int64{1, 1 << 30} {
+		var got strings.Builder
+		for p, err := range analyzer.InflectListSorted(words, memLimit, tempDir) {
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			fmt.Fprintf(&got, "%s %s %s\n", p.Word, p.Lemma, p.Tags)
+		}
+		if got.String() != expected.String() {
+			t.Errorf("Лимит %d: поток отличается от InflectList", memLimit)
+		}
+	}
+
+	// Граммемы переводятся на язык анализатора независимо от того, сбрасывались ли формы на диск.
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLang(steosmorphy.LangEnglish))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, memLimit := range []int64{1, 1 << 30} {
+		for p, err := range morph.InflectListSorted([]string{"кот"}, memLimit, tempDir) {
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if p.PartOfSpeech != "noun" || strings.Contains(p.Tags, "Существительное") {
+				t.Errorf("Лимит %d: форма %q не переведена: %q, %q", memLimit, p.Word, p.PartOfSpeech, p.Tags)
+				break
+			}
+		}
+	}
+
+	// Досрочное завершение обхода удаляет временные файлы.
+	for range analyzer.InflectListSorted(words, 1, tempDir) {
+		break
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+		t.Errorf("Временные файлы не удалены: %v", entries)
+	}
+}
+
+// TestLemmatize проверяет получение лемм без генерации словоформ.
+func TestLemmatize(t *testing.T) {
+	testCases := map[string]string{
+		"мыла":      "мыло мыть",
+		"кота":      "кот кота",
+		"нейросети": "нейросеть",
+		"":          "",
+	}
+	for word, expected := range testCases {
+		if got := strings.Join(analyzer.Lemmatize(word), " "); got != expected {
+			t.Errorf("Lemmatize(%q): ожидали '%s', получили '%s'", word, expected, got)
+		}
+	}
+}
+
+// TestInflectListTo проверяет постановку списка слов в одну форму с сохранением порядка.
+func TestInflectListTo(t *testing.T) {
+	words := []string{"кот", "мама", "нейросеть", "и", "стол"}
+	expected := []string{"котов", "мам", "нейросетей", "", "столов"}
+
+	results := analyzer.InflectListTo(words, []string{"Родительный", "Множественное число"})
+	if len(results) != len(words) {
+		t.Fatalf("Ожидалось %d результатов, получено %d", len(words), len(results))
+	}
+	for i, p := range results {
+		got := ""
+		if p != nil {
+			got = p.Word
+		}
+		if got != expected[i] {
+			t.Errorf("Для '%s' ожидали '%s', получили '%s'", words[i], expected[i], got)
+		}
+	}
+}
+
+// countingCache считает обращения к кэшу.
+type countingCache struct {
+	steosmorphy.Cache
+	hits, puts int
+}
+
+func (c *countingCache) Get(key string) ([]*steosmorphy.Parsed, bool) {
+	v, ok := c.Cache.Get(key)
+	if ok {
+		c.hits++
+	}
+	return v, ok
+}
+
+func (c *countingCache) Put(key string, v []*steosmorphy.Parsed) {
+	c.puts++
+	c.Cache.Put(key, v)
+}
+
+// TestCache проверяет подключение пользовательского кэша и вытеснение во встроенном LRU.
+func TestCache(t *testing.T) {
+	cache := &countingCache{Cache: steosmorphy.NewLRUCache(2)}
+	cached, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithCache(cache))
+	if err != nil {
+		t.Fatalf("Не удалось загрузить анализатор: %v", err)
+	}
+
+	first := cached.Parse("кота")
+	second := cached.Parse("кота")
+	if cache.hits != 1 || cache.puts != 1 || first[0] != second[0] {
+		t.Errorf("Повторный Parse должен обслуживаться кэшем: попаданий %d, записей %d", cache.hits, cache.puts)
+	}
+
+	// Вызывающий получает свою копию среза: перестановка не меняет результат для других.
+	if len(first) < 2 {
+		t.Fatalf("Ожидали несколько разборов 'кота', получили %d", len(first))
+	}
+	slices.Reverse(first)
+	if third := cached.Parse("кота"); third[0] != second[0] {
+		t.Error("Перестановка возвращенного среза изменила закэшированный результат")
+	}
+
+	// Емкость 2: после двух новых ключей "parse:кота" вытесняется.
+	cached.Parse("стол")
+	cached.ParsePredicted("нейросеть")
+	if _, ok := cache.Cache.Get("parse:кота"); ok {
+		t.Error("Самая давняя запись должна быть вытеснена из LRU")
+	}
+	if _, ok := cache.Cache.Get("predict:нейросеть"); !ok {
+		t.Error("Результат ParsePredicted должен быть закэширован")
+	}
+}
+
+// TestInterceptor проверяет порядок перехватчиков, переписывание входа и постобработку результата.
+func TestInterceptor(t *testing.T) {
+	var calls []string
+	logging := func(name string) steosmorphy.Interceptor {
+		return func(next steosmorphy.ParseFunc) steosmorphy.ParseFunc {
+			return func(word string) []*steosmorphy.Parsed {
+				calls = append(calls, name+":"+word)
+				return next(word)
+			}
+		}
+	}
+	// Переписываем вход: "ё" -> "е".
+	normalize := func(next steosmorphy.ParseFunc) steosmorphy.ParseFunc {
+		return func(word string) []*steosmorphy.Parsed {
+			return next(strings.ReplaceAll(word, "ё", "е"))
+		}
+	}
+	// Постобработка: оставляем только первый разбор.
+	firstOnly := func(next steosmorphy.ParseFunc) steosmorphy.ParseFunc {
+		return func(word string) []*steosmorphy.Parsed {
+			parses := next(word)
+			if len(parses) > 1 {
+				return parses[:1]
+			}
+			return parses
+		}
+	}
+
+	intercepted, err := steosmorphy.LoadMorphAnalyzer(
+		steosmorphy.WithInterceptor(logging("внешний")),
+		steosmorphy.WithInterceptor(normalize),
+		steosmorphy.WithInterceptor(logging("внутренний")),
+		steosmorphy.WithInflectInterceptor(firstOnly),
+	)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить анализатор: %v", err)
+	}
+
+	intercepted.Parse("ёлка")
+	if strings.Join(calls, " ") != "внешний:ёлка внутренний:елка" {
+		t.Errorf("Неверный порядок вызовов перехватчиков: %v", calls)
+	}
+	if forms := intercepted.Inflect("кот"); len(forms) != 1 {
+		t.Errorf("Перехватчик Inflect должен оставить одну форму, получили %d", len(forms))
+	}
+}
+
+// TestResolveAccusative проверяет отбрасывание винительного падежа, противоречащего одушевленности.
+func TestResolveAccusative(t *testing.T) {
+	testCases := []struct {
+		word             string
+		lemma            string
+		expectAccusative bool
+	}{
+		{word: "стола", lemma: "стол", expectAccusative: false}, // "стола" - не винительный неодушевленного "стол"
+		{word: "кота", lemma: "кот", expectAccusative: true},    // "кота" - винительный одушевленного "кот"
+		{word: "стол", lemma: "стол", expectAccusative: true},
+		{word: "кот", lemma: "кот", expectAccusative: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.word, func(t *testing.T) {
+			resolved := steosmorphy.ResolveAccusative(analyzer.Parse(tc.word))
+
+			hasAccusative := false
+			for _, p := range resolved {
+				if p.Lemma == tc.lemma && p.Case == "Винительный" {
+					hasAccusative = true
+				}
+			}
+			if hasAccusative != tc.expectAccusative {
+				t.Errorf("Для '%s' ожидали наличие винительного падежа: %v, получили: %v", tc.word, tc.expectAccusative, hasAccusative)
+			}
+		})
+	}
+}
+
+// TestMakeAgreeWithNumber проверяет согласование с числом с учетом pluralia/singularia tantum.
+func TestMakeAgreeWithNumber(t *testing.T) {
+	if got := analyzer.NumberTantum("ножницы"); got != steosmorphy.PluraliaTantum {
+		t.Errorf("'ножницы' должны быть pluralia tantum, получили %v", got)
+	}
+	if got := analyzer.NumberTantum("кот"); got != steosmorphy.NoTantum {
+		t.Errorf("'кот' не должен иметь ограничений по числу, получили %v", got)
+	}
+
+	testCases := []struct {
+		word     string
+		n        int
+		expected string
+	}{
+		{"кот", 1, "кот"},
+		{"кот", 2, "кота"},
+		{"кот", 5, "котов"},
+		{"кот", 21, "кот"},
+		{"кот", 12, "котов"},
+		{"котам", 1, "коту"},
+		{"ножницы", 1, "ножницы"}, // Несуществующее "ножница" не строится.
+		{"ножницы", 2, "ножниц"},
+		{"кофе", 5, "кофе"},
+	}
+	for _, tc := range testCases {
+		if got := analyzer.MakeAgreeWithNumber(tc.word, tc.n); got != tc.expected {
+			t.Errorf("MakeAgreeWithNumber(%q, %d): ожидали '%s', получили '%s'", tc.word, tc.n, tc.expected, got)
+		}
+	}
+}
+
+// TestIsIndeclinable проверяет определение несклоняемых слов.
+func TestIsIndeclinable(t *testing.T) {
+	testCases := map[string]bool{
+		"кофе":   true,
+		"метро":  true,
+		"пальто": true,
+		"сочи":   true, // Разговорное "в Сочах" не делает слово склоняемым.
+		"кот":    false,
+		"стали":  false,
+		"быстро": false, // Наречие не относится к склоняемым частям речи.
+	}
+	for word, expected := range testCases {
+		if got := analyzer.IsIndeclinable(word); got != expected {
+			t.Errorf("IsIndeclinable(%q): ожидали %v, получили %v", word, expected, got)
+		}
+	}
+}
+
+// TestHighlightMatches проверяет поиск словоформ лемм запроса в тексте.
+func TestHighlightMatches(t *testing.T) {
+	text := "Кот увидел котов у стола. Столы, коты и кто-нибудь ещё."
+	spans := analyzer.HighlightMatches(text, []string{"кот", "Стол"})
+
+	expected := []struct{ word, lemma string }{
+		{"Кот", "кот"},
+		{"котов", "кот"},
+		{"стола", "Стол"},
+		{"Столы", "Стол"},
+		{"коты", "кот"},
+	}
+	if len(spans) != len(expected) {
+		t.Fatalf("Ожидали %d совпадений, получили %d: %+v", len(expected), len(spans), spans)
+	}
+	for i, span := range spans {
+		if got := text[span.Start:span.End]; got != expected[i].word || span.Lemma != expected[i].lemma {
+			t.Errorf("Совпадение %d: ожидали '%s' (%s), получили '%s' (%s)", i, expected[i].word, expected[i].lemma, got, span.Lemma)
+		}
+	}
+}
+
+// TestAnalyzeStream проверяет потоковый разбор текста.
+func TestAnalyzeStream(t *testing.T) {
+	text := "Мама мыла раму, а кто-нибудь -- нейросеть!"
+
+	// Побайтовое чтение проверяет слова и многобайтовые символы на границах буфера.
+	for _, r := range []io.Reader{strings.NewReader(text), iotest.OneByteReader(strings.NewReader(text))} {
+		var words []string
+		err := analyzer.AnalyzeStream(r, func(p *steosmorphy.Parsed) error {
+			if len(words) == 0 || words[len(words)-1] != p.Word {
+				words = append(words, p.Word)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка: %v", err)
+		}
+		if got := strings.Join(words, " "); got != "Мама мыла раму а кто-нибудь нейросеть" {
+			t.Errorf("Неверная последовательность слов: %s", got)
+		}
+	}
+
+	// Ошибка обработчика прерывает чтение.
+	stopErr := errors.New("стоп")
+	calls := 0
+	err := analyzer.AnalyzeStream(strings.NewReader(text), func(*steosmorphy.Parsed) error {
+		calls++
+		return stopErr
+	})
+	if err != stopErr || calls != 1 {
+		t.Errorf("Ожидали остановку после первого разбора, получили ошибку %v и %d вызовов", err, calls)
+	}
+
+	// Последовательность букв длиннее буфера чтения пропускается, и поток продолжается.
+	blob := "кот " + strings.Repeat("аБ", 40<<10) + "! мама мыла раму"
+	unlimited, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithMaxWordLen(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, morph := range []*steosmorphy.MorphAnalyzer{analyzer, unlimited} {
+		var words []string
+		var end int64
+		err := morph.AnalyzeWordStream(strings.NewReader(blob), func(word string, offset int64, _ []*steosmorphy.Parsed) error {
+			words, end = append(words, word), offset
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Длинная последовательность букв прервала поток: %v", err)
+		}
+		if got := strings.Join(words, " "); got != "кот мама мыла раму" || end != int64(len(blob)) {
+			t.Errorf("Слова после длинной последовательности букв: %q, смещение %d из %d", got, end, len(blob))
+		}
+	}
+}
+
+// TestSameLexeme проверяет сравнение словоформ с точностью до лексемы.
+func TestSameLexeme(t *testing.T) {
+	testCases := []struct {
+		first, second string
+		expected      bool
+	}{
+		{"Иванову", "Ивановым", true}, // Несловарная фамилия: одна форма порождается из другой.
+		{"Москвой", "москве", true},
+		{"шёл", "идти", true}, // Супплетивные формы.
+		{"стали", "сталь", true},
+		{"стали", "стать", true},
+		{"сталь", "стать", false},
+		{"кот", "стол", false},
+	}
+	for _, tc := range testCases {
+		if got := analyzer.SameLexeme(tc.first, tc.second); got != tc.expected {
+			t.Errorf("SameLexeme(%q, %q): ожидали %v, получили %v", tc.first, tc.second, tc.expected, got)
+		}
+	}
+}
+
+// TestJSONOptions проверяет настраиваемую JSON-сериализацию разбора.
+func TestJSONOptions(t *testing.T) {
+	p := findParse(analyzer.Parse("кота"), "кот", "Существительное")
+	if p == nil {
+		t.Fatal("Не найден разбор 'кота'")
+	}
+
+	// Нулевые настройки совпадают со стандартной сериализацией.
+	standard, _ := json.Marshal(p)
+	custom, err := steosmorphy.JSONOptions{}.Marshal(p)
+	if err != nil || string(custom) != string(standard) {
+		t.Errorf("Нулевые настройки должны давать стандартный JSON:\n%s\n%s", standard, custom)
+	}
+
+	custom, err = steosmorphy.JSONOptions{
+		Naming:    steosmorphy.CamelCase,
+		OmitEmpty: true,
+		OtherTags: steosmorphy.OtherTagsArray,
+	}.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(custom, &decoded); err != nil {
+		t.Fatalf("Некорректный JSON: %v", err)
+	}
+	if decoded["partOfSpeech"] != "Существительное" {
+		t.Errorf("Ожидали поле partOfSpeech, получили %s", custom)
+	}
+	if _, ok := decoded["tense"]; ok {
+		t.Errorf("Пустое поле tense должно быть пропущено: %s", custom)
+	}
+	if _, ok := decoded["otherTags"].([]any); !ok {
+		t.Errorf("otherTags должен быть массивом: %s", custom)
+	}
+
+	inline, _ := steosmorphy.JSONOptions{OtherTags: steosmorphy.OtherTagsInline}.MarshalList([]*steosmorphy.Parsed{p})
+	var list []map[string]any
+	if err := json.Unmarshal(inline, &list); err != nil || list[0]["Нарицательное"] != true {
+		t.Errorf("Теги OtherTags должны быть встроены в объект: %s", inline)
+	}
+}
+
+// TestCoverageReport проверяет отчет о покрытии корпуса словарем.
+func TestCoverageReport(t *testing.T) {
+	words := []string{"Мама", "мыла", "раму", "нейросеть", "нейросеть", "думскроллинг", "краудфандинг", "лайфхакинг", ""}
+	report := analyzer.CoverageReport(slices.Values(words))
+
+	if report.TotalWords != 8 || report.DictionaryWords != 3 {
+		t.Fatalf("Ожидали 3 словарных слова из 8, получили %d из %d", report.DictionaryWords, report.TotalWords)
+	}
+	if report.TopOOV[0].Word != "нейросеть" || report.TopOOV[0].Count != 2 {
+		t.Errorf("Самым частым несловарным словом должна быть 'нейросеть', получили %+v", report.TopOOV[0])
+	}
+	if len(report.SuffixRules) != 1 || report.SuffixRules[0].Suffix != "инг" || report.SuffixRules[0].Words != 3 {
+		t.Errorf("Ожидали единственный кандидат 'инг' на 3 слова, получили %+v", report.SuffixRules)
+	}
+}
+
+// TestSuggestDictEntries проверяет группировку несловарных слов в предлагаемые лексемы.
+func TestSuggestDictEntries(t *testing.T) {
+	words := []string{"нейросеть", "нейросети", "нейросетями", "мама", "думскроллинг", "2024"}
+	suggestions := analyzer.SuggestDictEntries(slices.Values(words), 2)
+
+	if len(suggestions) != 1 {
+		t.Fatalf("Ожидали одну группу, получили %d: %+v", len(suggestions), suggestions)
+	}
+	s := suggestions[0]
+	if s.Lemma != "нейросеть" || s.Count != 3 || len(s.Observed) != 3 {
+		t.Errorf("Неверная группа: лемма %s, вхождений %d, форм в корпусе %d", s.Lemma, s.Count, len(s.Observed))
+	}
+
+	// Предложенные записи пригодны для TSV-лексикона компилятора словаря.
+	var buf strings.Builder
+	if err := steosmorphy.WriteTSVLexicon(&buf, s.Entries); err != nil {
+		t.Fatal(err)
+	}
+	var parsed []steosmorphy.LexEntry
+	err := steosmorphy.ReadTSVLexicon(strings.NewReader(buf.String()), func(e steosmorphy.LexEntry) error {
+		parsed = append(parsed, e)
+		return nil
+	})
+	if err != nil || !slices.Equal(parsed, s.Entries) {
+		t.Errorf("Записи не пережили запись и чтение TSV: %v", err)
+	}
+	if !slices.ContainsFunc(s.Entries, func(e steosmorphy.LexEntry) bool { return e.Word == "нейросетям" }) {
+		t.Error("В предложенной парадигме нет формы 'нейросетям'")
+	}
+}
+
+// TestLookupStrategies проверяет, что стратегии поиска переходов дают одинаковые результаты.
+func TestLookupStrategies(t *testing.T) {
+	if analyzer.LookupStrategy() == steosmorphy.LookupAuto {
+		t.Error("После загрузки стратегия должна быть выбрана замером")
+	}
+
+	strategies := []steosmorphy.LookupStrategy{steosmorphy.LookupBinarySearch, steosmorphy.LookupFirstCharIndex}
+	results := make([]string, len(strategies))
+	for i, strategy := range strategies {
+		morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLookupStrategy(strategy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if morph.LookupStrategy() != strategy {
+			t.Errorf("Ожидали стратегию %s, получили %s", strategy, morph.LookupStrategy())
+		}
+		var sb strings.Builder
+		for _, word := range []string{"стали", "Ёжик", "яблоко", "нейросетью", "qwerty", "ъ"} {
+			for _, p := range append(morph.Parse(word), morph.ParsePredicted(word)...) {
+				fmt.Fprintf(&sb, "%s %s %s\n", p.Word, p.Lemma, p.Tags)
+			}
+		}
+		results[i] = sb.String()
+	}
+	if results[0] == "" || results[0] != results[1] {
+		t.Errorf("Результаты стратегий различаются:\n%s\n---\n%s", results[0], results[1])
+	}
+
+	benchmarks := analyzer.MicroBenchmarks(time.Millisecond)
+	if len(benchmarks) != 4 {
+		t.Fatalf("Ожидали 4 микробенчмарка, получили %d", len(benchmarks))
+	}
+	for _, b := range benchmarks {
+		if b.Iterations == 0 || b.NsPerOp <= 0 {
+			t.Errorf("Неверный результат микробенчмарка: %+v", b)
+		}
+	}
+}
+
+// TestMixedScriptRepair проверяет исправление слов со смешанной кириллицей и латиницей.
+func TestMixedScriptRepair(t *testing.T) {
+	testCases := []struct {
+		word, expected string
+		ok             bool
+	}{
+		{"Моskва", "Москва", true},
+		{"пpивет", "привет", true}, // латинская p
+		{"coбaкa", "собака", true},
+		{"мама", "мама", false},   // только кириллица
+		{"hello", "hello", false}, // только латиница
+		{"кqщ", "кqщ", false},     // нет в словаре ни в каком варианте
+	}
+	for _, tc := range testCases {
+		repaired, ok := analyzer.RepairMixedScript(tc.word)
+		if repaired != tc.expected || ok != tc.ok {
+			t.Errorf("RepairMixedScript(%q) = %q, %v; ожидали %q, %v", tc.word, repaired, ok, tc.expected, tc.ok)
+		}
+	}
+
+	if len(analyzer.Parse("пpивет")) != 0 {
+		t.Error("Без опции слово со смешанным алфавитом не должно разбираться")
+	}
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithMixedScriptRepair())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parses := morph.Parse("coбaкa")
+	if len(parses) == 0 || parses[0].Lemma != "собака" || parses[0].Word != "coбaкa" {
+		t.Fatalf("Ожидали разбор 'собака' с исходным написанием, получили %+v", parses)
+	}
+	if findForm(morph.Inflect("coбaкa"), "собаками") == nil {
+		t.Error("Inflect исправленного слова не вернул форму 'собаками'")
+	}
+}
+
+// TestFoldHomoglyphs проверяет замену букв-двойников до поиска в словаре.
+func TestFoldHomoglyphs(t *testing.T) {
+	testCases := []struct{ word, expected string }{
+		{"пpивет", "привет"},   // латинская p
+		{"кoшкa", "кошка"},     // латинские o и a
+		{"pаypal", "paypal"},   // кириллическая а в латинском слове
+		{"СOР", "СОР"},         // все буквы - двойники: слово считается кириллическим
+		{"Моskва", "Моskва"},   // буквы без двойника в обоих алфавитах
+		{"кошка", "кошка"},     // только кириллица
+		{"paypal", "paypal"},   // только латиница
+		{"к-0-т", "к-0-т"},     // цифры не заменяются
+		{"ёжuк", "ёжuк"},       // u не двойник кириллической буквы
+		{"x-ray", "x-ray"},     // только латиница
+		{"хoрошо", "хорошо"},   // латинская o среди кириллицы
+		{"Hоrse", "Horse"},     // кириллическая о в латинском слове
+		{"тeст123", "тест123"}, // латинская e
+	}
+	for _, tc := range testCases {
+		if folded := steosmorphy.FoldHomoglyphs(tc.word); folded != tc.expected {
+			t.Errorf("FoldHomoglyphs(%q) = %q; ожидали %q", tc.word, folded, tc.expected)
+		}
+	}
+
+	morph := testdict.Load(t, steosmorphy.WithHomoglyphFolding())
+	parses, forms := morph.Analyze("кoшкa")
+	if len(parses) == 0 || parses[0].Lemma != "кошка" || parses[0].Method != steosmorphy.UnitDictionary {
+		t.Errorf("Analyze(кoшкa) = %v; ожидали словарный разбор 'кошка'", parses)
+	}
+	if findForm(forms, "кошками") == nil {
+		t.Error("Analyze(кoшкa) не вернул форму 'кошками'")
+	}
+	if parses := testdict.Load(t).Parse("кoшкa"); parses != nil {
+		t.Errorf("Без опции слово с латинскими буквами не должно быть словарным, получили %v", parses)
+	}
+}
+
+// TestParseOCR проверяет исправление типичных ошибок OCR одной заменой символа.
+func TestParseOCR(t *testing.T) {
+	testCases := []struct{ word, expected string }{
+		{"к0т", "кот"},   // цифра вместо буквы
+		{"пюди", "люди"}, // п вместо л
+		{"шука", "щука"}, // ш вместо щ
+	}
+	for _, tc := range testCases {
+		matches := analyzer.ParseOCR(tc.word)
+		if len(matches) == 0 || matches[0].Word != tc.expected {
+			t.Errorf("ParseOCR(%q): ожидали исправление %q, получили %+v", tc.word, tc.expected, matches)
+			continue
+		}
+		if c := matches[0].Confidence; c <= 0 || c >= 1 || matches[0].Parses[0].Word != tc.word {
+			t.Errorf("ParseOCR(%q): неверные уверенность %v или исходное слово", tc.word, c)
+		}
+	}
+	if matches := analyzer.ParseOCR("снег"); len(matches) != 1 || matches[0].Confidence != 1 {
+		t.Errorf("Для словарного слова ожидали точное совпадение, получили %+v", matches)
+	}
+	if matches := analyzer.ParseOCR("кщт"); len(matches) != 0 {
+		t.Errorf("Две ошибки не должны исправляться, получили %+v", matches)
+	}
+
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithOCRTolerance())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findParse(morph.Parse("3има"), "зима", "Существительное") == nil {
+		t.Error("С опцией Parse должен разобрать '3има' как 'зима'")
+	}
+	if findForm(morph.Inflect("3има"), "зимами") == nil {
+		t.Error("Inflect исправленного слова не вернул форму 'зимами'")
+	}
+}
+
+// TestSplitConcatenated проверяет разбиение слитных строк на словарные слова.
+func TestSplitConcatenated(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"купитьквартирумосква", []string{"купить", "квартиру", "москва"}},
+		{"#ЛюблюМоскву", []string{"Люблю", "Москву"}},
+		{"купить-квартиру.рф", []string{"купить", "квартиру", "рф"}},
+		{"ремонтквартирподключ", []string{"ремонт", "квартир", "под", "ключ"}},
+		{"", nil},
+	}
+	for _, tc := range testCases {
+		if got := analyzer.SplitConcatenated(tc.input); !slices.Equal(got, tc.expected) {
+			t.Errorf("SplitConcatenated(%q) = %q; ожидали %q", tc.input, got, tc.expected)
+		}
+	}
+
+	// Символы вне словаря не теряются, а образуют собственный сегмент.
+	if got := strings.Join(analyzer.SplitConcatenated("приветщщщмир"), ""); got != "приветщщщмир" {
+		t.Errorf("Разбиение потеряло символы: %q", got)
+	}
+}
+
+// TestTypeAhead проверяет дополнение префикса, исправление опечатки и ранжирование по частотам.
+func TestTypeAhead(t *testing.T) {
+	suggestions := analyzer.TypeAhead("Москв", 5)
+	if len(suggestions) != 5 || suggestions[0].Word != "москва" || suggestions[0].Distance != 0 {
+		t.Fatalf("TypeAhead(\"Москв\") = %+v; ожидали 5 подсказок, первая - \"москва\"", suggestions)
+	}
+	for _, s := range suggestions {
+		if !strings.HasPrefix(s.Word, "москв") {
+			t.Errorf("Подсказка %q не начинается с префикса", s.Word)
+		}
+	}
+
+	// Точных дополнений нет: исправляется опечатка в префиксе.
+	suggestions = analyzer.TypeAhead("програмист", 3)
+	if len(suggestions) == 0 || suggestions[0].Word != "программист" || suggestions[0].Distance != 1 {
+		t.Errorf("TypeAhead(\"програмист\") = %+v; ожидали исправление \"программист\"", suggestions)
+	}
+
+	if got := analyzer.TypeAhead("", 5); got != nil {
+		t.Errorf("Для пустого префикса ожидали nil, получили %+v", got)
+	}
+
+	ranked, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithWordFrequencies(map[string]uint64{"котлета": 100}))
+	if err != nil {
+		t.Fatalf("Не удалось загрузить словарь: %v", err)
+	}
+	if got := ranked.TypeAhead("кот", 3); len(got) == 0 || got[0].Word != "котлета" || got[0].Frequency != 100 {
+		t.Errorf("Ожидали, что частотная \"котлета\" будет первой, получили %+v", got)
+	}
+}
+
+// TestLemmatizeText проверяет токенизацию, смещения и фильтрацию токенов текста.
+func TestLemmatizeText(t *testing.T) {
+	const text = "Кошки и коты ловили 3,5 рыбы... Кто-нибудь!"
+
+	tokens := analyzer.LemmatizeText(text)
+	var lemmas []string
+	for _, tok := range tokens {
+		if text[tok.Start:tok.End] != tok.Text {
+			t.Errorf("Смещения токена %q не совпадают с текстом: %d-%d", tok.Text, tok.Start, tok.End)
+		}
+		lemmas = append(lemmas, tok.Lemma)
+	}
+	if expected := []string{"кошка", "и", "кот", "ловить", "3,5", "рыба", "кто-нибудь"}; !slices.Equal(lemmas, expected) {
+		t.Errorf("LemmatizeText() леммы = %q; ожидали %q", lemmas, expected)
+	}
+	if tokens[4].Kind != steosmorphy.TokenNumber {
+		t.Errorf("Токен %q должен быть числом, получили %s", tokens[4].Text, tokens[4].Kind)
+	}
+	if tokens[0].Parses == nil || tokens[4].Parses != nil {
+		t.Error("Разборы должны быть только у слов")
+	}
+
+	withPunct := analyzer.LemmatizeText(text, steosmorphy.KeepPunctuation(), steosmorphy.DropNumbers(), steosmorphy.WithStopwords("И"))
+	var texts []string
+	for _, tok := range withPunct {
+		texts = append(texts, tok.Text)
+	}
+	if want := []string{"Кошки", "коты", "ловили", "рыбы", "...", "Кто-нибудь", "!"}; !slices.Equal(texts, want) {
+		t.Errorf("LemmatizeText() с опциями = %q; ожидали %q", texts, want)
+	}
+}
+
+// TestStopwords проверяет встроенный список стоп-слов и их отбрасывание или пометку в тексте.
+func TestStopwords(t *testing.T) {
+	for _, word := range []string{"и", "На", "через", "чтобы", "же"} {
+		if !analyzer.IsStopword(word) {
+			t.Errorf("IsStopword(%q) = false; ожидали true", word)
+		}
+	}
+	for _, word := range []string{"кот", "стали", "быстро"} {
+		if analyzer.IsStopword(word) {
+			t.Errorf("IsStopword(%q) = true; ожидали false", word)
+		}
+	}
+	if !slices.Contains(steosmorphy.Stopwords(), "для") {
+		t.Error("Во встроенном списке нет предлога 'для'")
+	}
+
+	const text = "Кот и пёс гуляли по парку"
+	var lemmas []string
+	for _, tok := range analyzer.LemmatizeText(text, steosmorphy.DropStopwords()) {
+		lemmas = append(lemmas, tok.Lemma)
+	}
+	if want := []string{"кот", "пёс", "гулять", "парк"}; !slices.Equal(lemmas, want) {
+		t.Errorf("DropStopwords: леммы = %q; ожидали %q", lemmas, want)
+	}
+
+	var marked []string
+	tokens := analyzer.LemmatizeText(text, steosmorphy.MarkStopwords(), steosmorphy.WithStopwords("гулять"))
+	for _, tok := range tokens {
+		if tok.Stopword {
+			marked = append(marked, tok.Text)
+		}
+	}
+	if len(tokens) != 6 || !slices.Equal(marked, []string{"и", "гуляли", "по"}) {
+		t.Errorf("MarkStopwords: %d токенов, помечены %q", len(tokens), marked)
+	}
+}
+
+// TestFrequencyOrder проверяет чтение частот и упорядочивание словоформ по частоте.
+func TestFrequencyOrder(t *testing.T) {
+	freq, err := steosmorphy.ReadWordFrequencies(strings.NewReader("# частоты\nКотами\t50\nкота\t10\nкотами\t5\n"))
+	if err != nil {
+		t.Fatalf("Ошибка чтения частот: %v", err)
+	}
+	if freq["котами"] != 55 || freq["кота"] != 10 {
+		t.Fatalf("Неверные частоты: %v", freq)
+	}
+	if _, err := steosmorphy.ReadWordFrequencies(strings.NewReader("кот 10\n")); err == nil {
+		t.Error("Ожидали ошибку для строки без табуляции")
+	}
+
+	ordered, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithWordFrequencies(freq), steosmorphy.WithFrequencyOrder())
+	if err != nil {
+		t.Fatalf("Не удалось загрузить словарь: %v", err)
+	}
+	forms := ordered.Inflect("кот")
+	if len(forms) < 3 || forms[0].Word != "котами" || forms[1].Word != "кота" {
+		t.Fatalf("Ожидали 'котами' и 'кота' первыми, получили %v", forms)
+	}
+	rest := make([]string, 0, len(forms)-2)
+	for _, f := range forms[2:] {
+		rest = append(rest, f.Word)
+	}
+	if !slices.IsSorted(rest) {
+		t.Errorf("Формы без частоты должны идти по алфавиту: %q", rest)
+	}
+
+	// Без опции порядок остается алфавитным.
+	if forms := analyzer.Inflect("кот"); forms[0].Word != "кот" {
+		t.Errorf("Ожидали алфавитный порядок без WithFrequencyOrder, первая форма %q", forms[0].Word)
+	}
+}
+
+// TestDisambiguate проверяет выбор разбора по контексту и управление предлогов.
+func TestDisambiguate(t *testing.T) {
+	testCases := []struct {
+		text, word, lemma, pos, wordCase string
+	}{
+		{"Мы стали сильнее.", "стали", "стать", "Глагол", ""},
+		{"Нож из прочной стали.", "стали", "сталь", "Существительное", "Родительный"},
+		{"Нож из прочной стали.", "прочной", "прочный", "Прилагательное", "Родительный"},
+		{"Кот живет в доме", "доме", "дом", "Существительное", "Предложный"},
+	}
+	for _, tc := range testCases {
+		var found bool
+		for _, tok := range analyzer.AnalyzeText(tc.text) {
+			if tok.Text != tc.word {
+				continue
+			}
+			found = true
+			p := tok.Parses[0]
+			if tok.Lemma != tc.lemma || !strings.Contains(p.Tags, tc.pos) || p.Case != tc.wordCase {
+				t.Errorf("%q: для %q выбран разбор %s (%s); ожидали %s %s %s", tc.text, tc.word, p.Lemma, p.Tags, tc.lemma, tc.pos, tc.wordCase)
+			}
+		}
+		if !found {
+			t.Errorf("%q: нет токена %q", tc.text, tc.word)
+		}
+	}
+
+	// Результат Disambiguate выровнен по токенам: у знаков препинания и чисел разбора нет.
+	tokens := analyzer.LemmatizeText("Из стали, 2 ножа.", steosmorphy.KeepPunctuation())
+	chosen := analyzer.Disambiguate(tokens)
+	if len(chosen) != len(tokens) {
+		t.Fatalf("Ожидали %d разборов, получили %d", len(tokens), len(chosen))
+	}
+	for i, tok := range tokens {
+		if (chosen[i] == nil) != (tok.Kind != steosmorphy.TokenWord) {
+			t.Errorf("Токен %q: неожиданный разбор %v", tok.Text, chosen[i])
+		}
+	}
+
+	// Модель, обученная по корпусу, сохраняется и читается без потерь.
+	model := steosmorphy.NewTagModel()
+	model.AddSentence([]string{"Местоимение", "Глагол", "Пунктуация", "Союз", "Глагол"})
+	model.AddTaggedSentence([]string{"Мы", "стали"}, []string{"Местоимение", "Глагол"})
+	var first, second strings.Builder
+	if _, err := model.WriteTo(&first); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := steosmorphy.ReadTagModel(strings.NewReader(first.String()))
+	if err != nil {
+		t.Fatalf("Ошибка чтения модели: %v", err)
+	}
+	if _, err := restored.WriteTo(&second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() || !strings.Contains(first.String(), "^\tМестоимение\t2\n") ||
+		!strings.Contains(first.String(), "слово:мы\tМестоимение\t1\n") {
+		t.Errorf("Модель изменилась при сохранении:\n%s\n%s", first.String(), second.String())
+	}
+}
+
+// TestTagModelTrained проверяет, что встроенная модель переходов - подсчет
+// TagModel.AddTaggedSentence по корпусу analyzer/tagcorpus.tsv, а не ручная оценка.
+func TestTagModelTrained(t *testing.T) {
+	corpus, err := os.Open("../analyzer/tagcorpus.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer corpus.Close()
+	trained := steosmorphy.NewTagModel()
+	if err := steosmorphy.ReadTaggedCorpus(corpus, trained.AddTaggedSentence); err != nil {
+		t.Fatalf("Ошибка чтения корпуса: %v", err)
+	}
+
+	shipped, err := os.ReadFile("../analyzer/tagmodel.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := steosmorphy.ReadTagModel(bytes.NewReader(shipped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got strings.Builder
+	if _, err := trained.WriteTo(&want); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.WriteTo(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Error("analyzer/tagmodel.tsv не совпадает с моделью, обученной по analyzer/tagcorpus.tsv: " +
+			"пересоберите ее командой steosmorphy-build -tag-corpus")
+	}
+
+	bad := "кот\tСуществительное\nспит\tСказуемое\n"
+	if err := steosmorphy.ReadTaggedCorpus(strings.NewReader(bad), func(_, _ []string) {}); err == nil || !strings.Contains(err.Error(), "строка 2") {
+		t.Errorf("Ожидали ошибку неизвестной части речи в строке 2, получили %v", err)
+	}
+}
+
+// partsOfSpeech - теги частей речи словаря.
+var partsOfSpeech = []string{
+	"Существительное", "Прилагательное", "Глагол", "Наречие", "Причастие", "Деепричастие", "Местоимение",
+	"Числительное", "Предлог", "Частица", "Союз", "Междометие", "Вводное слово",
+}
+
+// heldOutSentence - предложение отложенной выборки: токены и их части речи.
+type heldOutSentence struct {
+	tokens, tags []string
+}
+
+// readHeldOut читает отложенную выборку tag-heldout.tsv (формат ReadTaggedCorpus).
+func readHeldOut(t *testing.T) []heldOutSentence {
+	t.Helper()
+	data, err := os.ReadFile("tag-heldout.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sentences []heldOutSentence
+	for _, block := range strings.Split(string(data), "\n\n") {
+		var s heldOutSentence
+		for _, line := range strings.Split(block, "\n") {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			token, tag, _ := strings.Cut(line, "\t")
+			s.tokens = append(s.tokens, token)
+			s.tags = append(s.tags, tag)
+		}
+		if len(s.tokens) > 0 {
+			sentences = append(sentences, s)
+		}
+	}
+	return sentences
+}
+
+// TestDisambiguateHeldOut проверяет Disambiguate на предложениях, не входивших в корпус
+// обучения: среди слов с разборами разных частей речи часть речи должна выбираться верно
+// заметно чаще, чем без модели переходов.
+func TestDisambiguateHeldOut(t *testing.T) {
+	untrained, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithTagModel(steosmorphy.NewTagModel()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer untrained.Close()
+
+	accuracy := func(morph *steosmorphy.MorphAnalyzer, sentences []heldOutSentence) (float64, []string) {
+		var total, correct int
+		var mistakes []string
+		for _, s := range sentences {
+			tokens := morph.LemmatizeText(strings.Join(s.tokens, " "), steosmorphy.KeepPunctuation())
+			if len(tokens) != len(s.tokens) {
+				t.Fatalf("%q: %d токенов, в разметке %d", s.tokens, len(tokens), len(s.tokens))
+			}
+			chosen := morph.Disambiguate(tokens)
+			for i, tok := range tokens {
+				var candidates []string
+				for _, p := range tok.Parses {
+					for _, tag := range strings.Split(p.Tags, ",") {
+						if slices.Contains(partsOfSpeech, tag) && !slices.Contains(candidates, tag) {
+							candidates = append(candidates, tag)
+						}
+					}
+				}
+				if len(candidates) < 2 || !slices.Contains(candidates, s.tags[i]) {
+					continue
+				}
+				total++
+				if slices.Contains(strings.Split(chosen[i].Tags, ","), s.tags[i]) {
+					correct++
+				} else {
+					mistakes = append(mistakes, fmt.Sprintf("%s: %q - %s вместо %s", strings.Join(s.tokens, " "), tok.Text, chosen[i].Tags, s.tags[i]))
+				}
+			}
+		}
+		return float64(correct) / float64(total), mistakes
+	}
+
+	sentences := readHeldOut(t)
+	trainedAcc, mistakes := accuracy(analyzer, sentences)
+	untrainedAcc, _ := accuracy(untrained, sentences)
+	t.Logf("Точность на неоднозначных словах: %.2f с моделью, %.2f без модели", trainedAcc, untrainedAcc)
+	if trainedAcc < 0.9 || trainedAcc < untrainedAcc+0.1 {
+		t.Errorf("Точность %.2f (без модели %.2f) ниже ожидаемой; ошибки:\n%s", trainedAcc, untrainedAcc, strings.Join(mistakes, "\n"))
+	}
+}
+
+// emojiUnit - пользовательское звено цепочки разбора для TestAnalyzerUnits.
+type emojiUnit struct{}
+
+func (emojiUnit) Name() string { return "emoji" }
+
+func (emojiUnit) Parse(_ *steosmorphy.MorphAnalyzer, word string) []*steosmorphy.Parsed {
+	if word != "🙂" {
+		return nil
+	}
+	parses, _ := analyzer.Analyze("улыбка")
+	return parses[:1]
+}
+
+func (emojiUnit) Inflect(*steosmorphy.MorphAnalyzer, string, []*steosmorphy.Parsed) []*steosmorphy.Parsed {
+	return nil
+}
+
+// TestAnalyzerUnits проверяет встроенные звенья цепочки разбора и ее настройку опциями.
+func TestAnalyzerUnits(t *testing.T) {
+	testCases := []struct {
+		word, lemma, pos string
+		form             string // Ожидаемая словоформа ("" - словоформ нет).
+	}{
+		{"2024", "2024", "Числительное", ""},
+		{"10%", "10%", "Числительное", ""},
+		{"5-ти", "5-ти", "Числительное", ""},
+		{"2-го", "2-й", "Числительное", "2-му"},
+		{"90-х", "90-й", "Числительное", "90-е"},
+		{"GitHub", "github", "", ""},
+		{"info@Example.com", "info@example.com", "", ""},
+		{"https://go.dev/doc", "https://go.dev/doc", "", ""},
+		{"x86", "x86", "", ""},
+		{"東京", "東京", "", ""},
+		{"интернет-магазина", "интернет-магазин", "Существительное", "интернет-магазинами"},
+		{"человека-паука", "человек-паук", "Существительное", "людьми-пауками"},
+		{"скажи-ка", "сказать-ка", "Глагол", "скажите-ка"},
+		{"видеоуроками", "видеоурок", "Существительное", "видеоурока"},
+		{"мегасуперкоту", "мегасуперкот", "Существительное", "мегасуперкотами"},
+		{"ютубканалами", "ютубканал", "Существительное", "ютубканалом"},
+	}
+	for _, tc := range testCases {
+		parses, forms := analyzer.Analyze(tc.word)
+		if findParse(parses, tc.lemma, tc.pos) == nil {
+			t.Errorf("%s: ожидали лемму %q (%s), получили %+v", tc.word, tc.lemma, tc.pos, parses)
+		}
+		hasForm := slices.ContainsFunc(forms, func(p *steosmorphy.Parsed) bool { return p.Word == tc.form })
+		if tc.form == "" && forms != nil || tc.form != "" && !hasForm {
+			t.Errorf("%s: ожидали словоформу %q, получили %d форм", tc.word, tc.form, len(forms))
+		}
+	}
+
+	// Без звена латиницы слово отдается предсказателю, а со словарем в качестве
+	// единственного звена несловарные слова не разбираются.
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithoutUnits(steosmorphy.UnitLatin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parses, _ := morph.Analyze("GitHub"); len(parses) > 0 && strings.Contains(parses[0].Tags, "Латиница") {
+		t.Errorf("Звено латиницы должно быть отключено, получили %+v", parses[0])
+	}
+	// Остаток после неизвестного начала должен занимать достаточную долю слова.
+	strict, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithUnits(steosmorphy.DictionaryUnit{}, steosmorphy.NewUnknownPrefixUnit(0.8)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parses, _ := strict.Analyze("ютубканалами"); parses != nil {
+		t.Errorf("Разбор с низкой уверенностью не должен приниматься, получили %+v", parses)
+	}
+	if parses, _ := strict.Analyze("шкотами"); findParse(parses, "шкот", "Существительное") == nil {
+		t.Errorf("Ожидали разбор 'шкот', получили %+v", parses)
+	}
+	// Порог по умолчанию требует остатка длиннее начала, нулевой принимает любой.
+	for unit, lemma := range map[steosmorphy.UnknownPrefixUnit]string{
+		steosmorphy.NewUnknownPrefixUnit(steosmorphy.DefaultUnknownPrefixMinConfidence): "",
+		steosmorphy.NewUnknownPrefixUnit(0):                                             "ютубкот",
+	} {
+		morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithUnits(steosmorphy.DictionaryUnit{}, unit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if parses, _ := morph.Analyze("ютубкоту"); len(parses) > 0 {
+			got = parses[0].Lemma
+		}
+		if got != lemma {
+			t.Errorf("Порог %+v: ожидали лемму %q, получили %q", unit, lemma, got)
+		}
+	}
+	foreign := map[string]string{
+		"info@example.com": "Электронная почта",
+		"www.ya.ru":        "Ссылка",
+		"Ελλάδα":           "Неизвестное",
+		"C++":              "Латиница",
+	}
+	for word, tag := range foreign {
+		if parses, _ := analyzer.Analyze(word); len(parses) != 1 || !parses[0].Has(tag) {
+			t.Errorf("%s: ожидали разбор с пометой %q, получили %+v", word, tag, parses)
+		}
+	}
+	if parses, _ := analyzer.Analyze("5-й"); len(parses) == 0 || parses[0].Case != "Именительный" || !parses[0].Has("Порядковое") {
+		t.Errorf("Ожидали первым разбор '5-й' в именительном падеже, получили %+v", parses)
+	}
+	var texts []string
+	for _, tok := range analyzer.LemmatizeText("в 90-х годах рост 10%") {
+		texts = append(texts, tok.Text+"/"+tok.Kind.String())
+	}
+	if expected := []string{"в/word", "90-х/word", "годах/word", "рост/word", "10%/number"}; !slices.Equal(texts, expected) {
+		t.Errorf("Числа с окончаниями и процентами должны быть одним токеном: %v", texts)
+	}
+	if parses, _ := analyzer.Analyze("пример.рф"); len(parses) > 0 && parses[0].Has("Ссылка") {
+		t.Errorf("Токены с кириллицей не должны разбираться звеном иностранных токенов: %+v", parses[0])
+	}
+
+	morph, err = steosmorphy.LoadMorphAnalyzer(steosmorphy.WithUnits(steosmorphy.DictionaryUnit{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parses, forms := morph.Analyze("видеоуроками"); parses != nil || forms != nil {
+		t.Errorf("Словарное звено не должно разбирать несловарные слова, получили %+v", parses)
+	}
+
+	// Пользовательское звено ставится перед стандартной цепочкой.
+	morph, err = steosmorphy.LoadMorphAnalyzer(steosmorphy.WithUnits(append([]steosmorphy.AnalyzerUnit{emojiUnit{}}, steosmorphy.DefaultUnits()...)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lemmas := morph.Lemmatize("🙂"); !slices.Equal(lemmas, []string{"улыбка"}) {
+		t.Errorf("Ожидали лемму 'улыбка' от пользовательского звена, получили %v", lemmas)
+	}
+	if lemmas := morph.Lemmatize("кошки"); !slices.Equal(lemmas, []string{"кошка"}) {
+		t.Errorf("Стандартные звенья должны работать после пользовательского, получили %v", lemmas)
+	}
+}
+
+// TestParseMethod проверяет источник разбора и уверенность в нем.
+func TestParseMethod(t *testing.T) {
+	tests := []struct {
+		word   string
+		method string
+		exact  bool // Уверенность равна 1.
+	}{
+		{"коту", steosmorphy.UnitDictionary, true},
+		{"2024", steosmorphy.UnitNumber, true},
+		{"интернет-магазина", steosmorphy.UnitHyphen, true},
+		{"суперкота", steosmorphy.UnitPrefix, false},
+		{"ютубканалами", steosmorphy.UnitUnknownPrefix, false},
+		{"видеоблогеров", steosmorphy.UnitPredictor, false},
+	}
+	for _, tt := range tests {
+		parses, forms := analyzer.Analyze(tt.word)
+		if len(parses) == 0 {
+			t.Errorf("%s: нет разборов", tt.word)
+			continue
+		}
+		for _, p := range parses {
+			if p.Method != tt.method || p.Confidence <= 0 || p.Confidence > 1 || (p.Confidence == 1) != tt.exact {
+				t.Errorf("%s: ожидали источник %q, получили %q с уверенностью %v", tt.word, tt.method, p.Method, p.Confidence)
+			}
+		}
+		for _, f := range forms {
+			if f.Method != "" || f.Confidence != 0 {
+				t.Errorf("%s: у словоформы %q не должно быть источника разбора", tt.word, f.Word)
+				break
+			}
+		}
+	}
+
+	// Уверенность предсказания совпадает с оценкой лучшего варианта ParsePredictedN.
+	predicted := analyzer.ParsePredicted("нейросетями")
+	if best := analyzer.ParsePredictedN("нейросетями", 1); len(predicted) != 1 || len(best) != 1 || predicted[0].Confidence != best[0].Score {
+		t.Errorf("Уверенность %+v не совпадает с оценкой %+v", predicted, best)
+	}
+}
+
+// TestDictionaryOnly проверяет, что без предсказателя несловарные слова не получают разборов.
+func TestDictionaryOnly(t *testing.T) {
+	strict, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithDictionaryOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parses, forms := strict.Analyze("коту"); findParse(parses, "кот", "Существительное") == nil || len(forms) == 0 {
+		t.Errorf("Словарное слово должно разбираться, получили %+v", parses)
+	}
+	if parses, _ := strict.Analyze("2024"); len(parses) != 1 || parses[0].Method != steosmorphy.UnitNumber {
+		t.Errorf("Число должно разбираться, получили %+v", parses)
+	}
+	for _, word := range []string{"нейросетями", "суперкота", "интернет-магазина", "о"} {
+		if parses, forms := strict.Analyze(word); parses != nil || forms != nil {
+			t.Errorf("%s: ожидали nil без предсказателя, получили %+v", word, parses)
+		}
+		if lemmas := strict.Lemmatize(word); lemmas != nil {
+			t.Errorf("%s: ожидали nil без предсказателя, получили %v", word, lemmas)
+		}
+	}
+	if strict.ParsePredicted("нейросетями") != nil || strict.Synthesize("нейросеть", []string{"Творительный", "Множественное число"}) != nil {
+		t.Error("Предсказатель должен быть отключен")
+	}
+
+	// Предсказывающие звенья исключаются при любом порядке опций, а остальная цепочка сохраняется.
+	withoutNumbers := steosmorphy.WithoutUnits(steosmorphy.UnitNumber)
+	for _, tc := range []struct {
+		opts    []steosmorphy.Option
+		numbers bool
+	}{
+		{[]steosmorphy.Option{steosmorphy.WithDictionaryOnly(), steosmorphy.WithUnits(steosmorphy.DefaultUnits()...)}, true},
+		{[]steosmorphy.Option{withoutNumbers, steosmorphy.WithDictionaryOnly()}, false},
+		{[]steosmorphy.Option{steosmorphy.WithDictionaryOnly(), withoutNumbers}, false},
+	} {
+		morph, err := steosmorphy.LoadMorphAnalyzer(tc.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parses, _ := morph.Analyze("нейросетями"); parses != nil {
+			t.Errorf("Предсказатель вернулся в цепочку: %+v", parses)
+		}
+		if parses, _ := morph.Analyze("коту"); findParse(parses, "кот", "Существительное") == nil {
+			t.Errorf("Словарное слово должно разбираться, получили %+v", parses)
+		}
+		if parses, _ := morph.Analyze("2024"); (parses != nil) != tc.numbers {
+			t.Errorf("Разбор числа при numbers=%v: %+v", tc.numbers, parses)
+		}
+	}
+}
+
+// TestLazyDetails проверяет отложенную раскладку граммем по категориям.
+func TestLazyDetails(t *testing.T) {
+	lazy, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLazyDetails())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"стали", "кота", "видеоблогеров"} {
+		parses, _ := lazy.Analyze(word)
+		expected, _ := analyzer.Analyze(word)
+		if len(parses) == 0 || len(parses) != len(expected) {
+			t.Fatalf("%s: ожидали %d разборов, получили %d", word, len(expected), len(parses))
+		}
+		for i, p := range parses {
+			e := expected[i]
+			if p.Lemma != e.Lemma || p.Tags != e.Tags || p.PartOfSpeech != e.PartOfSpeech || p.Method != e.Method {
+				t.Errorf("%s: разбор %+v не совпадает с %+v", word, *p, *e)
+			}
+			if p.Case != "" || p.OtherTags != nil {
+				t.Errorf("%s: категории не должны заполняться до Details: %+v", word, *p)
+			}
+			d := p.Details()
+			if d.Case != e.Case || d.Number != e.Number || d.Gender != e.Gender || len(d.OtherTags) != len(e.OtherTags) {
+				t.Errorf("%s: Details() = %+v, ожидали %+v", word, *d, *e)
+			}
+			got, _ := json.Marshal(p)
+			want, _ := json.Marshal(e)
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: JSON %s, ожидали %s", word, got, want)
+			}
+		}
+	}
+
+	if p := analyzer.Parse("кота")[0]; p.Details() != p {
+		t.Error("Details полного разбора должен возвращать сам разбор")
+	}
+	if got, want := lazy.Lemmatize("стали"), analyzer.Lemmatize("стали"); !slices.Equal(got, want) {
+		t.Errorf("Lemmatize = %v, ожидали %v", got, want)
+	}
+	if got, want := lazy.Superlative("красивого"), analyzer.Superlative("красивого"); !slices.Equal(got, want) {
+		t.Errorf("Superlative = %v, ожидали %v", got, want)
+	}
+	if got, want := steosmorphy.ResolveAccusative(lazy.Parse("стол")), steosmorphy.ResolveAccusative(analyzer.Parse("стол")); len(got) != len(want) {
+		t.Errorf("ResolveAccusative: %d разборов, ожидали %d", len(got), len(want))
+	}
+}
+
+// TestGrammemeMask проверяет битовые маски граммем.
+func TestGrammemeMask(t *testing.T) {
+	query, err := analyzer.MaskOf("Существительное", "Родительный")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Count() != 2 {
+		t.Errorf("Ожидали 2 граммемы в маске, получили %d", query.Count())
+	}
+	if codes, err := analyzer.MaskOf("NOUN", "genitive"); err != nil || codes != query {
+		t.Errorf("Маски граммем на разных языках должны совпадать: %v, %v", codes, err)
+	}
+	if _, err := analyzer.MaskOf("Родительный", "Несуществующий"); err == nil {
+		t.Error("Ожидали ошибку для граммемы, которой нет в словаре")
+	}
+
+	parses, forms := analyzer.Analyze("кота")
+	for _, p := range append(parses, forms...) {
+		mask := analyzer.Mask(p)
+		if want := p.Has("Существительное") && p.Has("Родительный"); mask.Contains(query) != want {
+			t.Errorf("%s (%s): Contains = %v, ожидали %v", p.Word, p.Tags, !want, want)
+		}
+		if mask.Count() != len(strings.Split(p.Tags, ",")) {
+			t.Errorf("%s (%s): в маске %d граммем", p.Word, p.Tags, mask.Count())
+		}
+	}
+	if p := parses[0]; analyzer.Mask(p.Localize(steosmorphy.LangCodes)) != analyzer.Mask(p) {
+		t.Error("Маска переведенного разбора должна совпадать с исходной")
+	}
+	if plural, _ := analyzer.MaskOf("Множественное число"); analyzer.Mask(parses[0]).Intersects(plural) {
+		t.Errorf("Разбор %s не должен содержать множественное число", parses[0].Tags)
+	}
+
+	grammemes := analyzer.Grammemes()
+	if len(grammemes) == 0 || !slices.Contains(grammemes, "Родительный") {
+		t.Errorf("Перечень граммем словаря неполон: %v", grammemes)
+	}
+}
+
+// TestErrorVariants проверяет варианты методов, возвращающие ошибку.
+func TestErrorVariants(t *testing.T) {
+	if parses, err := analyzer.ParseE("коту"); err != nil || findParse(parses, "кот", "Существительное") == nil {
+		t.Errorf("ParseE(коту) = %+v, %v", parses, err)
+	}
+	if _, err := analyzer.ParseE("нейросетями"); !errors.Is(err, steosmorphy.ErrNotFound) {
+		t.Errorf("Ожидали ErrNotFound для несловарного слова, получили %v", err)
+	}
+	if parses, forms, err := analyzer.AnalyzeE("нейросетями"); err != nil || len(parses) == 0 || len(forms) == 0 {
+		t.Errorf("AnalyzeE должен предсказывать несловарное слово, получили %v", err)
+	}
+	if _, _, err := analyzer.AnalyzeE("кф"); !errors.Is(err, steosmorphy.ErrNotFound) {
+		t.Errorf("Ожидали ErrNotFound для 'кф', получили %v", err)
+	}
+	if forms, err := analyzer.InflectE("кот"); err != nil || len(forms) == 0 {
+		t.Errorf("InflectE(кот) = %d форм, %v", len(forms), err)
+	}
+}
+
+// TestLogger проверяет журналирование загрузки словаря и объединения его частей.
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if _, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLogger(logger)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "словарь загружен") || !strings.Contains(buf.String(), "lemmas=") {
+		t.Errorf("Ожидали диагностику загрузки, получили %q", buf.String())
+	}
+
+	dir := t.TempDir()
+	writeTestDict(t, filepath.Join(dir, "dict.dawg"), testLexiconTSV)
+	data, err := os.ReadFile(filepath.Join(dir, "dict.dawg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, part := range [][]byte{data[:len(data)/2], data[len(data)/2:]} {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("morph_a%c", 'a'+i)), part, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.Reset()
+	steosmorphy.SetLogger(logger)
+	t.Cleanup(func() { steosmorphy.SetLogger(nil) })
+	if err := steosmorphy.MergeDictParts(dir, filepath.Join(dir, steosmorphy.DictFileName)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "объединение частей словаря") || !strings.Contains(buf.String(), "parts=2") {
+		t.Errorf("Ожидали запись об объединении частей, получили %q", buf.String())
+	}
+}
+
+// TestLocalize проверяет перевод граммем на английский и в машинные коды.
+func TestLocalize(t *testing.T) {
+	p := findParse(analyzer.Parse("кошки"), "кошка", "Существительное")
+	if p == nil {
+		t.Fatal("Не найден разбор 'кошки'")
+	}
+	en := p.Localize(steosmorphy.LangEnglish)
+	if en.PartOfSpeech != "noun" || en.Gender != "feminine" || en.Word != p.Word || en.Lemma != p.Lemma {
+		t.Errorf("Неверный перевод на английский: %+v", en)
+	}
+	if _, ok := en.OtherTags["common-noun"]; !ok {
+		t.Errorf("OtherTags должны быть переведены, получили %v", en.OtherTags)
+	}
+	codes := en.Localize(steosmorphy.LangCodes)
+	if codes.PartOfSpeech != "NOUN" || codes.Animacy != "anim" {
+		t.Errorf("Неверный перевод в коды: %+v", codes)
+	}
+	if back := codes.Localize(steosmorphy.LangRussian); back.Tags != p.Tags || back.Case != p.Case {
+		t.Errorf("Обратный перевод должен вернуть словарные теги %q, получили %q", p.Tags, back.Tags)
+	}
+	if lang, err := steosmorphy.ParseLang("codes"); err != nil || lang != steosmorphy.LangCodes {
+		t.Errorf("ParseLang(codes) = %v, %v", lang, err)
+	}
+	if _, err := steosmorphy.ParseLang("de"); err == nil {
+		t.Error("Ожидали ошибку для неизвестного языка")
+	}
+
+	// Анализатор с WithLang переводит результаты, а принимает граммемы на любом языке.
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLang(steosmorphy.LangEnglish))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parses, forms := morph.Analyze("кошки")
+	if len(parses) == 0 || parses[0].PartOfSpeech != "noun" || len(forms) == 0 || forms[0].PartOfSpeech != "noun" {
+		t.Fatalf("Analyze должен вернуть переведенные разборы, получили %+v", parses)
+	}
+	if _, ok := parses[0].OtherTags["Нарицательное"]; ok {
+		t.Errorf("Русские теги не должны оставаться в OtherTags: %v", parses[0].OtherTags)
+	}
+	to := morph.InflectListTo([]string{"кот"}, []string{"genitive", "plur"})
+	if to[0] == nil || to[0].Word != "котов" || to[0].Case != "genitive" {
+		t.Errorf("InflectListTo с английскими граммемами: ожидали 'котов', получили %+v", to[0])
+	}
+	tokens := morph.LemmatizeText("Мы стали из стали.", steosmorphy.KeepPunctuation())
+	chosen := morph.Disambiguate(tokens)
+	if chosen[1] == nil || chosen[1].PartOfSpeech != "verb" || chosen[3] == nil || chosen[3].Case != "genitive" {
+		t.Errorf("Disambiguate с переведенными разборами: получили %+v и %+v", chosen[1], chosen[3])
+	}
+}
+
+// TestReportMisparse проверяет запись отчетов о неверных разборах и их превращение в записи словаря.
+func TestReportMisparse(t *testing.T) {
+	if err := analyzer.ReportMisparse("кот", &steosmorphy.Parsed{Lemma: "кот"}); err == nil {
+		t.Error("Без приемника ReportMisparse должен возвращать ошибку")
+	}
+
+	path := filepath.Join(t.TempDir(), "reports.jsonl")
+	sink, err := steosmorphy.NewFileFeedbackSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithFeedbackSink(sink))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Словарное слово без ожидаемого разбора, несловарное слово и ошибка выбора разбора.
+	reports := []struct {
+		word     string
+		expected *steosmorphy.Parsed
+	}{
+		{"Коты", &steosmorphy.Parsed{Lemma: "кот", Tags: "Существительное,Одушевленное,Мужской,Множественное число,Звательный"}},
+		{"видеоуроками", &steosmorphy.Parsed{Lemma: "видеоурок", Tags: "noun,inanimate,masculine,plural,instrumental"}},
+		{"стали", findParse(analyzer.Parse("стали"), "сталь", "Существительное")},
+		{"Коты", &steosmorphy.Parsed{Lemma: "кот", Tags: "Существительное,Одушевленное,Мужской,Множественное число,Звательный"}},
+	}
+	for _, r := range reports {
+		if err := morph.ReportMisparse(r.word, r.expected); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var collected []steosmorphy.MisparseReport
+	err = steosmorphy.ReadMisparseReports(f, func(r steosmorphy.MisparseReport) error {
+		collected = append(collected, r)
+		return nil
+	})
+	if err != nil || len(collected) != len(reports) {
+		t.Fatalf("Ожидали %d отчетов, получили %d (%v)", len(reports), len(collected), err)
+	}
+	if !collected[0].InDictionary || collected[1].InDictionary || len(collected[0].Actual) == 0 {
+		t.Errorf("Неверные сведения о словаре в отчетах: %+v", collected[:2])
+	}
+
+	userDict, fixes := steosmorphy.MisparseFixes(collected)
+	wantUser := []steosmorphy.LexEntry{{Word: "видеоуроками", Lemma: "видеоурок", Tags: "Существительное,Неодушевленное,Мужской,Множественное число,Творительный"}}
+	wantFixes := []steosmorphy.LexEntry{{Word: "коты", Lemma: "кот", Tags: "Существительное,Одушевленное,Мужской,Множественное число,Звательный"}}
+	if !slices.Equal(userDict, wantUser) || !slices.Equal(fixes, wantFixes) {
+		t.Errorf("MisparseFixes: получили %+v и %+v", userDict, fixes)
+	}
+
+	// HTTP-приемник отправляет отчет JSON-телом и сообщает об ошибке сервера.
+	var received steosmorphy.MisparseReport
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	morph, err = steosmorphy.LoadMorphAnalyzer(steosmorphy.WithFeedbackSink(steosmorphy.NewHTTPFeedbackSink(server.URL, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := morph.ReportMisparse("кот", reports[0].expected); err != nil || received.Word != "кот" {
+		t.Errorf("HTTP-приемник: ошибка %v, получен отчет %+v", err, received)
+	}
+	status = http.StatusInternalServerError
+	if err := morph.ReportMisparse("кот", reports[0].expected); err == nil {
+		t.Error("Ожидали ошибку при ответе сервера 500")
+	}
+}
+
+// TestParsedMatch проверяет Has и запросы по граммемам.
+func TestParsedMatch(t *testing.T) {
+	p := findParse(analyzer.Parse("кошки"), "кошка", "Существительное")
+	if p == nil {
+		t.Fatal("Не найден разбор 'кошки'")
+	}
+	if !p.Has("Родительный") || !p.Has("genitive") || !p.Has(" gent ") || p.Has("Дательный") {
+		t.Errorf("Has работает неверно для %q", p.Tags)
+	}
+	en := p.Localize(steosmorphy.LangEnglish)
+	if !en.Has("Родительный") || !en.Match("noun & Единственное число") {
+		t.Errorf("Has и Match должны работать с переведенным разбором %q", en.Tags)
+	}
+
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{"Существительное & Родительный & !Множественное число", true},
+		{"Существительное & (Дательный | Родительный)", true},
+		{"Глагол | Прилагательное", false},
+		{"!(Существительное & Единственное число)", false},
+		{"!!Существительное", true},
+		{"Существительное & Родительный |  Глагол", true},
+		{"Существительное &", false},
+		{"(Существительное", false},
+		{"Существительное)", false},
+	}
+	for _, tc := range cases {
+		if got := p.Match(tc.query); got != tc.want {
+			t.Errorf("Match(%q) = %v, ожидали %v", tc.query, got, tc.want)
+		}
+	}
+	for _, query := range []string{"", "Существительное &", "(Существительное", "Существительное)", "& Глагол"} {
+		if _, err := steosmorphy.ParseTagQuery(query); err == nil {
+			t.Errorf("Ожидали ошибку для запроса %q", query)
+		}
+	}
+}
+
+// TestLexeme проверяет группировку словоформ по лексемам и поиск формы по граммемам.
+func TestLexeme(t *testing.T) {
+	lex := analyzer.Lexeme("коту")
+	if lex == nil || lex.Lemma != "кот" || lex.PartOfSpeech != "Существительное" {
+		t.Fatalf("Ожидали лексему 'кот', получили %+v", lex)
+	}
+	if f := lex.Form("Дательный", "Множественное число"); f == nil || f.Word != "котам" {
+		t.Errorf("Ожидали форму 'котам', получили %+v", f)
+	}
+	// Омонимичные формы сохраняются, а винительный падеж согласуется с одушевленностью.
+	if f := lex.Form("Винительный", "Единственное число"); f == nil || f.Word != "кота" {
+		t.Errorf("Ожидали винительный падеж 'кота', получили %+v", f)
+	}
+	if f := lex.Form("Родительный", "Единственное число"); f == nil || f.Word != "кота" {
+		t.Errorf("Ожидали родительный падеж 'кота', получили %+v", f)
+	}
+	if f := lex.Form("Дательный", "Глагол"); f != nil {
+		t.Errorf("Несуществующая комбинация граммем должна давать nil, получили %+v", f)
+	}
+	if len(lex.FormsWith()) != len(lex.Forms) || len(lex.FormsWith("Множественное число")) == 0 {
+		t.Error("FormsWith без граммем должен возвращать все формы")
+	}
+
+	lexemes := analyzer.Lexemes("стали")
+	if len(lexemes) != 2 || lexemes[0].Lemma != "сталь" || lexemes[1].Lemma != "стать" {
+		t.Fatalf("Ожидали лексемы 'сталь' и 'стать', получили %d", len(lexemes))
+	}
+	verb := lexemes[1]
+	if verb.PartOfSpeech != "Глагол" || len(verb.FormsWith("Причастие")) == 0 {
+		t.Errorf("Причастия должны входить в лексему глагола: %+v", verb.PartOfSpeech)
+	}
+	for _, f := range lexemes[0].Forms {
+		if f.Lemma != "сталь" {
+			t.Errorf("В лексему 'сталь' попала форма %+v", f)
+		}
+	}
+
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLang(steosmorphy.LangEnglish))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lex = morph.Lexeme("кот")
+	if f := lex.Form("dative", "plural"); lex.PartOfSpeech != "noun" || f == nil || f.Word != "котам" || f.Case != "dative" {
+		t.Errorf("Лексема с переводом граммем: %q, форма %+v", lex.PartOfSpeech, f)
+	}
+}
+
+func TestDeclensionTable(t *testing.T) {
+	table := analyzer.DeclensionTable("коту")
+	if table == nil || table.Lemma != "кот" || len(table.Columns) != 2 || len(table.Rows) < 6 {
+		t.Fatalf("Ожидали таблицу падеж×число для 'кот', получили %+v", table)
+	}
+	rows := make(map[string][][]string)
+	for _, row := range table.Rows {
+		rows[row.Label] = row.Cells
+	}
+	if cells := rows["Винительный"]; len(cells) != 2 || !slices.Equal(cells[0], []string{"кота"}) || !slices.Equal(cells[1], []string{"котов"}) {
+		t.Errorf("Винительный падеж должен согласоваться с одушевленностью: %v", cells)
+	}
+	if cells := rows["Дательный"]; len(cells) != 2 || !slices.Equal(cells[1], []string{"котам"}) {
+		t.Errorf("Ожидали 'котам' в дательном падеже, получили %v", cells)
+	}
+
+	verb := analyzer.DeclensionTable("читать")
+	if verb == nil || verb.PartOfSpeech != "Глагол" {
+		t.Fatalf("Ожидали таблицу глагола, получили %+v", verb)
+	}
+	rows = make(map[string][][]string)
+	for _, row := range verb.Rows {
+		rows[row.Label] = row.Cells
+	}
+	if cells := rows["Настоящее, 1-е лицо"]; len(cells) != 2 || !slices.Equal(cells[0], []string{"читаю"}) || !slices.Equal(cells[1], []string{"читаем"}) {
+		t.Errorf("Ожидали 'читаю' и 'читаем', получили %v", cells)
+	}
+	if _, ok := rows["Будущее, 1-е лицо"]; ok {
+		t.Error("У глагола несовершенного вида нет простого будущего времени")
+	}
+	if cells := rows["Повелительное"]; len(cells) != 2 || !slices.Equal(cells[1], []string{"читайте"}) {
+		t.Errorf("Ожидали повелительное наклонение 'читайте', получили %v", cells)
+	}
+
+	adjective := analyzer.DeclensionTable("красивый")
+	if adjective == nil || len(adjective.Columns) != 4 || adjective.Rows[len(adjective.Rows)-1].Label != "Краткая" {
+		t.Fatalf("Ожидали таблицу прилагательного с краткими формами, получили %+v", adjective)
+	}
+	if table := analyzer.DeclensionTable("и"); table != nil {
+		t.Errorf("Для союза таблица не строится, получили %+v", table)
+	}
+
+	var text, html, js strings.Builder
+	if err := table.WriteText(&text); err != nil || !strings.Contains(text.String(), "Винительный") || !strings.Contains(text.String(), "котов") {
+		t.Errorf("Неверный текстовый вывод (%v):\n%s", err, text.String())
+	}
+	if err := table.WriteHTML(&html); err != nil || !strings.Contains(html.String(), "<caption>кот</caption>") || !strings.Contains(html.String(), "<td>котам</td>") {
+		t.Errorf("Неверный HTML-вывод (%v):\n%s", err, html.String())
+	}
+	var decoded steosmorphy.Table
+	if err := table.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(js.String()), &decoded); err != nil || decoded.Lemma != "кот" || len(decoded.Rows) != len(table.Rows) {
+		t.Errorf("Неверный JSON-вывод (%v): %s", err, js.String())
+	}
+
+	morph, err := steosmorphy.LoadMorphAnalyzer(steosmorphy.WithLang(steosmorphy.LangEnglish))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table := morph.DeclensionTable("кот"); table == nil || table.PartOfSpeech != "noun" || table.Rows[0].Label != "nominative" {
+		t.Errorf("Таблица с переводом граммем: %+v", table)
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	cases := []struct {
+		lemma string
+		tags  []string
+		want  []string
+	}{
+		{"кот", []string{"Дательный", "Множественное число"}, []string{"котам"}},
+		{"кот", []string{"Винительный", "Единственное число"}, []string{"кота"}},
+		{"Кошка", []string{"Творительный", "Единственное число"}, []string{"кошкой", "кошкою"}},
+		{"идти", []string{"Прошедшее", "Женский"}, []string{"шла"}},
+		{"кот", []string{"dative", "plural"}, []string{"котам"}},
+		// Несловарная лемма изменяется по парадигме-образцу.
+		{"нейросеть", []string{"Родительный", "Множественное число"}, []string{"нейросетей"}},
+		{"кот", []string{"Дательный", "Глагол"}, nil},
+		{"стали", []string{"Родительный"}, nil},
+	}
+	for _, tc := range cases {
+		if got := analyzer.Synthesize(tc.lemma, tc.tags); !slices.Equal(got, tc.want) {
+			t.Errorf("Synthesize(%q, %v) = %v, ожидали %v", tc.lemma, tc.tags, got, tc.want)
+		}
+	}
+}
+
+func TestDegreesOfComparison(t *testing.T) {
+	if got := analyzer.Comparative("красивый"); len(got) < 2 || got[0] != "красивее" || got[len(got)-1] != "более красивый" {
+		t.Errorf("Ожидали 'красивее' ... 'более красивый', получили %v", got)
+	}
+	// Формы с "по-" идут после основных.
+	if got := analyzer.Comparative("хорошая"); !slices.Equal(got, []string{"лучше", "получше", "более хорошая"}) {
+		t.Errorf("Неверная сравнительная степень 'хорошая': %v", got)
+	}
+	// Превосходная степень согласуется с исходной словоформой.
+	if got := analyzer.Superlative("красивую"); !slices.Equal(got, []string{"красивейшую", "самую красивую", "наиболее красивую"}) {
+		t.Errorf("Неверная превосходная степень 'красивую': %v", got)
+	}
+	if got := analyzer.Superlative("красив"); !slices.Equal(got, []string{"наиболее красив"}) {
+		t.Errorf("Для краткой формы ожидали только 'наиболее красив', получили %v", got)
+	}
+	if got := analyzer.Superlative("красивее"); len(got) == 0 || got[0] != "красивейший" {
+		t.Errorf("Для сравнительной степени ожидали формы леммы, получили %v", got)
+	}
+	if got := analyzer.Comparative("кот"); got != nil {
+		t.Errorf("Для существительного ожидали nil, получили %v", got)
+	}
+}
+
+func TestParticiples(t *testing.T) {
+	groups := analyzer.Participles("читает")
+	if len(groups) != 5 {
+		t.Fatalf("Ожидали 4 группы причастий и деепричастия, получили %d", len(groups))
+	}
+	active := groups[0]
+	if active.PartOfSpeech != "Причастие" || active.Voice != "Действительный" || active.Tense != "Настоящее" || active.Forms[0].Word != "читающий" {
+		t.Errorf("Первой должна идти группа 'читающий', получили %+v", active)
+	}
+	if findForm(active.Forms, "читающими") == nil {
+		t.Error("Причастие должно возвращаться со склонением")
+	}
+	if last := groups[len(groups)-1]; last.PartOfSpeech != "Деепричастие" || last.Voice != "" || last.Forms[0].Word != "читая" {
+		t.Errorf("Последней должна идти группа деепричастий, получили %+v", last)
+	}
+
+	var words []string
+	for _, g := range analyzer.Participles("сделать", steosmorphy.InitialFormsOnly(), steosmorphy.ParticiplesOnly()) {
+		for _, f := range g.Forms {
+			words = append(words, f.Word)
+		}
+	}
+	if !slices.Equal(words, []string{"сделавший", "сделанный"}) {
+		t.Errorf("Ожидали начальные формы причастий 'сделать', получили %v", words)
+	}
+	if groups := analyzer.Participles("сделать", steosmorphy.GerundsOnly()); len(groups) != 1 || groups[0].Tense != "Прошедшее" {
+		t.Errorf("Ожидали деепричастия прошедшего времени, получили %+v", groups)
+	}
+	if groups := analyzer.Participles("кот"); groups != nil {
+		t.Errorf("Для существительного ожидали nil, получили %+v", groups)
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	cases := []struct {
+		scheme steosmorphy.TranslitScheme
+		text   string
+		want   string
+	}{
+		{steosmorphy.TranslitGOST, "Щука съела цирк", "Shhuka s``ela cirk"},
+		{steosmorphy.TranslitGOST, "Царь", "Czar`"},
+		{steosmorphy.TranslitICAO, "Юлия Щукина", "Iuliia Shchukina"},
+		{steosmorphy.TranslitPassport, "Юлия Щукина", "Yuliya Shchukina"},
+		{steosmorphy.TranslitInformal, "ЁЖИК в тумане", "YOZHIK v tumane"},
+	}
+	for _, tc := range cases {
+		got := steosmorphy.Transliterate(tc.text, tc.scheme)
+		if got != tc.want {
+			t.Errorf("Transliterate(%q, %v) = %q, ожидали %q", tc.text, tc.scheme, got, tc.want)
+		}
+	}
+	// ГОСТ 7.79-2000 обратим.
+	text := "Подъезд, Эхо, Ёжик и Царь-пушка!"
+	if got := steosmorphy.Detransliterate(steosmorphy.Transliterate(text, steosmorphy.TranslitGOST), steosmorphy.TranslitGOST); got != text {
+		t.Errorf("Обратная транслитерация ГОСТ: %q, ожидали %q", got, text)
+	}
+	if got := steosmorphy.Detransliterate("Shchukina", steosmorphy.TranslitICAO); got != "Щукина" {
+		t.Errorf("Ожидали 'Щукина', получили %q", got)
+	}
+	if scheme, ok := steosmorphy.ParseTranslitScheme("ICAO"); !ok || scheme != steosmorphy.TranslitICAO {
+		t.Errorf("Ожидали систему icao, получили %v", scheme)
+	}
+
+	// Разные формы слов дают один адрес.
+	slug := analyzer.Slug("Котам нужны игрушки!", steosmorphy.TranslitGOST)
+	if slug != "kot-nuzhnyj-igrushka" || analyzer.Slug("кот, нужный игрушке", steosmorphy.TranslitGOST) != slug {
+		t.Errorf("Неверный ЧПУ-адрес: %q", slug)
+	}
+	if slug := analyzer.Slug("Итоги 2024 года", steosmorphy.TranslitPassport); slug != "itog-2024-god" {
+		t.Errorf("Числа должны сохраняться в адресе: %q", slug)
+	}
+}
+
+func TestPhonemes(t *testing.T) {
+	cases := []struct {
+		word string
+		want string
+	}{
+		{"молоко́", "m ə ɫ ɐ k ˈo"},
+		{"елка", "j ˈo ɫ k ə"}, // "ё" восстанавливается по словарю и дает ударение.
+		{"хлеб", "x lʲ ˈe p"},
+		{"вход", "f x ˈo t"},
+		{"сде́лать", "z dʲ ˈe ɫ ə tʲ"},
+		{"жена́", "ʐ ɨ n ˈa"},
+		{"кра́сного", "k r ˈa s n ə v ə"},
+		{"мно́го", "m n ˈo ɡ ə"},
+		{"у́чится", "ˈu t͡ɕ ɪ t͡s ə"},
+		{"подъе́зд", "p ɐ d j ˈe s t"},
+	}
+	for _, tc := range cases {
+		if got := strings.Join(analyzer.Phonemes(tc.word), " "); got != tc.want {
+			t.Errorf("Phonemes(%q) = [%s], ожидали [%s]", tc.word, got, tc.want)
+		}
+	}
+	// Без ударения гласные не редуцируются.
+	if got := strings.Join(analyzer.Phonemes("ложка"), " "); got != "ɫ o ʂ k a" {
+		t.Errorf("Ожидали [ɫ o ʂ k a], получили [%s]", got)
+	}
+	if got := analyzer.Phonemes("GitHub"); got != nil {
+		t.Errorf("Для латиницы ожидали nil, получили %v", got)
+	}
+}
+
+func TestIsKnown(t *testing.T) {
+	for word, known := range map[string]bool{"Кошки": true, "стали": true, "кошкии": false, "GitHub": false, "": false} {
+		if got := analyzer.IsKnown(word); got != known {
+			t.Errorf("IsKnown(%q) = %v; ожидали %v", word, got, known)
+		}
+	}
+	testCases := []struct {
+		word, prefix string
+		ok           bool
+	}{
+		{"Интернетмагазин", "Интернет", true},
+		{"кошкии", "кошки", true},
+		{"кот", "кот", true},
+		{"qwerty", "", false},
+	}
+	for _, tc := range testCases {
+		if prefix, ok := analyzer.KnownPrefix(tc.word); prefix != tc.prefix || ok != tc.ok {
+			t.Errorf("KnownPrefix(%q) = %q, %v; ожидали %q, %v", tc.word, prefix, ok, tc.prefix, tc.ok)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	testCases := []struct {
+		word     string
+		maxDist  int
+		expected string // Словоформа, которая должна быть в результате.
+	}{
+		{"Кошка", 0, "кошка"},
+		{"кошкп", 1, "кошка"},  // Замена.
+		{"кшока", 1, "кошка"},  // Перестановка соседних букв.
+		{"коша", 1, "кошка"},   // Пропуск.
+		{"коошка", 1, "кошка"}, // Лишняя буква.
+		{"малако", 2, "молоко"},
+	}
+	for _, tc := range testCases {
+		if suggestions := analyzer.Suggest(tc.word, tc.maxDist); !slices.Contains(suggestions, tc.expected) {
+			t.Errorf("Suggest(%q, %d) = %v; ожидали %q", tc.word, tc.maxDist, suggestions, tc.expected)
+		}
+	}
+	if suggestions := analyzer.Suggest("кошка", 1); len(suggestions) < 2 || suggestions[0] != "кошка" {
+		t.Errorf("Словарное слово должно идти первым, получили %v", suggestions)
+	}
+	if suggestions := analyzer.Suggest("кшока", 0); suggestions != nil {
+		t.Errorf("Без правок несловарное слово не исправляется, получили %v", suggestions)
+	}
+}
+
+// TestInvalidInput проверяет разбор слов с недопустимым UTF-8, в разложенной форме Unicode
+// и слов длиннее лимита.
+func TestInvalidInput(t *testing.T) {
+	// "й" как "и" + U+0306 и "ё" как "е" + U+0308 приводятся к NFC.
+	if parses := analyzer.Parse("мои\u0306"); len(parses) == 0 || parses[0].Word != "мой" {
+		t.Errorf("Parse(мой в NFD) = %v", parses)
+	}
+	if lemmas := analyzer.Lemmatize("е\u0308жик"); !slices.Contains(lemmas, "ёжик") {
+		t.Errorf("Lemmatize(ёжик в NFD) = %v", lemmas)
+	}
+	// Недопустимые байты заменяются на U+FFFD, а не ломают разбор.
+	parses, _ := analyzer.Analyze("кош\xffка")
+	for _, p := range parses {
+		if !utf8.ValidString(p.Word) || !utf8.ValidString(p.Lemma) {
+			t.Errorf("Analyze(кош\\xffка): недопустимый UTF-8 в разборе %+v", p)
+		}
+	}
+	if forms := analyzer.Inflect("\xff\xfe"); forms != nil {
+		t.Errorf("Inflect(\\xff\\xfe) = %v; ожидали nil", forms)
+	}
+
+	long := strings.Repeat("а", steosmorphy.DefaultMaxWordLen+1)
+	if parses, forms := analyzer.Analyze(long); parses != nil || forms != nil {
+		t.Errorf("Analyze для слова длиннее лимита = %d разборов, %d форм; ожидали nil", len(parses), len(forms))
+	}
+	huge := strings.Repeat("кот-", 1<<18)
+	started := time.Now()
+	analyzer.Analyze(huge)
+	analyzer.Lemmatize(huge)
+	analyzer.ParsePredicted(huge)
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Разбор мегабайтного слова занял %v", elapsed)
+	}
+
+	unlimited := testdict.Load(t, steosmorphy.WithMaxWordLen(0))
+	if lemmas := unlimited.Lemmatize(strings.Repeat("кот", 50) + "ами"); len(lemmas) == 0 {
+		t.Error("WithMaxWordLen(0): длинное слово не разобрано")
+	}
+	limited := testdict.Load(t, steosmorphy.WithMaxWordLen(3))
+	if parses := limited.Parse("кошка"); parses != nil {
+		t.Errorf("WithMaxWordLen(3): Parse(кошка) = %v; ожидали nil", parses)
+	}
+	if parses := limited.Parse("кот"); len(parses) == 0 {
+		t.Error("WithMaxWordLen(3): Parse(кот) не нашел разборов")
+	}
+}
+
+// TestStats проверяет статистику анализатора и хуки разбора.
+func TestStats(t *testing.T) {
+	var mu sync.Mutex
+	var events []steosmorphy.AnalysisEvent
+	morph := testdict.Load(t,
+		steosmorphy.WithStats(),
+		steosmorphy.WithCache(steosmorphy.NewLRUCache(16)),
+		steosmorphy.WithAnalysisHook(func(e steosmorphy.AnalysisEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		}),
+	)
+	morph.Parse("кошку")
+	morph.Parse("кошку") // Из кэша.
+	morph.Parse("абырвалг")
+	morph.ParsePredicted("мышку")
+	morph.Analyze("2024")
+	morph.Lemmatize("кота")
+
+	stats := morph.Stats()
+	expected := steosmorphy.Stats{Words: 6, DictionaryHits: 3, PredictorHits: 1, OtherHits: 1, Misses: 1}
+	if stats.Words != expected.Words || stats.DictionaryHits != expected.DictionaryHits ||
+		stats.PredictorHits != expected.PredictorHits || stats.OtherHits != expected.OtherHits || stats.Misses != expected.Misses {
+		t.Errorf("Stats() = %+v; ожидали %+v", stats, expected)
+	}
+	if stats.CacheHits == 0 || stats.CacheMisses == 0 {
+		t.Errorf("Stats(): попаданий в кэш %d, промахов %d; ожидали оба ненулевыми", stats.CacheHits, stats.CacheMisses)
+	}
+	if stats.TotalLatency <= 0 || stats.AvgLatency() <= 0 || stats.AvgLatency() > stats.TotalLatency {
+		t.Errorf("Stats(): неверное время разбора %v (среднее %v)", stats.TotalLatency, stats.AvgLatency())
+	}
+	if rate := stats.OOVRate(); rate != 0.5 {
+		t.Errorf("OOVRate() = %v; ожидали 0.5", rate)
+	}
+
+	if len(events) != 6 {
+		t.Fatalf("Хук вызван %d раз; ожидали 6", len(events))
+	}
+	if e := events[2]; e.Op != steosmorphy.OpParse || e.Word != "абырвалг" || e.Method != "" || e.Parses != 0 {
+		t.Errorf("Событие разбора несловарного слова: %+v", e)
+	}
+	if e := events[3]; e.Op != steosmorphy.OpPredict || e.Method != steosmorphy.UnitPredictor || e.Parses == 0 {
+		t.Errorf("Событие предсказания: %+v", e)
+	}
+	if e := events[4]; e.Op != steosmorphy.OpAnalyze || e.Method != steosmorphy.UnitNumber {
+		t.Errorf("Событие Analyze(2024): %+v", e)
+	}
+
+	if stats := analyzer.Stats(); stats != (steosmorphy.Stats{}) {
+		t.Errorf("Без WithStats ожидали нулевую статистику, получили %+v", stats)
+	}
+}
+
+// findParse ищет в срезе разборов тот, который соответствует ожиданиям.
+// Необходимо для неоднозначных слов.
+func findParse(parses []*steosmorphy.Parsed, lemma, pos string) *steosmorphy.Parsed {
+	for _, p := range parses {
+		if p.Lemma == lemma && p.PartOfSpeech == pos {
+			return p
+		}
+	}
+	return nil
+}
+
+// TestFallbackStemmer проверяет разбор несловарных слов других языков стеммерами
+// по алфавиту слова: словарные слова по-прежнему разбирает словарь.
+func TestFallbackStemmer(t *testing.T) {
+	english, err := snowball.Option("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	greek := steosmorphy.WithFallbackStemmer(unicode.Greek, steosmorphy.StemmerFunc(func(word string) string {
+		return strings.TrimSuffix(word, "ς")
+	}))
+	morph := testdict.Load(t, english, greek)
+
+	if lemmas := morph.Lemmatize("Running"); !slices.Equal(lemmas, []string{"run"}) {
+		t.Errorf("Lemmatize(Running) = %v; ожидали [run]", lemmas)
+	}
+	if parses, _ := morph.Analyze("connections"); len(parses) != 1 || parses[0].Lemma != "connect" || parses[0].Method != steosmorphy.UnitStemmer || !parses[0].Has("Основа") || !parses[0].Has("Латиница") {
+		t.Errorf("Analyze(connections) = %+v", parses)
+	}
+	if parses, _ := morph.Analyze("λόγος"); len(parses) != 1 || parses[0].Lemma != "λόγο" || !parses[0].Has("Неизвестное") {
+		t.Errorf("Analyze(λόγος) = %+v", parses)
+	}
+	var lemmas []string
+	for _, token := range morph.LemmatizeText("Кошки running, GitHub") {
+		lemmas = append(lemmas, token.Lemma)
+	}
+	if want := []string{"кошка", "run", "github"}; !slices.Equal(lemmas, want) {
+		t.Errorf("LemmatizeText: леммы %v; ожидали %v", lemmas, want)
+	}
+
+	// Повторная опция для того же алфавита заменяет стеммер, без стеммеров латиница не меняется.
+	morph = testdict.Load(t, english, steosmorphy.WithFallbackStemmer(unicode.Latin, steosmorphy.StemmerFunc(strings.ToUpper)))
+	if lemmas := morph.Lemmatize("running"); !slices.Equal(lemmas, []string{"RUNNING"}) {
+		t.Errorf("Lemmatize(running) с заменой стеммера = %v", lemmas)
+	}
+	if lemmas := testdict.Load(t).Lemmatize("running"); !slices.Equal(lemmas, []string{"running"}) {
+		t.Errorf("Lemmatize(running) без стеммера = %v", lemmas)
+	}
+	if _, err := snowball.New("klingon"); err == nil {
+		t.Error("Неизвестный язык стеммера не вернул ошибку")
+	}
+}
+
+// TestPymorphyCompat проверяет фасад pymorphy2: разборы с тегами OpenCorpora и оценками,
+// постановку в форму, лексему, нормальную форму и согласование с числом.
+func TestPymorphyCompat(t *testing.T) {
+	morph := pymorphy.New(analyzer)
+
+	parses := morph.Parse("Стали")
+	score := 0.0
+	var verb *pymorphy.Parse
+	for _, p := range parses {
+		score += p.Score
+		if p.Word != "стали" {
+			t.Errorf("Слово разбора %q; ожидали в нижнем регистре", p.Word)
+		}
+		if p.NormalForm == "стать" && verb == nil {
+			verb = p
+		}
+	}
+	if math.Abs(score-1) > 1e-9 {
+		t.Errorf("Сумма оценок разборов %v; ожидали 1", score)
+	}
+	if verb == nil || verb.Tag.POS() != "VERB" || !verb.Tag.Contains("plur", "past") || verb.Tag.Aspect() != "perf" {
+		t.Fatalf("Нет разбора 'стать' (VERB,perf plur,past): %+v", parses)
+	}
+	if n := verb.Normalized(); n == nil || n.Word != "стать" || n.Tag.POS() != "INFN" {
+		t.Errorf("Normalized(стали/стать) = %+v", n)
+	}
+
+	cat := morph.Parse("котами")[0]
+	if cat.NormalForm != "кот" || cat.Tag.Case() != "ablt" || cat.Tag.Number() != "plur" || cat.Method != steosmorphy.UnitDictionary {
+		t.Fatalf("Parse(котами)[0] = %+v", cat)
+	}
+	if s := cat.Tag.String(); !strings.HasPrefix(s, "NOUN,anim,") || !strings.HasSuffix(s, " plur,ablt") {
+		t.Errorf("Строка тега %q; ожидали \"NOUN,anim,... plur,ablt\"", s)
+	}
+	if p := cat.Inflect("datv"); p == nil || p.Word != "котам" {
+		t.Errorf("Inflect(datv) = %+v; ожидали котам (число сохраняется)", p)
+	}
+	if p := cat.Inflect("gent", "sing"); p == nil || p.Word != "кота" || p.NormalForm != "кот" {
+		t.Errorf("Inflect(gent, sing) = %+v", p)
+	}
+	if p := cat.Inflect("past"); p != nil {
+		t.Errorf("Существительное поставлено в прошедшее время: %+v", p)
+	}
+	if n := cat.Normalized(); n == nil || n.Word != "кот" || !n.Tag.Contains("nomn", "sing") {
+		t.Errorf("Normalized(котами) = %+v", n)
+	}
+	if lexeme := cat.Lexeme(); !slices.ContainsFunc(lexeme, func(p *pymorphy.Parse) bool { return p.Word == "котов" }) {
+		t.Errorf("В лексеме кота нет 'котов': %d форм", len(lexeme))
+	}
+	nominative := cat.Normalized()
+	for n, want := range map[int]string{1: "кот", 2: "кота", 5: "котов"} {
+		if p := nominative.MakeAgreeWithNumber(n); p == nil || p.Word != want {
+			t.Errorf("MakeAgreeWithNumber(%d) = %+v; ожидали %s", n, p, want)
+		}
+	}
+
+	adj := morph.Parse("красивая")[0]
+	if adj.Tag.POS() != "ADJF" || adj.Tag.Gender() != "femn" {
+		t.Errorf("Parse(красивая)[0].Tag = %s", adj.Tag)
+	}
+	if p := adj.Inflect("ADJS"); p == nil || p.Word != "красива" || p.Tag.POS() != "ADJS" {
+		t.Errorf("Inflect(ADJS) = %+v; ожидали красива", p)
+	}
+	if forms := morph.NormalForms("стали"); !slices.Contains(forms, "сталь") || !slices.Contains(forms, "стать") {
+		t.Errorf("NormalForms(стали) = %v", forms)
+	}
+	if !morph.WordIsKnown("кошка") || morph.WordIsKnown("нейросетями") {
+		t.Error("WordIsKnown: ожидали true для 'кошка' и false для 'нейросетями'")
+	}
+	if p := morph.Parse("нейросетями"); len(p) == 0 || p[0].NormalForm != "нейросеть" || p[0].IsKnown() {
+		t.Errorf("Parse(нейросетями) = %+v", p)
+	}
 }
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	steosmorphy "github.com/steosofficial/steosmorphy/analyzer"
+)
+
+// writeTestDict компилирует TSV-лексикон в файл path атомарно: через временный файл и rename.
+func writeTestDict(t *testing.T, path, lexicon string) {
+	t.Helper()
+	builder := steosmorphy.NewDictBuilder()
+	if err := steosmorphy.ReadTSVLexicon(strings.NewReader(lexicon), builder.Add); err != nil {
+		t.Fatalf("Ошибка чтения TSV: %v", err)
+	}
+	tmp := path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.Build(out); err != nil {
+		t.Fatalf("Ошибка сборки словаря: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitReload ждет очередного события перезагрузки.
+func waitReload(t *testing.T, events <-chan steosmorphy.ReloadEvent) steosmorphy.ReloadEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(10 * time.Second):
+		t.Fatal("Не дождались перезагрузки словаря")
+		return steosmorphy.ReloadEvent{}
+	}
+}
+
+// TestDictWatcher проверяет подмену словаря при замене файла и отказ от невалидного файла.
+func TestDictWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	writeTestDict(t, path, testLexiconTSV)
+
+	events := make(chan steosmorphy.ReloadEvent, 4)
+	watcher, err := steosmorphy.WatchDictionary(path, func(e steosmorphy.ReloadEvent) { events <- e })
+	if err != nil {
+		t.Fatalf("Ошибка запуска наблюдения: %v", err)
+	}
+	defer watcher.Close()
+
+	// Ссылка на старый словарь остается рабочей и после подмены.
+	old, release := watcher.Acquire()
+	if len(old.Parse("дом")) != 0 {
+		t.Fatal("В исходном словаре не должно быть слова 'дом'")
+	}
+
+	writeTestDict(t, path, testLexiconTSV+"дом\tдом\tСуществительное,Неодушевленное,Мужской,Единственное число,Именительный\n")
+	if event := waitReload(t, events); event.Err != nil {
+		t.Fatalf("Ошибка перезагрузки: %v", event.Err)
+	}
+	current, releaseCurrent := watcher.Acquire()
+	if len(current.Parse("дом")) != 1 {
+		t.Error("Новый словарь не содержит слова 'дом'")
+	}
+	if len(old.Parse("кота")) != 2 {
+		t.Error("Старый словарь перестал работать до возврата ссылки")
+	}
+	release()
+	releaseCurrent()
+
+	// Поврежденный файл отвергается, текущий словарь сохраняется.
+	if err := os.WriteFile(path+".tmp", []byte("не словарь"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		t.Fatal(err)
+	}
+	if event := waitReload(t, events); event.Err == nil {
+		t.Error("Ожидали ошибку загрузки поврежденного словаря")
+	}
+	current, releaseCurrent = watcher.Acquire()
+	defer releaseCurrent()
+	if len(current.Parse("дом")) != 1 {
+		t.Error("После неудачной перезагрузки должен остаться прежний словарь")
+	}
+}
+
+// TestDictWatcherReload проверяет подмену словаря файлом по другому пути через Reload.
+func TestDictWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "morph.dawg")
+	writeTestDict(t, path, testLexiconTSV)
+	const house = "дом\tдом\tСуществительное,Неодушевленное,Мужской,Единственное число,Именительный\n"
+	next := filepath.Join(t.TempDir(), "morph-next.dawg")
+	writeTestDict(t, next, testLexiconTSV+house)
+
+	events := make(chan steosmorphy.ReloadEvent, 4)
+	watcher, err := steosmorphy.WatchDictionary(path, func(e steosmorphy.ReloadEvent) { events <- e })
+	if err != nil {
+		t.Fatalf("Ошибка запуска наблюдения: %v", err)
+	}
+	defer watcher.Close()
+
+	old, release := watcher.Acquire()
+	if err := watcher.Reload(next); err != nil {
+		t.Fatalf("Reload(%s): %v", next, err)
+	}
+	if event := <-events; event.Err != nil || event.Path != next {
+		t.Errorf("Событие перезагрузки: %+v", event)
+	}
+	if watcher.Path() != next {
+		t.Errorf("Path() = %q; ожидали %q", watcher.Path(), next)
+	}
+	current, releaseCurrent := watcher.Acquire()
+	if len(current.Parse("дом")) != 1 {
+		t.Error("Новый словарь не содержит слова 'дом'")
+	}
+	if len(old.Parse("кота")) != 2 {
+		t.Error("Старый словарь перестал работать до возврата ссылки")
+	}
+	release()
+	releaseCurrent()
+
+	// Несуществующий путь отвергается, отслеживаемый файл и словарь остаются прежними.
+	if err := watcher.Reload(filepath.Join(t.TempDir(), "missing.dawg")); err == nil {
+		t.Error("Ожидали ошибку перезагрузки из несуществующего файла")
+	}
+	if event := <-events; event.Err == nil {
+		t.Error("Ожидали событие с ошибкой")
+	}
+	if watcher.Path() != next {
+		t.Errorf("После неудачной перезагрузки Path() = %q", watcher.Path())
+	}
+
+	// Теперь отслеживается новый файл.
+	writeTestDict(t, next, testLexiconTSV)
+	if event := waitReload(t, events); event.Err != nil {
+		t.Fatalf("Ошибка перезагрузки: %v", event.Err)
+	}
+	current, releaseCurrent = watcher.Acquire()
+	defer releaseCurrent()
+	if len(current.Parse("дом")) != 0 {
+		t.Error("Замена отслеживаемого файла не подхвачена")
+	}
+}